@@ -2,14 +2,27 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"go-cantus-firmus/internal/cantusgen"
-	"go-cantus-firmus/internal/music"
-	"go-cantus-firmus/internal/musicxml"
-	"go-cantus-firmus/internal/rules"
-	"go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/analysis"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/corpus"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/grading"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/midi"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/musicxml"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/repair"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/server"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/cantusgen"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"io/fs"
 	"log"
+	"math/rand"
 	"os"
+	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,48 +31,218 @@ import (
 // Project: go-cantus-firmus
 // Created: 2025-06-21
 
+// budgetCandidates is the number of best melodies kept by a time-boxed generation.
+const budgetCandidates = 100
+
 func main() {
+	budget := flag.Duration("budget", 0, "search for at most this long and keep the best melodies found (e.g. 2s); 0 enumerates all. "+
+		fmt.Sprintf("Needed, or -sample, for melodies of more than %d notes", cantusgen.MaxNotes))
+	evolve := flag.Bool("evolve", false, "evolve melodies with a genetic algorithm and keep the best ones found instead of enumerating all. "+
+		fmt.Sprintf("Suited to melodies of more than %d notes", cantusgen.MaxNotes))
+	sample := flag.Int("sample", 0, "draw at most this many random melodies instead of enumerating all; 0 enumerates all")
+	best := flag.Bool("best", false, "rank the melodies by score (variety of intervals, smoothness, a single prominent climax, "+
+		"stepwise motion) and save the best ones instead of a random selection")
+	diverse := flag.Bool("diverse", false, "save melodies that differ from each other as much as possible in contour and intervals "+
+		"instead of a random selection (with -best or -budget, starting from the best one)")
+	unique := flag.Bool("unique", false, "drop the melodies that are the inversion, retrograde or retrograde inversion of one kept before")
+	markov := flag.String("markov", "", "with -sample or -budget, favor the interval choices typical of a style: "+
+		"\"fux\" for the cantus firmi of Fux, or a directory of melodies to learn them from (as for heatmap)")
+	seed := flag.Int64("seed", 0, "seed of the random choices (sampling, budget search, melodies selected for saving); 0 picks one and prints it")
+	ruleOpts := addRuleFlags(flag.CommandLine)
+	shapeOpts := addShapeFlags(flag.CommandLine)
+	voice := flag.String("voice", "", "voice the melodies must fit, moved by octaves if needed: "+
+		strings.Join(music.VoiceNames(), ", ")+" or a range such as C3-G4 (default: any)")
+	stats := flag.Bool("stats", false, "print how many candidates the search tried and each rule rejected, "+
+		"the melodies found, the time spent and the peak memory")
+	checkpoint := flag.String("checkpoint", "", "file to save the search to when interrupted (Ctrl+C) and to resume it from "+
+		"when run again with the same answers; removed once the search is complete (not with -budget or -sample)")
+	progress := flag.Bool("progress", false, "show the progress of the search on standard error (not with -budget or -sample)")
+	continueFrom := flag.String("continue", "", "beginning of a melody to complete, as note names starting on the final "+
+		"or the -opening note (e.g. \"D4 F4 E4 A4\"); only its valid completions are generated")
+	against := flag.String("against", "", "fixed voice to write the melody against in first-species counterpoint, as note names "+
+		"(e.g. \"D3 F3 E3 D3 G3 F3 A3 G3 F3 E3 D3\"); the melody has as many notes")
+	againstOffset := flag.Int("against-offset", 7, "steps from the first note of the -against voice to the first note of the melody, "+
+		"negative below it: 0, 4 or 7 (unison, fifth, octave) or these plus octaves")
+	flag.Parse()
+
+	if flag.NArg() > 0 {
+		args := flag.Args()
+		switch args[0] {
+		case "serve":
+			runServer(args[1:])
+			return
+		case "validate":
+			runValidate(args[1:])
+			return
+		case "analyze":
+			runAnalyze(args[1:])
+			return
+		case "harmonize":
+			runHarmonize(args[1:])
+			return
+		case "heatmap":
+			runHeatmap(args[1:])
+			return
+		case "config":
+			runConfig(args[1:])
+			return
+		case "version":
+			runVersion()
+			return
+		}
+	}
+
+	ruleSet := ruleOpts.ruleSet(shapeOpts.rules(ruleOpts.openingHeight())...)
+	var voiceRange *music.NoteRange
+	if *voice != "" {
+		r, err := music.ParseVoiceRange(*voice)
+		if err != nil {
+			log.Fatal(err)
+		}
+		voiceRange = &r
+	}
+	// The intervals of the melody to complete, if any
+	var prefix []int
+	if *continueFrom != "" {
+		notes := parseNoteArgs(strings.Fields(*continueFrom))
+		prefix = make([]int, len(notes)-1)
+		for i := range prefix {
+			prefix[i] = notes[i+1].DiatonicValue() - notes[i].DiatonicValue()
+		}
+	}
+
+	// The voice the melody is written against, if any
+	var fixedVoice *cantusgen.FixedVoice
+	var againstNotes music.Realization
+	if *against != "" {
+		againstNotes = parseNoteArgs(strings.Fields(*against))
+		if class := utils.Abs(*againstOffset) % 7; class != 0 && class != 4 {
+			log.Fatalf("invalid -against-offset %d: the voices begin on a unison, fifth or octave", *againstOffset)
+		}
+		fixedVoice = &cantusgen.FixedVoice{Intervals: make([]int, len(againstNotes)-1), Offset: *againstOffset}
+		for i := range fixedVoice.Intervals {
+			fixedVoice.Intervals[i] = againstNotes[i+1].DiatonicValue() - againstNotes[i].DiatonicValue()
+		}
+	}
+
 	fmt.Println("=== Cantus Firmus Generator ===")
 	fmt.Println("This program generates all possible cantus firmi in whole notes")
 	fmt.Println("that satisfy the rules of strict style and saves them to a MusicXML file.")
 	fmt.Println()
 
 	// Get user input
-	length := getIntegerInput("Enter desired length (8-16 notes): ", 8, 16)
+	// Longer melodies have too many solutions to enumerate: only a bounded search may generate them
+	maxNotes := cantusgen.MaxNotes
+	if *budget > 0 || *sample > 0 || *evolve {
+		maxNotes = cantusgen.MaxLongNotes
+	}
+	length := getIntegerInput(fmt.Sprintf("Enter desired length (%d-%d notes): ", cantusgen.MinNotes, maxNotes),
+		cantusgen.MinNotes, maxNotes)
+	if len(prefix) >= length-1 {
+		log.Fatalf("the melody to continue already has %d notes: choose a longer length", len(prefix)+1)
+	}
+	if fixedVoice != nil && len(fixedVoice.Intervals) != length-1 {
+		log.Fatalf("the -against voice has %d notes: choose that length", len(fixedVoice.Intervals)+1)
+	}
 	mode := getModeInput()
-	leaps := getIntegerInput(fmt.Sprintf("Enter desired number of leaps in the cantus firmus (0-%d): ", length-4), 0, length-4)
+	m, _ := music.ParseMode(mode)
+	if err := ruleSet.SetMode(m); err != nil {
+		log.Fatal(err)
+	}
+	mostLeaps := length - cantusgen.MaxLeapsOffset
+	leaps := getIntegerInput(fmt.Sprintf("Enter desired number of leaps in the cantus firmus (0-%d): ", mostLeaps), 0, mostLeaps)
+	var realizeOpts music.RealizeOptions
+	if mode == "minor" {
+		realizeOpts.Minor = getMinorPolicyInput()
+	}
+	// The vertical intervals are checked against the voice realized in the mode: it must be the voice as written
+	if fixedVoice != nil {
+		voiceOpts := realizeOpts
+		voiceOpts.Opening = ruleSet.Opening()
+		voice, err := fixedVoice.Realize(m, voiceOpts)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, n := range voice {
+			if n.PitchClass() != againstNotes[i].PitchClass() {
+				log.Fatalf("the -against voice is not written with the notes of %s: %s", mode, voice)
+			}
+		}
+	}
+	degrees := getDegreesInput()
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	fmt.Printf("\nRandom seed: %d (pass -seed %d to repeat the same choices)\n", *seed, *seed)
+	rng := rand.New(rand.NewSource(*seed))
 
 	fmt.Println("\nGenerating... Please wait...")
 	startTime := time.Now()
 
 	// Generate interval sequences with length-1 and leaps as part of allowed intervals
-	intervalSequences := cantusgen.GenerateCantus(length-1, []int{leaps})
+	genOpts := cantusgen.Options{
+		AllowedLeaps: []int{leaps},
+		Degrees:      degrees,
+		Rules:        ruleSet,
+		Prefix:       prefix,
+		Ambitus:      shapeOpts.window(),
+		Cadences:     shapeOpts.endings(),
+		Against:      fixedVoice,
+		Pins:         shapeOpts.pinned(),
+		Mode:         m,
+		Realize:      realizeOpts,
+		Rand:         rng,
+	}
+	if *stats {
+		genOpts.Stats = &cantusgen.Stats{}
+	}
+	if *progress {
+		genOpts.Progress = printProgress
+	}
+	if *markov != "" {
+		genOpts.Transitions = loadTransitions(*markov)
+	}
+	var score cantusgen.ScoreFunc
+	if *best {
+		score = cantusgen.MelodicScore(m)
+	}
+	var intervalSequences [][]int
+	if *budget > 0 {
+		// Best-scoring sequences first
+		intervalSequences = cantusgen.GenerateWithBudget(length-1, genOpts, *budget, budgetCandidates, score)
+	} else if *evolve {
+		// Best-scoring sequences first
+		intervalSequences = cantusgen.GenerateEvolved(length-1, genOpts, cantusgen.EvolveOptions{Score: score}, budgetCandidates)
+	} else if *sample > 0 {
+		intervalSequences = cantusgen.SampleCantus(length-1, *sample, genOpts)
+	} else if *checkpoint != "" {
+		intervalSequences = generateResumable(length-1, genOpts, *checkpoint)
+	} else {
+		intervalSequences = cantusgen.Generate(length-1, genOpts)
+	}
+	if *best && *budget == 0 && !*evolve {
+		intervalSequences = cantusgen.TopScoring(intervalSequences, len(intervalSequences), score, ruleSet)
+	}
+	if *unique {
+		// After ranking, so that the best melody of each family is kept
+		intervalSequences = cantusgen.DropVariants(intervalSequences)
+	}
+	if genOpts.Stats != nil {
+		printStats(genOpts.Stats)
+	}
 	if len(intervalSequences) == 0 {
 		fmt.Println("Generation failed: no sequences could be generated.")
 		return
 	}
 
-	var validRealizations []music.Realization
-
-	// Process each sequence
-	for _, seq := range intervalSequences {
-		// Convert []int to []music.Interval
-		intervals := make(music.CantusFirmus, len(seq))
-		for i, val := range seq {
-			intervals[i] = music.Interval(val)
-		}
-
-		// Realize the sequence in the chosen mode (with capitalized mode name)
-		realization, err := intervals.Realize(strings.Title(mode))
-		if err != nil {
-			continue // Skip sequences with realization errors
-		}
-
-		// Check for augmented/diminished intervals
-		if rules.IsFreeOfAugmentedDiminished(realization) {
-			validRealizations = append(validRealizations, realization)
-		}
-	}
+	// Check the rules on realized pitches: augmented and diminished intervals, tritones,
+	// the opening and closing final, the voice range
+	validRealizations := cantusgen.Realize(intervalSequences, m, ruleSet, cantusgen.RealizationOptions{
+		Realize: realizeOpts,
+		Range:   voiceRange,
+		Rules:   shapeOpts.realizationRules(),
+	})
 
 	generationTime := time.Since(startTime).Round(time.Millisecond)
 	fmt.Printf("\nGeneration completed in %s\n", generationTime)
@@ -71,17 +254,31 @@ func main() {
 	}
 
 	// Ask how many to save
+	ranked := *budget > 0 || *evolve || *best
+	selection := "random"
+	switch {
+	case *diverse:
+		selection = "as varied as possible"
+	case ranked:
+		selection = "the best"
+	}
 	maxToSave := len(validRealizations)
 	saveCount := getIntegerInput(
-		fmt.Sprintf("How many cantus firmi to save? (1-%d, selection will be random if less than total): ", maxToSave),
+		fmt.Sprintf("How many cantus firmi to save? (1-%d, selection will be %s if less than total): ", maxToSave, selection),
 		1, maxToSave*2) // Allow numbers larger than max
 
 	var toSave []music.Realization
 	if saveCount >= maxToSave {
 		toSave = validRealizations
 		fmt.Printf("Saving all %d cantus firmi...\n", maxToSave)
+	} else if *diverse {
+		toSave = utils.SelectDiverseItems(validRealizations, saveCount, music.Realization.Distance)
+		fmt.Printf("Selecting %d varied out of %d cantus firmi to save...\n", saveCount, maxToSave)
+	} else if ranked {
+		toSave = validRealizations[:saveCount]
+		fmt.Printf("Selecting the %d best of %d cantus firmi to save...\n", saveCount, maxToSave)
 	} else {
-		toSave = utils.SelectRandomItems(validRealizations, saveCount)
+		toSave = utils.SelectRandomItems(validRealizations, saveCount, rng)
 		fmt.Printf("Randomly selecting %d out of %d cantus firmi to save...\n", saveCount, maxToSave)
 	}
 
@@ -91,14 +288,754 @@ func main() {
 
 	// Convert to MusicXML format
 	xmlSequences := musicxml.ConvertRealizationsToXMLNotes(toSave)
+	if getYesNoInput("Label notes with scale degrees, climax and leading tone? (y/N): ") {
+		for i, r := range toSave {
+			xmlSequences[i] = musicxml.WithLabels(xmlSequences[i], music.Annotate(r, music.NewScale(m)))
+		}
+	}
 
-	// Save to file
-	err := musicxml.GenerateAndSaveMusicXML(xmlSequences, filename)
+	// Save to file, with the constraints that produced the melodies
+	generation := fmt.Sprintf("length=%d mode=%s leaps=%d degrees=%v seed=%d", length, strings.ToLower(mode), leaps, degrees, *seed)
+	if m == music.Minor {
+		generation += " minor=" + realizeOpts.Minor.String()
+	}
+	if voiceRange != nil {
+		generation += " voice=" + voiceRange.String()
+	}
+	if *continueFrom != "" {
+		generation += " continue=" + strings.Join(strings.Fields(*continueFrom), "-")
+	}
+	if *shapeOpts.pins != "" {
+		generation += " pins=" + *shapeOpts.pins
+	}
+	metadata := []musicxml.MetadataField{
+		{Name: "rules-fingerprint", Value: ruleSet.Fingerprint()},
+		{Name: "generation", Value: generation},
+		{Name: "generator-version", Value: versionString()},
+	}
+	var err error
+	if layout := getLayoutInput(length); layout != nil {
+		err = musicxml.GenerateAndSaveMusicXMLWithLayout(xmlSequences, *layout, filename, metadata...)
+	} else {
+		err = musicxml.GenerateAndSaveMusicXML(xmlSequences, filename, metadata...)
+	}
 	if err != nil {
 		log.Fatalf("Error saving file: %v", err)
 	}
 
 	fmt.Printf("\nSuccessfully saved %d cantus firmi to %s\n", len(toSave), filename)
+
+	if getYesNoInput("Also export to MIDI with one track per cantus? (y/N): ") {
+		midiFilename := strings.TrimSuffix(filename, ".musicxml") + ".mid"
+		if err := midi.GenerateAndSaveMIDI(toSave, strings.Title(mode), midiFilename); err != nil {
+			log.Fatalf("Error saving file: %v", err)
+		}
+		fmt.Printf("Successfully saved %d cantus firmi to %s\n", len(toSave), midiFilename)
+	}
+
+	if getYesNoInput("Also export tension profiles against the final (CSV and SVG)? (y/N): ") {
+		base := strings.TrimSuffix(filename, ".musicxml")
+		if err := saveTensionProfiles(toSave, base); err != nil {
+			log.Fatalf("Error saving file: %v", err)
+		}
+		fmt.Printf("Successfully saved tension profiles to %s_tension.csv and %s_tension_N.svg\n", base, base)
+	}
+}
+
+// saveTensionProfiles writes the tension profiles of all realizations to a single CSV file
+// (with a leading "cantus" column) and one SVG chart per cantus.
+func saveTensionProfiles(realizations []music.Realization, base string) error {
+	var csvData strings.Builder
+	for i, r := range realizations {
+		profile, err := analysis.TensionProfile(r, r[0])
+		if err != nil {
+			return err
+		}
+
+		var rows strings.Builder
+		if err := analysis.WriteTensionCSV(&rows, profile); err != nil {
+			return err
+		}
+		lines := strings.Split(strings.TrimSuffix(rows.String(), "\n"), "\n")
+		if i == 0 {
+			csvData.WriteString("cantus," + lines[0] + "\n")
+		}
+		for _, line := range lines[1:] {
+			fmt.Fprintf(&csvData, "%d,%s\n", i+1, line)
+		}
+
+		svgFile, err := os.Create(fmt.Sprintf("%s_tension_%d.svg", base, i+1))
+		if err != nil {
+			return err
+		}
+		err = analysis.WriteTensionSVG(svgFile, profile)
+		if closeErr := svgFile.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(base+"_tension.csv", []byte(csvData.String()), 0644)
+}
+
+// runServer starts the HTTP API (see package server).
+func runServer(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	fs.Parse(args)
+
+	fmt.Printf("Serving the cantus firmus API on %s\n", *addr)
+	log.Fatal(server.NewServer(*addr).ListenAndServe())
+}
+
+// runValidate checks a cantus firmus given as note names against the generator rules
+// and, if it is not valid, prints the best repair suggestions.
+func runValidate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	maxEdits := fs.Int("edits", 2, "maximum number of notes to change in a suggestion")
+	maxSuggestions := fs.Int("suggestions", 5, "maximum number of suggestions to print")
+	maxReplaced := fs.Int("replace", 2, "maximum number of intervals to replace in the nearest valid melodies; 0 skips them")
+	rubricFile := fs.String("rubric", "", "JSON grading rubric; when set, a grade report is printed")
+	ruleOpts := addRuleFlags(fs)
+	leapCounts := fs.String("leaps", "", "allowed numbers of leaps, comma-separated (e.g. 2,3); empty accepts any number")
+	leapRatio := fs.String("leap-ratio", "", "largest share of leaps among the intervals (e.g. 1/3), for melodies of any length")
+	shapeOpts := addShapeFlags(fs)
+	var mode music.Mode
+	fs.TextVar(&mode, "mode", music.Mode(0), "mode of the melody (default: the mode whose final is the last note, "+
+		"or the note the -ending is counted from, e.g. dorian for D)")
+	voice := fs.String("voice", "", "voice the melody must fit: "+strings.Join(music.VoiceNames(), ", ")+
+		" or a range such as C3-G4 (default: any)")
+	messages := fs.String("messages", "", messagesFlagUsage)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: validate [flags] NOTE NOTE ... (e.g. validate D4 F4 E4 D4 G4 F4 A4 G4 F4 E4 D4)")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	loadCatalog(*messages)
+
+	notes := parseNoteArgs(fs.Args())
+	if len(notes) < 3 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	extra := shapeOpts.rules(ruleOpts.openingHeight())
+	if *leapRatio != "" {
+		num, den, err := rules.ParseLeapRatio(*leapRatio)
+		if err != nil {
+			log.Fatal(err)
+		}
+		extra = append(extra, rules.LeapRatioRule(num, den))
+	}
+	ruleSet := ruleOpts.ruleSet(extra...)
+	if mode == 0 {
+		last := notes[len(notes)-1]
+		mode = modeOfFinal(music.Note{Step: music.Mod7(last.Step - ruleSet.Ending())})
+	}
+	if err := ruleSet.SetMode(mode); err != nil {
+		log.Fatal(err)
+	}
+	// The rules on realized pitches are checked on the notes as written, accidentals included
+	realizationRules := rules.RealizationRulesFrom(music.NewScale(mode), ruleSet.Opening(), ruleSet.Ending())
+	if *voice != "" {
+		r, err := music.ParseVoiceRange(*voice)
+		if err != nil {
+			log.Fatal(err)
+		}
+		realizationRules = append(realizationRules, rules.VoiceRangeRule(r))
+	}
+	realizationRules = append(realizationRules, shapeOpts.realizationRules()...)
+	opts := cantusgen.Options{Rules: ruleSet, Ambitus: shapeOpts.window(), Cadences: shapeOpts.endings(), Pins: shapeOpts.pinned()}
+	if *leapCounts != "" {
+		for _, field := range strings.Split(*leapCounts, ",") {
+			count, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || count < 0 {
+				log.Fatalf("invalid number of leaps %q", field)
+			}
+			opts.AllowedLeaps = append(opts.AllowedLeaps, count)
+		}
+	}
+
+	intervals := make([]int, len(notes)-1)
+	for i := range intervals {
+		intervals[i] = notes[i+1].DiatonicValue() - notes[i].DiatonicValue()
+	}
+
+	if *rubricFile != "" {
+		rubric, err := grading.LoadRubric(*rubricFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		report, err := grading.Grade(intervals, opts, rubric)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(report)
+		fmt.Println()
+	}
+
+	violations := cantusgen.Violations(intervals, opts)
+	intervalsValid := len(violations) == 0
+	realizationViolations := rules.CheckRealization(notes, realizationRules)
+	for _, v := range realizationViolations {
+		if !slices.Contains(violations, v.Rule) {
+			violations = append(violations, v.Rule)
+		}
+	}
+	if len(violations) == 0 {
+		fmt.Println("The cantus firmus is valid.")
+		for _, v := range ruleSet.Warnings(intervals) {
+			fmt.Printf("  - warning (%g): %s\n", ruleSet.Weight(v.Rule), rules.Explain(v, intervals, notes))
+		}
+		if penalty := ruleSet.Penalty(intervals); penalty > 0 {
+			fmt.Printf("Warning penalty: %g\n", penalty)
+		}
+		return
+	}
+
+	fmt.Printf("The cantus firmus violates the rules of strict style: %s\n", strings.Join(violations, ", "))
+	for _, v := range ruleSet.Check(intervals) {
+		if ruleSet.Severity(v.Rule) == rules.SeverityWarning {
+			fmt.Printf("  - warning (%g): %s\n", ruleSet.Weight(v.Rule), rules.Explain(v, intervals, notes))
+		} else {
+			fmt.Printf("  - %s\n", rules.Explain(v, intervals, notes))
+		}
+	}
+	var optionRules []rules.Rule
+	if a := opts.Ambitus; a != nil {
+		optionRules = append(optionRules, rules.AmbitusRule(a.Low, a.High, ruleSet.Opening()))
+	}
+	for _, p := range opts.Pins {
+		optionRules = append(optionRules, rules.PinRule(p))
+	}
+	for _, v := range rules.CheckRules(intervals, optionRules) {
+		fmt.Printf("  - %s\n", rules.Explain(v, intervals, notes))
+	}
+	if slices.Contains(violations, cantusgen.RuleEnding) {
+		fmt.Printf("  - The melody does not end with one of the intervals %v.\n", opts.Cadences)
+	}
+	for _, v := range realizationViolations {
+		fmt.Printf("  - %s\n", rules.Explain(v, intervals, notes))
+	}
+	if intervalsValid {
+		// Only the accidentals are wrong: the notes of the mode may mend them
+		diatonic := make(music.Realization, len(notes))
+		scale := music.NewScale(mode)
+		for i, n := range notes {
+			diatonic[i] = scale.NoteForDegree(scale.Degree(n), n.Octave)
+		}
+		if rules.AllRealizationRules(diatonic, realizationRules) {
+			fmt.Printf("Written with the notes of %s: %s\n", mode, diatonic)
+		}
+		os.Exit(1)
+	}
+	// The repairs are realized in the mode, so that they are free of augmented and diminished
+	// intervals as written out in it
+	opts.Mode = mode
+	suggestions := repair.Suggest(intervals, *maxEdits, opts)
+	if len(suggestions) == 0 {
+		fmt.Printf("No repair found by changing at most %d notes.\n", *maxEdits)
+	} else {
+		fmt.Println("Suggested repairs:")
+		for i, s := range suggestions {
+			if i == *maxSuggestions {
+				break
+			}
+			var edits []string
+			repaired := slices.Clone(notes)
+			for _, c := range s.Changes {
+				repaired[c.Index] = music.Transpose(notes[0], music.Interval(c.To))
+				edits = append(edits, fmt.Sprintf("note %d %s -> %s", c.Index+1, notes[c.Index], repaired[c.Index]))
+			}
+			fmt.Printf("%d. %s: %s\n", i+1, strings.Join(edits, ", "), repaired)
+		}
+	}
+
+	if *maxReplaced > 0 {
+		repairs := repair.Nearest(intervals, *maxReplaced, opts)
+		if len(repairs) == 0 {
+			fmt.Printf("No valid melody found by replacing at most %d intervals.\n", *maxReplaced)
+		} else {
+			fmt.Println("Nearest valid melodies, with the fewest intervals replaced:")
+			for i, r := range repairs {
+				if i == *maxSuggestions {
+					break
+				}
+				var edits []string
+				for _, e := range r.Edits {
+					edits = append(edits, fmt.Sprintf("interval %d %s -> %s", e.Index+1, music.Interval(e.From), music.Interval(e.To)))
+				}
+				repaired := make([]music.Note, len(notes))
+				for j, h := range music.PartialSums(r.Intervals) {
+					repaired[j] = music.Transpose(notes[0], music.Interval(h))
+				}
+				fmt.Printf("%d. %s: %s\n", i+1, strings.Join(edits, ", "), repaired)
+			}
+		}
+	}
+	os.Exit(1)
+}
+
+// runAnalyze prints the analysis report (see package analysis) of a melody given as note names as JSON.
+func runAnalyze(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	messages := fs.String("messages", "", messagesFlagUsage)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: analyze [flags] NOTE NOTE ... (e.g. analyze D4 F4 E4 D4 G4 F4 A4 G4 F4 E4 D4)")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	loadCatalog(*messages)
+
+	notes := parseNoteArgs(fs.Args())
+	if len(notes) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	report, err := analysis.Analyze(notes)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runHarmonize prints a chordal harmonization skeleton of a melody given as note names
+// and optionally saves it as MusicXML with a second, figured staff.
+func runHarmonize(args []string) {
+	fs := flag.NewFlagSet("harmonize", flag.ExitOnError)
+	var mode music.Mode
+	fs.TextVar(&mode, "mode", music.Mode(0), "mode of the melody (default: the mode whose final is the last note, e.g. dorian for D)")
+	output := fs.String("o", "", "MusicXML file to save the melody with a harmony staff to")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: harmonize [flags] NOTE NOTE ... (e.g. harmonize D4 F4 E4 D4 G4 F4 A4 G4 F4 E4 D4)")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	notes := parseNoteArgs(fs.Args())
+	if len(notes) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if mode == 0 {
+		mode = modeOfFinal(notes[len(notes)-1])
+	}
+
+	h, err := analysis.Harmonize(notes, mode)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Mode:   %s\n%s\n", mode, analysis.HarmonizationText(h))
+
+	if *output != "" {
+		bass := make(music.Realization, len(h))
+		numerals := make([]string, len(h))
+		for i, c := range h {
+			bass[i] = c.Bass
+			numerals[i] = c.Numeral
+		}
+		xmlNotes := musicxml.ConvertRealizationsToXMLNotes([]music.Realization{notes, bass})
+		if err := musicxml.GenerateAndSaveMusicXMLWithHarmony(xmlNotes[0], xmlNotes[1], numerals, *output); err != nil {
+			log.Fatalf("Error saving file: %v", err)
+		}
+		fmt.Printf("Saved to %s\n", *output)
+	}
+}
+
+// runHeatmap prints which rules the melodies of a folder break most often and where.
+func runHeatmap(args []string) {
+	fs := flag.NewFlagSet("heatmap", flag.ExitOnError)
+	asCSV := fs.Bool("csv", false, "print CSV instead of a table")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: heatmap [flags] DIR (MusicXML files and .txt files with one melody per line)")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	melodies, err := corpus.LoadDir(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	h, err := corpus.BuildHeatmap(melodies, cantusgen.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *asCSV {
+		err = h.WriteCSV(os.Stdout)
+	} else {
+		err = h.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runConfig writes the rule set selected by the rule flags as a configuration file,
+// to be edited and passed back with -config.
+func runConfig(args []string) {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	ruleOpts := addRuleFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: config [flags] > rules.toml")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if err := rules.WriteConfig(os.Stdout, ruleOpts.ruleSet()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// ruleFlags are the flags selecting the rules, shared by the generator and the subcommands checking melodies.
+type ruleFlags struct {
+	config, preset, soft, warn, maxRange, cadence, intervals, opening, ending *string
+	repeatedNotes                                                             *bool
+}
+
+// addRuleFlags defines the rule flags on fs.
+func addRuleFlags(fs *flag.FlagSet) ruleFlags {
+	return ruleFlags{
+		config: fs.String("config", "", "rule configuration file (see the config subcommand); replaces -preset"),
+		preset: fs.String("preset", rules.PresetFux, "strictness preset: "+strings.Join(rules.PresetNames(), ", ")),
+		soft: fs.String("soft", "", "comma-separated soft rules with their weights (e.g. ValidateClimax=2,NoSequences=1); "+
+			"breaking a soft rule adds its weight to a penalty instead of rejecting the melody"),
+		warn: fs.String("warn", "", "comma-separated rules reported as warnings instead of rejecting the melody "+
+			"(e.g. NoCloseLargeLeaps); a warning is a soft rule, of weight 1 unless given in -soft"),
+		maxRange: fs.String("range", "", "largest range of the melody: octave, tenth, twelfth or a number of steps (default: the preset's)"),
+		cadence:  fs.String("cadence", "", "approach to the final: above (2-1), below (7-1) or either (default)"),
+		intervals: fs.String("intervals", "", "leaps a melody may use, e.g. third,fourth,-sixth,octave; "+
+			"+ or - allows only the upward or downward leap (default: the preset's)"),
+		opening: fs.String("opening", "", "note the melody begins on: final, or an interval above or below the final "+
+			"such as fifth or fourth below; the melody still ends on the final (default: the preset's, the final)"),
+		ending: fs.String("ending", "", "note the melody ends on, like -opening: octave below ends on the final an octave lower, "+
+			"as in the plagal modes (default: the preset's, the final)"),
+		repeatedNotes: fs.Bool("repeated-notes", false,
+			"allow a single repeated note, not at the start or end (default: the preset's, which forbids them)"),
+	}
+}
+
+// ruleSet returns the rule set read from the configuration file, or else the one of
+// the strictness preset, with its range limited, the final approached, the leaps allowed and
+// the opening and ending notes set as the -range, -cadence, -intervals, -opening and -ending
+// flags require, if set (see rules.ParseRange, rules.ParseCadence, rules.ParseLeaps,
+// rules.ParseOpening and rules.ParseEnding), the extra rules added, repeated notes allowed
+// with -repeated-notes, and the rules listed in -soft made soft and those in -warn warnings.
+// It exits on invalid flag values.
+func (f ruleFlags) ruleSet(extra ...rules.Rule) *rules.RuleSet {
+	ruleSet, err := loadRuleSet(*f.config, *f.preset)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, r := range extra {
+		if err := ruleSet.Add(r); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *f.maxRange != "" {
+		limit, err := rules.ParseRange(*f.maxRange)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ruleSet.SetMaxRange(limit); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *f.cadence != "" {
+		degrees, err := rules.ParseCadence(*f.cadence)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ruleSet.SetCadence(degrees...); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *f.intervals != "" {
+		leaps, err := rules.ParseLeaps(*f.intervals)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ruleSet.SetLeaps(leaps...)
+	}
+	if *f.opening != "" {
+		if err := ruleSet.SetOpening(f.openingHeight()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *f.ending != "" {
+		height, err := rules.ParseEnding(*f.ending)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ruleSet.SetEnding(height); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *f.repeatedNotes {
+		ruleSet.SetRepeatedNotes(true)
+	}
+	if *f.warn != "" {
+		for _, name := range strings.Split(*f.warn, ",") {
+			if err := ruleSet.SetSeverity(strings.TrimSpace(name), rules.SeverityWarning); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	if *f.soft == "" {
+		return ruleSet
+	}
+	for _, item := range strings.Split(*f.soft, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			log.Fatalf("invalid soft rule %q: want Rule=weight", item)
+		}
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil {
+			log.Fatalf("invalid weight of soft rule %s: %v", name, err)
+		}
+		if err := ruleSet.SetSoft(name, w); err != nil {
+			log.Fatal(err)
+		}
+	}
+	return ruleSet
+}
+
+// openingHeight returns the height of the opening note above the final given with -opening
+// (see rules.ParseOpening), 0 if the flag is not set. It exits on an invalid value.
+func (f ruleFlags) openingHeight() int {
+	if *f.opening == "" {
+		return 0
+	}
+	height, err := rules.ParseOpening(*f.opening)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return height
+}
+
+// shapeFlags are the flags adding parameterized rules on the shape of the melody, and pinning
+// intervals or notes, shared by the generator and the validate subcommand.
+type shapeFlags struct {
+	consecutive, climax, climaxHeight, outline, pins, cadences, ambitus *string
+	directionChanges, leapRecovery                                      *int
+}
+
+// addShapeFlags defines the shape flags on fs.
+func addShapeFlags(fs *flag.FlagSet) shapeFlags {
+	return shapeFlags{
+		consecutive: fs.String("consecutive", "", "largest numbers of same-size intervals in a row, e.g. second=4,third=2,fourth=1"),
+		climax: fs.String("climax-approach", "",
+			"largest intervals into and out of the climax: step, third, or in=size,out=size (e.g. in=step,out=third)"),
+		climaxHeight: fs.String("climax-height", "",
+			"height of the climax above the final: an interval (e.g. sixth) or min=interval,max=interval (e.g. min=fifth,max=octave)"),
+		directionChanges: fs.Int("max-direction-changes", 0, "largest number of changes of direction, against zigzagging melodies (default: unlimited)"),
+		leapRecovery: fs.Int("leap-recovery", 0,
+			"smallest percentage of leaps followed immediately by a step in the opposite direction (e.g. 75)"),
+		outline: fs.String("outline", "", "intervals that no leap or pair of adjacent leaps may outline, e.g. seventh,ninth,tritone; "+
+			"tritones are checked on realized pitches only"),
+		pins: fs.String("pin", "", "intervals or notes fixed at given positions, e.g. interval3=-leap,note5=5 "+
+			"(a descending leap as the third interval, the dominant as the fifth note)"),
+		ambitus: fs.String("ambitus", "", "window of heights every note must lie in, in steps from the final, "+
+			"e.g. -2..7 for a third below to an octave above (default: anywhere the range allows)"),
+		cadences: fs.String("cadence-patterns", "", "intervals the melody may end with, patterns separated by semicolons, "+
+			"e.g. \"-1,-1;1,-1;-2,1,-1\" (default: any two steps)"),
+	}
+}
+
+// rules returns the rules the shape flags require: runs of same-size intervals limited as
+// -consecutive requires (see rules.ParseConsecutiveLimits), the changes of direction limited,
+// the motion into and out of the climax restricted as -climax-approach requires (see
+// rules.ParseClimaxApproach), the height of the climax bounded as -climax-height requires
+// (see rules.ParseClimaxHeight) for melodies opening the given number of steps above the final,
+// the share of recovered leaps bounded, and the intervals in -outline other than the tritone
+// forbidden between leap endpoints (see rules.ParseOutlineIntervals).
+// Flags left empty or at 0 add no rule. It exits on invalid flag values.
+func (f shapeFlags) rules(opening int) []rules.Rule {
+	var result []rules.Rule
+	if *f.consecutive != "" {
+		limits, err := rules.ParseConsecutiveLimits(*f.consecutive)
+		if err != nil {
+			log.Fatal(err)
+		}
+		result = append(result, rules.ConsecutiveIntervalsRule(limits))
+	}
+	if *f.directionChanges < 0 {
+		log.Fatalf("invalid number of direction changes %d: want a positive number", *f.directionChanges)
+	}
+	if *f.directionChanges > 0 {
+		result = append(result, rules.DirectionChangesRule(*f.directionChanges))
+	}
+	if *f.climax != "" {
+		approach, departure, err := rules.ParseClimaxApproach(*f.climax)
+		if err != nil {
+			log.Fatal(err)
+		}
+		result = append(result, rules.ClimaxApproachRule(approach, departure))
+	}
+	if *f.climaxHeight != "" {
+		low, high, err := rules.ParseClimaxHeight(*f.climaxHeight)
+		if err != nil {
+			log.Fatal(err)
+		}
+		result = append(result, rules.ClimaxHeightRulesFrom(low, high, opening)...)
+	}
+	if *f.leapRecovery < 0 || *f.leapRecovery > 100 {
+		log.Fatalf("invalid percentage of recovered leaps %d: want 0 to 100", *f.leapRecovery)
+	}
+	if *f.leapRecovery > 0 {
+		result = append(result, rules.LeapRecoveryRule(*f.leapRecovery))
+	}
+	if sizes, _ := f.outlineIntervals(); len(sizes) > 0 {
+		result = append(result, rules.LeapOutlineRule(sizes))
+	}
+	return result
+}
+
+// realizationRules returns the rules on realized pitches the shape flags require: leaps
+// outlining a tritone forbidden if -outline lists it. It exits on invalid flag values.
+func (f shapeFlags) realizationRules() []rules.RealizationRule {
+	if _, tritone := f.outlineIntervals(); tritone {
+		return []rules.RealizationRule{rules.TritoneLeapOutlineRule()}
+	}
+	return nil
+}
+
+// outlineIntervals parses the -outline flag. It exits on an invalid value.
+func (f shapeFlags) outlineIntervals() (sizes []int, tritone bool) {
+	if *f.outline == "" {
+		return nil, false
+	}
+	sizes, tritone, err := rules.ParseOutlineIntervals(*f.outline)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return sizes, tritone
+}
+
+// pinned parses the -pin flag (see rules.ParsePins). It exits on an invalid value.
+func (f shapeFlags) pinned() []rules.Pin {
+	if *f.pins == "" {
+		return nil
+	}
+	pins, err := rules.ParsePins(*f.pins)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return pins
+}
+
+// endings returns the ending patterns -cadence-patterns requires (see rules.ParseCadencePatterns),
+// or nil if it is empty. It exits on an invalid flag value.
+func (f shapeFlags) endings() [][]int {
+	if *f.cadences == "" {
+		return nil
+	}
+	patterns, err := rules.ParseCadencePatterns(*f.cadences)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return patterns
+}
+
+// window returns the ambitus -ambitus requires (see rules.ParseAmbitus), or nil if it is empty.
+// It exits on an invalid flag value.
+func (f shapeFlags) window() *cantusgen.Ambitus {
+	if *f.ambitus == "" {
+		return nil
+	}
+	low, high, err := rules.ParseAmbitus(*f.ambitus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return &cantusgen.Ambitus{Low: low, High: high}
+}
+
+// messagesFlagUsage describes the -messages flag of the subcommands explaining broken rules.
+const messagesFlagUsage = "JSON message catalog translating rule violations into another language (see rules.Catalog)"
+
+// loadCatalog translates the violation messages with the catalog in the file, if set.
+// It exits if the file cannot be read.
+func loadCatalog(path string) {
+	if path == "" {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	c, err := rules.ReadCatalog(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rules.SetCatalog(c)
+}
+
+// loadRuleSet reads the rule set from the configuration file if set, or else returns
+// the rule set of the strictness preset.
+func loadRuleSet(config, preset string) (*rules.RuleSet, error) {
+	if config == "" {
+		return rules.Preset(preset)
+	}
+	f, err := os.Open(config)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	ruleSet, err := rules.ReadConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", config, err)
+	}
+	return ruleSet, nil
+}
+
+// modeOfFinal returns the mode whose final has the step of the note, e.g. dorian for D.
+func modeOfFinal(final music.Note) music.Mode {
+	for _, m := range music.Modes() {
+		if music.NewScale(m).Tonic.Step == final.Step {
+			return m
+		}
+	}
+	return 0
+}
+
+// parseNoteArgs parses command-line arguments as note names (e.g. "D4", "F#4").
+func parseNoteArgs(args []string) music.Realization {
+	notes := make(music.Realization, len(args))
+	for i, arg := range args {
+		n, err := music.ParseNote(arg)
+		if err != nil {
+			log.Fatalf("%v (%s)", err, noteParseHint(err))
+		}
+		notes[i] = n
+	}
+	return notes
+}
+
+// noteParseHint explains how to fix a note name rejected by music.ParseNote.
+func noteParseHint(err error) string {
+	switch {
+	case errors.Is(err, music.ErrBadStep):
+		return "a note starts with a letter from A to G"
+	case errors.Is(err, music.ErrBadAccidental):
+		return "use at most one kind of accidental: #, ## or x for sharps, b or bb for flats"
+	case errors.Is(err, music.ErrBadOctave):
+		return "a note ends with its octave number, e.g. D4 or F#3"
+	}
+	return "write notes like D4, F#3 or Bb4"
 }
 
 func getIntegerInput(prompt string, min, max int) int {
@@ -137,3 +1074,219 @@ func getModeInput() string {
 		fmt.Println("Invalid mode. Please choose from the available options.")
 	}
 }
+
+// getDegreesInput asks for scale degrees to avoid and returns the remaining usable degrees.
+// An empty answer keeps the full scale (nil).
+func getDegreesInput() []int {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print("Enter scale degrees to avoid (2-7, comma-separated, empty for none): ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return nil
+		}
+
+		avoid := make(map[int]bool)
+		valid := true
+		for _, field := range strings.Split(input, ",") {
+			degree, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || degree < 2 || degree > 7 {
+				valid = false
+				break
+			}
+			avoid[degree] = true
+		}
+		if !valid {
+			fmt.Println("Please enter degrees between 2 and 7 separated by commas")
+			continue
+		}
+
+		var degrees []int
+		for degree := 1; degree <= 7; degree++ {
+			if !avoid[degree] {
+				degrees = append(degrees, degree)
+			}
+		}
+		return degrees
+	}
+}
+
+// getLayoutInput asks for the meter and the number of measures each cantus should occupy.
+// An empty meter keeps the default layout of one measure per cantus (nil).
+func getLayoutInput(length int) *musicxml.Layout {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print("Enter meter for export (e.g. 4/2, 3/1; empty for one measure per cantus): ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return nil
+		}
+
+		ts, err := music.ParseTimeSignature(input)
+		if err != nil {
+			fmt.Println("Please enter the meter as beats/beat-type, e.g. 4/2")
+			continue
+		}
+		layout := musicxml.Layout{Time: ts}
+
+		layout.Measures = getIntegerInput("Enter number of measures per cantus (0 for automatic): ", 0, length)
+		if _, err := musicxml.ToMusicXMLWithLayout([][]musicxml.Note{make([]musicxml.Note, length)}, layout); err != nil {
+			fmt.Printf("Cannot use this layout: %v\n", err)
+			continue
+		}
+		return &layout
+	}
+}
+
+// getYesNoInput asks a yes/no question; anything other than "y" or "yes" means no.
+func getYesNoInput(prompt string) bool {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print(prompt)
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(strings.ToLower(input))
+	return input == "y" || input == "yes"
+}
+
+// getMinorPolicyInput asks how the 6th and 7th degrees should be treated in minor mode.
+// An empty answer keeps the default contextual (melodic) treatment.
+func getMinorPolicyInput() music.MinorPolicy {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print("Enter minor treatment (melodic, natural, harmonic; empty for melodic): ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return music.MinorMelodic
+		}
+
+		policy, err := music.ParseMinorPolicy(input)
+		if err == nil {
+			return policy
+		}
+
+		fmt.Println("Invalid minor treatment. Please choose from the available options.")
+	}
+}
+
+// loadTransitions returns the transitions selected by the -markov flag: those of the cantus
+// firmi of Fux for "fux", or else those learned from the melodies of the directory.
+func loadTransitions(source string) *cantusgen.Transitions {
+	if source == "fux" {
+		t := cantusgen.DefaultTransitions()
+		return &t
+	}
+
+	melodies, err := corpus.LoadDir(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var sequences [][]int
+	for _, m := range melodies {
+		if len(m.Notes) < 2 {
+			continue
+		}
+		intervals := make([]int, len(m.Notes)-1)
+		for i := range intervals {
+			intervals[i] = m.Notes[i+1].DiatonicValue() - m.Notes[i].DiatonicValue()
+		}
+		sequences = append(sequences, intervals)
+	}
+	if len(sequences) == 0 {
+		log.Fatalf("no melody to learn from in %s", source)
+	}
+	t := cantusgen.LearnTransitions(sequences)
+	return &t
+}
+
+// generateResumable generates the melodies like cantusgen.Generate, resuming the search saved
+// in the checkpoint file if there is one. When interrupted, it saves the search to the file
+// and exits; the file is removed once the search is complete.
+func generateResumable(n int, opts cantusgen.Options, path string) [][]int {
+	cp := cantusgen.NewCheckpoint(n, opts)
+	if f, err := os.Open(path); err == nil {
+		cp, err = cantusgen.LoadCheckpoint(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+		fmt.Printf("Resuming the search saved in %s\n", path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		log.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	melodies, err := cp.Resume(opts, stop)
+	if errors.Is(err, cantusgen.ErrInterrupted) {
+		if err := saveCheckpoint(cp, path); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("\nInterrupted: the search was saved to %s. Run again with -checkpoint %s and the same answers to resume it.\n", path, path)
+		os.Exit(130)
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Print(err)
+	}
+	return melodies
+}
+
+// saveCheckpoint writes the checkpoint to the file, replacing it only once it is written
+// completely, so that an earlier checkpoint is never lost half overwritten.
+func saveCheckpoint(cp *cantusgen.Checkpoint, path string) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := cp.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// printProgress shows the progress of the search on a single line of standard error,
+// ending it once the search is complete.
+func printProgress(p cantusgen.Progress) {
+	fmt.Fprintf(os.Stderr, "\r%5.1f%% of the search explored, %d nodes, %d melodies found", 100*p.Fraction, p.Nodes, p.Found)
+	if p.Fraction == 1 {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// printStats prints the telemetry of the search and the rejection counts of the rules,
+// most rejections first.
+func printStats(stats *cantusgen.Stats) {
+	fmt.Println("\nSearch statistics:")
+	fmt.Printf("  %-34s %d\n", "Candidates tried", stats.Nodes)
+	fmt.Printf("  %-34s %d\n", "Melodies found", stats.Found)
+	fmt.Printf("  %-34s %s\n", "Search time", stats.Duration.Round(time.Millisecond))
+	fmt.Printf("  %-34s %.1f MiB\n", "Peak heap", float64(stats.PeakHeap)/(1<<20))
+	fmt.Println("\nRejected candidates by rule:")
+	for _, r := range stats.Ranking() {
+		fmt.Printf("  %-34s %d\n", r.Rule, r.Count)
+	}
+}
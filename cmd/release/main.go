@@ -0,0 +1,169 @@
+// Command release builds the cantus CLI for every supported platform
+// with the release version embedded, and packs each binary into an archive.
+//
+// Usage (from the repository root, after tagging the release):
+//
+//	go run ./cmd/release -version v1.0.0
+//
+// The archives are written to the dist directory, one per platform, e.g.
+// dist/cantus_v1.0.0_linux_amd64.tar.gz and dist/cantus_v1.0.0_windows_amd64.zip.
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// platforms lists the GOOS/GOARCH pairs a release is built for.
+var platforms = []struct{ goos, goarch string }{
+	{"linux", "amd64"},
+	{"linux", "arm64"},
+	{"darwin", "amd64"},
+	{"darwin", "arm64"},
+	{"windows", "amd64"},
+}
+
+func main() {
+	version := flag.String("version", "", "release version to embed, e.g. v1.0.0 (required)")
+	dist := flag.String("dist", "dist", "output directory")
+	flag.Parse()
+
+	if !strings.HasPrefix(*version, "v") {
+		log.Fatal("-version must be given as a semantic version starting with v, e.g. v1.0.0")
+	}
+	if err := os.MkdirAll(*dist, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, p := range platforms {
+		archive, err := build(*version, *dist, p.goos, p.goarch)
+		if err != nil {
+			log.Fatalf("%s/%s: %v", p.goos, p.goarch, err)
+		}
+		fmt.Println(archive)
+	}
+}
+
+// build compiles the CLI for one platform and returns the path of the archive containing it.
+func build(version, dist, goos, goarch string) (string, error) {
+	binary := "cantus"
+	if goos == "windows" {
+		binary += ".exe"
+	}
+
+	tmp, err := os.MkdirTemp("", "cantus-release")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	binPath := filepath.Join(tmp, binary)
+
+	cmd := exec.Command("go", "build", "-trimpath",
+		"-ldflags", "-s -w -X main.version="+version,
+		"-o", binPath, "./cmd")
+	cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH="+goarch, "CGO_ENABLED=0")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	base := filepath.Join(dist, fmt.Sprintf("cantus_%s_%s_%s", version, goos, goarch))
+	if goos == "windows" {
+		return base + ".zip", writeZip(base+".zip", binPath, binary)
+	}
+	return base + ".tar.gz", writeTarGz(base+".tar.gz", binPath, binary)
+}
+
+// writeTarGz packs the file at src into a gzipped tar archive under the given name.
+func writeTarGz(dst, src, name string) error {
+	in, info, err := open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, in); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// writeZip packs the file at src into a zip archive under the given name.
+func writeZip(dst, src, name string) error {
+	in, info, err := open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	hdr.Method = zip.Deflate
+	w, err := zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// open opens a file and returns it with its FileInfo.
+func open(path string) (*os.File, os.FileInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, info, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// version is the release version of the binary, set at build time with
+//
+//	go build -ldflags "-X main.version=v1.2.3"
+//
+// (see cmd/release). When it is empty, the module version recorded by
+// "go install module@version" is used, or "devel" for local builds.
+var version string
+
+// versionString returns the version of the binary.
+func versionString() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "devel"
+}
+
+// runVersion prints the version of the binary together with the Go version and platform.
+func runVersion() {
+	fmt.Printf("cantus %s (%s, %s/%s)\n", versionString(), runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
@@ -1,3 +1,3 @@
-module go-cantus-firmus
+module github.com/sergei-shchetnikov/go-cantus-firmus
 
 go 1.24.2
@@ -0,0 +1,166 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"strings"
+)
+
+// ChordSuggestion is the chord proposed under one note of the cantus firmus.
+//
+// Fields:
+//   - Note: the cantus note
+//   - Root: scale degree of the chord root: 1 (tonic), 4 (subdominant) or 5 (dominant)
+//   - Numeral: Roman numeral of the chord; uppercase for major, lowercase for minor,
+//     with "°" for diminished and "+" for augmented triads (e.g. "I", "iv", "V", "vii°")
+//   - Bass: the chord root for a second staff, in the octave below the final
+//     and moved down by octaves until it lies below the cantus note
+type ChordSuggestion struct {
+	Note    music.Note
+	Root    int
+	Numeral string
+	Bass    music.Note
+}
+
+// chordRoots lists, for every degree of the melody note (index 0 = 1st degree),
+// the roots of the primary triads containing it, the preferred one first.
+var chordRoots = [7][]int{
+	{1, 4}, // 1st degree: root of I, fifth of IV
+	{5},    // 2nd degree: fifth of V
+	{1},    // 3rd degree: third of I
+	{4},    // 4th degree: root of IV
+	{1, 5}, // 5th degree: fifth of I, root of V
+	{4},    // 6th degree: third of IV
+	{5},    // 7th degree: third of V
+}
+
+// romanNumerals holds the uppercase numerals of the degrees (index 0 = 1st degree).
+var romanNumerals = []string{"I", "II", "III", "IV", "V", "VI", "VII"}
+
+// Harmonize proposes a chordal skeleton for the cantus firmus using only the primary
+// triads (I, IV and V), oriented toward an I–V–I frame: the first and last notes are
+// harmonized with the tonic when possible, the penultimate note with the dominant, and
+// elsewhere the same chord is not repeated and V does not move to IV when there is a choice.
+//
+// The last note of the Realization is taken as the final of the given mode. Chord
+// qualities follow the mode; when the cantus note itself is a chord tone with another
+// alteration (e.g. the raised leading tone in minor), the chord takes that alteration.
+func Harmonize(r music.Realization, mode music.Mode) ([]ChordSuggestion, error) {
+	if len(r) == 0 {
+		return nil, errors.New("cannot harmonize an empty melody")
+	}
+
+	final := r[len(r)-1]
+	scale := music.Scale{Mode: mode, Tonic: final}
+
+	result := make([]ChordSuggestion, len(r))
+	previous := 0
+	for i, n := range r {
+		options := chordRoots[scale.Degree(n)-1]
+
+		var preferred int
+		switch i {
+		case 0, len(r) - 1:
+			preferred = 1
+		case len(r) - 2:
+			preferred = 5
+		}
+		root := chooseRoot(options, preferred, previous)
+
+		numeral, err := chordNumeral(scale, root, n)
+		if err != nil {
+			return nil, fmt.Errorf("note %d (%s): %w", i+1, n, err)
+		}
+
+		bass := scale.NoteForDegree(root, final.Octave-1)
+		for !bass.Less(n) {
+			bass.Octave--
+		}
+
+		result[i] = ChordSuggestion{Note: n, Root: root, Numeral: numeral, Bass: bass}
+		previous = root
+	}
+
+	return result, nil
+}
+
+// chooseRoot picks a chord root among the options: the preferred root if it is available,
+// otherwise the first option that neither repeats the previous chord nor moves from V to IV.
+func chooseRoot(options []int, preferred, previous int) int {
+	for _, root := range options {
+		if root == preferred {
+			return root
+		}
+	}
+	for _, root := range options {
+		if root != previous && !(previous == 5 && root == 4) {
+			return root
+		}
+	}
+	return options[0]
+}
+
+// chordNumeral returns the Roman numeral of the triad on the given root in the scale,
+// with the cantus note's alteration applied to the chord tone it doubles.
+func chordNumeral(scale music.Scale, root int, n music.Note) (string, error) {
+	tones := make([]music.Note, 3)
+	for i := range tones {
+		tones[i] = scale.NoteForDegree(root+2*i, scale.Tonic.Octave)
+		if tones[i].Step == n.Step {
+			tones[i].Alteration = n.Alteration
+		}
+	}
+
+	third := mod12(tones[1].Semitones() - tones[0].Semitones())
+	fifth := mod12(tones[2].Semitones() - tones[0].Semitones())
+
+	numeral := romanNumerals[root-1]
+	switch third {
+	case 4:
+	case 3:
+		numeral = strings.ToLower(numeral)
+	default:
+		return "", fmt.Errorf("no triad on degree %d: third of %d semitones", root, third)
+	}
+	switch fifth {
+	case 7:
+	case 6:
+		numeral += "°"
+	case 8:
+		numeral += "+"
+	default:
+		return "", fmt.Errorf("no triad on degree %d: fifth of %d semitones", root, fifth)
+	}
+	return numeral, nil
+}
+
+// mod12 returns the non-negative remainder of division of n by 12.
+func mod12(n int) int {
+	return ((n % 12) + 12) % 12
+}
+
+// HarmonizationText formats the skeleton as aligned columns: the cantus notes,
+// the Roman numerals and the bass notes, e.g.
+//
+//	Cantus: D4  F4  E4  D4
+//	Chords: i   i   V   i
+//	Bass:   D3  D3  A2  D3
+func HarmonizationText(h []ChordSuggestion) string {
+	width := 0
+	for _, c := range h {
+		width = max(width, len(c.Note.String()), len([]rune(c.Numeral)), len(c.Bass.String()))
+	}
+
+	var cantus, chords, bass strings.Builder
+	for _, c := range h {
+		fmt.Fprintf(&cantus, "%-*s", width+2, c.Note)
+		fmt.Fprintf(&chords, "%s%s", c.Numeral, strings.Repeat(" ", width+2-len([]rune(c.Numeral))))
+		fmt.Fprintf(&bass, "%-*s", width+2, c.Bass)
+	}
+
+	return fmt.Sprintf("Cantus: %s\nChords: %s\nBass:   %s",
+		strings.TrimRight(cantus.String(), " "),
+		strings.TrimRight(chords.String(), " "),
+		strings.TrimRight(bass.String(), " "))
+}
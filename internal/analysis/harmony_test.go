@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"strings"
+	"testing"
+)
+
+// realizationOf builds a realization from note names.
+func realizationOf(t *testing.T, names string) music.Realization {
+	t.Helper()
+	var r music.Realization
+	for _, name := range strings.Fields(names) {
+		n, err := music.ParseNote(name)
+		if err != nil {
+			t.Fatalf("ParseNote(%q): %v", name, err)
+		}
+		r = append(r, n)
+	}
+	return r
+}
+
+func TestHarmonize(t *testing.T) {
+	tests := []struct {
+		name         string
+		notes        string
+		mode         music.Mode
+		wantNumerals string
+		wantBass     string
+	}{
+		{
+			name:         "Dorian",
+			notes:        "D4 F4 E4 D4 G4 F4 A4 G4 F4 E4 D4",
+			mode:         music.Dorian,
+			wantNumerals: "i i v i IV i v IV i v i",
+			wantBass:     "D3 D3 A3 D3 G3 D3 A3 G3 D3 A3 D3",
+		},
+		{
+			name:         "major with tonic alternating with subdominant",
+			notes:        "C4 C4 D4 C4",
+			mode:         music.Major,
+			wantNumerals: "I IV V I",
+			wantBass:     "C3 F3 G3 C3",
+		},
+		{
+			name:         "raised leading tone in minor",
+			notes:        "A4 C5 B4 G#4 A4",
+			mode:         music.Minor,
+			wantNumerals: "i i v V i",
+			wantBass:     "A3 A3 E4 E4 A3",
+		},
+		{
+			name:         "bass stays below a low melody",
+			notes:        "G4 D4 E4 F#4 G4",
+			mode:         music.Major,
+			wantNumerals: "I V IV V I",
+			wantBass:     "G3 D3 C4 D4 G3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := Harmonize(realizationOf(t, tt.notes), tt.mode)
+			if err != nil {
+				t.Fatalf("Harmonize() unexpected error: %v", err)
+			}
+
+			var numerals, bass []string
+			for _, c := range h {
+				numerals = append(numerals, c.Numeral)
+				bass = append(bass, c.Bass.String())
+				if !c.Bass.Less(c.Note) {
+					t.Errorf("bass %s is not below %s", c.Bass, c.Note)
+				}
+			}
+			if got := strings.Join(numerals, " "); got != tt.wantNumerals {
+				t.Errorf("numerals = %s, want %s", got, tt.wantNumerals)
+			}
+			if got := strings.Join(bass, " "); got != tt.wantBass {
+				t.Errorf("bass = %s, want %s", got, tt.wantBass)
+			}
+		})
+	}
+}
+
+func TestHarmonize_Empty(t *testing.T) {
+	if _, err := Harmonize(nil, music.Major); err == nil {
+		t.Errorf("Harmonize(nil) expected error, got nil")
+	}
+}
+
+func TestHarmonizationText(t *testing.T) {
+	h, err := Harmonize(realizationOf(t, "B3 C4 D4 C4 B3"), music.Locrian)
+	if err != nil {
+		t.Fatalf("Harmonize() unexpected error: %v", err)
+	}
+
+	want := "Cantus: B3  C4  D4  C4  B3\n" +
+		"Chords: i°  V   i°  V   i°\n" +
+		"Bass:   B2  F3  B2  F3  B2"
+	if got := HarmonizationText(h); got != want {
+		t.Errorf("HarmonizationText() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package analysis
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+)
+
+// Report bundles all analyses of a melody, as printed by the analyze command.
+type Report struct {
+	Notes       []string   `json:"notes"`
+	Set         SetProfile `json:"set"`
+	MeanTension float64    `json:"mean_tension"`
+	Tension     []string   `json:"tension_intervals"`
+	Violations  []string   `json:"violations,omitempty"`
+}
+
+// Analyze runs every analysis of the package on the Realization, using its last note as the final,
+// and explains the registered rules it breaks (see rules.Explain).
+func Analyze(r music.Realization) (Report, error) {
+	set, err := SetAnalysis(r)
+	if err != nil {
+		return Report{}, err
+	}
+	profile, err := TensionProfile(r, r[len(r)-1])
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Set: set, MeanTension: MeanTension(profile)}
+	for _, n := range r {
+		report.Notes = append(report.Notes, n.String())
+	}
+	for _, p := range profile {
+		report.Tension = append(report.Tension, p.Interval)
+	}
+
+	intervals := make([]int, len(r)-1)
+	for i := range intervals {
+		intervals[i] = r[i+1].DiatonicValue() - r[i].DiatonicValue()
+	}
+	for _, v := range rules.Check(intervals) {
+		report.Violations = append(report.Violations, rules.Explain(v, intervals, r))
+	}
+	return report, nil
+}
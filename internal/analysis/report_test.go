@@ -0,0 +1,41 @@
+package analysis
+
+import (
+	"encoding/json"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	r := music.Realization{{Step: 1, Octave: 4}, {Step: 3, Octave: 4}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}}
+
+	report, err := Analyze(r)
+	if err != nil {
+		t.Fatalf("Analyze() unexpected error: %v", err)
+	}
+	if strings.Join(report.Notes, " ") != "D4 F4 E4 D4" {
+		t.Errorf("Notes = %v", report.Notes)
+	}
+	if strings.Join(report.Tension, " ") != "P1 m3 M2 P1" {
+		t.Errorf("Tension = %v", report.Tension)
+	}
+
+	if len(report.Violations) == 0 || !strings.Contains(strings.Join(report.Violations, " "), "change direction") {
+		t.Errorf("Violations = %v, want MinDirectionChanges explained", report.Violations)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	for _, key := range []string{`"interval_vector":[2,1,0]`, `"degree_usage":[2,1,1,0,0,0,0]`, `"mean_tension"`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("JSON %s does not contain %s", data, key)
+		}
+	}
+
+	if _, err := Analyze(nil); err == nil {
+		t.Errorf("Analyze(nil) expected error, got nil")
+	}
+}
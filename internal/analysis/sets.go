@@ -0,0 +1,65 @@
+package analysis
+
+import (
+	"errors"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+// SetProfile is the diatonic set fingerprint of a melody.
+//
+// Fields:
+//   - PitchClasses: letter names of the distinct diatonic pitch classes used, in scale order from the final
+//   - IntervalVector: number of pairs of those pitch classes forming each diatonic interval class:
+//     seconds/sevenths, thirds/sixths and fourths/fifths
+//   - DegreeUsage: number of notes on each degree (index 0 = 1st degree, the final)
+//   - DegreeFrequency: DegreeUsage divided by the number of notes
+//   - MelodicIntervals: number of melodic intervals of each size and quality (e.g. "M2": 5), regardless of direction
+type SetProfile struct {
+	PitchClasses     []string       `json:"pitch_classes"`
+	IntervalVector   [3]int         `json:"interval_vector"`
+	DegreeUsage      [7]int         `json:"degree_usage"`
+	DegreeFrequency  [7]float64     `json:"degree_frequency"`
+	MelodicIntervals map[string]int `json:"melodic_intervals"`
+}
+
+// SetAnalysis computes the set profile of the Realization. Degrees are counted
+// relative to its last note, the final of the mode; alterations are ignored.
+func SetAnalysis(r music.Realization) (SetProfile, error) {
+	if len(r) == 0 {
+		return SetProfile{}, errors.New("cannot analyze an empty melody")
+	}
+
+	final := r[len(r)-1]
+	profile := SetProfile{MelodicIntervals: map[string]int{}}
+
+	for _, n := range r {
+		profile.DegreeUsage[music.Mod7(n.Step-final.Step)]++
+	}
+
+	var used []int // degrees (0-based) that occur in the melody
+	for degree, count := range profile.DegreeUsage {
+		profile.DegreeFrequency[degree] = float64(count) / float64(len(r))
+		if count > 0 {
+			used = append(used, degree)
+			pc := music.PitchClass{Step: music.Mod7(final.Step + degree)}
+			profile.PitchClasses = append(profile.PitchClasses, pc.String())
+		}
+	}
+
+	for i, a := range used {
+		for _, b := range used[i+1:] {
+			d := b - a
+			profile.IntervalVector[min(d, 7-d)-1]++
+		}
+	}
+
+	for i := 1; i < len(r); i++ {
+		iq, err := music.NewIntervalWithQuality(r[i-1], r[i])
+		if err != nil {
+			return SetProfile{}, err
+		}
+		profile.MelodicIntervals[iq.ShortName()]++
+	}
+
+	return profile, nil
+}
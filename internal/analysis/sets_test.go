@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"slices"
+	"testing"
+)
+
+func TestSetAnalysis(t *testing.T) {
+	// D4 F4 E4 D4 G4 F4 A4 G4 F4 E4 D4 (Dorian)
+	steps := []int{1, 3, 2, 1, 4, 3, 5, 4, 3, 2, 1}
+	r := make(music.Realization, len(steps))
+	for i, s := range steps {
+		r[i] = music.Note{Step: s, Octave: 4}
+	}
+
+	got, err := SetAnalysis(r)
+	if err != nil {
+		t.Fatalf("SetAnalysis() unexpected error: %v", err)
+	}
+
+	if want := []string{"D", "E", "F", "G", "A"}; !slices.Equal(got.PitchClasses, want) {
+		t.Errorf("PitchClasses = %v, want %v", got.PitchClasses, want)
+	}
+	// Pentachord D-A: 4 seconds, 3 thirds, 3 fourths/fifths
+	if want := [3]int{4, 3, 3}; got.IntervalVector != want {
+		t.Errorf("IntervalVector = %v, want %v", got.IntervalVector, want)
+	}
+	if want := [7]int{3, 2, 3, 2, 1, 0, 0}; got.DegreeUsage != want {
+		t.Errorf("DegreeUsage = %v, want %v", got.DegreeUsage, want)
+	}
+	if got.DegreeFrequency[0] != 3.0/11 || got.DegreeFrequency[6] != 0 {
+		t.Errorf("DegreeFrequency = %v", got.DegreeFrequency)
+	}
+	wantIntervals := map[string]int{"m3": 1, "M2": 5, "m2": 2, "P4": 1, "M3": 1}
+	for name, count := range wantIntervals {
+		if got.MelodicIntervals[name] != count {
+			t.Errorf("MelodicIntervals[%s] = %d, want %d (all: %v)", name, got.MelodicIntervals[name], count, got.MelodicIntervals)
+		}
+	}
+}
+
+func TestSetAnalysis_FullScale(t *testing.T) {
+	r := music.Realization{}
+	for step := 0; step < 7; step++ {
+		r = append(r, music.Note{Step: step, Octave: 4})
+	}
+	r = append(r, music.Note{Step: 0, Octave: 5})
+
+	got, err := SetAnalysis(r)
+	if err != nil {
+		t.Fatalf("SetAnalysis() unexpected error: %v", err)
+	}
+	// Every interval class occurs 7 times in the complete diatonic set
+	if want := [3]int{7, 7, 7}; got.IntervalVector != want {
+		t.Errorf("IntervalVector = %v, want %v", got.IntervalVector, want)
+	}
+}
+
+func TestSetAnalysis_Empty(t *testing.T) {
+	if _, err := SetAnalysis(nil); err == nil {
+		t.Errorf("SetAnalysis(nil) expected error, got nil")
+	}
+}
@@ -0,0 +1,171 @@
+// Package analysis provides descriptive analyses of realized cantus firmi,
+// such as the tension profile of a melody against its modal final.
+// The results are meant for users studying melodies and for ranking
+// generated melodies, not for accepting or rejecting them (see package rules).
+package analysis
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TensionPoint describes one note of a melody measured against the modal final
+// sounding as a drone below it.
+//
+// Fields:
+//   - Index: position of the note in the melody (0-based)
+//   - Note: the note itself
+//   - Interval: simple interval above the final with its quality (e.g. "P5", "m3", "A4")
+//   - Consonant: whether the interval is a consonance against the drone
+//   - Tension: dissonance degree from 0 (unison/octave) to 1 (tritone)
+type TensionPoint struct {
+	Index     int
+	Note      music.Note
+	Interval  string
+	Consonant bool
+	Tension   float64
+}
+
+// intervalTension maps simple intervals to their tension against the drone.
+// Intervals that are not listed (other augmented and diminished intervals) have tension 1.
+var intervalTension = map[string]float64{
+	"P1": 0,
+	"P5": 0.1,
+	"M3": 0.25,
+	"m3": 0.25,
+	"M6": 0.3,
+	"m6": 0.3,
+	"P4": 0.5,
+	"M2": 0.7,
+	"m7": 0.7,
+	"m2": 0.9,
+	"M7": 0.9,
+	"A4": 1,
+	"d5": 1,
+}
+
+// consonances lists the intervals treated as consonant against the drone in strict style.
+var consonances = map[string]bool{"P1": true, "P5": true, "M3": true, "m3": true, "M6": true, "m6": true}
+
+// TensionProfile computes the interval between every note of the Realization and the final,
+// reduced to a simple interval above the final, and derives a consonance/dissonance curve from it.
+// Only the final's step and alteration are used; its octave is ignored.
+func TensionProfile(r music.Realization, final music.Note) ([]TensionPoint, error) {
+	profile := make([]TensionPoint, len(r))
+	for i, n := range r {
+		// Place the drone at most a seventh below the note
+		noteValue := n.DiatonicValue()
+		droneOctave := (noteValue - final.Step) / 7
+		if noteValue-final.Step < 0 && (noteValue-final.Step)%7 != 0 {
+			droneOctave--
+		}
+		drone := music.Note{Step: final.Step, Octave: droneOctave, Alteration: final.Alteration}
+
+		iq, err := music.NewIntervalWithQuality(drone, n)
+		if err != nil {
+			return nil, fmt.Errorf("note %d (%s): %w", i+1, n, err)
+		}
+		interval := iq.ShortName()
+
+		tension, ok := intervalTension[interval]
+		if !ok {
+			tension = 1
+		}
+
+		profile[i] = TensionPoint{
+			Index:     i,
+			Note:      n,
+			Interval:  interval,
+			Consonant: consonances[interval],
+			Tension:   tension,
+		}
+	}
+	return profile, nil
+}
+
+// MeanTension returns the average tension of the profile, or 0 for an empty profile.
+func MeanTension(profile []TensionPoint) float64 {
+	if len(profile) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, p := range profile {
+		sum += p.Tension
+	}
+	return sum / float64(len(profile))
+}
+
+// WriteTensionCSV writes the profile as CSV with a header row:
+// index, note, interval, consonant, tension.
+func WriteTensionCSV(w io.Writer, profile []TensionPoint) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"index", "note", "interval", "consonant", "tension"}); err != nil {
+		return err
+	}
+	for _, p := range profile {
+		record := []string{
+			strconv.Itoa(p.Index),
+			p.Note.String(),
+			p.Interval,
+			strconv.FormatBool(p.Consonant),
+			strconv.FormatFloat(p.Tension, 'f', 2, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// SVG chart geometry
+const (
+	svgStepWidth = 40
+	svgHeight    = 200
+	svgMargin    = 30
+)
+
+// WriteTensionSVG writes the profile as an SVG line chart: time runs from left to right,
+// tension from bottom (0) to top (1). Dissonant notes are marked in red and every point
+// is labeled with its interval.
+func WriteTensionSVG(w io.Writer, profile []TensionPoint) error {
+	width := 2*svgMargin + svgStepWidth*max(len(profile)-1, 0)
+	plotHeight := float64(svgHeight - 2*svgMargin)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, svgHeight, width, svgHeight)
+	fmt.Fprintf(&b, `  <line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#999"/>`+"\n",
+		svgMargin, svgHeight-svgMargin, width-svgMargin, svgHeight-svgMargin)
+
+	points := make([]string, len(profile))
+	for i, p := range profile {
+		x := svgMargin + i*svgStepWidth
+		y := float64(svgHeight-svgMargin) - p.Tension*plotHeight
+		points[i] = fmt.Sprintf("%d,%.1f", x, y)
+	}
+	if len(points) > 0 {
+		fmt.Fprintf(&b, `  <polyline points="%s" fill="none" stroke="#333" stroke-width="2"/>`+"\n",
+			strings.Join(points, " "))
+	}
+
+	for i, p := range profile {
+		x := svgMargin + i*svgStepWidth
+		y := float64(svgHeight-svgMargin) - p.Tension*plotHeight
+		color := "#2a7"
+		if !p.Consonant {
+			color = "#c33"
+		}
+		fmt.Fprintf(&b, `  <circle cx="%d" cy="%.1f" r="4" fill="%s"/>`+"\n", x, y, color)
+		fmt.Fprintf(&b, `  <text x="%d" y="%.1f" font-size="10" text-anchor="middle">%s</text>`+"\n",
+			x, y-8, p.Interval)
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"bytes"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"strings"
+	"testing"
+)
+
+func TestTensionProfile(t *testing.T) {
+	// D Dorian: D4 F4 E4 A4 G#4 C5 B3 D4
+	r := music.Realization{
+		{Step: 1, Octave: 4},
+		{Step: 3, Octave: 4},
+		{Step: 2, Octave: 4},
+		{Step: 5, Octave: 4},
+		{Step: 4, Octave: 4, Alteration: 1},
+		{Step: 0, Octave: 5},
+		{Step: 6, Octave: 3},
+		{Step: 1, Octave: 4},
+	}
+	final := music.Note{Step: 1, Octave: 4}
+
+	profile, err := TensionProfile(r, final)
+	if err != nil {
+		t.Fatalf("TensionProfile() unexpected error: %v", err)
+	}
+
+	want := []struct {
+		interval  string
+		consonant bool
+		tension   float64
+	}{
+		{"P1", true, 0},
+		{"m3", true, 0.25},
+		{"M2", false, 0.7},
+		{"P5", true, 0.1},
+		{"A4", false, 1},
+		{"m7", false, 0.7},
+		{"M6", true, 0.3},
+		{"P1", true, 0},
+	}
+
+	if len(profile) != len(want) {
+		t.Fatalf("TensionProfile() returned %d points, want %d", len(profile), len(want))
+	}
+	for i, w := range want {
+		p := profile[i]
+		if p.Index != i || p.Interval != w.interval || p.Consonant != w.consonant || p.Tension != w.tension {
+			t.Errorf("point %d = %+v, want interval %s consonant %v tension %v",
+				i, p, w.interval, w.consonant, w.tension)
+		}
+	}
+
+	if got := MeanTension(profile); got < 0.38 || got > 0.39 {
+		t.Errorf("MeanTension() = %v, want about 0.381", got)
+	}
+}
+
+func TestMeanTension_Empty(t *testing.T) {
+	if got := MeanTension(nil); got != 0 {
+		t.Errorf("MeanTension(nil) = %v, want 0", got)
+	}
+}
+
+func TestWriteTensionCSV(t *testing.T) {
+	profile := []TensionPoint{
+		{Index: 0, Note: music.Note{Step: 0, Octave: 4}, Interval: "P1", Consonant: true, Tension: 0},
+		{Index: 1, Note: music.Note{Step: 1, Octave: 4}, Interval: "M2", Consonant: false, Tension: 0.7},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTensionCSV(&buf, profile); err != nil {
+		t.Fatalf("WriteTensionCSV() unexpected error: %v", err)
+	}
+
+	want := "index,note,interval,consonant,tension\n0,C4,P1,true,0.00\n1,D4,M2,false,0.70\n"
+	if buf.String() != want {
+		t.Errorf("WriteTensionCSV() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteTensionSVG(t *testing.T) {
+	profile := []TensionPoint{
+		{Index: 0, Note: music.Note{Step: 0, Octave: 4}, Interval: "P1", Consonant: true, Tension: 0},
+		{Index: 1, Note: music.Note{Step: 3, Octave: 4, Alteration: 1}, Interval: "A4", Consonant: false, Tension: 1},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTensionSVG(&buf, profile); err != nil {
+		t.Fatalf("WriteTensionSVG() unexpected error: %v", err)
+	}
+
+	svg := buf.String()
+	for _, want := range []string{"<svg", "<polyline", `fill="#c33"`, ">A4</text>", "</svg>"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("WriteTensionSVG() output does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package codec implements a compact binary encoding for interval sequences,
+// intended for storing generated cantus firmi (caches, databases, checkpoints).
+//
+// An encoded value consists of:
+//   - the 4-byte magic header "CFIS"
+//   - a version byte (currently 1)
+//   - the number of sequences as an unsigned varint
+//   - for each sequence, its length as an unsigned varint followed by
+//     its intervals as signed (zigzag) varints
+//
+// Taneyev intervals are small, so every interval normally takes a single byte.
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Magic is the header every encoded value starts with.
+const Magic = "CFIS"
+
+// Version is the format version written by the encoder.
+const Version = 1
+
+var (
+	// ErrBadMagic is returned when the data does not start with Magic.
+	ErrBadMagic = errors.New("codec: not an interval sequence encoding")
+	// ErrTruncated is returned when the data ends in the middle of a value.
+	ErrTruncated = errors.New("codec: truncated data")
+)
+
+// Encode encodes a single interval sequence.
+func Encode(intervals []int) []byte {
+	return EncodeAll([][]int{intervals})
+}
+
+// EncodeAll encodes a list of interval sequences.
+func EncodeAll(sequences [][]int) []byte {
+	buf := make([]byte, 0, len(Magic)+1+binary.MaxVarintLen64)
+	buf = append(buf, Magic...)
+	buf = append(buf, Version)
+	buf = binary.AppendUvarint(buf, uint64(len(sequences)))
+	for _, seq := range sequences {
+		buf = binary.AppendUvarint(buf, uint64(len(seq)))
+		for _, val := range seq {
+			buf = binary.AppendVarint(buf, int64(val))
+		}
+	}
+	return buf
+}
+
+// Decode decodes data produced by Encode.
+// It returns an error if the data holds anything but exactly one sequence.
+func Decode(data []byte) ([]int, error) {
+	sequences, err := DecodeAll(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(sequences) != 1 {
+		return nil, fmt.Errorf("codec: expected 1 sequence, got %d", len(sequences))
+	}
+	return sequences[0], nil
+}
+
+// DecodeAll decodes data produced by EncodeAll or Encode.
+func DecodeAll(data []byte) ([][]int, error) {
+	if !bytes.HasPrefix(data, []byte(Magic)) {
+		return nil, ErrBadMagic
+	}
+	data = data[len(Magic):]
+	if len(data) == 0 {
+		return nil, ErrTruncated
+	}
+	if data[0] != Version {
+		return nil, fmt.Errorf("codec: unsupported version %d", data[0])
+	}
+
+	d := decoder{data: data[1:]}
+	count, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	// Every sequence takes at least one byte, which bounds the allocation for corrupt counts
+	if count > uint64(len(d.data)) {
+		return nil, ErrTruncated
+	}
+
+	sequences := make([][]int, 0, count)
+	for i := uint64(0); i < count; i++ {
+		length, err := d.uvarint()
+		if err != nil {
+			return nil, fmt.Errorf("sequence %d: %w", i, err)
+		}
+		if length > uint64(len(d.data)) {
+			return nil, fmt.Errorf("sequence %d: %w", i, ErrTruncated)
+		}
+
+		seq := make([]int, length)
+		for j := range seq {
+			val, err := d.varint()
+			if err != nil {
+				return nil, fmt.Errorf("sequence %d: interval %d: %w", i, j, err)
+			}
+			seq[j] = val
+		}
+		sequences = append(sequences, seq)
+	}
+
+	if len(d.data) != 0 {
+		return nil, fmt.Errorf("codec: %d trailing bytes", len(d.data))
+	}
+	return sequences, nil
+}
+
+// decoder reads varints from the front of data.
+type decoder struct {
+	data []byte
+}
+
+func (d *decoder) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data)
+	if err := d.check(n); err != nil {
+		return 0, err
+	}
+	d.data = d.data[n:]
+	return v, nil
+}
+
+func (d *decoder) varint() (int, error) {
+	v, n := binary.Varint(d.data)
+	if err := d.check(n); err != nil {
+		return 0, err
+	}
+	if int64(int(v)) != v {
+		return 0, errors.New("codec: interval out of range")
+	}
+	d.data = d.data[n:]
+	return int(v), nil
+}
+
+// check validates the size n returned by binary.Uvarint/Varint for the varint at the front of data.
+// Overlong varints (ending in a zero continuation byte) are rejected so that every
+// sequence has exactly one encoding.
+func (d *decoder) check(n int) error {
+	switch {
+	case n == 0:
+		return ErrTruncated
+	case n < 0:
+		return errors.New("codec: varint overflow")
+	case n > 1 && d.data[n-1] == 0:
+		return errors.New("codec: non-minimal varint")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+	}{
+		{"empty", []int{}},
+		{"cantus", []int{1, 1, -2, 4, -1, -1, -1, 1, -1}},
+		{"large intervals", []int{64, -65, 1000, -1000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := Encode(tt.intervals)
+			got, err := Decode(data)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.intervals) {
+				t.Errorf("Decode(Encode(%v)) = %v", tt.intervals, got)
+			}
+		})
+	}
+}
+
+func TestEncode_Layout(t *testing.T) {
+	got := Encode([]int{1, -1, 5})
+	want := []byte{'C', 'F', 'I', 'S', Version, 1, 3, 2, 1, 10}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Encode() = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeDecodeAll(t *testing.T) {
+	sequences := [][]int{
+		{1, 1, -2, -1, 1, -1},
+		{},
+		{-4, 2, 1, 1, -1, 1},
+	}
+	got, err := DecodeAll(EncodeAll(sequences))
+	if err != nil {
+		t.Fatalf("DecodeAll() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, sequences) {
+		t.Errorf("DecodeAll(EncodeAll(%v)) = %v", sequences, got)
+	}
+
+	if got, err := DecodeAll(EncodeAll(nil)); err != nil || len(got) != 0 {
+		t.Errorf("DecodeAll(EncodeAll(nil)) = %v, %v; want empty", got, err)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	valid := Encode([]int{1, -1, 2})
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"empty", nil, ErrBadMagic},
+		{"wrong magic", []byte("XXXX\x01\x00"), ErrBadMagic},
+		{"missing version", []byte(Magic), ErrTruncated},
+		{"unsupported version", []byte(Magic + "\x02\x00"), nil},
+		{"missing count", []byte(Magic + "\x01"), ErrTruncated},
+		{"truncated intervals", valid[:len(valid)-1], ErrTruncated},
+		{"huge count", []byte(Magic + "\x01\xff\xff\xff\xff\x0f"), ErrTruncated},
+		{"overlong varint", []byte(Magic + "\x01\x01\x01\x81\x00"), nil},
+		{"trailing bytes", append(bytes.Clone(valid), 0), nil},
+		{"two sequences", EncodeAll([][]int{{1}, {2}}), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.data)
+			if err == nil {
+				t.Fatal("Decode() error = nil, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func FuzzDecodeAll(f *testing.F) {
+	f.Add(Encode([]int{1, 1, -2, 4, -1, -1, -1, 1, -1}))
+	f.Add(EncodeAll([][]int{{1, -1}, {}, {5, -4}}))
+	f.Add([]byte(Magic + "\x01"))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sequences, err := DecodeAll(data)
+		if err != nil {
+			return
+		}
+		// Whatever decodes must encode back to the same bytes
+		if re := EncodeAll(sequences); !bytes.Equal(re, data) {
+			t.Errorf("EncodeAll(DecodeAll(%v)) = %v", data, re)
+		}
+	})
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	f.Add([]byte{1, 1, 0xfe, 4, 0xff})
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		intervals := make([]int, len(raw))
+		for i, b := range raw {
+			intervals[i] = int(int8(b))
+		}
+		got, err := Decode(Encode(intervals))
+		if err != nil {
+			t.Fatalf("Decode(Encode(%v)) error = %v", intervals, err)
+		}
+		if !reflect.DeepEqual(got, intervals) {
+			t.Errorf("Decode(Encode(%v)) = %v", intervals, got)
+		}
+	})
+}
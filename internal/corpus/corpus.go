@@ -0,0 +1,115 @@
+// Package corpus loads collections of melodies from a folder and aggregates
+// rule violations across them, so instructors can see which rules students
+// break most often and where in the melody.
+package corpus
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/musicxml"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Melody is a melody read from a corpus.
+//
+// Fields:
+//   - Source: file the melody was read from, with the sequence number within the file (e.g. "a.txt:2")
+//   - Notes: the melody
+type Melody struct {
+	Source string
+	Notes  music.Realization
+}
+
+// LoadDir reads all melodies from the files of a directory (not recursively), in file name order:
+//   - .musicxml and .xml files: every cantus of the first part (see musicxml.FromMusicXML)
+//   - .txt files: one melody per line as note names separated by spaces (e.g. "D4 F4 E4 D4");
+//     empty lines and lines starting with "#" are skipped
+//
+// Files with other extensions are ignored.
+func LoadDir(dir string) ([]Melody, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading corpus directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var result []Melody
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+
+		var melodies []music.Realization
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".musicxml", ".xml":
+			melodies, err = loadMusicXML(path)
+		case ".txt":
+			melodies, err = loadText(path)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		for i, m := range melodies {
+			result = append(result, Melody{Source: fmt.Sprintf("%s:%d", name, i+1), Notes: m})
+		}
+	}
+
+	return result, nil
+}
+
+// loadMusicXML reads the cantus firmi of a MusicXML file.
+func loadMusicXML(path string) ([]music.Realization, error) {
+	sequences, err := musicxml.LoadMusicXML(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]music.Realization, len(sequences))
+	for i, seq := range sequences {
+		for _, n := range seq {
+			result[i] = append(result[i], music.Note{Step: n.Step, Octave: n.Octave, Alteration: n.Alteration})
+		}
+	}
+	return result, nil
+}
+
+// loadText reads melodies written as lines of note names.
+func loadText(path string) ([]music.Realization, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var result []music.Realization
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var melody music.Realization
+		for _, field := range strings.Fields(line) {
+			n, err := music.ParseNote(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			melody = append(melody, n)
+		}
+		result = append(result, melody)
+	}
+	return result, scanner.Err()
+}
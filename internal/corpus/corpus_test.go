@@ -0,0 +1,66 @@
+package corpus
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/musicxml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile creates a file with the given content in dir.
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "b.txt", "# homework 1\nD4 F4 E4 D4\n\nC4 D4 C4\n")
+	writeFile(t, dir, "notes.md", "not a melody")
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	sequences := [][]musicxml.Note{{{Step: 5, Octave: 4}, {Step: 6, Octave: 4}, {Step: 5, Octave: 4}}}
+	if err := musicxml.GenerateAndSaveMusicXML(sequences, filepath.Join(dir, "a.musicxml")); err != nil {
+		t.Fatal(err)
+	}
+
+	melodies, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() unexpected error: %v", err)
+	}
+
+	want := []struct {
+		source string
+		notes  string
+	}{
+		{"a.musicxml:1", "A4 B4 A4"},
+		{"b.txt:1", "D4 F4 E4 D4"},
+		{"b.txt:2", "C4 D4 C4"},
+	}
+	if len(melodies) != len(want) {
+		t.Fatalf("LoadDir() returned %d melodies, want %d", len(melodies), len(want))
+	}
+	for i, w := range want {
+		if melodies[i].Source != w.source || melodies[i].Notes.String() != w.notes {
+			t.Errorf("melody %d = %s %s, want %s %s", i, melodies[i].Source, melodies[i].Notes, w.source, w.notes)
+		}
+	}
+}
+
+func TestLoadDir_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "bad.txt", "D4 F4\nD4 H4\n")
+
+	_, err := LoadDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "bad.txt: line 2") {
+		t.Errorf("LoadDir() error = %v, want containing %q", err, "bad.txt: line 2")
+	}
+
+	if _, err := LoadDir(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("LoadDir() of a missing directory expected error, got nil")
+	}
+}
@@ -0,0 +1,161 @@
+package corpus
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/cantusgen"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Section is the part of a melody in which a rule is broken.
+type Section int
+
+const (
+	// Start is the first third of the melody's intervals.
+	Start Section = iota
+	// Middle is the part between Start and Cadence.
+	Middle
+	// Cadence is the last third of the melody's intervals, always including the last interval.
+	Cadence
+	// Whole is used for rules that apply to the melody as a whole (see rules.Violation.Located).
+	Whole
+)
+
+// sectionNames holds the names of the sections in the order of their constants.
+var sectionNames = []string{"start", "middle", "cadence", "whole"}
+
+// String returns the name of the section.
+func (s Section) String() string {
+	return sectionNames[s]
+}
+
+// sectionOf returns the section of the interval at the given position in a melody of n intervals.
+func sectionOf(position, n int) Section {
+	switch {
+	case position < 0:
+		return Whole
+	case position*3 >= 2*n || position == n-1:
+		return Cadence
+	case position*3 < n:
+		return Start
+	default:
+		return Middle
+	}
+}
+
+// RuleStats counts the violations of one rule across a corpus.
+//
+// Fields:
+//   - Rule: rule name (see cantusgen.RuleNames)
+//   - BySection: number of melodies breaking the rule in each section, indexed by Section
+//   - Melodies: number of melodies breaking the rule
+type RuleStats struct {
+	Rule      string
+	BySection [4]int
+	Melodies  int
+}
+
+// Heatmap aggregates rule violations across a corpus.
+//
+// Fields:
+//   - Melodies: number of melodies analyzed
+//   - Valid: number of melodies breaking no rule
+//   - Rules: statistics of every broken rule, most often broken first
+type Heatmap struct {
+	Melodies int
+	Valid    int
+	Rules    []RuleStats
+}
+
+// BuildHeatmap checks every melody with cantusgen.Check and counts the violations
+// per rule and section. Melodies with fewer than two notes are skipped.
+func BuildHeatmap(melodies []Melody, opts cantusgen.Options) (Heatmap, error) {
+	var h Heatmap
+	stats := make(map[string]*RuleStats)
+
+	for _, m := range melodies {
+		if len(m.Notes) < 2 {
+			continue
+		}
+		cf, _, err := m.Notes.Intervals()
+		if err != nil {
+			return Heatmap{}, fmt.Errorf("%s: %w", m.Source, err)
+		}
+		intervals := make([]int, len(cf))
+		for i, interval := range cf {
+			intervals[i] = int(interval)
+		}
+
+		h.Melodies++
+		violations := cantusgen.Check(intervals, opts)
+		if len(violations) == 0 {
+			h.Valid++
+		}
+		for _, v := range violations {
+			s, ok := stats[v.Rule]
+			if !ok {
+				s = &RuleStats{Rule: v.Rule}
+				stats[v.Rule] = s
+			}
+			position := v.End
+			if !v.Located {
+				position = -1
+			}
+			s.BySection[sectionOf(position, len(intervals))]++
+			s.Melodies++
+		}
+	}
+
+	for _, s := range stats {
+		h.Rules = append(h.Rules, *s)
+	}
+	sort.Slice(h.Rules, func(i, j int) bool {
+		if h.Rules[i].Melodies != h.Rules[j].Melodies {
+			return h.Rules[i].Melodies > h.Rules[j].Melodies
+		}
+		return h.Rules[i].Rule < h.Rules[j].Rule
+	})
+
+	return h, nil
+}
+
+// WriteText writes the heatmap as an aligned table with one row per rule,
+// the number of violations per section and the share of melodies breaking the rule.
+func (h Heatmap) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Rule\tStart\tMiddle\tCadence\tWhole\tMelodies\n")
+	for _, s := range h.Rules {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%.0f%%\n", s.Rule,
+			s.BySection[Start], s.BySection[Middle], s.BySection[Cadence], s.BySection[Whole],
+			100*float64(s.Melodies)/float64(h.Melodies))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d melodies, %d valid\n", h.Melodies, h.Valid)
+	return err
+}
+
+// WriteCSV writes the heatmap as CSV with a header row:
+// rule, start, middle, cadence, whole, melodies.
+func (h Heatmap) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"rule", "start", "middle", "cadence", "whole", "melodies"}); err != nil {
+		return err
+	}
+	for _, s := range h.Rules {
+		record := []string{s.Rule}
+		for _, count := range s.BySection {
+			record = append(record, strconv.Itoa(count))
+		}
+		record = append(record, strconv.Itoa(s.Melodies))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,112 @@
+package corpus
+
+import (
+	"bytes"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/cantusgen"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"strings"
+	"testing"
+)
+
+// melodyOf builds a corpus melody from note names.
+func melodyOf(t *testing.T, names string) Melody {
+	t.Helper()
+	var r music.Realization
+	for _, name := range strings.Fields(names) {
+		n, err := music.ParseNote(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = append(r, n)
+	}
+	return Melody{Source: names, Notes: r}
+}
+
+func TestSectionOf(t *testing.T) {
+	tests := []struct {
+		position, n int
+		want        Section
+	}{
+		{-1, 9, Whole},
+		{0, 9, Start},
+		{2, 9, Start},
+		{3, 9, Middle},
+		{5, 9, Middle},
+		{6, 9, Cadence},
+		{8, 9, Cadence},
+		{0, 2, Start},
+		{1, 2, Cadence},
+	}
+
+	for _, tt := range tests {
+		if got := sectionOf(tt.position, tt.n); got != tt.want {
+			t.Errorf("sectionOf(%d, %d) = %v, want %v", tt.position, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestBuildHeatmap(t *testing.T) {
+	melodies := []Melody{
+		melodyOf(t, "D4 F4 E4 D4 G4 F4 A4 G4 F4 E4 D4"), // valid
+		melodyOf(t, "D4 B4 A4 G4 F4 E4 D4"),             // starts with a sixth
+		melodyOf(t, "D4 B4 A4 G4 F4 E4 F4"),             // starts with a sixth, does not return
+		melodyOf(t, "D4"),                               // skipped
+	}
+
+	h, err := BuildHeatmap(melodies, cantusgen.Options{})
+	if err != nil {
+		t.Fatalf("BuildHeatmap() unexpected error: %v", err)
+	}
+	if h.Melodies != 3 || h.Valid != 1 {
+		t.Errorf("Melodies = %d, Valid = %d, want 3 and 1", h.Melodies, h.Valid)
+	}
+	if len(h.Rules) == 0 || h.Rules[0].Rule != "NoBeginWithFive" {
+		t.Fatalf("Rules = %+v, want NoBeginWithFive first", h.Rules)
+	}
+	if first := h.Rules[0]; first.Melodies != 2 || first.BySection[Start] != 2 {
+		t.Errorf("NoBeginWithFive stats = %+v, want 2 melodies at the start", first)
+	}
+
+	found := false
+	for _, s := range h.Rules {
+		if s.Rule == cantusgen.RuleReturnToFinal {
+			found = true
+			if s.Melodies != 1 || s.BySection[Cadence] != 1 {
+				t.Errorf("ReturnToFinal stats = %+v, want 1 melody at the cadence", s)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Rules = %+v, want ReturnToFinal", h.Rules)
+	}
+}
+
+func TestHeatmap_Write(t *testing.T) {
+	h := Heatmap{
+		Melodies: 4,
+		Valid:    1,
+		Rules: []RuleStats{
+			{Rule: "NoBeginWithFive", BySection: [4]int{2, 0, 0, 0}, Melodies: 2},
+			{Rule: "ValidateClimax", BySection: [4]int{0, 0, 0, 1}, Melodies: 1},
+		},
+	}
+
+	var text bytes.Buffer
+	if err := h.WriteText(&text); err != nil {
+		t.Fatalf("WriteText() unexpected error: %v", err)
+	}
+	for _, want := range []string{"Cadence", "NoBeginWithFive", "50%", "25%", "4 melodies, 1 valid"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("WriteText() output does not contain %q:\n%s", want, text.String())
+		}
+	}
+
+	var csvData bytes.Buffer
+	if err := h.WriteCSV(&csvData); err != nil {
+		t.Fatalf("WriteCSV() unexpected error: %v", err)
+	}
+	want := "rule,start,middle,cadence,whole,melodies\nNoBeginWithFive,2,0,0,0,2\nValidateClimax,0,0,0,1,1\n"
+	if csvData.String() != want {
+		t.Errorf("WriteCSV() = %q, want %q", csvData.String(), want)
+	}
+}
@@ -0,0 +1,141 @@
+// Package grading scores a student's cantus firmus against an instructor's rubric.
+// A rubric maps rule names (see cantusgen.RuleNames) to point deductions and sets
+// the maximum score, so the grade report can reproduce a published rubric exactly.
+package grading
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/cantusgen"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Rubric describes how a cantus firmus is graded.
+//
+// Fields:
+//   - MaxScore: score of a melody without violations
+//   - DefaultDeduction: points deducted for a violated rule not listed in Deductions
+//   - Deductions: points deducted per violated rule, keyed by rule name; 0 makes a rule ungraded
+//
+// In a rubric file the fields are named "max_score", "default_deduction" and "deductions", e.g.
+//
+//	{"max_score": 20, "default_deduction": 2, "deductions": {"ReturnToFinal": 10, "NoSequences": 1}}
+type Rubric struct {
+	MaxScore         int            `json:"max_score"`
+	DefaultDeduction int            `json:"default_deduction"`
+	Deductions       map[string]int `json:"deductions"`
+}
+
+// DefaultRubric returns the rubric used when the instructor does not provide one:
+// 100 points with 10 points deducted for every violated rule.
+func DefaultRubric() Rubric {
+	return Rubric{MaxScore: 100, DefaultDeduction: 10}
+}
+
+// Validate checks that the scores are not negative and that every rule in the rubric exists.
+func (r Rubric) Validate() error {
+	if r.MaxScore <= 0 {
+		return fmt.Errorf("max score must be positive, got %d", r.MaxScore)
+	}
+	if r.DefaultDeduction < 0 {
+		return fmt.Errorf("default deduction must not be negative, got %d", r.DefaultDeduction)
+	}
+
+	known := cantusgen.RuleNames()
+	for name, points := range r.Deductions {
+		if !slices.Contains(known, name) {
+			return fmt.Errorf("unknown rule in rubric: %s", name)
+		}
+		if points < 0 {
+			return fmt.Errorf("deduction for %s must not be negative, got %d", name, points)
+		}
+	}
+	return nil
+}
+
+// deduction returns the points deducted when the named rule is violated.
+func (r Rubric) deduction(rule string) int {
+	if points, ok := r.Deductions[rule]; ok {
+		return points
+	}
+	return r.DefaultDeduction
+}
+
+// ParseRubric decodes a rubric from JSON and validates it.
+// Unknown fields are rejected so that typos in a rubric file do not go unnoticed.
+func ParseRubric(data []byte) (Rubric, error) {
+	var r Rubric
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&r); err != nil {
+		return Rubric{}, fmt.Errorf("invalid rubric: %w", err)
+	}
+	if err := r.Validate(); err != nil {
+		return Rubric{}, fmt.Errorf("invalid rubric: %w", err)
+	}
+	return r, nil
+}
+
+// LoadRubric reads and parses a rubric file.
+func LoadRubric(filename string) (Rubric, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Rubric{}, fmt.Errorf("error reading rubric file: %w", err)
+	}
+	return ParseRubric(data)
+}
+
+// Deduction is a line of a grade report.
+type Deduction struct {
+	Rule   string
+	Points int
+}
+
+// Report is the result of grading a cantus firmus.
+//
+// Fields:
+//   - Score: final score, never below 0
+//   - MaxScore: maximum score of the rubric
+//   - Deductions: violated rules in the order they are checked, including those worth 0 points
+type Report struct {
+	Score      int
+	MaxScore   int
+	Deductions []Deduction
+}
+
+// Grade checks the interval sequence with cantusgen.Violations and applies the rubric.
+func Grade(intervals []int, opts cantusgen.Options, rubric Rubric) (Report, error) {
+	if err := rubric.Validate(); err != nil {
+		return Report{}, err
+	}
+	if len(intervals) == 0 {
+		return Report{}, errors.New("cannot grade an empty melody")
+	}
+
+	report := Report{Score: rubric.MaxScore, MaxScore: rubric.MaxScore}
+	for _, rule := range cantusgen.Violations(intervals, opts) {
+		points := rubric.deduction(rule)
+		report.Deductions = append(report.Deductions, Deduction{Rule: rule, Points: points})
+		report.Score -= points
+	}
+	report.Score = max(report.Score, 0)
+
+	return report, nil
+}
+
+// String formats the report with one line per deduction followed by the total, e.g.
+//
+//	-10  ReturnToFinal
+//	 -2  NoSequences
+//	Score: 8/20
+func (r Report) String() string {
+	var b strings.Builder
+	for _, d := range r.Deductions {
+		fmt.Fprintf(&b, "%4d  %s\n", -d.Points, d.Rule)
+	}
+	fmt.Fprintf(&b, "Score: %d/%d", r.Score, r.MaxScore)
+	return b.String()
+}
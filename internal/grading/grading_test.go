@@ -0,0 +1,149 @@
+package grading
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/cantusgen"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseRubric(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		want        Rubric
+		errContains string
+	}{
+		{
+			name: "full rubric",
+			data: `{"max_score": 20, "default_deduction": 2, "deductions": {"ReturnToFinal": 10}}`,
+			want: Rubric{MaxScore: 20, DefaultDeduction: 2, Deductions: map[string]int{"ReturnToFinal": 10}},
+		},
+		{name: "unknown rule", data: `{"max_score": 20, "deductions": {"NoParallelFifths": 1}}`, errContains: "unknown rule"},
+		{name: "unknown field", data: `{"max_score": 20, "maximum": 30}`, errContains: "unknown field"},
+		{name: "missing max score", data: `{"default_deduction": 2}`, errContains: "max score must be positive"},
+		{name: "negative deduction", data: `{"max_score": 20, "deductions": {"NoSequences": -1}}`, errContains: "must not be negative"},
+		{name: "not JSON", data: `max_score = 20`, errContains: "invalid rubric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRubric([]byte(tt.data))
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ParseRubric() error = %v, want containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRubric() unexpected error: %v", err)
+			}
+			if got.MaxScore != tt.want.MaxScore || got.DefaultDeduction != tt.want.DefaultDeduction ||
+				len(got.Deductions) != len(tt.want.Deductions) {
+				t.Errorf("ParseRubric() = %+v, want %+v", got, tt.want)
+			}
+			for name, points := range tt.want.Deductions {
+				if got.Deductions[name] != points {
+					t.Errorf("deduction for %s = %d, want %d", name, got.Deductions[name], points)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadRubric(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "rubric.json")
+	if err := os.WriteFile(filename, []byte(`{"max_score": 10, "default_deduction": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := LoadRubric(filename)
+	if err != nil {
+		t.Fatalf("LoadRubric() unexpected error: %v", err)
+	}
+	if r.MaxScore != 10 || r.DefaultDeduction != 1 {
+		t.Errorf("LoadRubric() = %+v", r)
+	}
+
+	if _, err := LoadRubric(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("LoadRubric() of a missing file expected error, got nil")
+	}
+}
+
+func TestGrade(t *testing.T) {
+	valid := cantusgen.Generate(9, cantusgen.Options{AllowedLeaps: []int{2}})[0]
+
+	tests := []struct {
+		name           string
+		intervals      []int
+		rubric         Rubric
+		wantScore      int
+		wantDeductions []Deduction
+	}{
+		{
+			name:      "valid melody gets the maximum score",
+			intervals: valid,
+			rubric:    DefaultRubric(),
+			wantScore: 100,
+		},
+		{
+			name:           "default deduction",
+			intervals:      []int{5, -1, -1, -1, -1, -1},
+			rubric:         DefaultRubric(),
+			wantScore:      70,
+			wantDeductions: []Deduction{{"NoBeginWithFive", 10}, {"LimitDirectionalMotion", 10}, {"MinDirectionChanges", 10}},
+		},
+		{
+			name:           "rubric deductions",
+			intervals:      []int{1, 1, 2},
+			rubric:         Rubric{MaxScore: 20, DefaultDeduction: 1, Deductions: map[string]int{"ReturnToFinal": 8, "StepwiseEnding": 0}},
+			wantScore:      10,
+			wantDeductions: []Deduction{{"ReturnToFinal", 8}, {"StepwiseEnding", 0}, {"MinDirectionChanges", 1}, {"Cadence", 1}},
+		},
+		{
+			name:           "score does not go below zero",
+			intervals:      []int{1, 1, 2},
+			rubric:         Rubric{MaxScore: 5, DefaultDeduction: 4},
+			wantScore:      0,
+			wantDeductions: []Deduction{{"ReturnToFinal", 4}, {"StepwiseEnding", 4}, {"MinDirectionChanges", 4}, {"Cadence", 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Grade(tt.intervals, cantusgen.Options{}, tt.rubric)
+			if err != nil {
+				t.Fatalf("Grade() unexpected error: %v", err)
+			}
+			if report.Score != tt.wantScore || report.MaxScore != tt.rubric.MaxScore {
+				t.Errorf("Grade() score = %d/%d, want %d/%d", report.Score, report.MaxScore, tt.wantScore, tt.rubric.MaxScore)
+			}
+			if len(report.Deductions) != len(tt.wantDeductions) {
+				t.Fatalf("Grade() deductions = %v, want %v", report.Deductions, tt.wantDeductions)
+			}
+			for i, d := range tt.wantDeductions {
+				if report.Deductions[i] != d {
+					t.Errorf("deduction %d = %v, want %v", i, report.Deductions[i], d)
+				}
+			}
+		})
+	}
+}
+
+func TestGrade_Errors(t *testing.T) {
+	if _, err := Grade(nil, cantusgen.Options{}, DefaultRubric()); err == nil {
+		t.Errorf("Grade() of an empty melody expected error, got nil")
+	}
+	if _, err := Grade([]int{1, -1}, cantusgen.Options{}, Rubric{}); err == nil {
+		t.Errorf("Grade() with an invalid rubric expected error, got nil")
+	}
+}
+
+func TestReport_String(t *testing.T) {
+	r := Report{Score: 8, MaxScore: 20, Deductions: []Deduction{{"ReturnToFinal", 10}, {"NoSequences", 2}}}
+	want := " -10  ReturnToFinal\n  -2  NoSequences\nScore: 8/20"
+	if got := r.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
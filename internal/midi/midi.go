@@ -0,0 +1,126 @@
+// Package midi exports realized cantus firmi as Standard MIDI Files (SMF).
+// A batch of melodies is written as a single type-1 file in which every
+// cantus firmus occupies its own named track, so that sequencers and DAWs
+// show the batch as a browsable list of tracks.
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"io"
+	"os"
+)
+
+// TicksPerQuarter is the time division of the generated files.
+const TicksPerQuarter = 480
+
+// wholeNoteTicks is the length of a whole note in ticks.
+const wholeNoteTicks = 4 * TicksPerQuarter
+
+// tempoMicroseconds is the tempo of the generated files in microseconds per quarter note (120 BPM).
+const tempoMicroseconds = 500000
+
+// velocity is the note-on velocity of every note.
+const velocity = 80
+
+// MIDINumber returns the MIDI note number of a note (C4 = 60).
+func MIDINumber(n music.Note) int {
+	return n.Semitones() + 12
+}
+
+// WriteSMF writes the realizations to w as a type-1 Standard MIDI File.
+// The first track holds the tempo; each realization follows on its own track
+// named with its number and mode (e.g. "Cantus 3 (Dorian)"), using whole notes.
+func WriteSMF(w io.Writer, realizations []music.Realization, mode string) error {
+	if len(realizations) == 0 {
+		return errors.New("cannot create MIDI from empty realizations")
+	}
+
+	var buf bytes.Buffer
+
+	// Header chunk: format 1, number of tracks, division
+	buf.WriteString("MThd")
+	binary.Write(&buf, binary.BigEndian, uint32(6))
+	binary.Write(&buf, binary.BigEndian, uint16(1))
+	binary.Write(&buf, binary.BigEndian, uint16(len(realizations)+1))
+	binary.Write(&buf, binary.BigEndian, uint16(TicksPerQuarter))
+
+	writeTrack(&buf, tempoTrack())
+	for i, r := range realizations {
+		track, err := cantusTrack(r, fmt.Sprintf("Cantus %d (%s)", i+1, mode))
+		if err != nil {
+			return fmt.Errorf("realization %d: %w", i+1, err)
+		}
+		writeTrack(&buf, track)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// tempoTrack returns the events of the conductor track.
+func tempoTrack() []byte {
+	var events []byte
+	events = append(events, 0x00, 0xFF, 0x51, 0x03,
+		byte(tempoMicroseconds>>16&0xFF), byte(tempoMicroseconds>>8&0xFF), byte(tempoMicroseconds&0xFF))
+	events = append(events, 0x00, 0xFF, 0x2F, 0x00)
+	return events
+}
+
+// cantusTrack returns the events of a track holding a single realization.
+func cantusTrack(r music.Realization, name string) ([]byte, error) {
+	var events []byte
+	events = append(events, 0x00, 0xFF, 0x03)
+	events = appendVarLen(events, len(name))
+	events = append(events, name...)
+
+	for _, n := range r {
+		key := MIDINumber(n)
+		if key < 0 || key > 127 {
+			return nil, fmt.Errorf("note %s is outside the MIDI range", n)
+		}
+		events = append(events, 0x00, 0x90, byte(key), velocity)
+		events = appendVarLen(events, wholeNoteTicks)
+		events = append(events, 0x80, byte(key), 0x00)
+	}
+
+	events = append(events, 0x00, 0xFF, 0x2F, 0x00)
+	return events, nil
+}
+
+// writeTrack writes a track chunk with the given events.
+func writeTrack(buf *bytes.Buffer, events []byte) {
+	buf.WriteString("MTrk")
+	binary.Write(buf, binary.BigEndian, uint32(len(events)))
+	buf.Write(events)
+}
+
+// appendVarLen appends a value in the MIDI variable-length quantity encoding.
+func appendVarLen(b []byte, value int) []byte {
+	var groups []byte
+	groups = append(groups, byte(value&0x7F))
+	for value >>= 7; value > 0; value >>= 7 {
+		groups = append(groups, byte(value&0x7F)|0x80)
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		b = append(b, groups[i])
+	}
+	return b
+}
+
+// GenerateAndSaveMIDI writes the realizations as a type-1 MIDI file with one track per cantus
+func GenerateAndSaveMIDI(realizations []music.Realization, mode, filename string) error {
+	var buf bytes.Buffer
+	if err := WriteSMF(&buf, realizations, mode); err != nil {
+		return fmt.Errorf("error generating MIDI: %w", err)
+	}
+
+	err := os.WriteFile(filename, buf.Bytes(), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing MIDI file: %w", err)
+	}
+	return nil
+}
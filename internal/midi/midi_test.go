@@ -0,0 +1,140 @@
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMIDINumber(t *testing.T) {
+	tests := []struct {
+		note music.Note
+		want int
+	}{
+		{music.Note{Step: 0, Octave: 4}, 60},
+		{music.Note{Step: 5, Octave: 4}, 69},
+		{music.Note{Step: 4, Octave: 4, Alteration: 1}, 68},
+		{music.Note{Step: 6, Octave: 3, Alteration: -1}, 58},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.note.String(), func(t *testing.T) {
+			if got := MIDINumber(tt.note); got != tt.want {
+				t.Errorf("MIDINumber(%s) = %d, want %d", tt.note, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendVarLen(t *testing.T) {
+	tests := []struct {
+		value int
+		want  []byte
+	}{
+		{0, []byte{0x00}},
+		{0x7F, []byte{0x7F}},
+		{0x80, []byte{0x81, 0x00}},
+		{1920, []byte{0x8F, 0x00}},
+		{0x3FFF, []byte{0xFF, 0x7F}},
+	}
+
+	for _, tt := range tests {
+		if got := appendVarLen(nil, tt.value); !bytes.Equal(got, tt.want) {
+			t.Errorf("appendVarLen(%d) = % X, want % X", tt.value, got, tt.want)
+		}
+	}
+}
+
+// readChunks splits an SMF into its chunks.
+func readChunks(t *testing.T, data []byte) (ids []string, bodies [][]byte) {
+	t.Helper()
+	for len(data) > 0 {
+		if len(data) < 8 {
+			t.Fatalf("truncated chunk header")
+		}
+		length := binary.BigEndian.Uint32(data[4:8])
+		ids = append(ids, string(data[:4]))
+		bodies = append(bodies, data[8:8+length])
+		data = data[8+length:]
+	}
+	return ids, bodies
+}
+
+func TestWriteSMF(t *testing.T) {
+	realizations := []music.Realization{
+		{{Step: 1, Octave: 4}, {Step: 3, Octave: 4}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}},
+		{{Step: 1, Octave: 4}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSMF(&buf, realizations, "Dorian"); err != nil {
+		t.Fatalf("WriteSMF() unexpected error: %v", err)
+	}
+
+	ids, bodies := readChunks(t, buf.Bytes())
+	if len(ids) != 4 || ids[0] != "MThd" || ids[1] != "MTrk" {
+		t.Fatalf("unexpected chunks %v", ids)
+	}
+
+	header := bodies[0]
+	if format := binary.BigEndian.Uint16(header[0:2]); format != 1 {
+		t.Errorf("format = %d, want 1", format)
+	}
+	if tracks := binary.BigEndian.Uint16(header[2:4]); tracks != 3 {
+		t.Errorf("tracks = %d, want 3", tracks)
+	}
+
+	for i, name := range []string{"Cantus 1 (Dorian)", "Cantus 2 (Dorian)"} {
+		track := bodies[i+2]
+		if !bytes.Contains(track, []byte(name)) {
+			t.Errorf("track %d does not contain name %q", i+2, name)
+		}
+		if noteOns := bytes.Count(track, []byte{0x00, 0x90}); noteOns != len(realizations[i]) {
+			t.Errorf("track %d has %d note-on events, want %d", i+2, noteOns, len(realizations[i]))
+		}
+		if !bytes.HasSuffix(track, []byte{0x00, 0xFF, 0x2F, 0x00}) {
+			t.Errorf("track %d does not end with end-of-track event", i+2)
+		}
+	}
+}
+
+func TestWriteSMF_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		realizations []music.Realization
+		errContains  string
+	}{
+		{"empty", nil, "empty realizations"},
+		{"out of range", []music.Realization{{{Step: 0, Octave: 12}}}, "outside the MIDI range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WriteSMF(&bytes.Buffer{}, tt.realizations, "Major")
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("WriteSMF() error = %v, want containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestGenerateAndSaveMIDI(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "batch.mid")
+	realizations := []music.Realization{{{Step: 0, Octave: 4}, {Step: 1, Octave: 4}, {Step: 0, Octave: 4}}}
+
+	if err := GenerateAndSaveMIDI(realizations, "Major", filename); err != nil {
+		t.Fatalf("GenerateAndSaveMIDI() unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("MThd")) {
+		t.Errorf("file does not start with MThd header")
+	}
+}
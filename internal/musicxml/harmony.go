@@ -0,0 +1,87 @@
+package musicxml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"os"
+)
+
+// harmonyScore is a score with the cantus firmus and a figured bass staff as two parts.
+type harmonyScore struct {
+	XMLName  xml.Name `xml:"score-partwise"`
+	PartList struct {
+		ScoreParts []ScorePart `xml:"score-part"`
+	} `xml:"part-list"`
+	Parts []Part `xml:"part"`
+}
+
+// ToMusicXMLWithHarmony converts a cantus firmus and its harmonization skeleton into a MusicXML
+// string with two parts: the cantus in whole notes on a treble staff, and the bass notes on a
+// bass staff with the Roman numerals printed below them. As in ToMusicXML, the whole cantus
+// occupies a single measure.
+func ToMusicXMLWithHarmony(cantus, bass []Note, numerals []string) (string, error) {
+	if len(cantus) == 0 {
+		return "", errors.New("cannot create MusicXML from an empty sequence")
+	}
+	if len(bass) != len(cantus) || len(numerals) != len(cantus) {
+		return "", fmt.Errorf("harmonization has %d bass notes and %d numerals for %d cantus notes",
+			len(bass), len(numerals), len(cantus))
+	}
+
+	if err := validateSequence(cantus); err != nil {
+		return "", fmt.Errorf("cantus: %w", err)
+	}
+	if err := validateSequence(bass); err != nil {
+		return "", fmt.Errorf("bass: %w", err)
+	}
+
+	ts := music.TimeSignature{Beats: len(cantus), BeatType: 1}
+
+	upper := Measure{Number: 1, Barline: finalBarline()}
+	upper.Attributes = scoreAttributes(4, ts)
+	upper.Direction = tempoDirection()
+	for _, n := range cantus {
+		upper.Notes = append(upper.Notes, newNoteXML(n, 4, "whole", false))
+	}
+
+	lower := Measure{Number: 1, Barline: finalBarline()}
+	lower.Attributes = scoreAttributes(4, ts)
+	lower.Attributes.Clef = &Clef{Sign: "F", Line: 4}
+	for i, n := range bass {
+		noteXML := newNoteXML(n, 4, "whole", false)
+		noteXML.Lyrics = append(noteXML.Lyrics, Lyric{Text: numerals[i]})
+		lower.Notes = append(lower.Notes, noteXML)
+	}
+
+	var score harmonyScore
+	score.PartList.ScoreParts = []ScorePart{
+		{ID: "P1", PartName: PartName{Text: "Cantus Firmus"}},
+		{ID: "P2", PartName: PartName{Text: "Harmony"}},
+	}
+	score.Parts = []Part{
+		{ID: "P1", Measures: []Measure{upper}},
+		{ID: "P2", Measures: []Measure{lower}},
+	}
+
+	output, err := xml.MarshalIndent(score, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error marshalling MusicXML: %w", err)
+	}
+	return xml.Header + string(output), nil
+}
+
+// GenerateAndSaveMusicXMLWithHarmony generates MusicXML with a harmony staff and saves it to file
+func GenerateAndSaveMusicXMLWithHarmony(cantus, bass []Note, numerals []string, filename string) error {
+	xmlString, err := ToMusicXMLWithHarmony(cantus, bass, numerals)
+	if err != nil {
+		return fmt.Errorf("error generating MusicXML: %w", err)
+	}
+
+	err = os.WriteFile(filename, []byte(xmlString), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing MusicXML file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package musicxml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToMusicXMLWithHarmony(t *testing.T) {
+	cantus := []Note{{Step: 1, Octave: 4}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}}
+	bass := []Note{{Step: 1, Octave: 3}, {Step: 5, Octave: 2}, {Step: 1, Octave: 3}}
+	numerals := []string{"i", "v", "i"}
+
+	gotXML, err := ToMusicXMLWithHarmony(cantus, bass, numerals)
+	if err != nil {
+		t.Fatalf("ToMusicXMLWithHarmony() unexpected error: %v", err)
+	}
+
+	gotXML = strings.ReplaceAll(gotXML, " ", "")
+	gotXML = strings.ReplaceAll(gotXML, "\n", "")
+	wantParts := []string{
+		`<score-partid="P1"><part-name>CantusFirmus</part-name></score-part>`,
+		`<score-partid="P2"><part-name>Harmony</part-name></score-part>`,
+		`<time><beats>3</beats><beat-type>1</beat-type></time>`,
+		`<clef><sign>F</sign><line>4</line></clef>`,
+		`<pitch><step>A</step><octave>2</octave></pitch><duration>4</duration><type>whole</type><lyric><text>v</text></lyric>`,
+	}
+	for _, want := range wantParts {
+		if !strings.Contains(gotXML, want) {
+			t.Errorf("ToMusicXMLWithHarmony() got XML does not contain expected part.\nGot:\n%s\nWant part:\n%s", gotXML, want)
+		}
+	}
+	if strings.Count(gotXML, "<lyric>") != 3 {
+		t.Errorf("expected 3 lyrics, got %d", strings.Count(gotXML, "<lyric>"))
+	}
+}
+
+func TestToMusicXMLWithHarmony_Errors(t *testing.T) {
+	cantus := []Note{{Step: 0, Octave: 4}, {Step: 0, Octave: 4}}
+
+	if _, err := ToMusicXMLWithHarmony(nil, nil, nil); err == nil {
+		t.Errorf("expected error for an empty cantus")
+	}
+	if _, err := ToMusicXMLWithHarmony(cantus, cantus[:1], []string{"I", "I"}); err == nil {
+		t.Errorf("expected error for a bass of different length")
+	}
+	if _, err := ToMusicXMLWithHarmony(cantus, cantus, []string{"I"}); err == nil {
+		t.Errorf("expected error for missing numerals")
+	}
+}
@@ -0,0 +1,76 @@
+package musicxml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// importedScore holds the parts of a score read from MusicXML.
+type importedScore struct {
+	XMLName xml.Name `xml:"score-partwise"`
+	Parts   []Part   `xml:"part"`
+}
+
+// FromMusicXML reads the note sequences of the first part of a MusicXML score.
+// Every final (light-heavy) barline ends a sequence, as written by ToMusicXML and
+// ToMusicXMLWithLayout; notes after the last final barline form a sequence of their own.
+// Lyrics are read back as note labels; durations are ignored.
+func FromMusicXML(data []byte) ([][]Note, error) {
+	var score importedScore
+	if err := xml.Unmarshal(data, &score); err != nil {
+		return nil, fmt.Errorf("error parsing MusicXML: %w", err)
+	}
+	if len(score.Parts) == 0 {
+		return nil, errors.New("MusicXML score has no parts")
+	}
+
+	var sequences [][]Note
+	var current []Note
+	for _, m := range score.Parts[0].Measures {
+		for _, n := range m.Notes {
+			note, err := noteFromXML(n)
+			if err != nil {
+				return nil, fmt.Errorf("measure %d: %w", m.Number, err)
+			}
+			current = append(current, note)
+		}
+		if m.Barline != nil && m.Barline.BarStyle.Text == "light-heavy" && len(current) > 0 {
+			sequences = append(sequences, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		sequences = append(sequences, current)
+	}
+
+	return sequences, nil
+}
+
+// noteFromXML converts a MusicXML note back into a Note.
+func noteFromXML(n NoteXML) (Note, error) {
+	step := strings.Index("CDEFGAB", n.Pitch.Step)
+	if step < 0 || len(n.Pitch.Step) != 1 {
+		return Note{}, fmt.Errorf("invalid pitch step %q", n.Pitch.Step)
+	}
+
+	note := Note{Step: step, Octave: n.Pitch.Octave}
+	if n.Pitch.Alter != nil {
+		note.Alteration = *n.Pitch.Alter
+	}
+	for _, lyric := range n.Lyrics {
+		note.Labels = append(note.Labels, lyric.Text)
+	}
+	return note, nil
+}
+
+// LoadMusicXML reads the note sequences of a MusicXML file (see FromMusicXML).
+func LoadMusicXML(filename string) ([][]Note, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading MusicXML file: %w", err)
+	}
+	return FromMusicXML(data)
+}
@@ -0,0 +1,88 @@
+package musicxml
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromMusicXML_RoundTrip(t *testing.T) {
+	sequences := [][]Note{
+		{{Step: 1, Octave: 4}, {Step: 3, Octave: 4, Alteration: 1}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}, {Step: 1, Octave: 4}},
+		{{Step: 0, Octave: 5}, {Step: 6, Octave: 4, Alteration: -1}, {Step: 5, Octave: 4, Labels: []string{"6"}}, {Step: 6, Octave: 4}, {Step: 0, Octave: 5}},
+	}
+
+	single, err := ToMusicXML(sequences)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout, err := ToMusicXMLWithLayout(sequences, Layout{Time: music.TimeSignature{Beats: 4, BeatType: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, xmlString := range map[string]string{"one measure per cantus": single, "with layout": layout} {
+		t.Run(name, func(t *testing.T) {
+			got, err := FromMusicXML([]byte(xmlString))
+			if err != nil {
+				t.Fatalf("FromMusicXML() unexpected error: %v", err)
+			}
+			if len(got) != len(sequences) {
+				t.Fatalf("FromMusicXML() returned %d sequences, want %d", len(got), len(sequences))
+			}
+			for i := range sequences {
+				if len(got[i]) != len(sequences[i]) {
+					t.Fatalf("sequence %d = %v, want %v", i, got[i], sequences[i])
+				}
+				for j := range sequences[i] {
+					if !reflect.DeepEqual(got[i][j], sequences[i][j]) {
+						t.Errorf("sequence %d note %d = %v, want %v", i, j, got[i][j], sequences[i][j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFromMusicXML_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		errContains string
+	}{
+		{"not XML", "cantus", "error parsing MusicXML"},
+		{"no parts", `<score-partwise></score-partwise>`, "no parts"},
+		{"invalid step", `<score-partwise><part id="P1"><measure number="1"><note><pitch><step>H</step><octave>4</octave></pitch></note></measure></part></score-partwise>`, "invalid pitch step"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromMusicXML([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("FromMusicXML() error = %v, want containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestLoadMusicXML(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cantus.musicxml")
+	sequences := [][]Note{{{Step: 0, Octave: 4}, {Step: 1, Octave: 4}, {Step: 0, Octave: 4}}}
+	if err := GenerateAndSaveMusicXML(sequences, filename); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadMusicXML(filename)
+	if err != nil {
+		t.Fatalf("LoadMusicXML() unexpected error: %v", err)
+	}
+	if len(got) != 1 || len(got[0]) != 3 {
+		t.Errorf("LoadMusicXML() = %v", got)
+	}
+
+	if _, err := LoadMusicXML(filepath.Join(t.TempDir(), "missing.musicxml")); err == nil {
+		t.Errorf("LoadMusicXML() of a missing file expected error, got nil")
+	}
+}
@@ -0,0 +1,138 @@
+package musicxml
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"os"
+)
+
+// Layout describes how every cantus firmus is barred in the exported score.
+//
+// Fields:
+//   - Measures: number of measures each cantus occupies; 0 uses the smallest number that fits
+//   - Time: time signature of the score; a measure must hold a whole number of whole notes
+//
+// All notes except the last are whole notes. The last note starts in the final measure
+// and fills it completely, so depending on the meter it becomes a whole note, a breve,
+// a dotted breve, a longa or a dotted longa. For example, a cantus of 11 notes in 4/2
+// occupies 6 measures: ten whole notes in five measures and a final breve.
+type Layout struct {
+	Measures int
+	Time     music.TimeSignature
+}
+
+// layoutDivisions is the number of divisions per quarter note used by layouts,
+// so that a duration in divisions equals the music.Duration value (in eighths).
+const layoutDivisions = int(music.Quarter)
+
+// layoutMelody turns a single cantus into a melody of whole notes whose final note
+// fills the last of the layout's measures.
+func layoutMelody(sequence []Note, l Layout) (music.Melody, error) {
+	if len(sequence) == 0 {
+		return nil, errors.New("cannot lay out an empty sequence")
+	}
+	if err := l.Time.Validate(); err != nil {
+		return nil, err
+	}
+	if err := validateSequence(sequence); err != nil {
+		return nil, err
+	}
+
+	measureDuration := l.Time.MeasureDuration()
+	if measureDuration%music.Whole != 0 {
+		return nil, fmt.Errorf("meter %s does not hold a whole number of whole notes", l.Time)
+	}
+	perMeasure := int(measureDuration / music.Whole)
+
+	// The last note starts after len-1 whole notes and must begin in the final measure
+	lastStart := len(sequence) - 1
+	measures := l.Measures
+	if measures == 0 {
+		measures = lastStart/perMeasure + 1
+	}
+	if lastStart < (measures-1)*perMeasure || lastStart >= measures*perMeasure {
+		return nil, fmt.Errorf("%d notes cannot fill exactly %d measures of %s", len(sequence), measures, l.Time)
+	}
+
+	melody := make(music.Melody, len(sequence))
+	for i, n := range sequence {
+		melody[i] = music.MelodyNote{
+			Note:     music.Note{Step: n.Step, Octave: n.Octave, Alteration: n.Alteration},
+			Duration: music.Whole,
+		}
+	}
+	melody[lastStart].Duration = music.Duration(measures*perMeasure-lastStart) * music.Whole
+	if _, _, ok := melody[lastStart].Duration.Name(); !ok {
+		return nil, fmt.Errorf("final note of %d whole notes cannot be notated as a single note",
+			measures*perMeasure-lastStart)
+	}
+
+	return melody, nil
+}
+
+// layoutSequence splits a single cantus into measures according to the layout.
+// Measure numbers start at firstNumber.
+func layoutSequence(sequence []Note, l Layout, firstNumber int) ([]Measure, error) {
+	melody, err := layoutMelody(sequence, l)
+	if err != nil {
+		return nil, err
+	}
+
+	bars, err := melody.Bars(l.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bars never splits a note, so the notes of the bars follow the sequence one to one
+	result := make([]Measure, len(bars))
+	index := 0
+	for i, bar := range bars {
+		result[i].Number = firstNumber + i
+		for _, mn := range bar {
+			noteType, dotted, _ := mn.Duration.Name()
+			result[i].Notes = append(result[i].Notes, newNoteXML(sequence[index], int(mn.Duration), noteType, dotted))
+			index++
+		}
+	}
+	result[len(result)-1].Barline = finalBarline()
+
+	return result, nil
+}
+
+// ToMusicXMLWithLayout converts a slice of note sequences into a MusicXML string where
+// each sequence is barred according to the given layout instead of occupying one measure.
+// The metadata fields, if any, are written to the score's identification.
+func ToMusicXMLWithLayout(sequences [][]Note, l Layout, metadata ...MetadataField) (string, error) {
+	if len(sequences) == 0 {
+		return "", errors.New("cannot create MusicXML from empty sequences")
+	}
+
+	var measures []Measure
+	for i, sequence := range sequences {
+		sequenceMeasures, err := layoutSequence(sequence, l, len(measures)+1)
+		if err != nil {
+			return "", fmt.Errorf("sequence %d: %w", i+1, err)
+		}
+		measures = append(measures, sequenceMeasures...)
+	}
+
+	measures[0].Attributes = scoreAttributes(layoutDivisions, l.Time)
+	measures[0].Direction = tempoDirection()
+
+	return marshalScore(measures, metadata)
+}
+
+// GenerateAndSaveMusicXMLWithLayout generates MusicXML with the given layout and metadata and saves it to file
+func GenerateAndSaveMusicXMLWithLayout(sequences [][]Note, l Layout, filename string, metadata ...MetadataField) error {
+	xmlString, err := ToMusicXMLWithLayout(sequences, l, metadata...)
+	if err != nil {
+		return fmt.Errorf("error generating MusicXML: %w", err)
+	}
+
+	err = os.WriteFile(filename, []byte(xmlString), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing MusicXML file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package musicxml
+
+import (
+	"encoding/xml"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"strings"
+	"testing"
+)
+
+// cantusOfLength returns a sequence of n C4 notes.
+func cantusOfLength(n int) []Note {
+	seq := make([]Note, n)
+	for i := range seq {
+		seq[i] = Note{Step: 0, Octave: 4}
+	}
+	return seq
+}
+
+func TestToMusicXMLWithLayout(t *testing.T) {
+	tests := []struct {
+		name         string
+		sequences    [][]Note
+		layout       Layout
+		wantErr      bool
+		errContains  string
+		wantMeasures int
+		wantXML      []string
+	}{
+		{
+			name:        "empty sequences",
+			sequences:   [][]Note{},
+			layout:      Layout{Time: music.TimeSignature{Beats: 4, BeatType: 2}},
+			wantErr:     true,
+			errContains: "cannot create MusicXML from empty sequences",
+		},
+		{
+			name:        "unsupported beat type",
+			sequences:   [][]Note{cantusOfLength(5)},
+			layout:      Layout{Time: music.TimeSignature{Beats: 3, BeatType: 16}},
+			wantErr:     true,
+			errContains: "unsupported beat type",
+		},
+		{
+			name:        "meter without whole number of whole notes",
+			sequences:   [][]Note{cantusOfLength(5)},
+			layout:      Layout{Time: music.TimeSignature{Beats: 3, BeatType: 2}},
+			wantErr:     true,
+			errContains: "does not hold a whole number",
+		},
+		{
+			name:        "too few measures",
+			sequences:   [][]Note{cantusOfLength(11)},
+			layout:      Layout{Measures: 4, Time: music.TimeSignature{Beats: 4, BeatType: 2}},
+			wantErr:     true,
+			errContains: "cannot fill exactly 4 measures",
+		},
+		{
+			name:         "4/2 with derived measure count ends with a breve",
+			sequences:    [][]Note{cantusOfLength(11)},
+			layout:       Layout{Time: music.TimeSignature{Beats: 4, BeatType: 2}},
+			wantMeasures: 6,
+			wantXML: []string{
+				`<divisions>2</divisions>`,
+				`<time><beats>4</beats><beat-type>2</beat-type></time>`,
+				`<measure number="6"><note><pitch><step>C</step><octave>4</octave></pitch><duration>16</duration><type>breve</type></note>` +
+					`<barline location="right"><bar-style>light-heavy</bar-style></barline></measure>`,
+			},
+		},
+		{
+			name:         "3/1 ends with a dotted breve",
+			sequences:    [][]Note{cantusOfLength(10)},
+			layout:       Layout{Measures: 4, Time: music.TimeSignature{Beats: 3, BeatType: 1}},
+			wantMeasures: 4,
+			wantXML: []string{
+				`<duration>24</duration><type>breve</type><dot></dot>`,
+			},
+		},
+		{
+			name:         "4/1 ends with a longa",
+			sequences:    [][]Note{cantusOfLength(9)},
+			layout:       Layout{Measures: 3, Time: music.TimeSignature{Beats: 4, BeatType: 1}},
+			wantMeasures: 3,
+			wantXML: []string{
+				`<duration>32</duration><type>long</type>`,
+			},
+		},
+		{
+			name:         "final note filling a partially used measure",
+			sequences:    [][]Note{cantusOfLength(10)},
+			layout:       Layout{Time: music.TimeSignature{Beats: 4, BeatType: 2}},
+			wantMeasures: 5,
+			wantXML: []string{
+				`<measure number="5"><note><pitch><step>C</step><octave>4</octave></pitch><duration>8</duration><type>whole</type></note>` +
+					`<note><pitch><step>C</step><octave>4</octave></pitch><duration>8</duration><type>whole</type></note>`,
+			},
+		},
+		{
+			name:         "several sequences are numbered continuously",
+			sequences:    [][]Note{cantusOfLength(5), cantusOfLength(5)},
+			layout:       Layout{Measures: 3, Time: music.TimeSignature{Beats: 2, BeatType: 1}},
+			wantMeasures: 6,
+			wantXML: []string{
+				`<measure number="3"><note><pitch><step>C</step><octave>4</octave></pitch><duration>16</duration><type>breve</type></note>`,
+				`<measure number="4"><note>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotXML, err := ToMusicXMLWithLayout(tt.sequences, tt.layout)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ToMusicXMLWithLayout() expected error, got nil")
+				} else if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ToMusicXMLWithLayout() error = %v, want containing %q", err, tt.errContains)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ToMusicXMLWithLayout() unexpected error: %v", err)
+			}
+
+			var score ScorePartwise
+			if err := xml.Unmarshal([]byte(gotXML), &score); err != nil {
+				t.Fatalf("failed to parse generated XML: %v", err)
+			}
+			if len(score.Part.Measures) != tt.wantMeasures {
+				t.Errorf("got %d measures, want %d", len(score.Part.Measures), tt.wantMeasures)
+			}
+
+			gotXML = strings.ReplaceAll(gotXML, " ", "")
+			gotXML = strings.ReplaceAll(gotXML, "\n", "")
+			for _, want := range tt.wantXML {
+				want = strings.ReplaceAll(want, " ", "")
+				if !strings.Contains(gotXML, want) {
+					t.Errorf("ToMusicXMLWithLayout() got XML does not contain expected part.\nGot:\n%s\nWant part:\n%s", gotXML, want)
+				}
+			}
+		})
+	}
+}
@@ -4,15 +4,36 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
-	"go-cantus-firmus/internal/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
 	"os"
 )
 
 // ScorePartwise represents the root element of a MusicXML score.
 type ScorePartwise struct {
-	XMLName  xml.Name `xml:"score-partwise"`
-	PartList PartList `xml:"part-list"`
-	Part     Part     `xml:"part"`
+	XMLName        xml.Name        `xml:"score-partwise"`
+	Identification *Identification `xml:"identification,omitempty"`
+	PartList       PartList        `xml:"part-list"`
+	Part           Part            `xml:"part"`
+}
+
+// Identification holds metadata about the score.
+type Identification struct {
+	XMLName       xml.Name      `xml:"identification"`
+	Miscellaneous Miscellaneous `xml:"miscellaneous"`
+}
+
+// Miscellaneous holds metadata fields that have no dedicated MusicXML element.
+type Miscellaneous struct {
+	XMLName xml.Name        `xml:"miscellaneous"`
+	Fields  []MetadataField `xml:"miscellaneous-field"`
+}
+
+// MetadataField is a named metadata value written to the score's identification,
+// e.g. the fingerprint of the rule set that produced the melodies (see rules.RuleSet.Fingerprint).
+type MetadataField struct {
+	XMLName xml.Name `xml:"miscellaneous-field"`
+	Name    string   `xml:"name,attr"`
+	Value   string   `xml:",chardata"`
 }
 
 // PartList contains the score-parts.
@@ -86,6 +107,21 @@ type NoteXML struct {
 	Pitch    Pitch    `xml:"pitch"`
 	Duration int      `xml:"duration"`
 	Type     string   `xml:"type"`
+	Dot      *Dot     `xml:"dot,omitempty"`
+	Lyrics   []Lyric  `xml:"lyric"`
+}
+
+// Lyric represents text printed below a note. Number selects the line
+// when a note carries several lyrics.
+type Lyric struct {
+	XMLName xml.Name `xml:"lyric"`
+	Number  int      `xml:"number,attr,omitempty"`
+	Text    string   `xml:"text"`
+}
+
+// Dot represents an augmentation dot of a note.
+type Dot struct {
+	XMLName xml.Name `xml:"dot"`
 }
 
 // Pitch represents the pitch of a note.
@@ -137,14 +173,17 @@ type Sound struct {
 }
 
 // Note represents a musical note for conversion to MusicXML.
+// Labels are printed below the note as lyrics, one line per label.
 type Note struct {
 	Step       int
 	Octave     int
 	Alteration int
+	Labels     []string
 }
 
 // ToMusicXML converts a slice of note sequences into a MusicXML string.
-func ToMusicXML(sequences [][]Note) (string, error) {
+// The metadata fields, if any, are written to the score's identification.
+func ToMusicXML(sequences [][]Note, metadata ...MetadataField) (string, error) {
 	if len(sequences) == 0 {
 		return "", errors.New("cannot create MusicXML from empty sequences")
 	}
@@ -157,69 +196,117 @@ func ToMusicXML(sequences [][]Note) (string, error) {
 		}
 	}
 
-	stepMap := []string{"C", "D", "E", "F", "G", "A", "B"}
-
 	var measures []Measure
 	for measureNum, sequence := range sequences {
+		if err := validateSequence(sequence); err != nil {
+			return "", fmt.Errorf("sequence %d: %w", measureNum+1, err)
+		}
+
 		var notesXML []NoteXML
 		for _, n := range sequence {
-			var alter *int
-			if n.Alteration != 0 {
-				a := n.Alteration
-				alter = &a
-			}
-
-			notesXML = append(notesXML, NoteXML{
-				Pitch: Pitch{
-					Step:   stepMap[n.Step],
-					Alter:  alter,
-					Octave: n.Octave,
-				},
-				Duration: 4,
-				Type:     "whole",
-			})
+			notesXML = append(notesXML, newNoteXML(n, 4, "whole", false))
 		}
 
 		measure := Measure{
-			Number: measureNum + 1,
-			Notes:  notesXML,
-			Barline: &Barline{
-				Location: "right",
-				BarStyle: BarStyle{Text: "light-heavy"},
-			},
+			Number:  measureNum + 1,
+			Notes:   notesXML,
+			Barline: finalBarline(),
 		}
 
 		if measureNum == 0 {
-			beats := fmt.Sprintf("%d", len(sequence))
-			measure.Attributes = &Attributes{
-				Divisions: 4,
-				Key:       &Key{Fifths: 0},
-				Time: &Time{
-					Beats:    beats,
-					BeatType: "1",
-				},
-				Clef: &Clef{
-					Sign: "G",
-					Line: 2,
-				},
-			}
-			measure.Direction = &Direction{
-				Placement: "above",
-				DirectionType: DirectionType{
-					Metronome: &Metronome{
-						BeatUnit:  "quarter",
-						PerMinute: 300,
-					},
-				},
-				Sound: &Sound{
-					Tempo: 300.0,
-				},
-			}
+			// The whole cantus fits in a single measure of len(sequence) whole-note beats
+			measure.Attributes = scoreAttributes(4, music.TimeSignature{Beats: len(sequence), BeatType: 1})
+			measure.Direction = tempoDirection()
 		}
 
 		measures = append(measures, measure)
 	}
 
+	return marshalScore(measures, metadata)
+}
+
+// validateSequence checks that every note of the sequence can be written as a MusicXML pitch.
+func validateSequence(sequence []Note) error {
+	for i, n := range sequence {
+		if _, err := music.NewNote(n.Step, n.Octave, n.Alteration); err != nil {
+			return fmt.Errorf("note %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// newNoteXML converts a Note into its MusicXML representation with the given
+// duration (in divisions), note type and optional augmentation dot.
+func newNoteXML(n Note, duration int, noteType string, dotted bool) NoteXML {
+	stepMap := []string{"C", "D", "E", "F", "G", "A", "B"}
+
+	var alter *int
+	if n.Alteration != 0 {
+		a := n.Alteration
+		alter = &a
+	}
+
+	noteXML := NoteXML{
+		Pitch: Pitch{
+			Step:   stepMap[n.Step],
+			Alter:  alter,
+			Octave: n.Octave,
+		},
+		Duration: duration,
+		Type:     noteType,
+	}
+	if dotted {
+		noteXML.Dot = &Dot{}
+	}
+	for i, label := range n.Labels {
+		noteXML.Lyrics = append(noteXML.Lyrics, Lyric{Number: i + 1, Text: label})
+	}
+	return noteXML
+}
+
+// finalBarline returns the light-heavy barline closing a cantus firmus.
+func finalBarline() *Barline {
+	return &Barline{
+		Location: "right",
+		BarStyle: BarStyle{Text: "light-heavy"},
+	}
+}
+
+// scoreAttributes returns the attributes of the first measure of the score.
+func scoreAttributes(divisions int, ts music.TimeSignature) *Attributes {
+	return &Attributes{
+		Divisions: divisions,
+		Key:       &Key{Fifths: 0},
+		Time: &Time{
+			Beats:    fmt.Sprintf("%d", ts.Beats),
+			BeatType: fmt.Sprintf("%d", ts.BeatType),
+		},
+		Clef: &Clef{
+			Sign: "G",
+			Line: 2,
+		},
+	}
+}
+
+// tempoDirection returns the tempo marking of the first measure of the score.
+func tempoDirection() *Direction {
+	return &Direction{
+		Placement: "above",
+		DirectionType: DirectionType{
+			Metronome: &Metronome{
+				BeatUnit:  "quarter",
+				PerMinute: 300,
+			},
+		},
+		Sound: &Sound{
+			Tempo: 300.0,
+		},
+	}
+}
+
+// marshalScore wraps the measures into a single-part score with the given metadata
+// and marshals it to a MusicXML string.
+func marshalScore(measures []Measure, metadata []MetadataField) (string, error) {
 	score := ScorePartwise{
 		PartList: PartList{
 			ScorePart: ScorePart{
@@ -233,6 +320,10 @@ func ToMusicXML(sequences [][]Note) (string, error) {
 		},
 	}
 
+	if len(metadata) > 0 {
+		score.Identification = &Identification{Miscellaneous: Miscellaneous{Fields: metadata}}
+	}
+
 	output, err := xml.MarshalIndent(score, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("error marshalling MusicXML: %w", err)
@@ -258,9 +349,22 @@ func ConvertRealizationsToXMLNotes(realizations []music.Realization) [][]Note {
 	return xmlSequences
 }
 
-// GenerateAndSaveMusicXML generates MusicXML from note sequences and saves to file
-func GenerateAndSaveMusicXML(sequences [][]Note, filename string) error {
-	xmlString, err := ToMusicXML(sequences)
+// WithLabels returns a copy of the sequence with the labels of the annotations attached to its notes.
+// Labels of indices outside the sequence are ignored.
+func WithLabels(sequence []Note, annotations music.Annotations) []Note {
+	labeled := make([]Note, len(sequence))
+	copy(labeled, sequence)
+	for i := range labeled {
+		if labels := annotations.Labels(i); labels != nil {
+			labeled[i].Labels = append(labeled[i].Labels[:len(labeled[i].Labels):len(labeled[i].Labels)], labels...)
+		}
+	}
+	return labeled
+}
+
+// GenerateAndSaveMusicXML generates MusicXML from note sequences with the given metadata and saves to file
+func GenerateAndSaveMusicXML(sequences [][]Note, filename string, metadata ...MetadataField) error {
+	xmlString, err := ToMusicXML(sequences, metadata...)
 	if err != nil {
 		return fmt.Errorf("error generating MusicXML: %w", err)
 	}
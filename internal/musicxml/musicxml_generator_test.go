@@ -2,8 +2,9 @@ package musicxml
 
 import (
 	"encoding/xml"
-	"go-cantus-firmus/internal/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -31,6 +32,22 @@ func TestToMusicXML(t *testing.T) {
 			wantErr:     true,
 			errContains: "sequence 2 has length 2, expected 1",
 		},
+		{
+			name: "invalid step",
+			sequences: [][]Note{
+				{{Step: 0, Octave: 4, Alteration: 0}, {Step: 7, Octave: 4, Alteration: 0}},
+			},
+			wantErr:     true,
+			errContains: "sequence 1: note 2: invalid note step 7",
+		},
+		{
+			name: "triple sharp",
+			sequences: [][]Note{
+				{{Step: 3, Octave: 4, Alteration: 3}},
+			},
+			wantErr:     true,
+			errContains: "invalid note alteration 3",
+		},
 		{
 			name: "single measure, single note, C4",
 			sequences: [][]Note{
@@ -252,7 +269,7 @@ func TestConvertRealizationsToXMLNotes(t *testing.T) {
 				}
 
 				for j, note := range seq {
-					if note != tt.expected[i][j] {
+					if !reflect.DeepEqual(note, tt.expected[i][j]) {
 						t.Errorf("sequence %d, note %d: expected %v, got %v", i, j, tt.expected[i][j], note)
 					}
 				}
@@ -375,3 +392,65 @@ func TestGenerateAndSaveMusicXML_FileContent(t *testing.T) {
 		}
 	}
 }
+
+func TestWithLabels(t *testing.T) {
+	sequence := []Note{{Step: 1, Octave: 4}, {Step: 5, Octave: 4}, {Step: 1, Octave: 4}}
+	annotations := music.Annotations{1: {"5", "climax"}, 7: {"ignored"}}
+
+	labeled := WithLabels(sequence, annotations)
+	if sequence[1].Labels != nil {
+		t.Error("WithLabels() modified its input")
+	}
+	want := []Note{{Step: 1, Octave: 4}, {Step: 5, Octave: 4, Labels: []string{"5", "climax"}}, {Step: 1, Octave: 4}}
+	if !reflect.DeepEqual(labeled, want) {
+		t.Fatalf("WithLabels() = %v, want %v", labeled, want)
+	}
+
+	got, err := ToMusicXML([][]Note{labeled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fragment := range []string{`<lyric number="1">`, `<text>5</text>`, `<lyric number="2">`, `<text>climax</text>`} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("MusicXML does not contain %s", fragment)
+		}
+	}
+	if strings.Count(got, "<lyric") != 2 {
+		t.Errorf("expected 2 lyrics, got %d", strings.Count(got, "<lyric"))
+	}
+}
+
+func TestToMusicXML_Metadata(t *testing.T) {
+	sequences := [][]Note{{{Step: 1, Octave: 4}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}}}
+
+	plain, err := ToMusicXML(sequences)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(plain, "<identification>") {
+		t.Error("a score without metadata must not have an identification element")
+	}
+
+	xmlString, err := ToMusicXML(sequences,
+		MetadataField{Name: "rules-fingerprint", Value: "0123456789ab"},
+		MetadataField{Name: "generation", Value: "length=3 mode=dorian"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<miscellaneous-field name="rules-fingerprint">0123456789ab</miscellaneous-field>`,
+		`<miscellaneous-field name="generation">length=3 mode=dorian</miscellaneous-field>`,
+	} {
+		if !strings.Contains(xmlString, want) {
+			t.Errorf("ToMusicXML() output lacks %s", want)
+		}
+	}
+	if strings.Index(xmlString, "<identification>") > strings.Index(xmlString, "<part-list>") {
+		t.Error("identification must come before part-list")
+	}
+
+	got, err := FromMusicXML([]byte(xmlString))
+	if err != nil || len(got) != 1 || len(got[0]) != 3 {
+		t.Errorf("FromMusicXML() = %v, %v; want the single melody back", got, err)
+	}
+}
@@ -0,0 +1,243 @@
+// Package repair suggests minimal edits that turn an invalid cantus firmus into a valid one.
+// Melodies are handled as interval sequences (see package rules). Suggest moves single
+// notes up or down by a number of diatonic steps while the other notes stay in place;
+// Nearest replaces as few intervals as possible, which also mends a melody that misses
+// its final.
+package repair
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/cantusgen"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"slices"
+	"sort"
+)
+
+// MaxShift is the largest distance, in diatonic steps, by which a single note is moved.
+const MaxShift = 4
+
+// Change describes one edited note.
+//
+// Fields:
+//   - Index: position of the note in the melody (0-based)
+//   - From: original height of the note in steps above the first note
+//   - To: suggested height of the note in steps above the first note
+type Change struct {
+	Index int
+	From  int
+	To    int
+}
+
+// Shift returns the signed distance by which the note is moved.
+func (c Change) Shift() int {
+	return c.To - c.From
+}
+
+// Suggestion is a repaired version of a melody.
+//
+// Fields:
+//   - Intervals: the repaired interval sequence
+//   - Changes: the edited notes, ordered by index
+type Suggestion struct {
+	Intervals []int
+	Changes   []Change
+}
+
+// Distance returns the total number of steps by which notes are moved.
+func (s Suggestion) Distance() int {
+	d := 0
+	for _, c := range s.Changes {
+		d += utils.Abs(c.Shift())
+	}
+	return d
+}
+
+// Suggest searches for melodies that differ from intervals in at most maxEdits notes
+// and satisfy cantusgen.IsValidCantus with the given options.
+// The first and last notes (the final) are never changed.
+//
+// Suggestions are ranked by the number of edited notes, then by the total distance
+// the notes are moved, then by the position of the edits. A valid melody yields
+// no suggestions.
+func Suggest(intervals []int, maxEdits int, opts cantusgen.Options) []Suggestion {
+	if len(intervals) < 2 || maxEdits <= 0 || cantusgen.IsValidCantus(intervals, opts) {
+		return nil
+	}
+
+	heights := make([]int, len(intervals)+1)
+	for i, val := range intervals {
+		heights[i+1] = heights[i] + val
+	}
+
+	var result []Suggestion
+	var edit func(from int, changes []Change)
+	edit = func(from int, changes []Change) {
+		for i := from; i < len(heights)-1; i++ {
+			for shift := -MaxShift; shift <= MaxShift; shift++ {
+				if shift == 0 {
+					continue
+				}
+				next := append(changes[:len(changes):len(changes)], Change{Index: i, From: heights[i], To: heights[i] + shift})
+				candidate := apply(heights, next)
+				if cantusgen.IsValidCantus(candidate, opts) {
+					if isMinimal(heights, next, opts) {
+						result = append(result, Suggestion{Intervals: candidate, Changes: next})
+					}
+				} else if len(next) < maxEdits {
+					edit(i+1, next)
+				}
+			}
+		}
+	}
+	edit(1, nil)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if len(a.Changes) != len(b.Changes) {
+			return len(a.Changes) < len(b.Changes)
+		}
+		return a.Distance() < b.Distance()
+	})
+	return result
+}
+
+// isMinimal reports whether no proper subset of the changes already produces a valid melody,
+// so that a suggestion never contains an edit that is not needed.
+func isMinimal(heights []int, changes []Change, opts cantusgen.Options) bool {
+	for mask := 1; mask < 1<<len(changes)-1; mask++ {
+		var subset []Change
+		for i, c := range changes {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, c)
+			}
+		}
+		if cantusgen.IsValidCantus(apply(heights, subset), opts) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the interval sequence of the melody with the given heights after the changes.
+func apply(heights []int, changes []Change) []int {
+	edited := make([]int, len(heights))
+	copy(edited, heights)
+	for _, c := range changes {
+		edited[c.Index] = c.To
+	}
+
+	intervals := make([]int, len(heights)-1)
+	for i := range intervals {
+		intervals[i] = edited[i+1] - edited[i]
+	}
+	return intervals
+}
+
+// Edit describes one replaced interval.
+//
+// Fields:
+//   - Index: position of the interval in the melody (0-based)
+//   - From: original interval
+//   - To: suggested interval
+type Edit struct {
+	Index int
+	From  int
+	To    int
+}
+
+// Repair is a valid melody among the nearest to an invalid one.
+//
+// Fields:
+//   - Intervals: the repaired interval sequence
+//   - Edits: the replaced intervals, ordered by index
+type Repair struct {
+	Intervals []int
+	Edits     []Edit
+}
+
+// Distance returns the total number of steps by which the intervals are changed.
+func (r Repair) Distance() int {
+	d := 0
+	for _, e := range r.Edits {
+		d += utils.Abs(e.To - e.From)
+	}
+	return d
+}
+
+// Nearest returns the valid cantus firmi (see cantusgen.IsValidCantus) that differ from
+// intervals in the fewest intervals, at most maxEdits, along with the replaced intervals.
+// The search widens the neighbourhood of the melody one edit at a time: for every set of
+// positions it generates the melodies keeping the other intervals (see cantusgen.Options.Pins),
+// so the rules prune the replacements as in any other search. The length of the melody is kept.
+//
+// Repairs are ranked by the total distance the intervals are changed, then by the position of
+// the edits. A valid melody yields no repairs. An empty opts.AllowedLeaps accepts any number
+// of leaps, as in cantusgen.IsValidCantus.
+func Nearest(intervals []int, maxEdits int, opts cantusgen.Options) []Repair {
+	n := len(intervals)
+	if n < 2 || maxEdits <= 0 || cantusgen.IsValidCantus(intervals, opts) {
+		return nil
+	}
+	if len(opts.AllowedLeaps) == 0 {
+		for count := 0; count <= n-2; count++ {
+			opts.AllowedLeaps = append(opts.AllowedLeaps, count)
+		}
+	}
+
+	for edits := 1; edits <= min(maxEdits, n); edits++ {
+		var result []Repair
+		positions := make([]int, 0, edits)
+		var choose func(from int)
+		choose = func(from int) {
+			if len(positions) == edits {
+				result = append(result, replace(intervals, positions, opts)...)
+				return
+			}
+			for i := from; i <= n-(edits-len(positions)); i++ {
+				positions = append(positions, i)
+				choose(i + 1)
+				positions = positions[:len(positions)-1]
+			}
+		}
+		choose(0)
+
+		if len(result) > 0 {
+			sort.SliceStable(result, func(i, j int) bool {
+				return result[i].Distance() < result[j].Distance()
+			})
+			return result
+		}
+	}
+	return nil
+}
+
+// replace returns the valid melodies that keep every interval except those at the positions,
+// which all differ from the original ones. Melodies keeping one of them were found with fewer
+// edits.
+func replace(intervals, positions []int, opts cantusgen.Options) []Repair {
+	pinned := slices.Clone(opts.Pins)
+	for i, val := range intervals {
+		if !slices.Contains(positions, i) {
+			pinned = append(pinned, rules.Pin{Position: i + 1, Values: []int{val}})
+		}
+	}
+	opts.Pins = pinned
+	opts.Stats = nil
+	opts.Progress = nil
+
+	var result []Repair
+	for melody := range cantusgen.GenerateSeq(len(intervals), opts) {
+		edits := make([]Edit, 0, len(positions))
+		for _, i := range positions {
+			if melody[i] == intervals[i] {
+				edits = nil
+				break
+			}
+			edits = append(edits, Edit{Index: i, From: intervals[i], To: melody[i]})
+		}
+		if edits != nil {
+			result = append(result, Repair{Intervals: slices.Clone(melody), Edits: edits})
+		}
+	}
+	return result
+}
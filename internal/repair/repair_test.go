@@ -0,0 +1,175 @@
+package repair
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/cantusgen"
+	"slices"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	opts := cantusgen.Options{AllowedLeaps: []int{2}}
+	generated := cantusgen.Generate(9, opts)
+	if len(generated) == 0 {
+		t.Fatal("Generate returned no cantus firmi")
+	}
+	valid := generated[0]
+
+	// Move the third note two steps up: intervals 1 and 2 change
+	broken := append([]int{}, valid...)
+	broken[1] += 2
+	broken[2] -= 2
+
+	tests := []struct {
+		name      string
+		intervals []int
+		maxEdits  int
+		wantNone  bool
+	}{
+		{"valid melody needs no repair", valid, 2, true},
+		{"no edits allowed", broken, 0, true},
+		{"too short", []int{1}, 2, true},
+		{"one moved note", broken, 1, false},
+		{"one moved note with two edits allowed", broken, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if cantusgen.IsValidCantus(broken, opts) {
+				t.Fatalf("test melody %v is unexpectedly valid", broken)
+			}
+
+			suggestions := Suggest(tt.intervals, tt.maxEdits, opts)
+			if tt.wantNone {
+				if len(suggestions) != 0 {
+					t.Errorf("Suggest() returned %d suggestions, want none", len(suggestions))
+				}
+				return
+			}
+			if len(suggestions) == 0 {
+				t.Fatalf("Suggest(%v) returned no suggestions", tt.intervals)
+			}
+
+			restored := false
+			for i, s := range suggestions {
+				if !cantusgen.IsValidCantus(s.Intervals, opts) {
+					t.Errorf("suggestion %d (%v) is not a valid cantus", i, s.Intervals)
+				}
+				if len(s.Changes) == 0 || len(s.Changes) > tt.maxEdits {
+					t.Errorf("suggestion %d has %d changes, want 1..%d", i, len(s.Changes), tt.maxEdits)
+				}
+				if i > 0 && len(s.Changes) < len(suggestions[i-1].Changes) {
+					t.Errorf("suggestion %d has fewer changes than suggestion %d", i, i-1)
+				}
+				if len(s.Changes) == 1 && s.Changes[0] == (Change{Index: 2, From: s.Changes[0].From, To: s.Changes[0].From - 2}) {
+					restored = true
+				}
+				for _, c := range s.Changes {
+					if c.Index == 0 || c.Index == len(tt.intervals) {
+						t.Errorf("suggestion %d changes the final (note %d)", i, c.Index)
+					}
+				}
+			}
+			if !restored {
+				t.Errorf("Suggest() did not propose moving note 2 back by two steps")
+			}
+			if len(suggestions[0].Changes) != 1 {
+				t.Errorf("best suggestion has %d changes, want 1", len(suggestions[0].Changes))
+			}
+		})
+	}
+}
+
+func TestSuggestion_Distance(t *testing.T) {
+	s := Suggestion{Changes: []Change{{Index: 1, From: 2, To: -1}, {Index: 3, From: 0, To: 1}}}
+	if got := s.Distance(); got != 4 {
+		t.Errorf("Distance() = %d, want 4", got)
+	}
+}
+
+func BenchmarkSuggest(b *testing.B) {
+	// A 16-note melody that ends with a leap and does not return to its final
+	intervals := []int{1, 1, 1, -3, 1, 1, 2, -1, -1, -1, 1, 1, -1, -1, 2}
+	for i := 0; i < b.N; i++ {
+		Suggest(intervals, 2, cantusgen.Options{})
+	}
+}
+
+func TestNearest(t *testing.T) {
+	opts := cantusgen.Options{AllowedLeaps: []int{2}}
+	valid := cantusgen.Generate(9, opts)[0]
+
+	// Replacing the last interval misses the final, which no moved note can mend
+	missed := slices.Clone(valid)
+	missed[8] = -missed[8]
+	// Moving the third note two steps up changes two intervals
+	moved := slices.Clone(valid)
+	moved[1] += 2
+	moved[2] -= 2
+
+	tests := []struct {
+		name      string
+		intervals []int
+		maxEdits  int
+		wantEdits int
+	}{
+		{"valid melody needs no repair", valid, 2, 0},
+		{"no edits allowed", missed, 0, 0},
+		{"too short", []int{1}, 2, 0},
+		{"missed final", missed, 2, 1},
+		{"moved note", moved, 3, 2},
+		{"moved note with too few edits", moved, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repairs := Nearest(tt.intervals, tt.maxEdits, opts)
+			if tt.wantEdits == 0 {
+				if len(repairs) != 0 {
+					t.Errorf("Nearest() returned %d repairs, want none", len(repairs))
+				}
+				return
+			}
+			if len(repairs) == 0 {
+				t.Fatalf("Nearest(%v) returned no repairs", tt.intervals)
+			}
+
+			restored := false
+			for i, r := range repairs {
+				if !cantusgen.IsValidCantus(r.Intervals, opts) {
+					t.Errorf("repair %d (%v) is not a valid cantus", i, r.Intervals)
+				}
+				if len(r.Edits) != tt.wantEdits {
+					t.Errorf("repair %d has %d edits, want %d", i, len(r.Edits), tt.wantEdits)
+				}
+				if i > 0 && r.Distance() < repairs[i-1].Distance() {
+					t.Errorf("repair %d is nearer than repair %d", i, i-1)
+				}
+				for _, e := range r.Edits {
+					if r.Intervals[e.Index] != e.To || tt.intervals[e.Index] != e.From || e.From == e.To {
+						t.Errorf("repair %d: edit %+v does not match %v", i, e, r.Intervals)
+					}
+				}
+				if slices.Equal(r.Intervals, valid) {
+					restored = true
+				}
+			}
+			if !restored {
+				t.Errorf("Nearest() did not restore %v", valid)
+			}
+		})
+	}
+}
+
+func TestRepair_Distance(t *testing.T) {
+	r := Repair{Edits: []Edit{{Index: 1, From: 2, To: -1}, {Index: 3, From: -1, To: 1}}}
+	if got := r.Distance(); got != 5 {
+		t.Errorf("Distance() = %d, want 5", got)
+	}
+}
+
+func BenchmarkNearest(b *testing.B) {
+	intervals := []int{1, 1, 1, -3, 1, 1, 2, -1, -1, -1, 1, 1, -1, -1, 2}
+	for i := 0; i < b.N; i++ {
+		Nearest(intervals, 3, cantusgen.Options{})
+	}
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/cantusgen"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bounds on the search behind a permalink, so that no request can tie up the server
+const (
+	searchTimeout   = 10 * time.Second
+	searchMaxMemory = 512 << 20
+)
+
+// ErrTruncated is returned by Permalink.Resolve when the search stopped before it was complete,
+// on a timeout, the memory limit or the end of the request, so that the melodies are unknown.
+var ErrTruncated = errors.New("search stopped before it was complete")
+
+// Permalink holds everything needed to reproduce one generated melody exactly.
+// It is encoded as a URL query string, e.g.
+//
+//	length=10&mode=dorian&leaps=2&seed=42&index=3
+//
+// Fields:
+//   - Length: number of notes
+//   - Mode: mode name (see music.ParseMode)
+//   - Leaps: allowed numbers of leaps
+//   - Degrees: allowed scale degrees; nil allows all degrees
+//   - Minor: treatment of the 6th and 7th degrees in minor mode
+//   - Seed: seed of the shuffle that orders the generated melodies
+//   - Index: position of the selected melody in the shuffled list (0-based)
+type Permalink struct {
+	Length  int
+	Mode    music.Mode
+	Leaps   []int
+	Degrees []int
+	Minor   music.MinorPolicy
+	Seed    int64
+	Index   int
+}
+
+// Encode returns the permalink as a URL query string.
+// Optional parameters with default values are omitted.
+func (p Permalink) Encode() string {
+	q := url.Values{}
+	q.Set("length", strconv.Itoa(p.Length))
+	q.Set("mode", strings.ToLower(p.Mode.String()))
+	q.Set("leaps", joinInts(p.Leaps))
+	if p.Degrees != nil {
+		q.Set("degrees", joinInts(p.Degrees))
+	}
+	if p.Minor != music.MinorMelodic {
+		q.Set("minor", p.Minor.String())
+	}
+	q.Set("seed", strconv.FormatInt(p.Seed, 10))
+	q.Set("index", strconv.Itoa(p.Index))
+	return q.Encode()
+}
+
+// ParsePermalink decodes and validates a permalink from query parameters.
+// Length, mode and leaps are required. A missing seed is reported by hasSeed == false
+// so that the caller can pick one; a missing index selects the first melody.
+func ParsePermalink(q url.Values) (p Permalink, hasSeed bool, err error) {
+	if p.Length, err = strconv.Atoi(q.Get("length")); err != nil {
+		return Permalink{}, false, fmt.Errorf("invalid length: %q", q.Get("length"))
+	}
+	if p.Length < cantusgen.MinNotes || p.Length > cantusgen.MaxNotes {
+		return Permalink{}, false, fmt.Errorf("length must be between %d and %d, got %d",
+			cantusgen.MinNotes, cantusgen.MaxNotes, p.Length)
+	}
+
+	if p.Mode, err = music.ParseMode(q.Get("mode")); err != nil {
+		return Permalink{}, false, err
+	}
+
+	if p.Leaps, err = splitInts(q.Get("leaps")); err != nil || len(p.Leaps) == 0 {
+		return Permalink{}, false, fmt.Errorf("invalid leaps: %q", q.Get("leaps"))
+	}
+	for _, count := range p.Leaps {
+		if most := p.Length - cantusgen.MaxLeapsOffset; count < 0 || count > most {
+			return Permalink{}, false, fmt.Errorf("leaps out of range 0-%d: %d", most, count)
+		}
+	}
+
+	if q.Has("degrees") {
+		if p.Degrees, err = splitInts(q.Get("degrees")); err != nil || len(p.Degrees) == 0 {
+			return Permalink{}, false, fmt.Errorf("invalid degrees: %q", q.Get("degrees"))
+		}
+		for _, d := range p.Degrees {
+			if d < 1 || d > 7 {
+				return Permalink{}, false, fmt.Errorf("degree out of range 1-7: %d", d)
+			}
+		}
+	}
+
+	if q.Has("minor") {
+		if p.Minor, err = music.ParseMinorPolicy(q.Get("minor")); err != nil {
+			return Permalink{}, false, err
+		}
+	}
+
+	if q.Has("seed") {
+		if p.Seed, err = strconv.ParseInt(q.Get("seed"), 10, 64); err != nil {
+			return Permalink{}, false, fmt.Errorf("invalid seed: %q", q.Get("seed"))
+		}
+		hasSeed = true
+	}
+
+	if q.Has("index") {
+		if p.Index, err = strconv.Atoi(q.Get("index")); err != nil || p.Index < 0 {
+			return Permalink{}, false, fmt.Errorf("invalid index: %q", q.Get("index"))
+		}
+	}
+
+	return p, hasSeed, nil
+}
+
+// RuleSet returns the rules the permalink's melodies satisfy: the registered rules adapted to its mode.
+func (p Permalink) RuleSet() *rules.RuleSet {
+	ruleSet := rules.DefaultRuleSet()
+	ruleSet.SetMode(p.Mode)
+	return ruleSet
+}
+
+// Melodies generates all valid realizations for the permalink's parameters, as
+// cantusgen.GenerateRealizations does for its mode, and shuffles them deterministically with
+// its seed. The search stops with ErrTruncated after a timeout, once the heap grows too large
+// or when ctx is done.
+func (p Permalink) Melodies(ctx context.Context) ([]music.Realization, error) {
+	timeout := searchTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline))
+	}
+	ruleSet := p.RuleSet()
+	realize := music.RealizeOptions{Minor: p.Minor}
+	options := []cantusgen.Option{
+		cantusgen.WithLength(p.Length),
+		cantusgen.WithLeaps(p.Leaps...),
+		cantusgen.WithRules(ruleSet),
+		cantusgen.WithMode(p.Mode, realize),
+		cantusgen.WithTimeout(max(timeout, time.Nanosecond)),
+		cantusgen.WithMaxMemory(searchMaxMemory),
+		cantusgen.WithCallback(func([]int) bool { return ctx.Err() == nil }),
+	}
+	if p.Degrees != nil {
+		options = append(options, cantusgen.WithDegrees(p.Degrees...))
+	}
+	g, err := cantusgen.NewGenerator(options...)
+	if err != nil {
+		return nil, err
+	}
+	sequences, truncated := g.GenerateLimited()
+	if truncated || ctx.Err() != nil {
+		return nil, ErrTruncated
+	}
+	result := cantusgen.Realize(sequences, p.Mode, ruleSet, cantusgen.RealizationOptions{Realize: realize})
+
+	rng := rand.New(rand.NewSource(p.Seed))
+	rng.Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+	return result, nil
+}
+
+// Resolve returns the melody the permalink points to, and the number of melodies matching
+// its parameters. It fails with ErrTruncated if the search could not be completed in time
+// (see Melodies).
+func (p Permalink) Resolve(ctx context.Context) (music.Realization, int, error) {
+	melodies, err := p.Melodies(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(melodies) == 0 {
+		return nil, 0, errors.New("no cantus firmus satisfies the parameters")
+	}
+	if p.Index >= len(melodies) {
+		return nil, len(melodies), fmt.Errorf("index %d out of range: %d melodies", p.Index, len(melodies))
+	}
+	return melodies[p.Index], len(melodies), nil
+}
+
+// joinInts formats integers as a comma-separated list.
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// splitInts parses a comma-separated list of integers.
+func splitInts(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var result []int
+	for _, part := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPermalink_RoundTrip(t *testing.T) {
+	tests := []Permalink{
+		{Length: 10, Mode: music.Dorian, Leaps: []int{2}, Seed: 42, Index: 3},
+		{Length: 8, Mode: music.Minor, Leaps: []int{1, 2}, Degrees: []int{1, 2, 3, 4, 5}, Minor: music.MinorHarmonic, Seed: -7},
+	}
+
+	for _, p := range tests {
+		t.Run(p.Encode(), func(t *testing.T) {
+			q, err := url.ParseQuery(p.Encode())
+			if err != nil {
+				t.Fatalf("Encode() produced an invalid query: %v", err)
+			}
+			got, hasSeed, err := ParsePermalink(q)
+			if err != nil {
+				t.Fatalf("ParsePermalink() unexpected error: %v", err)
+			}
+			if !hasSeed {
+				t.Errorf("ParsePermalink() hasSeed = false, want true")
+			}
+			if got.Length != p.Length || got.Mode != p.Mode || got.Minor != p.Minor || got.Seed != p.Seed ||
+				got.Index != p.Index || !slices.Equal(got.Leaps, p.Leaps) || !slices.Equal(got.Degrees, p.Degrees) {
+				t.Errorf("ParsePermalink(Encode()) = %+v, want %+v", got, p)
+			}
+		})
+	}
+}
+
+func TestParsePermalink_Errors(t *testing.T) {
+	tests := []struct {
+		query       string
+		errContains string
+	}{
+		{"mode=dorian&leaps=2", "invalid length"},
+		{"length=30&mode=dorian&leaps=2", "length must be between"},
+		{"length=10&mode=ionian&leaps=2", "unknown mode"},
+		{"length=10&mode=dorian", "invalid leaps"},
+		{"length=10&mode=dorian&leaps=two", "invalid leaps"},
+		{"length=10&mode=dorian&leaps=2,7", "leaps out of range"},
+		{"length=10&mode=dorian&leaps=-1", "leaps out of range"},
+		{"length=10&mode=dorian&leaps=2&degrees=1,9", "degree out of range"},
+		{"length=10&mode=minor&leaps=2&minor=gypsy", "unknown minor policy"},
+		{"length=10&mode=dorian&leaps=2&seed=x", "invalid seed"},
+		{"length=10&mode=dorian&leaps=2&index=-1", "invalid index"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			_, _, err := ParsePermalink(q)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("ParsePermalink(%q) error = %v, want containing %q", tt.query, err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestPermalink_ResolveIsReproducible(t *testing.T) {
+	p := Permalink{Length: 9, Mode: music.Dorian, Leaps: []int{2}, Seed: 1, Index: 5}
+
+	first, total, err := p.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+	second, _, _ := p.Resolve(context.Background())
+	if !slices.Equal(first, second) {
+		t.Errorf("Resolve() is not reproducible: %v vs %v", first, second)
+	}
+
+	other := p
+	other.Seed = 2
+	if melodies, err := other.Melodies(context.Background()); err != nil || len(melodies) != total {
+		t.Errorf("another seed produced %d melodies, want %d", len(melodies), total)
+	}
+
+	p.Index = total
+	if _, _, err := p.Resolve(context.Background()); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("Resolve() with index %d error = %v, want out of range", total, err)
+	}
+
+	// A search the request no longer waits for is abandoned
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := p.Resolve(ctx); !errors.Is(err, ErrTruncated) {
+		t.Errorf("Resolve() with a canceled context error = %v, want %v", err, ErrTruncated)
+	}
+}
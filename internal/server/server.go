@@ -0,0 +1,187 @@
+// Package server exposes the cantus firmus generator over HTTP.
+// Client applications query GET /capabilities to discover what the server
+// supports (modes, rules, export formats and limits) and build their UI from it,
+// and GET /melody to fetch a generated melody together with a permalink reproducing it.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/cantusgen"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Capabilities describes what this server version supports.
+type Capabilities struct {
+	Modes   []string   `json:"modes"`
+	Rules   []RuleInfo `json:"rules"`
+	Formats []string   `json:"formats"`
+	Limits  Limits     `json:"limits"`
+}
+
+// RuleInfo describes a single rule applied during generation.
+//
+// Fields:
+//   - ID: stable identifier of the rule (see rules.Rule.Name)
+//   - Description: human-readable summary of the rule
+//   - Scope: "partial" for rules checked on incomplete melodies during the search,
+//     "complete" for rules checked on finished melodies, "realization" for rules
+//     checked on realized pitches
+//   - Params: names of the parameters the rule accepts, if any
+type RuleInfo struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Scope       string   `json:"scope"`
+	Params      []string `json:"params,omitempty"`
+}
+
+// Limits describes the accepted ranges of the generation parameters.
+type Limits struct {
+	MinNotes int `json:"min_notes"`
+	MaxNotes int `json:"max_notes"`
+	// MaxLeaps is the maximum number of leaps relative to the number of notes: notes - MaxLeapsOffset
+	// (see cantusgen.MaxLeapsOffset)
+	MaxLeapsOffset int `json:"max_leaps_offset"`
+}
+
+// generatorRules lists the rules applied by the generator, in the order they are checked:
+// the degree restriction, the registered rules (see rules.Registry) and the checks on realized pitches.
+func generatorRules() []RuleInfo {
+	result := []RuleInfo{{
+		ID:          cantusgen.RuleDegrees,
+		Description: "Only the given scale degrees may be used.",
+		Scope:       "partial",
+		Params:      []string{"degrees"},
+	}}
+	for _, r := range rules.Registry() {
+		scope := "complete"
+		if r.Partial {
+			scope = "partial"
+		}
+		result = append(result, RuleInfo{ID: r.Name, Description: r.Description, Scope: scope})
+	}
+	// The scale only affects which notes BeginsAndEndsOnFinal accepts, not its description
+	for _, r := range rules.RealizationRules(music.NewScale(music.Modes()[0])) {
+		result = append(result, RuleInfo{ID: r.Name, Description: r.Description, Scope: "realization"})
+	}
+	return result
+}
+
+// GetCapabilities returns the capabilities of this server version.
+func GetCapabilities() Capabilities {
+	var modes []string
+	for _, mode := range music.Modes() {
+		modes = append(modes, mode.String())
+	}
+
+	return Capabilities{
+		Modes:   modes,
+		Rules:   generatorRules(),
+		Formats: []string{"musicxml", "midi"},
+		Limits: Limits{
+			MinNotes:       cantusgen.MinNotes,
+			MaxNotes:       cantusgen.MaxNotes,
+			MaxLeapsOffset: cantusgen.MaxLeapsOffset,
+		},
+	}
+}
+
+// NewHandler returns the HTTP handler serving the API.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /capabilities", handleCapabilities)
+	mux.HandleFunc("GET /melody", handleMelody)
+	return mux
+}
+
+// NewServer returns a server of the API (see NewHandler) listening on addr, with timeouts
+// allowing the slowest search (see Permalink.Melodies) but no client holding a connection
+// forever.
+func NewServer(addr string) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           NewHandler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      searchTimeout + 5*time.Second,
+		IdleTimeout:       time.Minute,
+	}
+}
+
+// handleCapabilities serves GET /capabilities.
+func handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, GetCapabilities())
+}
+
+// MelodyResponse is the response of GET /melody.
+//
+// Fields:
+//   - Permalink: query string that reproduces this exact melody (see Permalink)
+//   - Notes: the melody as note names (e.g. "D4")
+//   - Intervals: qualities of the melodic intervals (e.g. "m3")
+//   - Total: number of melodies matching the parameters; indexes run from 0 to Total-1
+//   - RulesFingerprint: identifies the rule set the melody satisfies (see rules.RuleSet.Fingerprint)
+type MelodyResponse struct {
+	Permalink        string   `json:"permalink"`
+	Notes            []string `json:"notes"`
+	Intervals        []string `json:"intervals"`
+	Total            int      `json:"total"`
+	RulesFingerprint string   `json:"rules_fingerprint"`
+}
+
+// ErrorResponse is returned with a non-2xx status code.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// handleMelody serves GET /melody. The query parameters form a permalink (see Permalink);
+// without a seed a random one is chosen and returned in the response's permalink.
+func handleMelody(w http.ResponseWriter, r *http.Request) {
+	p, hasSeed, err := ParsePermalink(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !hasSeed {
+		p.Seed = rand.Int63()
+	}
+
+	melody, total, err := p.Resolve(r.Context())
+	if errors.Is(err, ErrTruncated) {
+		writeJSON(w, http.StatusServiceUnavailable, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	_, qualities, err := melody.Intervals()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	notes := make([]string, len(melody))
+	for i, n := range melody {
+		notes[i] = n.String()
+	}
+
+	writeJSON(w, http.StatusOK, MelodyResponse{
+		Permalink:        p.Encode(),
+		Notes:            notes,
+		Intervals:        qualities,
+		Total:            total,
+		RulesFingerprint: p.RuleSet().Fingerprint(),
+	})
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCapabilitiesEndpoint(t *testing.T) {
+	handler := NewHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/capabilities", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /capabilities status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var got Capabilities
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(got.Modes) != 7 || got.Modes[0] != "Major" {
+		t.Errorf("unexpected modes %v", got.Modes)
+	}
+	if len(got.Rules) == 0 {
+		t.Errorf("expected rules in capabilities")
+	}
+	for _, r := range got.Rules {
+		if r.ID == "" || r.Description == "" || r.Scope == "" {
+			t.Errorf("incomplete rule %+v", r)
+		}
+	}
+	if got.Limits.MinNotes != 8 || got.Limits.MaxNotes != 16 || got.Limits.MaxLeapsOffset != 4 {
+		t.Errorf("unexpected limits %+v", got.Limits)
+	}
+	if len(got.Formats) != 2 {
+		t.Errorf("unexpected formats %v", got.Formats)
+	}
+}
+
+func TestCapabilitiesEndpoint_MethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/capabilities", nil)
+	rec := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /capabilities status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestMelodyEndpoint(t *testing.T) {
+	handler := NewHandler()
+
+	get := func(query string) (*httptest.ResponseRecorder, MelodyResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/melody?"+query, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var resp MelodyResponse
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return rec, resp
+	}
+
+	// Without a seed the server picks one and returns a permalink containing it
+	rec, first := get("length=9&mode=dorian&leaps=2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /melody status = %d, body %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(first.Permalink, "seed=") || len(first.Notes) != 9 || len(first.Intervals) != 8 || first.Total == 0 {
+		t.Errorf("unexpected response %+v", first)
+	}
+	if len(first.RulesFingerprint) != 12 {
+		t.Errorf("rules_fingerprint = %q, want a 12-digit fingerprint", first.RulesFingerprint)
+	}
+	if first.Notes[0] != "D4" || first.Notes[8] != "D4" {
+		t.Errorf("Dorian melody should start and end on D4, got %v", first.Notes)
+	}
+
+	// The permalink reproduces the same melody
+	_, second := get(first.Permalink)
+	if second.Permalink != first.Permalink || strings.Join(second.Notes, " ") != strings.Join(first.Notes, " ") {
+		t.Errorf("permalink %s returned %v, want %v", first.Permalink, second.Notes, first.Notes)
+	}
+
+	if rec, _ := get("length=9&mode=dorian"); rec.Code != http.StatusBadRequest {
+		t.Errorf("GET /melody without leaps status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec, _ := get("length=9&mode=dorian&leaps=2&seed=1&index=100000"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /melody with index out of range status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	// A search that cannot complete is reported instead of holding the request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/melody?length=9&mode=dorian&leaps=2", nil).WithContext(ctx)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /melody with a canceled request status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
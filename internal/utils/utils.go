@@ -12,8 +12,10 @@ func Abs(x int) int {
 	return x
 }
 
-// SelectRandomItems selects 'count' random items from a slice using reservoir sampling algorithm
-func SelectRandomItems[T any](items []T, count int) []T {
+// SelectRandomItems selects 'count' random items from a slice using reservoir sampling algorithm.
+// The selection is drawn from rng, so a seeded rng reproduces it; nil uses a source seeded
+// from the global one.
+func SelectRandomItems[T any](items []T, count int, rng *rand.Rand) []T {
 	if count <= 0 || len(items) == 0 {
 		return nil
 	}
@@ -23,11 +25,14 @@ func SelectRandomItems[T any](items []T, count int) []T {
 		return result
 	}
 
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
 	result := make([]T, count)
 	copy(result, items[:count])
 
 	for i := count; i < len(items); i++ {
-		j := rand.Intn(i + 1)
+		j := rng.Intn(i + 1)
 		if j < count {
 			result[j] = items[i]
 		}
@@ -35,3 +40,39 @@ func SelectRandomItems[T any](items []T, count int) []T {
 
 	return result
 }
+
+// SelectDiverseItems selects 'count' items from a slice that differ from each other as much as
+// possible by farthest-point sampling: starting from the first item, it repeatedly picks the
+// item farthest from those already selected, the distance to a set being the distance to the
+// nearest of its items. Ties go to the earlier item, so the selection is deterministic and a
+// ranked slice keeps its best item. The items are returned in the order they were picked.
+func SelectDiverseItems[T any](items []T, count int, distance func(a, b T) int) []T {
+	if count <= 0 || len(items) == 0 {
+		return nil
+	}
+	count = min(count, len(items))
+
+	// nearest[i] is the distance from items[i] to the nearest selected item, -1 once selected
+	nearest := make([]int, len(items))
+	result := make([]T, 0, count)
+	next := 0
+	for len(result) < count {
+		result = append(result, items[next])
+		nearest[next] = -1
+		picked := next
+		for i := range items {
+			if nearest[i] < 0 {
+				continue
+			}
+			d := distance(items[i], items[picked])
+			if len(result) == 1 || d < nearest[i] {
+				nearest[i] = d
+			}
+			if nearest[next] < 0 || nearest[i] > nearest[next] {
+				next = i
+			}
+		}
+	}
+
+	return result
+}
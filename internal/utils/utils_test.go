@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"math/rand"
+	"slices"
 	"testing"
 	"time"
 )
@@ -78,7 +80,7 @@ func TestSelectRandomItems(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := SelectRandomItems(tt.items, tt.count)
+			result := SelectRandomItems(tt.items, tt.count, nil)
 
 			if len(result) != tt.expected {
 				t.Errorf("expected %d items, got %d", tt.expected, len(result))
@@ -113,7 +115,7 @@ func TestSelectRandomItems(t *testing.T) {
 
 		counts := make(map[int]int)
 		for i := 0; i < iterations; i++ {
-			result := SelectRandomItems(items, selectSize)
+			result := SelectRandomItems(items, selectSize, nil)
 			for _, item := range result {
 				counts[item]++
 			}
@@ -132,6 +134,40 @@ func TestSelectRandomItems(t *testing.T) {
 	})
 }
 
+func TestSelectRandomItems_Seeded(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	first := SelectRandomItems(items, 3, rand.New(rand.NewSource(42)))
+	second := SelectRandomItems(items, 3, rand.New(rand.NewSource(42)))
+	if !slices.Equal(first, second) {
+		t.Errorf("the same seed selected %v and %v", first, second)
+	}
+}
+
+func TestSelectDiverseItems(t *testing.T) {
+	distance := func(a, b int) int { return Abs(a - b) }
+	tests := []struct {
+		name  string
+		items []int
+		count int
+		want  []int
+	}{
+		{"spread over a cluster", []int{5, 1, 2, 3, 9, 10}, 3, []int{5, 10, 1}},
+		{"ties go to the earlier item", []int{0, 4, -4, 2}, 2, []int{0, 4}},
+		{"near-duplicates come last", []int{0, 0, 10, 10, 5}, 4, []int{0, 10, 5, 0}},
+		{"all items", []int{3, 1, 2}, 5, []int{3, 1, 2}},
+		{"nothing requested", []int{1, 2}, 0, nil},
+		{"no items", nil, 2, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectDiverseItems(tt.items, tt.count, distance); !slices.Equal(got, tt.want) {
+				t.Errorf("SelectDiverseItems(%v, %d) = %v, want %v", tt.items, tt.count, got, tt.want)
+			}
+		})
+	}
+}
+
 func abs(x float64) float64 {
 	if x < 0 {
 		return -x
@@ -0,0 +1,63 @@
+package cantusgen
+
+import (
+	"fmt"
+	"time"
+)
+
+// deadlineCheckInterval is the number of search nodes visited between two clock reads.
+const deadlineCheckInterval = 256
+
+// GenerateWithBudget searches for cantus firmi satisfying the same conditions as Generate
+// for at most the given time and returns up to limit distinct melodies with the highest
+// scores found so far, best first. A nil score uses DefaultScore. The penalty of the soft
+// rules a melody breaks (see rules.RuleSet.Penalty) is subtracted from its score.
+//
+// The search is the randomized backtracking of GenerateRandom, restarted until the budget
+// expires, so the running time is bounded regardless of how hard the parameters are. It ends
+// early if a restart walks the whole search tree, as it has then found every melody.
+// The result may be empty if no melody was found in time.
+func GenerateWithBudget(n int, opts Options, budget time.Duration, limit int, score ScoreFunc) [][]int {
+	defer opts.Stats.since(time.Now())
+	s := newSearch(n, opts)
+	if s == nil || limit <= 0 {
+		return nil
+	}
+	deadline := time.Now().Add(budget)
+	expired := false
+	// nodes counts the nodes of the current restart, total those of all of them, so that the
+	// clock is read on schedule however small the restarts are
+	total, nodes, attemptBudget := 0, 0, nodeBudget(n)
+	s.order = opts.randomOrder()
+	s.stop = func() bool {
+		nodes++
+		total++
+		if total%deadlineCheckInterval == 0 && time.Now().After(deadline) {
+			expired = true
+		}
+		return expired || nodes > attemptBudget
+	}
+
+	best := newRanking(limit, score, opts.ruleSet())
+	seen := make(map[string]bool)
+
+	for !expired {
+		nodes = 0
+		s.walk(make([]int, 0, n), 0, 0, func(finalSlice []int) bool {
+			key := fmt.Sprint(finalSlice)
+			if seen[key] {
+				return true
+			}
+			seen[key] = true
+
+			best.add(finalSlice)
+			return true
+		})
+		if !expired && nodes <= attemptBudget {
+			// The whole tree was walked
+			break
+		}
+	}
+
+	return best.melodies()
+}
@@ -0,0 +1,91 @@
+package cantusgen
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestGenerateWithBudget(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2, 3}}
+	start := time.Now()
+	result := GenerateWithBudget(10, opts, 100*time.Millisecond, 5, nil)
+	elapsed := time.Since(start)
+
+	if elapsed > time.Second {
+		t.Errorf("GenerateWithBudget() took %s with a budget of 100ms", elapsed)
+	}
+	if len(result) == 0 || len(result) > 5 {
+		t.Fatalf("GenerateWithBudget() returned %d melodies, want 1-5", len(result))
+	}
+
+	seen := make(map[string]bool)
+	for i, seq := range result {
+		if !IsValidCantus(seq, opts) {
+			t.Errorf("melody %d (%v) is not a valid cantus", i, seq)
+		}
+		if seen[fmt.Sprint(seq)] {
+			t.Errorf("melody %d (%v) is returned twice", i, seq)
+		}
+		seen[fmt.Sprint(seq)] = true
+		if i > 0 && DefaultScore(seq) > DefaultScore(result[i-1]) {
+			t.Errorf("melody %d scores higher than melody %d", i, i-1)
+		}
+	}
+}
+
+func TestGenerateWithBudget_CustomScore(t *testing.T) {
+	// Prefer melodies starting with the largest upward interval. The search tree is small
+	// enough for a restart to walk it whole, which ends the search with the true best long
+	// before the budget expires.
+	score := func(intervals []int) float64 { return float64(intervals[0]) }
+	opts := Options{AllowedLeaps: []int{1, 2}, Rand: rand.New(rand.NewSource(1))}
+	start := time.Now()
+	result := GenerateWithBudget(7, opts, 10*time.Second, 3, score)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("GenerateWithBudget() took %s, want it to walk the whole tree and end early", elapsed)
+	}
+
+	want := TopScoring(Generate(7, Options{AllowedLeaps: []int{1, 2}}), 3, score, nil)
+	if len(result) != len(want) {
+		t.Fatalf("GenerateWithBudget() returned %d melodies, want %d", len(result), len(want))
+	}
+	for i, seq := range result {
+		if !IsValidCantus(seq, opts) {
+			t.Errorf("melody %d (%v) is not a valid cantus", i, seq)
+		}
+		if score(seq) != score(want[i]) {
+			t.Errorf("melody %d (%v) scores %v, want %v", i, seq, score(seq), score(want[i]))
+		}
+	}
+}
+
+func TestGenerateWithBudget_SmallTree(t *testing.T) {
+	// The whole search tree has fewer nodes than are visited between two clock reads
+	opts := Options{AllowedLeaps: []int{0, 1}}
+	start := time.Now()
+	result := GenerateWithBudget(3, opts, 100*time.Millisecond, 5, nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("GenerateWithBudget() took %s with a budget of 100ms", elapsed)
+	}
+	if want := len(Generate(3, opts)); len(result) != min(want, 5) {
+		t.Errorf("GenerateWithBudget() returned %d melodies, want %d", len(result), min(want, 5))
+	}
+	// A prefix leaves a small tree of any length
+	opts = Options{AllowedLeaps: []int{2}, Prefix: []int{1, 1, -1, 2, -1, -1, 1}}
+	start = time.Now()
+	GenerateWithBudget(9, opts, 100*time.Millisecond, 5, nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GenerateWithBudget() with a prefix took %s with a budget of 100ms", elapsed)
+	}
+}
+
+func TestGenerateWithBudget_InvalidInput(t *testing.T) {
+	if result := GenerateWithBudget(1, Options{AllowedLeaps: []int{1}}, time.Millisecond, 5, nil); result != nil {
+		t.Errorf("Expected nil result for n=1, got %v", result)
+	}
+	if result := GenerateWithBudget(10, Options{AllowedLeaps: []int{2}}, time.Millisecond, 0, nil); result != nil {
+		t.Errorf("Expected nil result for limit 0, got %v", result)
+	}
+}
@@ -0,0 +1,686 @@
+package cantusgen
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"iter"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// Practical limits on the number of notes in a generated cantus firmus. Melodies of up to
+// MaxNotes notes can be enumerated exhaustively; longer ones, up to MaxLongNotes, have too
+// many solutions to collect and are generated by a Generator with a limit, a timeout or a
+// callback only.
+const (
+	MinNotes     = 8
+	MaxNotes     = 16
+	MaxLongNotes = 24
+)
+
+// MaxLeapsOffset bounds the number of leaps the program and the server ask for: a melody of
+// n notes may have at most n - MaxLeapsOffset leaps.
+const MaxLeapsOffset = 4
+
+var steps = []int{-1, 1}
+
+// defaultRules is the rule set used when Options.Rules is nil
+var defaultRules = rules.DefaultRuleSet()
+
+// incrementalRules returns fresh incremental checkers for the rules (see rules.NewIncremental).
+func incrementalRules(named []rules.Rule) []rules.IncrementalRule {
+	result := make([]rules.IncrementalRule, len(named))
+	for i, r := range named {
+		result[i] = rules.NewIncremental(r)
+	}
+	return result
+}
+
+// validators returns the validation functions of the rules.
+func validators(named []rules.Rule) []rules.ValidationFunc {
+	result := make([]rules.ValidationFunc, len(named))
+	for i, r := range named {
+		result[i] = r.Check
+	}
+	return result
+}
+
+// Options configures cantus firmus generation.
+//
+// Fields:
+//   - AllowedLeaps: allowed numbers of leaps in the cantus firmus (e.g. []int{2,3,4})
+//   - Degrees: scale degrees (1 = tonic, ..., 7) the melody may use; nil allows all degrees.
+//     Degrees are counted from the final, even when the rule set opens elsewhere (see
+//     rules.RuleSet.SetOpening).
+//     Restricting degrees produces gapped-scale (modal subset) melodies, e.g. []int{1, 2, 3, 4, 5}
+//     avoids the 6th and 7th degrees entirely.
+//   - Rules: the rules the melody must satisfy; nil selects all registered rules (see rules.DefaultRuleSet)
+//   - Leaps: the leaps the melody may use, in the order the search tries them, e.g. []int{2, -2, 3, -3, -5, 7, -7}
+//     allows thirds, fourths and octaves both ways and the descending sixth; nil uses the leaps of the
+//     rule set (see rules.RuleSet.SetLeaps and rules.ParseLeaps). Steps are always allowed.
+//   - Ambitus: if set, the window of heights every note must lie in, counted from the final even
+//     when the rule set opens elsewhere, e.g. &Ambitus{Low: -2, High: 7} for a melody staying
+//     between a third below and an octave above the final. Unlike the range rule, it fixes where
+//     the range lies and prunes every beginning leaving the window; nil leaves the notes free.
+//   - RepeatedNotes: if positive, the melody may repeat a note immediately, though not at the start,
+//     up to that many times (see rules.RepeatedNotesRule) even if the rule set forbids repeated
+//     notes; 0 leaves them to the rule set (see rules.RuleSet.SetRepeatedNotes). The rules of the
+//     rule set still apply, so its SingleRepeatedNote rule must be disabled to allow more than one.
+//   - Prefix: the first intervals of the melody, e.g. a student's work in progress, so that only
+//     its valid completions are generated; nil leaves the beginning free
+//   - Cadences: if set, the melody must end with one of these interval patterns, e.g.
+//     [][]int{{-1, -1}, {1, -1}, {-2, 1, -1}} (see rules.EndsWith); nil accepts any ending
+//     of two steps. The last two intervals are always steps, so patterns ending otherwise
+//     match no melody.
+//   - Against: if set, a fixed voice the melody must form first-species counterpoint with, note
+//     against note: consonant vertical intervals, no parallel fifths or octaves and no crossing
+//     (see rules.FirstSpecies), so that a second voice is generated as a cantus. With Mode set,
+//     both voices are realized in it and no vertical interval may be augmented or diminished,
+//     e.g. a tritone (see FixedVoice.Realize and rules.ConsonantVerticals). Only melodies as
+//     long as the voice are generated; nil leaves the melody alone
+//   - Pins: intervals or notes fixed at given positions, e.g. a descending leap as the third interval
+//     or the dominant as the fifth note, for fill-in-the-blank exercises (see rules.Pin)
+//   - Stats: if set, records the candidates tried and rejected by each rule, the melodies found,
+//     the time spent and the memory used during generation (see Stats)
+//   - Mode: if set, the mode the melodies are meant to be realized in. Melodies whose realization
+//     in it breaks rules.IsFreeOfAugmentedDiminished are then pruned during the search, as early
+//     as their beginning allows, instead of being discarded after generation
+//   - Realize: how the melodies are realized in Mode (see music.RealizeOptions); the opening is
+//     always the one of the rule set and the range is ignored, as octaves do not change the
+//     quality of intervals
+//   - Progress: if set, called with the progress of the exhaustive searches (Generate, GenerateSeq
+//     and CountCantus, also through a Generator) every few thousand nodes and once more when
+//     the search is complete, e.g. to show a progress bar. The calls are never concurrent, but may
+//     come from any goroutine.
+//   - Transitions: if set, the randomized searches (GenerateRandom, SampleCantus, GenerateWithBudget)
+//     try the candidate intervals in a random order weighted by it, finding stylistically typical
+//     melodies first (see Transitions and DefaultTransitions); nil tries them in a uniformly
+//     random order
+//   - Rand: the source of randomness of the randomized searches, so that a seeded source makes
+//     GenerateRandom and SampleCantus reproducible (GenerateWithBudget also depends on the time
+//     available); nil uses a source seeded from the global one. It must not be shared by
+//     concurrent searches.
+type Options struct {
+	AllowedLeaps  []int
+	Degrees       []int
+	Rules         *rules.RuleSet
+	Leaps         []int
+	Ambitus       *Ambitus
+	RepeatedNotes int
+	Prefix        []int
+	Cadences      [][]int
+	Against       *FixedVoice
+	Pins          []rules.Pin
+	Stats         *Stats
+	Mode          music.Mode
+	Realize       music.RealizeOptions
+	Progress      func(Progress)
+	Transitions   *Transitions
+	Rand          *rand.Rand
+}
+
+// Ambitus is a window of heights in steps above the final, negative below it (see Options.Ambitus).
+//
+// Fields:
+//   - Low: the height of the lowest note allowed
+//   - High: the height of the highest note allowed
+type Ambitus struct {
+	Low  int
+	High int
+}
+
+// FixedVoice is a voice the melody is written against (see Options.Against).
+//
+// Fields:
+//   - Intervals: the intervals of the voice, one for each interval of the melody
+//   - Offset: the number of steps the first note of the melody lies above the first note of
+//     the voice, negative below it; it must be a unison, fifth or octave, e.g. 4 or -7
+type FixedVoice struct {
+	Intervals []int
+	Offset    int
+}
+
+// Realize realizes the voice in the mode against a melody realized with opts: the voice begins
+// Offset steps below the first note of the melody, opts.Opening steps above the tonic.
+func (v FixedVoice) Realize(mode music.Mode, opts music.RealizeOptions) (music.Realization, error) {
+	cf := make(music.CantusFirmus, len(v.Intervals))
+	for i, val := range v.Intervals {
+		cf[i] = music.Interval(val)
+	}
+	opts.Opening -= v.Offset
+	opts.Range = nil
+	return cf.RealizeWithOptions(mode.String(), opts)
+}
+
+// ruleSet returns the rule set selected by the options.
+func (opts Options) ruleSet() *rules.RuleSet {
+	if opts.Rules != nil {
+		return opts.Rules
+	}
+	return defaultRules
+}
+
+// leaps returns the leaps a melody may use: opts.Leaps if set, or else the leaps of the rule set.
+func (opts Options) leaps() []int {
+	if opts.Leaps != nil {
+		return slices.Clone(opts.Leaps)
+	}
+	return opts.ruleSet().Leaps()
+}
+
+// target returns the sum of the intervals of a complete melody: 0 when it opens and ends on
+// the final, or else the ending minus the opening of the rule set (see rules.RuleSet.SetOpening
+// and rules.RuleSet.SetEnding), e.g. -7 for a melody ending on the final an octave lower.
+func (opts Options) target() int {
+	ruleSet := opts.ruleSet()
+	return ruleSet.Ending() - ruleSet.Opening()
+}
+
+// repeats reports whether a melody may repeat a note immediately: if opts.RepeatedNotes
+// is positive or the rule set allows it (see rules.RuleSet.SetRepeatedNotes).
+func (opts Options) repeats() bool {
+	return opts.RepeatedNotes > 0 || opts.ruleSet().RepeatedNotes()
+}
+
+// rng returns the source of randomness of a randomized search: opts.Rand if set, or else
+// a source seeded from the global one.
+func (opts Options) rng() *rand.Rand {
+	if opts.Rand != nil {
+		return opts.Rand
+	}
+	return rand.New(rand.NewSource(rand.Int63()))
+}
+
+// alphabet returns the intervals a melody may use: steps, the repeated note if the options
+// allow it (see Options.repeats), and the leaps of the options.
+func (opts Options) alphabet() []int {
+	result := slices.Clone(steps)
+	if opts.repeats() {
+		result = append(result, 0)
+	}
+	return append(result, opts.leaps()...)
+}
+
+// partialRules returns the rules checked on every prefix of a melody: the required prefix,
+// the ambitus, the limit on repeated notes, the pins and the degree restriction, if any,
+// followed by the partial rules of the rule set.
+func (opts Options) partialRules() []rules.Rule {
+	var partial []rules.Rule
+	if opts.Prefix != nil {
+		partial = append(partial, rules.Rule{
+			Name:        RulePrefix,
+			Description: fmt.Sprintf("The melody must begin with the intervals %v.", opts.Prefix),
+			Partial:     true,
+			Check:       rules.StartsWith(opts.Prefix),
+		})
+	}
+	opening := opts.ruleSet().Opening()
+	if opts.Ambitus != nil {
+		partial = append(partial, rules.AmbitusRule(opts.Ambitus.Low, opts.Ambitus.High, opening))
+	}
+	if opts.RepeatedNotes > 0 {
+		partial = append(partial, rules.RepeatedNotesRule(opts.RepeatedNotes))
+	}
+	for _, p := range opts.Pins {
+		r := rules.PinRule(p)
+		if p.Note && opening != 0 {
+			r.Check = rules.PinnedDegree(p.Position, fromOpening(p.Values, opening))
+		}
+		partial = append(partial, r)
+	}
+	if opts.Degrees != nil {
+		degrees := fromOpening(opts.Degrees, opening)
+		partial = append(partial, rules.Rule{
+			Name:        RuleDegrees,
+			Partial:     true,
+			Check:       rules.RestrictToDegrees(degrees),
+			Incremental: rules.IncrementalRestrictToDegrees(degrees),
+		})
+	}
+	partial = append(partial, opts.ruleSet().Partial()...)
+	realizationPartial, _ := opts.realizationRules()
+	return append(partial, realizationPartial...)
+}
+
+// fromOpening converts scale degrees into the degrees of the same notes counted from the
+// first note of a melody opening the given number of steps above the final, as the degree
+// rules count them: the final is degree 5 of a melody opening on the fifth.
+func fromOpening(degrees []int, opening int) []int {
+	result := make([]int, len(degrees))
+	for i, d := range degrees {
+		result[i] = ((d-1-opening)%7+7)%7 + 1
+	}
+	return result
+}
+
+// leapCountRules returns the rules enforcing opts.AllowedLeaps, both named RuleLeapCount:
+// a partial rule allowing at most the largest allowed number of leaps, and a complete rule
+// requiring one of the allowed numbers. Both are nil when opts.AllowedLeaps is empty.
+func (opts Options) leapCountRules() (partial, complete []rules.Rule) {
+	if len(opts.AllowedLeaps) == 0 {
+		return nil, nil
+	}
+
+	most := slices.Max(opts.AllowedLeaps)
+	allowed := slices.Clone(opts.AllowedLeaps)
+	partial = []rules.Rule{{
+		Name:        RuleLeapCount,
+		Description: fmt.Sprintf("At most %d leaps.", most),
+		Partial:     true,
+		Local:       true,
+		Check:       rules.MaxLeaps(most),
+		Incremental: rules.IncrementalMaxLeaps(most),
+	}}
+	complete = []rules.Rule{{
+		Name:        RuleLeapCount,
+		Description: fmt.Sprintf("The number of leaps must be one of %v.", allowed),
+		Check: func(intervals []int) bool {
+			// Too many leaps are reported by the partial rule
+			count := rules.CountLeaps(intervals)
+			return count > most || slices.Contains(allowed, count)
+		},
+	}}
+	return partial, complete
+}
+
+// lengthRules returns the partial rules depending on the number n of intervals of the melody:
+// the rule enforcing opts.Cadences, named RuleEnding, and the rule enforcing opts.Against,
+// named RuleCounterpoint, if set. The latter breaks on any note when n is not the length
+// of the fixed voice.
+func (opts Options) lengthRules(n int) []rules.Rule {
+	var partial []rules.Rule
+	if opts.Cadences != nil {
+		partial = append(partial, rules.Rule{
+			Name:        RuleEnding,
+			Description: fmt.Sprintf("The melody must end with one of the intervals %v.", opts.Cadences),
+			Partial:     true,
+			Check:       rules.EndsWith(n, opts.Cadences),
+			Incremental: rules.IncrementalEndsWith(n, opts.Cadences),
+		})
+	}
+	if v := opts.Against; v != nil {
+		r := rules.FirstSpeciesRule(v.Intervals, v.Offset)
+		if len(v.Intervals) != n {
+			r.Check = func([]int) bool { return false }
+			r.Incremental = nil
+		}
+		partial = append(partial, r)
+	}
+	return partial
+}
+
+// completeRules returns the rules checked on finished melodies.
+func (opts Options) completeRules() []rules.Rule {
+	_, realizationComplete := opts.realizationRules()
+	return append(opts.ruleSet().Complete(), realizationComplete...)
+}
+
+// GenerateCantus generates a set of integer slices of length n,
+// satisfying specific contrapuntal and structural conditions:
+//   - The sum of all intervals in the complete slice equals 0 (returns to starting pitch),
+//     unless the rule set opens or ends on another note than the final
+//     (see rules.RuleSet.SetOpening and rules.RuleSet.SetEnding)
+//   - The slice always ends with two step motions (values from {-1, 1})
+//   - All slices adhere to both the partial and the complete rules of the rule set
+//
+// Parameters:
+//   - n: the number of intervals between adjacent pairs of notes in cantus firmus
+//   - allowedLeaps: slice of integers specifying allowed number of leaps (e.g. []int{2,3,4})
+//   - ruleSet: the enabled rules; nil selects all registered rules (see rules.DefaultRuleSet)
+//
+// The function uses recursive backtracking with these optimization strategies:
+//   - Early pruning of invalid partial melodies using the partial rules
+//   - Final validation of complete melodies using the complete rules
+//
+// GenerateCantus is kept for compatibility and enumerates melodies of any length without the
+// safeguards of a Generator; new code should configure a Generator instead.
+func GenerateCantus(n int, allowedLeaps []int, ruleSet *rules.RuleSet) [][]int {
+	return Generate(n, Options{AllowedLeaps: slices.Clone(allowedLeaps), Rules: ruleSet})
+}
+
+// Generate works like GenerateCantus but takes its parameters from opts.
+// When opts.Degrees is set, melodies touching any other degree are pruned
+// during the search in addition to the regular rules.
+// When the rule set has soft rules, the melodies are ranked by their penalty
+// (see rules.RuleSet.Penalty), lowest first; melodies with equal penalties keep
+// the order of the search.
+//
+// The search is split on the first intervals of the melody and the resulting subtrees are
+// explored in parallel by up to GOMAXPROCS workers; the melodies are returned in the same
+// order as a single-threaded search. The Check functions of custom rules must therefore be
+// safe for concurrent use.
+func Generate(n int, opts Options) [][]int {
+	result := generateParallel(n, opts)
+
+	if ruleSet := opts.ruleSet(); len(ruleSet.Soft()) > 0 {
+		rankByPenalty(result, ruleSet)
+	}
+
+	return result
+}
+
+// GenerateSeq works like Generate but yields the melodies one at a time, as the search
+// finds them, instead of accumulating them all in memory. Breaking out of the loop stops
+// the search. The melodies come in the order of the search: they are not ranked by
+// penalty even if the rule set has soft rules. Each iteration runs a new search.
+func GenerateSeq(n int, opts Options) iter.Seq[[]int] {
+	return generateSeq(n, opts, nil)
+}
+
+// generateSeq works like GenerateSeq, stopping the search as soon as stop, if set, returns true.
+func generateSeq(n int, opts Options, stop func() bool) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		s := newSearch(n, opts)
+		if s == nil {
+			return
+		}
+		s.stop = stop
+		defer opts.Stats.since(time.Now())
+		tracker := newProgressTracker(opts.Progress)
+		s.progress, s.weight = tracker.counter(), 1
+		if s.walk([]int{}, 0, 0, yield) {
+			s.progress.flush()
+			tracker.finish()
+		}
+	}
+}
+
+// rankByPenalty sorts the melodies by their penalty under the rule set, lowest first,
+// keeping the order of melodies with equal penalties.
+func rankByPenalty(melodies [][]int, ruleSet *rules.RuleSet) {
+	type ranked struct {
+		intervals []int
+		penalty   float64
+	}
+	ranking := make([]ranked, len(melodies))
+	for i, m := range melodies {
+		ranking[i] = ranked{m, ruleSet.Penalty(m)}
+	}
+	slices.SortStableFunc(ranking, func(a, b ranked) int {
+		return cmp.Compare(a.penalty, b.penalty)
+	})
+	for i, r := range ranking {
+		melodies[i] = r.intervals
+	}
+}
+
+// search holds the state shared by all backtracking strategies of the generator.
+type search struct {
+	n        int
+	leaps    []int
+	repeats  bool
+	maxLeaps int
+	// target is the sum of the intervals of a complete melody (see Options.target)
+	target int
+	// widest is the size of the largest leap, used to prune prefixes that cannot reach the target
+	widest   int
+	partial  []rules.IncrementalRule
+	complete []rules.ValidationFunc
+
+	// names of the partial and complete rules, and the statistics to record their rejections in
+	partialNames  []string
+	completeNames []string
+	stats         *Stats
+
+	// pushed holds, for every interval of the melody being built, the number of partial
+	// rules it was pushed to (see push)
+	pushed []int
+
+	// order, if set, returns the candidate intervals extending the prefix in the order they
+	// should be tried
+	order func(prefix, candidates []int) []int
+	// stop, if set, is consulted at every node and aborts the walk when it returns true
+	stop func() bool
+	// final, if set, receives every complete melody instead of a newly allocated slice
+	final []int
+	// key and summary are the buffers count builds the states of the search in
+	key, summary []byte
+
+	// progress, if set, counts the progress of the walk (see Options.Progress), and weight is
+	// the share of the search tree below the beginning walk is extending
+	progress *progressCounter
+	weight   float64
+}
+
+// newSearch prepares a search for cantus firmi of n intervals.
+// It returns nil if no melody can satisfy the parameters.
+func newSearch(n int, opts Options) *search {
+	if n < 2 {
+		return nil
+	}
+
+	// Only counts leaving room for the final two steps can be reached
+	maxLeaps := -1
+	for _, count := range opts.AllowedLeaps {
+		if count >= 0 && count <= n-2 {
+			maxLeaps = max(maxLeaps, count)
+		}
+	}
+	if maxLeaps < 0 {
+		return nil
+	}
+
+	leapPartial, leapComplete := opts.leapCountRules()
+	partial := append(append(leapPartial, opts.lengthRules(n)...), opts.partialRules()...)
+	complete := append(leapComplete, opts.completeRules()...)
+	// The incremental checkers only see the intervals, so the first note is checked here
+	if !rules.AllRules(nil, validators(partial)) {
+		return nil
+	}
+
+	return &search{
+		n:             n,
+		leaps:         opts.leaps(),
+		repeats:       opts.repeats(),
+		maxLeaps:      maxLeaps,
+		target:        opts.target(),
+		widest:        widest(opts.leaps()),
+		partial:       incrementalRules(partial),
+		complete:      validators(complete),
+		partialNames:  ruleNames(partial),
+		completeNames: ruleNames(complete),
+		stats:         opts.Stats,
+	}
+}
+
+// widest returns the size of the largest of the leaps, or 1 if there are none.
+func widest(leaps []int) int {
+	result := 1
+	for _, leap := range leaps {
+		result = max(result, utils.Abs(leap))
+	}
+	return result
+}
+
+// reachable reports whether a prefix of the given length, sum and number of leaps can still
+// be completed into a melody whose intervals sum to the target: the remaining intervals, at
+// most the remaining number of leaps of which may be leaps, the final two being steps,
+// must be able to cover the distance to it. Pruning the other prefixes loses no melody and
+// keeps long melodies from wandering too far from the final to return.
+func (s *search) reachable(length, sum, leaps int) bool {
+	free := s.n - 2 - length
+	wide := min(free, s.maxLeaps-leaps)
+	return utils.Abs(s.target-sum) <= wide*s.widest+(free-wide)+2
+}
+
+// ruleNames returns the names of the rules.
+func ruleNames(named []rules.Rule) []string {
+	result := make([]string, len(named))
+	for i, r := range named {
+		result[i] = r.Name
+	}
+	return result
+}
+
+// stopped reports whether the search has been stopped (see search.stop).
+func (s *search) stopped() bool {
+	return s.stop != nil && s.stop()
+}
+
+// node records a candidate interval tried in the statistics and the progress, if any.
+func (s *search) node() {
+	s.stats.node()
+	s.progress.node()
+}
+
+// push appends an interval to the melody being built and reports whether the melody
+// still satisfies the partial rules. The rules are checked in order and the first one
+// broken stops the check. Every push must be undone by pop.
+func (s *search) push(interval int) bool {
+	for i, r := range s.partial {
+		if !r.Push(interval) {
+			s.pushed = append(s.pushed, i+1)
+			s.stats.reject(s.partialNames[i])
+			return false
+		}
+	}
+	s.pushed = append(s.pushed, len(s.partial))
+	return true
+}
+
+// accepts reports whether a complete melody satisfies the complete rules,
+// checking them in order until the first one broken.
+func (s *search) accepts(melody []int) bool {
+	for i, check := range s.complete {
+		if !check(melody) {
+			s.stats.reject(s.completeNames[i])
+			return false
+		}
+	}
+	return true
+}
+
+// pop removes the interval appended last by push.
+func (s *search) pop() {
+	count := s.pushed[len(s.pushed)-1]
+	s.pushed = s.pushed[:len(s.pushed)-1]
+	for i := count - 1; i >= 0; i-- {
+		s.partial[i].Pop()
+	}
+}
+
+// candidates returns the intervals that may extend a prefix containing currentLeapsCount leaps:
+// steps first, then the repeated note if allowed, then leaps. walk tries them in that order
+// unless the search has an order of its own.
+func (s *search) candidates(currentLeapsCount int) []int {
+	var result []int
+
+	// Try adding a step (if we can still have steps)
+	if (s.n - 2 - currentLeapsCount) > 0 { // -2 for final two steps
+		result = append(result, steps...)
+		if s.repeats {
+			result = append(result, 0)
+		}
+	}
+
+	// Try adding a leap (if we haven't exceeded allowed leaps)
+	if currentLeapsCount < s.maxLeaps {
+		result = append(result, s.leaps...)
+	}
+	return result
+}
+
+// walk extends currentSlice recursively and calls visit for every valid complete melody.
+// It returns false as soon as visit returns false or the search is stopped.
+func (s *search) walk(currentSlice []int, currentSum int, currentLeapsCount int, visit func([]int) bool) bool {
+	if s.stopped() {
+		return false
+	}
+	// The share of the search tree below currentSlice, set by the caller
+	weight := s.weight
+
+	// When we reach the position where we need to add the final two steps
+	if len(currentSlice) == s.n-2 {
+		endSteps := steps
+		if s.order != nil {
+			endSteps = s.order(currentSlice, steps)
+		}
+
+		for _, end1Val := range endSteps {
+			// Validate the melody with each final step against the partial rules
+			s.node()
+			if !s.push(end1Val) {
+				s.pop()
+				continue
+			}
+			for _, end2Val := range endSteps {
+				if currentSum+end1Val+end2Val != s.target {
+					continue
+				}
+				s.node()
+				ok := s.push(end2Val)
+				s.pop()
+				if !ok {
+					continue
+				}
+
+				finalSlice := s.final
+				if finalSlice == nil {
+					finalSlice = make([]int, s.n)
+				}
+				copy(finalSlice, currentSlice)
+				finalSlice[s.n-2] = end1Val
+				finalSlice[s.n-1] = end2Val
+
+				// Final check for complete melody-specific rules
+				if !s.accepts(finalSlice) {
+					continue
+				}
+				s.stats.melody()
+				s.progress.melody()
+				if !visit(finalSlice) {
+					s.pop()
+					return false
+				}
+			}
+			s.pop()
+		}
+		s.progress.advance(weight)
+		return true
+	}
+
+	candidates := s.candidates(currentLeapsCount)
+	if s.order != nil {
+		candidates = s.order(currentSlice, candidates)
+	}
+	if len(candidates) == 0 {
+		s.progress.advance(weight)
+		return true
+	}
+	share := weight / float64(len(candidates))
+	for _, val := range candidates {
+		nextLeapsCount := currentLeapsCount
+		if utils.Abs(val) > 1 {
+			nextLeapsCount++
+		}
+		if !s.reachable(len(currentSlice)+1, currentSum+val, nextLeapsCount) {
+			s.progress.advance(share)
+			continue
+		}
+
+		// Validate the extended melody against the partial rules;
+		// a rejected candidate still counts as a visited node
+		s.node()
+		if !s.push(val) {
+			s.pop()
+			s.progress.advance(share)
+			if s.stopped() {
+				return false
+			}
+			continue
+		}
+		nextSlice := append(currentSlice, val)
+		s.weight = share
+		if !s.walk(nextSlice, currentSum+val, nextLeapsCount, visit) {
+			s.pop()
+			return false
+		}
+		s.pop()
+	}
+
+	return true
+}
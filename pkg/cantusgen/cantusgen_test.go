@@ -0,0 +1,588 @@
+package cantusgen
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"slices"
+	"testing"
+)
+
+func TestGenerateCantus_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name         string
+		n            int
+		allowedLeaps []int
+	}{
+		{"n less than 2", 1, []int{2, 3}},
+		{"no allowed leaps", 10, []int{}},
+		{"all leap counts too large", 10, []int{9, 10}}, // only 8 possible leaps max (n-2)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GenerateCantus(tt.n, tt.allowedLeaps, nil)
+			if result != nil {
+				t.Errorf("Expected nil result for n=%d, allowedLeaps=%v, got %v", tt.n, tt.allowedLeaps, result)
+			}
+		})
+	}
+}
+
+func TestGenerateCantus_ValidInput(t *testing.T) {
+	tests := []struct {
+		name         string
+		n            int
+		allowedLeaps []int
+	}{
+		{"n=5 with 1-2 leaps", 5, []int{1, 2}},
+		{"n=8 with 2-3 leaps", 8, []int{2, 3}},
+		{"n=10 with 2-4 leaps", 10, []int{2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GenerateCantus(tt.n, tt.allowedLeaps, nil)
+			if result == nil {
+				t.Fatalf("Expected non-nil result for n=%d, allowedLeaps=%v", tt.n, tt.allowedLeaps)
+			}
+
+			for _, sequence := range result {
+				// Check sequence length
+				if len(sequence) != tt.n {
+					t.Errorf("Expected sequence length %d, got %d", tt.n, len(sequence))
+				}
+
+				// Check sum of elements
+				sum := 0
+				for _, val := range sequence {
+					sum += val
+				}
+				if sum != 0 {
+					t.Errorf("Expected sum 0, got %d for sequence %v", sum, sequence)
+				}
+
+				// Verify last two elements are from steps
+				if !contains(steps, sequence[tt.n-2]) || !contains(steps, sequence[tt.n-1]) {
+					t.Errorf("Last two elements must be from steps, got %v", sequence[tt.n-2:])
+				}
+
+				// Count steps and leaps (excluding last two steps)
+				stepsCount := 0
+				leapsCount := 0
+				for i := 0; i < tt.n-2; i++ {
+					if contains(steps, sequence[i]) {
+						stepsCount++
+					} else if contains(rules.DefaultRuleSet().Leaps(), sequence[i]) {
+						leapsCount++
+					} else {
+						t.Errorf("Unexpected value %d in sequence %v", sequence[i], sequence)
+					}
+				}
+
+				// Verify leap count is in allowedLeaps
+				if !contains(tt.allowedLeaps, leapsCount) {
+					t.Errorf("Leap count %d not in allowedLeaps %v for sequence %v", leapsCount, tt.allowedLeaps, sequence)
+				}
+
+				// Verify last two elements are steps
+				if !contains(steps, sequence[tt.n-2]) || !contains(steps, sequence[tt.n-1]) {
+					t.Errorf("Last two elements must be steps, got %v", sequence[tt.n-2:])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateCantusContainsSpecificSlice(t *testing.T) {
+	n := 10
+	allowedLeaps := []int{2, 3, 4}
+	targets := [][]int{
+		{2, -1, -1, 3, -1, 2, -1, -1, -1, -1}, // cantus by Johann Joseph Fux
+		{1, 2, -1, 1, 1, 1, -1, -2, -1, -1},   // Schenker
+	}
+
+	result := GenerateCantus(n, allowedLeaps, nil)
+
+	for _, target := range targets {
+		found := false
+		for _, slice := range result {
+			if equalSlices(slice, target) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("Expected slice %v not found in GenerateCantus(%d, %v) result", target, n, allowedLeaps)
+		}
+	}
+}
+
+func TestGenerate_RestrictedDegrees(t *testing.T) {
+	n := 10
+	degrees := []int{1, 2, 3, 4, 5}
+	result := Generate(n, Options{AllowedLeaps: []int{2, 3}, Degrees: degrees})
+	if len(result) == 0 {
+		t.Fatalf("Expected gapped-scale melodies for n=%d, degrees=%v", n, degrees)
+	}
+
+	for _, sequence := range result {
+		height := 0
+		for _, val := range sequence {
+			height += val
+			degree := (height%7+7)%7 + 1
+			if !contains(degrees, degree) {
+				t.Errorf("Sequence %v reaches degree %d outside %v", sequence, degree, degrees)
+				break
+			}
+		}
+	}
+
+	full := GenerateCantus(n, []int{2, 3}, nil)
+	if len(result) >= len(full) {
+		t.Errorf("Expected fewer melodies with restricted degrees, got %d (unrestricted %d)", len(result), len(full))
+	}
+}
+
+func TestGenerateCantus_RuleSet(t *testing.T) {
+	n := 9
+	allowedLeaps := []int{2}
+	strict := GenerateCantus(n, allowedLeaps, nil)
+
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.Disable("ValidateClimax"); err != nil {
+		t.Fatal(err)
+	}
+	relaxed := GenerateCantus(n, allowedLeaps, ruleSet)
+	if len(relaxed) <= len(strict) {
+		t.Fatalf("Expected more melodies without ValidateClimax, got %d (strict %d)", len(relaxed), len(strict))
+	}
+
+	opts := Options{AllowedLeaps: allowedLeaps, Rules: ruleSet}
+	for _, sequence := range relaxed {
+		if !IsValidCantus(sequence, opts) {
+			t.Errorf("Sequence %v is invalid under the rule set it was generated with", sequence)
+		}
+	}
+	if !slices.ContainsFunc(relaxed, func(s []int) bool { return !rules.ValidateClimax(s) }) {
+		t.Error("Expected melodies violating the disabled rule")
+	}
+}
+
+func TestGenerate_Preset(t *testing.T) {
+	jeppesen, err := rules.Preset(rules.PresetJeppesen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{AllowedLeaps: []int{2, 3}, Rules: jeppesen}
+	result := Generate(10, opts)
+	if !slices.ContainsFunc(result, func(s []int) bool { return slices.Contains(s, 7) || slices.Contains(s, -7) }) {
+		t.Error("Expected melodies with an octave leap from the Jeppesen preset")
+	}
+	for _, sequence := range result {
+		if !IsValidCantus(sequence, opts) {
+			t.Errorf("Sequence %v is invalid under the preset it was generated with", sequence)
+		}
+	}
+}
+
+func TestGenerate_SoftRules(t *testing.T) {
+	n := 9
+	allowedLeaps := []int{2}
+	strict := Generate(n, Options{AllowedLeaps: allowedLeaps})
+
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.SetSoft("ValidateClimax", 1); err != nil {
+		t.Fatal(err)
+	}
+	ranked := Generate(n, Options{AllowedLeaps: allowedLeaps, Rules: ruleSet})
+	if len(ranked) <= len(strict) {
+		t.Fatalf("Expected more melodies with a soft rule, got %d (strict %d)", len(ranked), len(strict))
+	}
+
+	// Melodies satisfying the soft rule come first, in the order of the strict search
+	for i, sequence := range ranked {
+		want := 0.0
+		if i >= len(strict) {
+			want = 1
+		} else if !slices.Equal(sequence, strict[i]) {
+			t.Errorf("Melody %d is %v, want %v", i, sequence, strict[i])
+		}
+		if got := ruleSet.Penalty(sequence); got != want {
+			t.Errorf("Melody %d %v has penalty %g, want %g", i, sequence, got, want)
+		}
+	}
+}
+
+func TestGenerate_MaxRange(t *testing.T) {
+	n := 10
+	allowedLeaps := []int{2, 3}
+	sixth := 5
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.SetMaxRange(sixth); err != nil {
+		t.Fatal(err)
+	}
+
+	narrow := Generate(n, Options{AllowedLeaps: allowedLeaps, Rules: ruleSet})
+	if len(narrow) == 0 {
+		t.Fatal("Expected melodies within a sixth")
+	}
+	for _, sequence := range narrow {
+		heights := music.PartialSums(sequence)
+		if slices.Max(heights)-slices.Min(heights) > sixth {
+			t.Errorf("Sequence %v exceeds a sixth", sequence)
+		}
+	}
+
+	if full := Generate(n, Options{AllowedLeaps: allowedLeaps}); len(narrow) >= len(full) {
+		t.Errorf("Expected fewer melodies within a sixth, got %d (tenth %d)", len(narrow), len(full))
+	}
+}
+
+func TestGenerate_Cadence(t *testing.T) {
+	n := 9
+	allowedLeaps := []int{2}
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.SetCadence(rules.CadenceSupertonic); err != nil {
+		t.Fatal(err)
+	}
+
+	fromAbove := Generate(n, Options{AllowedLeaps: allowedLeaps, Rules: ruleSet})
+	if len(fromAbove) == 0 {
+		t.Fatal("Expected melodies ending 2-1")
+	}
+	for _, sequence := range fromAbove {
+		if sequence[n-1] != -1 {
+			t.Errorf("Sequence %v does not approach the final from above", sequence)
+		}
+	}
+
+	if either := Generate(n, Options{AllowedLeaps: allowedLeaps}); len(fromAbove) >= len(either) {
+		t.Errorf("Expected fewer melodies ending 2-1, got %d (either %d)", len(fromAbove), len(either))
+	}
+}
+
+func TestGenerate_RepeatedNotes(t *testing.T) {
+	n := 9
+	allowedLeaps := []int{2}
+	ruleSet := rules.DefaultRuleSet()
+	ruleSet.SetRepeatedNotes(true)
+	opts := Options{AllowedLeaps: allowedLeaps, Rules: ruleSet}
+
+	result := Generate(n, opts)
+	withRepeat := 0
+	for _, sequence := range result {
+		repeats := 0
+		for i, interval := range sequence {
+			if interval == 0 {
+				repeats++
+				if i == 0 || i >= n-2 {
+					t.Errorf("Sequence %v repeats a note at the start or end", sequence)
+				}
+			}
+		}
+		if repeats > 1 {
+			t.Errorf("Sequence %v repeats more than one note", sequence)
+		}
+		if repeats == 1 {
+			withRepeat++
+		}
+		if !IsValidCantus(sequence, opts) {
+			t.Errorf("IsValidCantus(%v) = false for a generated sequence", sequence)
+		}
+		if rules.CountLeaps(sequence) != 2 {
+			t.Errorf("Sequence %v does not have 2 leaps", sequence)
+		}
+	}
+	if withRepeat == 0 {
+		t.Error("Expected melodies with a repeated note")
+	}
+
+	strict := Generate(n, Options{AllowedLeaps: allowedLeaps})
+	if len(result)-withRepeat != len(strict) {
+		t.Errorf("Expected the %d melodies without repeated notes, got %d", len(strict), len(result)-withRepeat)
+	}
+	if IsValidCantus(result[slices.IndexFunc(result, func(s []int) bool { return slices.Contains(s, 0) })], Options{AllowedLeaps: allowedLeaps}) {
+		t.Error("Repeated notes must be invalid unless the rule set allows them")
+	}
+}
+
+func TestGenerate_Leaps(t *testing.T) {
+	// Thirds and octaves both ways, and the descending sixth; no fourths or fifths
+	leaps := []int{2, -2, -5, 7, -7}
+	opts := Options{AllowedLeaps: []int{2}, Leaps: leaps}
+	result := Generate(11, opts)
+	if len(result) == 0 {
+		t.Fatal("Expected melodies with the given leaps")
+	}
+
+	used := make(map[int]bool)
+	for _, sequence := range result {
+		for _, val := range sequence {
+			if val != 1 && val != -1 && !slices.Contains(leaps, val) {
+				t.Fatalf("Sequence %v uses the interval %d", sequence, val)
+			}
+			used[val] = true
+		}
+		if !IsValidCantus(sequence, opts) {
+			t.Errorf("Sequence %v is invalid under the options it was generated with", sequence)
+		}
+	}
+	if !used[7] {
+		t.Errorf("Expected melodies with ascending octaves, used %v", used)
+	}
+	if IsValidCantus([]int{1, 1, 3, -1, -1, -1, -1, -1, 1, -1}, opts) {
+		t.Error("A fourth must not be valid when the leaps exclude it")
+	}
+}
+
+func TestGenerate_Prefix(t *testing.T) {
+	all := Generate(9, Options{AllowedLeaps: []int{2}})
+	prefix := slices.Clone(all[len(all)/2][:4])
+
+	var want [][]int
+	for _, sequence := range all {
+		if slices.Equal(sequence[:4], prefix) {
+			want = append(want, sequence)
+		}
+	}
+	opts := Options{AllowedLeaps: []int{2}, Prefix: prefix}
+	if got := Generate(9, opts); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate with prefix %v = %v, want %v", prefix, got, want)
+	}
+	if got := CountCantus(9, opts); got != len(want) {
+		t.Errorf("CountCantus with prefix %v = %d, want %d", prefix, got, len(want))
+	}
+	if melody := GenerateRandom(9, opts); melody == nil || !slices.Equal(melody[:4], prefix) {
+		t.Errorf("GenerateRandom with prefix %v = %v", prefix, melody)
+	}
+
+	// A prefix breaking the rules has no completion
+	if got := Generate(9, Options{AllowedLeaps: []int{2}, Prefix: []int{5, 5}}); got != nil {
+		t.Errorf("Generate with an invalid prefix = %v, want nil", got)
+	}
+}
+
+func TestGenerate_Pins(t *testing.T) {
+	pins := []rules.Pin{
+		{Position: 3, Values: []int{-2, -3, -4, -5}},
+		{Note: true, Position: 6, Values: []int{5}},
+	}
+	opts := Options{AllowedLeaps: []int{2, 3}, Pins: pins}
+	result := Generate(9, opts)
+	if len(result) == 0 {
+		t.Fatal("Expected melodies with a descending leap as the third interval and the dominant as the sixth note")
+	}
+	for _, sequence := range result {
+		height := sequence[0] + sequence[1] + sequence[2] + sequence[3] + sequence[4]
+		if sequence[2] > -2 || (height%7+7)%7 != 4 {
+			t.Errorf("Sequence %v does not respect the pins", sequence)
+		}
+	}
+	if all := Generate(9, Options{AllowedLeaps: []int{2, 3}}); len(result) >= len(all) {
+		t.Errorf("Expected the pins to reject melodies, got %d (without them %d)", len(result), len(all))
+	}
+	if IsValidCantus([]int{1, 1, -1, 2, -1, 1, -1, -1, -1}, opts) {
+		t.Error("A melody breaking the pins must not be valid")
+	}
+}
+
+func TestGenerate_Opening(t *testing.T) {
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.SetOpening(4); err != nil {
+		t.Fatal(err)
+	}
+	degrees := []int{1, 2, 3, 4, 5, 6}
+	pins := []rules.Pin{{Note: true, Position: 4, Values: []int{4}}}
+	opts := Options{AllowedLeaps: []int{2, 3}, Rules: ruleSet, Degrees: degrees, Pins: pins}
+	result := Generate(9, opts)
+	if len(result) == 0 {
+		t.Fatal("Expected melodies opening on the fifth")
+	}
+	for _, sequence := range result {
+		// Heights above the final
+		height := 4
+		for i, val := range sequence {
+			height += val
+			if degree := (height%7+7)%7 + 1; !contains(degrees, degree) || (i == 2 && degree != 4) {
+				t.Errorf("Sequence %v reaches degree %d at note %d", sequence, degree, i+2)
+				break
+			}
+		}
+		if height != 0 {
+			t.Errorf("Sequence %v ends %d steps above the final", sequence, height)
+		}
+		if !IsValidCantus(sequence, opts) {
+			t.Errorf("Generated sequence %v is not valid", sequence)
+		}
+	}
+
+	if IsValidCantus([]int{1, 1, -1, 2, -1, 1, -1, -1, -1}, Options{AllowedLeaps: []int{1}, Rules: ruleSet}) {
+		t.Error("A melody returning to its first note must not be valid when opening on the fifth")
+	}
+	if got := Violations([]int{-1, -1, -1, -1}, Options{Rules: ruleSet}); slices.Contains(got, RuleReturnToFinal) {
+		t.Errorf("Violations() = %v for a descent from the fifth to the final, want no %s", got, RuleReturnToFinal)
+	}
+}
+
+func TestGenerate_Ending(t *testing.T) {
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.SetEnding(-7); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{AllowedLeaps: []int{3}, Rules: ruleSet}
+	result := Generate(10, opts)
+	if len(result) == 0 {
+		t.Fatal("Expected melodies ending on the final an octave lower")
+	}
+	for _, sequence := range result {
+		sum := 0
+		for _, val := range sequence {
+			sum += val
+		}
+		if sum != -7 || !contains(steps, sequence[9]) || !contains(steps, sequence[8]) {
+			t.Errorf("Sequence %v does not close by step on the lower final", sequence)
+		}
+	}
+	if IsValidCantus([]int{1, 1, -1, 2, -1, 1, -1, -1, -1}, opts) {
+		t.Error("A melody returning to its first note must not be valid when ending an octave lower")
+	}
+	if got := CountCantus(10, opts); got != len(result) {
+		t.Errorf("CountCantus() = %d, want %d", got, len(result))
+	}
+}
+
+func TestGenerate_CustomRule(t *testing.T) {
+	n := 9
+	allowedLeaps := []int{2}
+	ruleSet := rules.DefaultRuleSet()
+	noFourths := func(intervals []int) bool {
+		return !slices.Contains(intervals, 3) && !slices.Contains(intervals, -3)
+	}
+	if err := ruleSet.Add(rules.Rule{Name: "NoFourths", Partial: true, Local: true, Check: noFourths}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := Generate(n, Options{AllowedLeaps: allowedLeaps, Rules: ruleSet})
+	if len(result) == 0 {
+		t.Fatal("Expected melodies without fourths")
+	}
+	for _, sequence := range result {
+		if !noFourths(sequence) {
+			t.Errorf("Sequence %v breaks the custom rule", sequence)
+		}
+	}
+	if all := Generate(n, Options{AllowedLeaps: allowedLeaps}); len(result) >= len(all) {
+		t.Errorf("Expected the custom rule to reject melodies, got %d (without it %d)", len(result), len(all))
+	}
+}
+
+func TestGenerate_Mode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    music.Mode
+		realize music.RealizeOptions
+	}{
+		{"lydian", music.Lydian, music.RealizeOptions{}},
+		{"locrian", music.Locrian, music.RealizeOptions{}},
+		{"melodic minor", music.Minor, music.RealizeOptions{Minor: music.MinorMelodic}},
+		{"harmonic minor", music.Minor, music.RealizeOptions{Minor: music.MinorHarmonic}},
+	}
+
+	all := len(Generate(10, Options{AllowedLeaps: []int{3}}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{AllowedLeaps: []int{3}}
+			// Pruning during the search keeps exactly the melodies a filter after it would keep
+			var want [][]int
+			for _, sequence := range Generate(10, opts) {
+				cf := make(music.CantusFirmus, len(sequence))
+				for i, val := range sequence {
+					cf[i] = music.Interval(val)
+				}
+				r, err := cf.RealizeWithOptions(tt.mode.String(), tt.realize)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if rules.IsFreeOfAugmentedDiminished(r) {
+					want = append(want, sequence)
+				}
+			}
+
+			opts.Mode, opts.Realize = tt.mode, tt.realize
+			got := Generate(10, opts)
+			if len(got) == 0 || !slices.EqualFunc(got, want, slices.Equal) {
+				t.Errorf("Generate() in %s returned %d melodies, want the %d free of augmented and diminished intervals",
+					tt.mode, len(got), len(want))
+			}
+			if len(want) == all {
+				t.Errorf("no melody of %d has augmented or diminished intervals in %s", all, tt.mode)
+			}
+		})
+	}
+}
+
+func TestCheck_Mode(t *testing.T) {
+	// F–B–A–G–F... in lydian leaps an augmented fourth without stepwise motion around it
+	intervals := []int{3, -1, -1, -1, 1, 1, -1, -1}
+	opts := Options{AllowedLeaps: []int{1}, Mode: music.Lydian}
+	if !slices.Contains(Violations(intervals, opts), RuleAugmentedDiminished) {
+		t.Errorf("Violations(%v) in lydian = %v, want %s", intervals, Violations(intervals, opts), RuleAugmentedDiminished)
+	}
+	opts.Mode = music.Major
+	if slices.Contains(Violations(intervals, opts), RuleAugmentedDiminished) {
+		t.Errorf("Violations(%v) in major reports %s", intervals, RuleAugmentedDiminished)
+	}
+}
+
+func TestGenerateSeq(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2}}
+	all := Generate(9, opts)
+
+	var streamed [][]int
+	for melody := range GenerateSeq(9, opts) {
+		streamed = append(streamed, melody)
+	}
+	if !slices.EqualFunc(streamed, all, slices.Equal) {
+		t.Fatalf("GenerateSeq yielded %d melodies, want the %d of Generate in the same order", len(streamed), len(all))
+	}
+
+	// Breaking out of the loop stops the search
+	count := 0
+	for range GenerateSeq(9, opts) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("stopped after %d melodies, want 3", count)
+	}
+
+	for range GenerateSeq(1, opts) {
+		t.Fatal("GenerateSeq(1) must yield nothing")
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Generate(12, Options{AllowedLeaps: []int{2, 3, 4}})
+	}
+}
+
+// Helper function to check if a value exists in a slice
+func contains(slice []int, val int) bool {
+	return slices.Contains(slice, val)
+}
+
+func equalSlices(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
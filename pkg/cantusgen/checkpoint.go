@@ -0,0 +1,152 @@
+package cantusgen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/codec"
+	"io"
+	"slices"
+	"sync"
+)
+
+// checkpointDepth is the number of leading intervals a resumable search splits the search
+// tree on: finer than parallelDepth, so that an interruption loses less work.
+const checkpointDepth = 4
+
+// ErrInterrupted is returned by Checkpoint.Resume when the search is stopped before it is complete.
+var ErrInterrupted = errors.New("search interrupted")
+
+// Checkpoint records the progress of a resumable search for all the melodies of Generate:
+// the parameters of the search, and the melodies of every part of the search tree explored
+// so far, kept in the compact encoding of package codec. A search interrupted by Resume can
+// be saved (see Checkpoint.Save), loaded later (see LoadCheckpoint) and resumed with the same
+// parameters, returning the same melodies as an uninterrupted one:
+//
+//	cp := cantusgen.NewCheckpoint(n, opts)
+//	melodies, err := cp.Resume(opts, stop)
+//	if errors.Is(err, cantusgen.ErrInterrupted) {
+//		err = cp.Save(file)
+//	}
+//
+// The methods of a Checkpoint are safe for concurrent use, so that it can be saved while
+// the search runs.
+type Checkpoint struct {
+	mu        sync.Mutex
+	intervals int
+	options   string
+	explored  map[int][]byte
+}
+
+// checkpointFile is the JSON form of a Checkpoint. The melodies of every explored part of
+// the search tree are encoded with codec.EncodeAll (base64 in JSON).
+type checkpointFile struct {
+	Intervals int            `json:"intervals"`
+	Options   string         `json:"options"`
+	Explored  map[int][]byte `json:"explored"`
+}
+
+// NewCheckpoint returns the checkpoint of a search for cantus firmi of n intervals with the
+// given options that has not started yet.
+func NewCheckpoint(n int, opts Options) *Checkpoint {
+	return &Checkpoint{intervals: n, options: opts.fingerprint(n), explored: map[int][]byte{}}
+}
+
+// LoadCheckpoint reads a checkpoint written by Checkpoint.Save.
+func LoadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	var file checkpointFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if file.Explored == nil {
+		file.Explored = map[int][]byte{}
+	}
+	for tree, melodies := range file.Explored {
+		if _, err := codec.DecodeAll(melodies); err != nil {
+			return nil, fmt.Errorf("invalid checkpoint: part %d: %w", tree, err)
+		}
+	}
+	return &Checkpoint{intervals: file.Intervals, options: file.Options, explored: file.Explored}, nil
+}
+
+// Save writes the checkpoint as JSON.
+func (cp *Checkpoint) Save(w io.Writer) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return json.NewEncoder(w).Encode(checkpointFile{Intervals: cp.intervals, Options: cp.options, Explored: cp.explored})
+}
+
+// Resume continues the search where the checkpoint left it, in parallel like Generate,
+// recording every part of the search tree in the checkpoint as soon as it is explored.
+// Once the whole tree is explored, it returns the melodies of Generate for the parameters
+// of the checkpoint, in the same order. If stop is closed first, the search stops and
+// Resume returns ErrInterrupted; the parts being explored are explored again on the next
+// Resume. opts must select the same melodies as the options the checkpoint was created
+// with, but may report the progress or count the rejections of the resumed search.
+func (cp *Checkpoint) Resume(opts Options, stop <-chan struct{}) ([][]int, error) {
+	if opts.fingerprint(cp.intervals) != cp.options {
+		return nil, errors.New("the options differ from those of the checkpoint")
+	}
+
+	trees := splitSearchAt(cp.intervals, opts, checkpointDepth)
+	var pending []int
+	cp.mu.Lock()
+	for i := range trees {
+		if _, ok := cp.explored[i]; !ok {
+			pending = append(pending, i)
+		}
+	}
+	cp.mu.Unlock()
+
+	pendingTrees := make([]subtree, len(pending))
+	for i, tree := range pending {
+		pendingTrees[i] = trees[tree]
+	}
+	found := make([][][]int, len(pending))
+	stopped := func() bool {
+		select {
+		case <-stop:
+			return true
+		default:
+			return false
+		}
+	}
+	complete := exploreTrees(cp.intervals, opts, pendingTrees, false, stopped, func(i int, melody []int) {
+		found[i] = append(found[i], melody)
+	}, func(i int) {
+		encoded := codec.EncodeAll(found[i])
+		found[i] = nil
+		cp.mu.Lock()
+		defer cp.mu.Unlock()
+		cp.explored[pending[i]] = encoded
+	})
+	if !complete {
+		return nil, ErrInterrupted
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	result := make([][][]int, len(trees))
+	for i := range trees {
+		melodies, err := codec.DecodeAll(cp.explored[i])
+		if err != nil {
+			return nil, fmt.Errorf("part %d of the checkpoint: %w", i, err)
+		}
+		result[i] = melodies
+	}
+	melodies := slices.Concat(result...)
+	if ruleSet := opts.ruleSet(); len(ruleSet.Soft()) > 0 {
+		rankByPenalty(melodies, ruleSet)
+	}
+	return melodies, nil
+}
+
+// fingerprint identifies the melodies of a search for cantus firmi of n intervals with the
+// options: it covers every option selecting them, but neither the statistics, the progress
+// reports nor the source of randomness.
+func (opts Options) fingerprint(n int) string {
+	realize := opts.Realize
+	realize.Range = nil
+	return fmt.Sprintf("%d %v %v %v %+v %d %v %v %+v %+v %v %+v %s", n, opts.AllowedLeaps, opts.Degrees, opts.leaps(),
+		opts.Ambitus, opts.RepeatedNotes, opts.Prefix, opts.Cadences, opts.Against, opts.Pins, opts.Mode, realize, opts.ruleSet().Fingerprint())
+}
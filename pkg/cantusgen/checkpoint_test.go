@@ -0,0 +1,75 @@
+package cantusgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCheckpoint_Resume(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2, 3, 4}}
+	want := Generate(11, opts)
+
+	got, err := NewCheckpoint(11, opts).Resume(opts, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Resume returned %d melodies differing from the %d of Generate", len(got), len(want))
+	}
+}
+
+func TestCheckpoint_Interrupted(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2, 3, 4}}
+	want := Generate(11, opts)
+
+	// Stop the search at its first progress report, saving and loading the checkpoint
+	cp := NewCheckpoint(11, opts)
+	stop := make(chan struct{})
+	var once sync.Once
+	interrupted := opts
+	interrupted.Progress = func(Progress) {
+		once.Do(func() { close(stop) })
+	}
+	if _, err := cp.Resume(interrupted, stop); !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("Resume after stop = %v, want ErrInterrupted", err)
+	}
+	var buf bytes.Buffer
+	if err := cp.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCheckpoint(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loaded.Resume(opts, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("resumed search returned %d melodies differing from the %d of Generate", len(got), len(want))
+	}
+}
+
+func TestCheckpoint_Errors(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2}}
+	cp := NewCheckpoint(9, opts)
+
+	if _, err := cp.Resume(Options{AllowedLeaps: []int{3}}, nil); err == nil {
+		t.Error("Resume with other options succeeded")
+	}
+	stop := make(chan struct{})
+	close(stop)
+	if _, err := cp.Resume(opts, stop); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("Resume with stop closed = %v, want ErrInterrupted", err)
+	}
+	if _, err := LoadCheckpoint(bytes.NewBufferString("not json")); err == nil {
+		t.Error("LoadCheckpoint of invalid data succeeded")
+	}
+	if _, err := LoadCheckpoint(bytes.NewBufferString(`{"intervals": 9, "explored": {"0": "AAAA"}}`)); err == nil {
+		t.Error("LoadCheckpoint of invalid melodies succeeded")
+	}
+}
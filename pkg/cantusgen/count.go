@@ -0,0 +1,124 @@
+package cantusgen
+
+import (
+	"encoding/binary"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+)
+
+// CountCantus returns the number of melodies Generate would return for the same parameters,
+// without materializing them: the search runs in parallel like Generate's, but every worker
+// writes the melodies it finds into a single reused slice and only counts them. Use it to
+// check whether parameters are feasible, or to gather statistics on the search (see
+// Options.Stats), when the melodies themselves are not needed.
+//
+// When the rules permit, the melodies are counted by dynamic programming instead, orders of
+// magnitude faster for long melodies: every beginning of a melody is summarized by its length,
+// its last note, its number of leaps and the state of every partial rule (see
+// rules.Summarizer), and the beginnings with equal summaries, which have the same number of
+// completions, are only extended once. This requires every partial rule to summarize its
+// state, no complete rule besides the allowed numbers of leaps, no statistics and no progress
+// reports; the default rule set, whose complete rules need whole melodies, is counted by the
+// search.
+func CountCantus(n int, opts Options) int {
+	if s := newSearch(n, opts); s != nil && s.summarizable(opts) {
+		s.final = make([]int, n)
+		return s.count(make([]int, 0, n), 0, 0, map[string]int{})
+	}
+
+	trees := splitSearch(n, opts)
+	counts := make([]int, len(trees))
+	exploreParallel(n, opts, trees, true, func(i int, _ []int) {
+		counts[i]++
+	})
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+// summarizable reports whether the melodies of the search can be counted by count: every
+// partial rule summarizes its state, the only complete rule is the one on the number of leaps,
+// which the state includes, and neither rejections nor progress are to be recorded.
+func (s *search) summarizable(opts Options) bool {
+	if opts.Stats != nil || opts.Progress != nil || len(opts.completeRules()) > 0 {
+		return false
+	}
+	for _, r := range s.partial {
+		if _, ok := r.(rules.Summarizer); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// count returns the number of valid complete melodies extending currentSlice, like walk,
+// recording the number for the state of the search in memo (see state) and looking it up
+// rather than extending another beginning in the same state.
+func (s *search) count(currentSlice []int, currentSum, currentLeapsCount int, memo map[string]int) int {
+	s.key = s.state(s.key[:0], len(currentSlice), currentSum, currentLeapsCount)
+	if total, ok := memo[string(s.key)]; ok {
+		return total
+	}
+	key := string(s.key)
+
+	total := 0
+	if len(currentSlice) == s.n-2 {
+		for _, end1Val := range steps {
+			if s.push(end1Val) {
+				for _, end2Val := range steps {
+					if currentSum+end1Val+end2Val != s.target {
+						continue
+					}
+					ok := s.push(end2Val)
+					s.pop()
+					if !ok {
+						continue
+					}
+					copy(s.final, currentSlice)
+					s.final[s.n-2] = end1Val
+					s.final[s.n-1] = end2Val
+					if s.accepts(s.final) {
+						total++
+					}
+				}
+			}
+			s.pop()
+		}
+	} else {
+		for _, val := range s.candidates(currentLeapsCount) {
+			nextLeapsCount := currentLeapsCount
+			if utils.Abs(val) > 1 {
+				nextLeapsCount++
+			}
+			if !s.reachable(len(currentSlice)+1, currentSum+val, nextLeapsCount) {
+				continue
+			}
+			if s.push(val) {
+				total += s.count(append(currentSlice, val), currentSum+val, nextLeapsCount, memo)
+			}
+			s.pop()
+		}
+	}
+
+	memo[key] = total
+	return total
+}
+
+// state appends to b the state of the beginning of a melody of the given length, sum and
+// number of leaps that the search is extending: these numbers and the summaries of the
+// partial rules, each preceded by its size.
+func (s *search) state(b []byte, length, sum, leaps int) []byte {
+	for _, v := range []int{length, sum, leaps} {
+		b = binary.AppendVarint(b, int64(v))
+	}
+	for _, r := range s.partial {
+		s.summary = r.(rules.Summarizer).AppendSummary(s.summary[:0])
+		b = binary.AppendUvarint(b, uint64(len(s.summary)))
+		b = append(b, s.summary...)
+	}
+	return b
+}
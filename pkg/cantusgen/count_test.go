@@ -0,0 +1,95 @@
+package cantusgen
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+)
+
+func TestCountCantus(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		opts Options
+	}{
+		{"two leaps", 9, Options{AllowedLeaps: []int{2}}},
+		{"up to four leaps", 11, Options{AllowedLeaps: []int{2, 3, 4}}},
+		{"restricted degrees", 9, Options{AllowedLeaps: []int{1, 2}, Degrees: []int{1, 2, 3, 4, 5}}},
+		{"no melody", 1, Options{AllowedLeaps: []int{2}}},
+		{"unreachable leap count", 9, Options{AllowedLeaps: []int{8}}},
+	}
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, procs := range []int{1, 4} {
+		runtime.GOMAXPROCS(procs)
+		for _, tt := range tests {
+			want := len(Generate(tt.n, tt.opts))
+			if got := CountCantus(tt.n, tt.opts); got != want {
+				t.Errorf("%s with GOMAXPROCS %d: CountCantus() = %d, want %d", tt.name, procs, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkCountCantus(b *testing.B) {
+	opts := Options{AllowedLeaps: []int{2, 3, 4}}
+	b.ReportAllocs()
+	for b.Loop() {
+		CountCantus(11, opts)
+	}
+}
+
+// summarizableRules returns the default rule set without the rules whose state cannot be
+// summarized and without complete rules, so that CountCantus counts by dynamic programming.
+func summarizableRules(t testing.TB) *rules.RuleSet {
+	t.Helper()
+	ruleSet := rules.DefaultRuleSet()
+	for _, name := range []string{"NoRepeatingExtremes", "NoSequences", "OctaveLeap"} {
+		if err := ruleSet.Disable(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, r := range ruleSet.Complete() {
+		if err := ruleSet.Disable(r.Name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ruleSet
+}
+
+func TestCountCantus_Memoized(t *testing.T) {
+	ruleSet := summarizableRules(t)
+	tests := []struct {
+		name string
+		n    int
+		opts Options
+	}{
+		{"two leaps", 9, Options{AllowedLeaps: []int{2}, Rules: ruleSet}},
+		{"up to four leaps", 11, Options{AllowedLeaps: []int{2, 3, 4}, Rules: ruleSet}},
+		{"restricted degrees", 10, Options{AllowedLeaps: []int{1, 2, 3}, Degrees: []int{1, 2, 3, 4, 5}, Rules: ruleSet}},
+		{"cadences", 11, Options{AllowedLeaps: []int{2, 3}, Cadences: [][]int{{-1, -1}, {-2, 1, -1}}, Rules: ruleSet}},
+		{"against a voice", 9, Options{AllowedLeaps: []int{2}, Against: &FixedVoice{Intervals: []int{1, 1, -1, 2, -1, -1, 1, -1, -1}, Offset: -7}, Rules: ruleSet}},
+		{"repeated notes", 10, Options{AllowedLeaps: []int{2}, RepeatedNotes: 1, Rules: ruleSet}},
+		{"no melody", 1, Options{AllowedLeaps: []int{2}, Rules: ruleSet}},
+	}
+
+	for _, tt := range tests {
+		s := newSearch(tt.n, tt.opts)
+		if s != nil && !s.summarizable(tt.opts) {
+			t.Fatalf("%s: the search is not summarizable", tt.name)
+		}
+		want := len(Generate(tt.n, tt.opts))
+		if got := CountCantus(tt.n, tt.opts); got != want {
+			t.Errorf("%s: CountCantus() = %d, want %d", tt.name, got, want)
+		}
+	}
+}
+
+func BenchmarkCountCantus_Memoized(b *testing.B) {
+	opts := Options{AllowedLeaps: []int{2, 3, 4}, Rules: summarizableRules(b)}
+	b.ReportAllocs()
+	for b.Loop() {
+		CountCantus(11, opts)
+	}
+}
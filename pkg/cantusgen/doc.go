@@ -0,0 +1,22 @@
+// Package cantusgen generates cantus firmi: melodies, written as sequences of diatonic
+// intervals, that satisfy the rules of strict counterpoint (see package rules) and can be
+// realized in a mode (see package music).
+//
+// A Generator gathers the parameters of a search:
+//
+//	g, err := cantusgen.NewGenerator(cantusgen.WithLength(11), cantusgen.WithLeaps(2, 3))
+//	if err != nil {
+//		return err
+//	}
+//	for melody := range g.Seq() {
+//		fmt.Println(melody)
+//	}
+//
+// The functions taking Options (Generate, GenerateSeq, CountCantus, SampleCantus,
+// GenerateRandom, GenerateWithBudget, GenerateLimited and GenerateEvolved) give finer control
+// over the same searches, and IsValidCantus and Check validate melodies written elsewhere.
+//
+// The exported API of this package and of packages rules and music follows semantic versioning
+// from v1 on: within a major version, identifiers are only added, and new fields of Options
+// and of the other option structs leave the behavior unchanged at their zero values.
+package cantusgen
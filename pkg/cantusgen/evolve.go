@@ -0,0 +1,252 @@
+package cantusgen
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// Defaults of EvolveOptions.
+const (
+	defaultPopulation  = 200
+	defaultGenerations = 300
+	defaultMutation    = 0.5
+)
+
+// violationCost is the fitness an evolved melody loses for every requirement it breaks: more
+// than any score, so that a valid melody is always fitter than an invalid one.
+const violationCost = 10
+
+// seedShare is the inverse of the share of the first generation drawn by the randomized
+// backtracking of GenerateRandom rather than at random.
+const seedShare = 4
+
+// tournamentSize is the number of melodies drawn at random to pick a parent, the fittest of
+// them winning.
+const tournamentSize = 3
+
+// EvolveOptions configures the genetic algorithm of GenerateEvolved. Zero fields take the
+// default values.
+//
+// Fields:
+//   - Population: the number of melodies of every generation (default 200)
+//   - Generations: the number of generations bred (default 300)
+//   - Mutation: the probability that a child is mutated, from 0 to 1 (default 0.5)
+//   - Score: rates the valid melodies (see ScoreFunc); nil uses DefaultScore
+type EvolveOptions struct {
+	Population  int
+	Generations int
+	Mutation    float64
+	Score       ScoreFunc
+}
+
+// GenerateEvolved evolves cantus firmi of n intervals satisfying the same conditions as
+// Generate with a genetic algorithm and returns up to limit distinct valid melodies with the
+// highest scores found, best first, less the penalty of the soft rules they break. It is
+// meant for lengths the exhaustive search cannot cover, up to MaxLongNotes notes: its running
+// time depends on the population and the number of generations only.
+//
+// The first generation is drawn at random from the intervals the melody may use. Every next
+// one keeps the fittest tenth of the previous one and breeds the rest from parents picked by
+// tournament: a child joins the beginning of one parent with the end of the other and may be
+// mutated, either by replacing an interval or by widening one interval by a step and narrowing
+// another one, which keeps the sum of the intervals. A melody loses fitness for every
+// requirement it breaks (see Check) and for its distance from the final, and gains its score
+// once it is valid. opts.Rand makes the evolution reproducible. The result may be empty if no
+// valid melody evolved.
+func GenerateEvolved(n int, opts Options, eopts EvolveOptions, limit int) [][]int {
+	defer opts.Stats.since(time.Now())
+	if n < 2 || limit <= 0 {
+		return nil
+	}
+	if eopts.Population <= 0 {
+		eopts.Population = defaultPopulation
+	}
+	if eopts.Generations <= 0 {
+		eopts.Generations = defaultGenerations
+	}
+	if eopts.Mutation <= 0 {
+		eopts.Mutation = defaultMutation
+	}
+
+	leapPartial, leapComplete := opts.leapCountRules()
+	e := &evolution{n: n, opts: opts, alphabet: opts.alphabet(), rng: opts.rng(),
+		partial:  append(append(leapPartial, opts.lengthRules(n)...), opts.partialRules()...),
+		complete: validators(append(leapComplete, opts.completeRules()...)),
+		best:     newRanking(limit, eopts.Score, opts.ruleSet()), seen: map[string]bool{}}
+	population := make([]individual, eopts.Population)
+	seeds := newRandomSearch(n, opts)
+	for i := range population {
+		var intervals []int
+		if seeds != nil && i < eopts.Population/seedShare {
+			intervals = seeds.attempt()
+		}
+		if intervals == nil {
+			intervals = e.random()
+		}
+		population[i] = e.rate(intervals)
+	}
+
+	elite := max(1, eopts.Population/10)
+	for range eopts.Generations {
+		slices.SortStableFunc(population, func(a, b individual) int {
+			return cmp.Compare(b.fitness, a.fitness)
+		})
+		next := slices.Clone(population[:elite])
+		for len(next) < eopts.Population {
+			child := e.crossover(e.pick(population).intervals, e.pick(population).intervals)
+			if e.rng.Float64() < eopts.Mutation {
+				e.mutate(child)
+			}
+			next = append(next, e.rate(child))
+		}
+		population = next
+	}
+	return e.best.melodies()
+}
+
+// individual is a melody of the population with its fitness.
+type individual struct {
+	intervals []int
+	fitness   float64
+}
+
+// evolution holds the state of GenerateEvolved.
+type evolution struct {
+	n        int
+	opts     Options
+	alphabet []int
+	rng      *rand.Rand
+	// partial and complete are the rules the melodies must satisfy, the number of leaps included
+	partial  []rules.Rule
+	complete []rules.ValidationFunc
+	// best ranks the valid melodies found, seen holds them all
+	best *ranking
+	seen map[string]bool
+}
+
+// random returns a melody of random intervals, ending with two steps.
+func (e *evolution) random() []int {
+	intervals := make([]int, e.n)
+	for i := range intervals {
+		intervals[i] = e.alphabet[e.rng.Intn(len(e.alphabet))]
+	}
+	intervals[e.n-2] = steps[e.rng.Intn(2)]
+	intervals[e.n-1] = steps[e.rng.Intn(2)]
+	return intervals
+}
+
+// rate returns the melody with its fitness, and ranks it if it is valid and new.
+func (e *evolution) rate(intervals []int) individual {
+	violations := e.violations(intervals)
+	fitness := -float64(violationCost*violations + utils.Abs(e.opts.target()-sum(intervals)))
+	if violations == 0 {
+		fitness = e.best.score(intervals) - e.best.ruleSet.Penalty(intervals)
+		if key := fmt.Sprint(intervals); !e.seen[key] {
+			e.seen[key] = true
+			e.best.add(intervals)
+		}
+	}
+	return individual{intervals: intervals, fitness: fitness}
+}
+
+// violations returns the number of requirements of IsValidCantus the melody breaks, counting
+// every interval outside the alphabet and every place a Local partial rule is broken at: the
+// checker of such a rule starts afresh after each interval it rejects.
+func (e *evolution) violations(intervals []int) int {
+	count := 0
+	for _, val := range intervals {
+		if !slices.Contains(e.alphabet, val) {
+			count++
+		}
+	}
+	if sum(intervals) != e.opts.target() {
+		count++
+	}
+	if !slices.Contains(steps, intervals[e.n-2]) || !slices.Contains(steps, intervals[e.n-1]) {
+		count++
+	}
+
+	for _, r := range e.partial {
+		checker := rules.NewIncremental(r)
+		for _, val := range intervals {
+			if checker.Push(val) {
+				continue
+			}
+			count++
+			if !r.Local {
+				break
+			}
+			checker = rules.NewIncremental(r)
+		}
+	}
+	for _, check := range e.complete {
+		if !check(intervals) {
+			count++
+		}
+	}
+	return count
+}
+
+// sum returns the sum of the intervals.
+func sum(intervals []int) int {
+	total := 0
+	for _, val := range intervals {
+		total += val
+	}
+	return total
+}
+
+// pick returns the fittest of tournamentSize melodies drawn at random from the population.
+func (e *evolution) pick(population []individual) individual {
+	best := population[e.rng.Intn(len(population))]
+	for range tournamentSize - 1 {
+		if other := population[e.rng.Intn(len(population))]; other.fitness > best.fitness {
+			best = other
+		}
+	}
+	return best
+}
+
+// crossover returns a new melody made of the beginning of a and the end of b, cut after a
+// random note on which both melodies are at the same height, so that the end of b follows on
+// from the beginning of a and the child ends where b does, or else at a random interval.
+func (e *evolution) crossover(a, b []int) []int {
+	var cuts []int
+	heightA, heightB := 0, 0
+	for i := 1; i < e.n; i++ {
+		heightA += a[i-1]
+		heightB += b[i-1]
+		if heightA == heightB {
+			cuts = append(cuts, i)
+		}
+	}
+	cut := e.rng.Intn(e.n + 1)
+	if len(cuts) > 0 {
+		cut = cuts[e.rng.Intn(len(cuts))]
+	}
+	return slices.Concat(a[:cut], b[cut:])
+}
+
+// mutate changes the melody in place: it either replaces a random interval with another one
+// the melody may use, or adds a step up or down to a random interval and subtracts it from
+// another one, if both remain intervals the melody may use, keeping the sum of the intervals.
+func (e *evolution) mutate(intervals []int) {
+	if e.rng.Intn(2) == 0 {
+		intervals[e.rng.Intn(e.n)] = e.alphabet[e.rng.Intn(len(e.alphabet))]
+		return
+	}
+	i, j := e.rng.Intn(e.n), e.rng.Intn(e.n)
+	if i == j {
+		return
+	}
+	shift := steps[e.rng.Intn(2)]
+	if slices.Contains(e.alphabet, intervals[i]+shift) && slices.Contains(e.alphabet, intervals[j]-shift) {
+		intervals[i] += shift
+		intervals[j] -= shift
+	}
+}
@@ -0,0 +1,64 @@
+package cantusgen
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestGenerateEvolved(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2, 3, 4}, Rand: rand.New(rand.NewSource(1))}
+	eopts := EvolveOptions{Population: 60, Generations: 30}
+	melodies := GenerateEvolved(12, opts, eopts, 5)
+	if len(melodies) == 0 || len(melodies) > 5 {
+		t.Fatalf("GenerateEvolved returned %d melodies, want 1 to 5", len(melodies))
+	}
+
+	seen := map[string]bool{}
+	for i, m := range melodies {
+		if !IsValidCantus(m, opts) {
+			t.Errorf("invalid melody %v", m)
+		}
+		if key := fmt.Sprint(m); seen[key] {
+			t.Errorf("melody %v returned twice", m)
+		} else {
+			seen[key] = true
+		}
+		if i > 0 && DefaultScore(m) > DefaultScore(melodies[i-1]) {
+			t.Errorf("melody %v scores higher than the one before it", m)
+		}
+	}
+
+	opts.Rand = rand.New(rand.NewSource(1))
+	if again := GenerateEvolved(12, opts, eopts, 5); fmt.Sprint(again) != fmt.Sprint(melodies) {
+		t.Errorf("same seed evolved %v, then %v", melodies, again)
+	}
+}
+
+func TestGenerateEvolved_Degenerate(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2}}
+	if got := GenerateEvolved(1, opts, EvolveOptions{}, 5); got != nil {
+		t.Errorf("GenerateEvolved of one interval = %v, want nil", got)
+	}
+	if got := GenerateEvolved(10, opts, EvolveOptions{}, 0); got != nil {
+		t.Errorf("GenerateEvolved with no melody wanted = %v, want nil", got)
+	}
+}
+
+func TestEvolution_Violations(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2, 3}}
+	valid := Generate(10, opts)[0]
+	leapPartial, leapComplete := opts.leapCountRules()
+	e := &evolution{n: 10, opts: opts, alphabet: opts.alphabet(),
+		partial:  append(leapPartial, opts.partialRules()...),
+		complete: validators(append(leapComplete, opts.completeRules()...))}
+
+	if got := e.violations(valid); got != 0 {
+		t.Errorf("violations(%v) = %d, want 0", valid, got)
+	}
+	// Ending on a leap breaks at least the stepwise ending and the return to the final
+	broken := append(valid[:8:8], 3, -1)
+	if got := e.violations(broken); got < 2 {
+		t.Errorf("violations(%v) = %d, want at least 2", broken, got)
+	}
+}
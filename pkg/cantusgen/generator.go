@@ -0,0 +1,410 @@
+package cantusgen
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"iter"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// ErrUnbounded is returned by NewGenerator for melodies too long to generate them all.
+var ErrUnbounded = errors.New("unbounded generation")
+
+// Generator generates cantus firmi of a given length. It gathers the parameters of the
+// generation functions (Generate, GenerateSeq, CountCantus, SampleCantus, GenerateRandom)
+// in one value configured with options:
+//
+//	g, err := cantusgen.NewGenerator(cantusgen.WithLength(11), cantusgen.WithLeaps(2, 3))
+//	if err != nil {
+//		return err
+//	}
+//	melodies := g.Generate()
+//
+// Melodies longer than MaxNotes notes, up to MaxLongNotes, have too many solutions to collect
+// them all: their generator needs a limit, a timeout, a memory limit or a callback (see NewGenerator).
+type Generator struct {
+	notes     int
+	opts      Options
+	limit     int
+	timeout   time.Duration
+	maxMemory uint64
+	onMelody  func(intervals []int) bool
+	top       int
+	score     ScoreFunc
+	variants  bool
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// WithLength sets the number of notes of the melodies.
+func WithLength(notes int) Option {
+	return func(g *Generator) {
+		g.notes = notes
+	}
+}
+
+// WithLeaps sets the allowed numbers of leaps in a melody (see Options.AllowedLeaps).
+func WithLeaps(counts ...int) Option {
+	return func(g *Generator) {
+		g.opts.AllowedLeaps = slices.Clone(counts)
+	}
+}
+
+// WithIntervals sets the leaps a melody may use (see Options.Leaps). The rule set given
+// with WithRules is not modified.
+func WithIntervals(leaps ...int) Option {
+	return func(g *Generator) {
+		g.opts.Leaps = slices.Clone(leaps)
+	}
+}
+
+// WithAmbitus keeps every note between low and high steps above the final, below it if
+// negative (see Options.Ambitus).
+func WithAmbitus(low, high int) Option {
+	return func(g *Generator) {
+		g.opts.Ambitus = &Ambitus{Low: low, High: high}
+	}
+}
+
+// WithRepeatedNotes allows up to limit repeated notes in a melody (see Options.RepeatedNotes).
+// The rule set given with WithRules is not modified.
+func WithRepeatedNotes(limit int) Option {
+	return func(g *Generator) {
+		g.opts.RepeatedNotes = limit
+	}
+}
+
+// WithDegrees restricts the scale degrees a melody may use (see Options.Degrees).
+func WithDegrees(degrees ...int) Option {
+	return func(g *Generator) {
+		g.opts.Degrees = slices.Clone(degrees)
+	}
+}
+
+// WithPrefix generates only the completions of a melody beginning with the given intervals
+// (see Options.Prefix).
+func WithPrefix(intervals ...int) Option {
+	return func(g *Generator) {
+		g.opts.Prefix = slices.Clone(intervals)
+	}
+}
+
+// WithCadences requires the melodies to end with one of the interval patterns
+// (see Options.Cadences).
+func WithCadences(patterns ...[]int) Option {
+	return func(g *Generator) {
+		g.opts.Cadences = make([][]int, len(patterns))
+		for i, p := range patterns {
+			g.opts.Cadences[i] = slices.Clone(p)
+		}
+	}
+}
+
+// WithAgainst generates melodies forming first-species counterpoint with a fixed voice
+// given by its intervals, the melodies beginning offset steps above it, below it if negative
+// (see Options.Against).
+func WithAgainst(voice []int, offset int) Option {
+	return func(g *Generator) {
+		g.opts.Against = &FixedVoice{Intervals: slices.Clone(voice), Offset: offset}
+	}
+}
+
+// WithPins fixes intervals or notes at given positions (see Options.Pins).
+func WithPins(pins ...rules.Pin) Option {
+	return func(g *Generator) {
+		g.opts.Pins = slices.Clone(pins)
+	}
+}
+
+// WithRules sets the rules the melodies must satisfy; nil selects all registered rules.
+func WithRules(ruleSet *rules.RuleSet) Option {
+	return func(g *Generator) {
+		g.opts.Rules = ruleSet
+	}
+}
+
+// WithMode prunes the melodies whose realization in the mode, made as realize tells, is not free
+// of augmented and diminished intervals (see Options.Mode and Options.Realize). The rule set
+// given with WithRules is not adapted to the mode (see rules.RuleSet.SetMode).
+func WithMode(mode music.Mode, realize music.RealizeOptions) Option {
+	return func(g *Generator) {
+		g.opts.Mode = mode
+		g.opts.Realize = realize
+	}
+}
+
+// WithStats counts the candidates rejected by each rule in st (see Stats).
+func WithStats(st *Stats) Option {
+	return func(g *Generator) {
+		g.opts.Stats = st
+	}
+}
+
+// WithProgress calls f with the progress of the search (see Options.Progress).
+func WithProgress(f func(Progress)) Option {
+	return func(g *Generator) {
+		g.opts.Progress = f
+	}
+}
+
+// WithRand sets the source of randomness of Sample and Random (see Options.Rand).
+func WithRand(rng *rand.Rand) Option {
+	return func(g *Generator) {
+		g.opts.Rand = rng
+	}
+}
+
+// WithLimit stops Generate after the given number of melodies; 0 means no limit.
+func WithLimit(limit int) Option {
+	return func(g *Generator) {
+		g.limit = limit
+	}
+}
+
+// WithTimeout stops Generate after the given time, returning the melodies found so far;
+// 0 means no timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(g *Generator) {
+		g.timeout = d
+	}
+}
+
+// WithMaxMemory stops Generate once the live heap exceeds the given number of bytes,
+// returning the melodies found so far (see Limits); 0 means no limit.
+func WithMaxMemory(bytes uint64) Option {
+	return func(g *Generator) {
+		g.maxMemory = bytes
+	}
+}
+
+// WithTop makes Generate return only the n melodies with the highest scores, best first
+// (see TopScoring), instead of all of them in the order of the search. A nil score uses
+// DefaultScore; MelodicScore rates the melodies by more criteria.
+func WithTop(n int, score ScoreFunc) Option {
+	return func(g *Generator) {
+		g.top = n
+		g.score = score
+	}
+}
+
+// WithoutVariants drops the melodies that are the inversion, retrograde or retrograde
+// inversion of one found before (see DropVariants), from Generate and Seq alike.
+func WithoutVariants() Option {
+	return func(g *Generator) {
+		g.variants = true
+	}
+}
+
+// WithCallback makes Generate call f with every melody as soon as it is found.
+// Returning false stops the generation.
+func WithCallback(f func(intervals []int) bool) Option {
+	return func(g *Generator) {
+		g.onMelody = f
+	}
+}
+
+// NewGenerator returns a generator configured by the options. The length and the allowed
+// numbers of leaps are required; the other options default to the zero Options. A length of
+// more than MaxNotes notes also requires a limit, a timeout, a memory limit or a callback, so
+// that Generate cannot exhaust memory, and a length of more than MaxLongNotes notes is rejected.
+func NewGenerator(options ...Option) (*Generator, error) {
+	g := &Generator{}
+	for _, option := range options {
+		option(g)
+	}
+
+	if g.notes < 3 || g.notes > MaxLongNotes {
+		return nil, fmt.Errorf("invalid length %d: want 3 to %d notes", g.notes, MaxLongNotes)
+	}
+	if g.notes > MaxNotes && g.unbounded() {
+		return nil, fmt.Errorf("%w: a melody of %d notes needs a limit, a timeout, a memory limit or a callback", ErrUnbounded, g.notes)
+	}
+	if len(g.opts.AllowedLeaps) == 0 {
+		return nil, errors.New("no allowed number of leaps")
+	}
+	if len(g.opts.Prefix) >= g.notes {
+		return nil, fmt.Errorf("prefix of %d intervals too long for a melody of %d notes", len(g.opts.Prefix), g.notes)
+	}
+	for _, p := range g.opts.Cadences {
+		n := len(p)
+		if n == 0 || n >= g.notes {
+			return nil, fmt.Errorf("invalid cadence %v: want 1 to %d intervals", p, g.notes-1)
+		}
+		if !slices.Contains(steps, p[n-1]) || n > 1 && !slices.Contains(steps, p[n-2]) {
+			return nil, fmt.Errorf("invalid cadence %v: a melody ends with two steps", p)
+		}
+	}
+	if v := g.opts.Against; v != nil {
+		if len(v.Intervals) != g.notes-1 {
+			return nil, fmt.Errorf("fixed voice of %d intervals for a melody of %d notes", len(v.Intervals), g.notes)
+		}
+		if class := utils.Abs(v.Offset) % 7; class != 0 && class != 4 {
+			return nil, fmt.Errorf("invalid offset %d: the voices begin on a unison, fifth or octave", v.Offset)
+		}
+	}
+	for _, p := range g.opts.Pins {
+		if p.Position < 1 || len(p.Values) == 0 {
+			return nil, fmt.Errorf("invalid pin %+v: want a position from 1 and allowed values", p)
+		}
+		last := g.notes - 1
+		if p.Note {
+			last = g.notes
+		}
+		if p.Position > last {
+			return nil, fmt.Errorf("invalid pin %+v: the melody has %d notes", p, g.notes)
+		}
+	}
+	if g.limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d", g.limit)
+	}
+	if g.timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout %s", g.timeout)
+	}
+	if g.top < 0 {
+		return nil, fmt.Errorf("invalid number of best melodies %d", g.top)
+	}
+	if a := g.opts.Ambitus; a != nil && a.Low > a.High {
+		return nil, fmt.Errorf("invalid ambitus %d..%d: the lowest note is above the highest", a.Low, a.High)
+	}
+	if g.opts.RepeatedNotes < 0 {
+		return nil, fmt.Errorf("invalid number of repeated notes %d", g.opts.RepeatedNotes)
+	}
+	for _, leap := range g.opts.Leaps {
+		if utils.Abs(leap) < 2 {
+			return nil, fmt.Errorf("invalid leap %d: steps and repeated notes are not leaps", leap)
+		}
+	}
+	return g, nil
+}
+
+// Options returns the options the generator passes to the generation functions.
+func (g *Generator) Options() Options {
+	return g.opts
+}
+
+// Generate returns the melodies like Generate. With a limit, a timeout, a memory limit or a
+// callback, the melodies are streamed in the order of the search instead (see GenerateSeq):
+// the search stops once the limit is reached, the time is up, the heap exceeds the memory limit
+// or the callback returns false, and the melodies found so far are ranked by penalty if the rule
+// set has soft rules. With WithTop, only the best-scoring of the melodies found are returned.
+func (g *Generator) Generate() [][]int {
+	result, _ := g.generate(false)
+	if g.top > 0 {
+		return TopScoring(result, g.top, g.score, g.opts.ruleSet())
+	}
+	return result
+}
+
+// GenerateLimited works like Generate and also reports whether the limit, the timeout, the
+// memory limit or the callback stopped the search before it was complete, so that a service
+// can tell a truncated result (see GenerateLimited). Once the limit is reached, the search goes
+// on until it finds one more melody, which is not returned.
+func (g *Generator) GenerateLimited() (melodies [][]int, truncated bool) {
+	melodies, truncated = g.generate(true)
+	if g.top > 0 {
+		return TopScoring(melodies, g.top, g.score, g.opts.ruleSet()), truncated
+	}
+	return melodies, truncated
+}
+
+// unbounded reports whether the generator has no limit, timeout, memory limit or callback.
+func (g *Generator) unbounded() bool {
+	return g.limit == 0 && g.timeout == 0 && g.maxMemory == 0 && g.onMelody == nil
+}
+
+// generate returns the melodies of Generate before WithTop selects the best ones, and whether
+// the search was stopped before it was complete. Unless probe is set, reaching the limit counts
+// as stopping it.
+func (g *Generator) generate(probe bool) ([][]int, bool) {
+	if g.unbounded() {
+		if g.variants {
+			return DropVariants(Generate(g.notes-1, g.opts)), false
+		}
+		return Generate(g.notes-1, g.opts), false
+	}
+
+	truncated := false
+	seq := g.limitedSeq(&truncated)
+	if g.variants {
+		seq = dropVariants(seq)
+	}
+	var result [][]int
+	for melody := range seq {
+		if g.limit > 0 && len(result) == g.limit {
+			// Only reached when probing: the melody beyond the limit
+			truncated = true
+			break
+		}
+		result = append(result, melody)
+		if g.onMelody != nil && !g.onMelody(melody) {
+			truncated = true
+			break
+		}
+		if len(result) == g.limit && !probe {
+			truncated = true
+			break
+		}
+	}
+	if ruleSet := g.opts.ruleSet(); len(ruleSet.Soft()) > 0 {
+		rankByPenalty(result, ruleSet)
+	}
+	return result, truncated
+}
+
+// Seq yields the melodies as the search finds them (see GenerateSeq), until the timeout
+// or the memory limit if one is set.
+func (g *Generator) Seq() iter.Seq[[]int] {
+	seq := g.limitedSeq(new(bool))
+	if g.variants {
+		return dropVariants(seq)
+	}
+	return seq
+}
+
+// Pull returns the melodies of Seq one at a time, so that a program can interleave the search
+// with its own work without ranging over Seq (see iter.Pull): next returns the next melody, or
+// false once the search is complete, and stop ends the search early. stop must be called
+// unless next has returned false; calling it more than once is harmless.
+//
+//	next, stop := g.Pull()
+//	defer stop()
+//	for melody, ok := next(); ok; melody, ok = next() {
+//		...
+//	}
+func (g *Generator) Pull() (next func() ([]int, bool), stop func()) {
+	return iter.Pull(g.Seq())
+}
+
+// limitedSeq yields the melodies of Seq before WithoutVariants drops the variants, and sets
+// *truncated if the timeout or the memory limit stops the search.
+func (g *Generator) limitedSeq(truncated *bool) iter.Seq[[]int] {
+	if g.timeout == 0 {
+		return limitedSeq(g.notes-1, g.opts, g.maxMemory, nil, truncated)
+	}
+	return func(yield func([]int) bool) {
+		// The clock starts with the iteration, as every iteration runs a new search
+		deadline := time.Now().Add(g.timeout)
+		limitedSeq(g.notes-1, g.opts, g.maxMemory, &deadline, truncated)(yield)
+	}
+}
+
+// Count returns the number of melodies (see CountCantus).
+func (g *Generator) Count() int {
+	return CountCantus(g.notes-1, g.opts)
+}
+
+// Sample returns up to k distinct random melodies (see SampleCantus).
+func (g *Generator) Sample(k int) [][]int {
+	return SampleCantus(g.notes-1, k, g.opts)
+}
+
+// Random returns a single random melody, or nil if none was found (see GenerateRandom).
+func (g *Generator) Random() []int {
+	return GenerateRandom(g.notes-1, g.opts)
+}
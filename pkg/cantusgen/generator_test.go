@@ -0,0 +1,480 @@
+package cantusgen
+
+import (
+	"errors"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewGenerator_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{"no length", []Option{WithLeaps(2)}},
+		{"too short", []Option{WithLength(2), WithLeaps(0)}},
+		{"no leap counts", []Option{WithLength(10)}},
+		{"negative limit", []Option{WithLength(10), WithLeaps(2), WithLimit(-1)}},
+		{"step among the leaps", []Option{WithLength(10), WithLeaps(2), WithIntervals(2, 1)}},
+		{"pin beyond the last interval", []Option{WithLength(10), WithLeaps(2), WithPins(rules.Pin{Position: 10, Values: []int{1}})}},
+		{"prefix too long", []Option{WithLength(4), WithLeaps(0), WithPrefix(1, 1, -1, -1)}},
+		{"pin without values", []Option{WithLength(10), WithLeaps(2), WithPins(rules.Pin{Note: true, Position: 10})}},
+		{"long melody without bounds", []Option{WithLength(MaxNotes + 1), WithLeaps(4)}},
+		{"too long", []Option{WithLength(MaxLongNotes + 1), WithLeaps(4), WithLimit(1)}},
+		{"negative timeout", []Option{WithLength(10), WithLeaps(2), WithTimeout(-time.Second)}},
+		{"negative number of best melodies", []Option{WithLength(10), WithLeaps(2), WithTop(-1, nil)}},
+		{"negative number of repeated notes", []Option{WithLength(10), WithLeaps(2), WithRepeatedNotes(-1)}},
+		{"empty ambitus", []Option{WithLength(10), WithLeaps(2), WithAmbitus(3, -1)}},
+		{"empty cadence", []Option{WithLength(10), WithLeaps(2), WithCadences([]int{})}},
+		{"cadence longer than the melody", []Option{WithLength(4), WithLeaps(0), WithCadences([]int{1, 1, -1, -1})}},
+		{"cadence ending with a leap", []Option{WithLength(10), WithLeaps(2), WithCadences([]int{-1, -1}, []int{1, -2})}},
+		{"fixed voice of another length", []Option{WithLength(10), WithLeaps(2), WithAgainst([]int{1, 1, -1, -1}, 7)}},
+		{"voices beginning on a third", []Option{WithLength(5), WithLeaps(0), WithAgainst([]int{1, 1, -1, -1}, 2)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGenerator(tt.options...); err == nil {
+				t.Error("NewGenerator() must fail")
+			}
+		})
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	g, err := NewGenerator(WithLength(10), WithLeaps(2), WithDegrees(1, 2, 3, 4, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{AllowedLeaps: []int{2}, Degrees: []int{1, 2, 3, 4, 5}}
+	want := Generate(9, opts)
+	if got := g.Generate(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate() returned %d melodies, want the %d of Generate(9, %+v)", len(got), len(want), opts)
+	}
+	if got := g.Count(); got != len(want) {
+		t.Errorf("Count() = %d, want %d", got, len(want))
+	}
+	if melody := g.Random(); melody == nil || !IsValidCantus(melody, opts) {
+		t.Errorf("Random() = %v, want a valid melody", melody)
+	}
+	if got := g.Sample(3); len(got) != 3 {
+		t.Errorf("Sample(3) returned %d melodies", len(got))
+	}
+}
+
+func TestGenerator_LimitAndCallback(t *testing.T) {
+	g, err := NewGenerator(WithLength(10), WithLeaps(2), WithLimit(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := slices.Collect(GenerateSeq(9, Options{AllowedLeaps: []int{2}}))
+	if got := g.Generate(); !slices.EqualFunc(got, all[:5], slices.Equal) {
+		t.Errorf("Generate() with limit 5 = %v, want %v", got, all[:5])
+	}
+
+	var seen [][]int
+	g, err = NewGenerator(WithLength(10), WithLeaps(2), WithCallback(func(intervals []int) bool {
+		seen = append(seen, intervals)
+		return len(seen) < 3
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := g.Generate()
+	if len(seen) != 3 || !slices.EqualFunc(got, seen, slices.Equal) {
+		t.Errorf("callback saw %v and Generate() returned %v, want the same 3 melodies", seen, got)
+	}
+}
+
+func TestGenerator_Top(t *testing.T) {
+	score := MelodicScore(music.Major)
+	g, err := NewGenerator(WithLength(10), WithLeaps(2), WithTop(5, score))
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := Generate(9, Options{AllowedLeaps: []int{2}})
+	want := TopScoring(all, 5, score, nil)
+	got := g.Generate()
+	if len(got) != 5 || !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("Generate() with the top 5 = %v, want %v", got, want)
+	}
+	for _, melody := range all {
+		if score(melody) > score(got[len(got)-1]) && !slices.ContainsFunc(got, func(m []int) bool {
+			return slices.Equal(m, melody)
+		}) {
+			t.Errorf("melody %v scores higher than the top 5 %v", melody, got)
+		}
+	}
+
+	// The best are chosen among the melodies found within the limit
+	g, err = NewGenerator(WithLength(10), WithLeaps(2), WithLimit(20), WithTop(3, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := slices.Collect(GenerateSeq(9, Options{AllowedLeaps: []int{2}}))[:20]
+	if got, want := g.Generate(), TopScoring(first, 3, DefaultScore, nil); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate() with limit 20 and the top 3 = %v, want %v", got, want)
+	}
+}
+
+func TestGenerator_WithoutVariants(t *testing.T) {
+	g, err := NewGenerator(WithLength(10), WithLeaps(2), WithoutVariants())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DropVariants(Generate(9, Options{AllowedLeaps: []int{2}}))
+	if got := g.Generate(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate() without variants returned %d melodies, want %d", len(got), len(want))
+	}
+
+	// Streaming drops the variants of the melodies found before as well
+	g, err = NewGenerator(WithLength(10), WithLeaps(2), WithoutVariants(), WithLimit(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	streamed := g.Generate()
+	if len(streamed) != 10 || !slices.EqualFunc(streamed, DropVariants(streamed), slices.Equal) {
+		t.Errorf("Generate() without variants and limit 10 = %v", streamed)
+	}
+}
+
+func TestGenerator_Intervals(t *testing.T) {
+	ruleSet := rules.DefaultRuleSet()
+	g, err := NewGenerator(WithLength(10), WithLeaps(2), WithRules(ruleSet), WithIntervals(3, -3, 2, -2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	melodies := g.Generate()
+	if len(melodies) == 0 {
+		t.Fatal("expected melodies leaping by thirds and fourths only")
+	}
+	for _, melody := range melodies {
+		for _, interval := range melody {
+			if utils.Abs(interval) > 3 {
+				t.Fatalf("melody %v uses the interval %d", melody, interval)
+			}
+		}
+	}
+	if !slices.Equal(ruleSet.Leaps(), rules.DefaultRuleSet().Leaps()) {
+		t.Error("WithIntervals must not modify the rule set")
+	}
+}
+
+func TestGenerator_RepeatedNotes(t *testing.T) {
+	// With the default rules, the option allows what the rule set allows with SetRepeatedNotes
+	g, err := NewGenerator(WithLength(9), WithLeaps(2), WithRepeatedNotes(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ruleSet := rules.DefaultRuleSet()
+	ruleSet.SetRepeatedNotes(true)
+	want := Generate(8, Options{AllowedLeaps: []int{2}, Rules: ruleSet})
+	if got := g.Generate(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate() returned %d melodies, want the %d allowed by the rule set", len(got), len(want))
+	}
+
+	ruleSet = rules.DefaultRuleSet()
+	if err := ruleSet.Disable("SingleRepeatedNote"); err != nil {
+		t.Fatal(err)
+	}
+	g, err = NewGenerator(WithLength(9), WithLeaps(2), WithRules(ruleSet), WithRepeatedNotes(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	most := 0
+	for _, melody := range g.Generate() {
+		count := 0
+		for _, interval := range melody {
+			if interval == 0 {
+				count++
+			}
+		}
+		if count > 2 || melody[0] == 0 {
+			t.Fatalf("melody %v repeats notes beyond the limit", melody)
+		}
+		most = max(most, count)
+	}
+	if most != 2 {
+		t.Errorf("melodies repeat at most %d notes, want 2", most)
+	}
+	if ruleSet.RepeatedNotes() {
+		t.Error("WithRepeatedNotes must not modify the rule set")
+	}
+}
+
+func TestGenerator_Cadences(t *testing.T) {
+	patterns := [][]int{{-1, -1}, {-2, 1, -1}}
+	g, err := NewGenerator(WithLength(10), WithLeaps(2), WithCadences(patterns...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want [][]int
+	for _, melody := range Generate(9, Options{AllowedLeaps: []int{2}}) {
+		if slices.Equal(melody[7:], patterns[0]) || slices.Equal(melody[6:], patterns[1]) {
+			want = append(want, melody)
+		}
+	}
+	if len(want) == 0 {
+		t.Fatal("no melody ends with the patterns")
+	}
+	if got := g.Generate(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate() returned %d melodies, want the %d ending with %v", len(got), len(want), patterns)
+	}
+	if got := g.Count(); got != len(want) {
+		t.Errorf("Count() = %d, want %d", got, len(want))
+	}
+	for _, melody := range want {
+		if !IsValidCantus(melody, g.Options()) {
+			t.Errorf("IsValidCantus(%v) = false with the cadences", melody)
+		}
+	}
+}
+
+func TestGenerator_Against(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2}}
+	melodies := Generate(9, opts)
+	voice := melodies[len(melodies)/2]
+	g, err := NewGenerator(WithLength(10), WithLeaps(2), WithAgainst(voice, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counterpoint := rules.FirstSpecies(voice, 7)
+	var want [][]int
+	for _, melody := range melodies {
+		if counterpoint(melody) {
+			want = append(want, melody)
+		}
+	}
+	if len(want) == 0 {
+		t.Fatalf("no melody forms counterpoint with %v", voice)
+	}
+	if got := g.Generate(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate() returned %d melodies, want the %d forming counterpoint with %v", len(got), len(want), voice)
+	}
+	if got := g.Count(); got != len(want) {
+		t.Errorf("Count() = %d, want %d", got, len(want))
+	}
+	for _, melody := range want {
+		if !IsValidCantus(melody, g.Options()) {
+			t.Errorf("IsValidCantus(%v) = false against %v", melody, voice)
+		}
+	}
+
+	// A melody as long as the voice is required
+	opts.Against = &FixedVoice{Intervals: voice[:8], Offset: 7}
+	if got := Violations(want[0], opts); !slices.Equal(got, []string{RuleCounterpoint}) {
+		t.Errorf("Violations() against a shorter voice = %v, want [%s]", got, RuleCounterpoint)
+	}
+}
+
+func TestGenerator_AgainstInMode(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2}, Mode: music.Dorian}
+	melodies := Generate(9, Options{AllowedLeaps: []int{2}})
+	against := &FixedVoice{Intervals: melodies[len(melodies)/3], Offset: -7}
+	voice, err := against.Realize(opts.Mode, music.RealizeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The diatonic check lets tritones between the voices pass; their realizations do not
+	counterpoint := rules.FirstSpecies(against.Intervals, against.Offset)
+	var diatonic, want [][]int
+	for _, melody := range Generate(9, opts) {
+		if !counterpoint(melody) {
+			continue
+		}
+		diatonic = append(diatonic, melody)
+		if rules.ConsonantVerticals(Realize([][]int{melody}, opts.Mode, nil, RealizationOptions{})[0], voice) {
+			want = append(want, melody)
+		}
+	}
+	if len(want) == 0 || len(want) == len(diatonic) {
+		t.Fatalf("%d of the %d melodies forming counterpoint with %v are free of tritones, want some but not all",
+			len(want), len(diatonic), against.Intervals)
+	}
+
+	opts.Against = against
+	if got := Generate(9, opts); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate() against %v in %s = %v, want %v", against.Intervals, opts.Mode, got, want)
+	}
+	for _, melody := range diatonic {
+		if slices.ContainsFunc(want, func(m []int) bool { return slices.Equal(m, melody) }) {
+			continue
+		}
+		if got := Violations(melody, opts); !slices.Contains(got, RuleCounterpoint) {
+			t.Errorf("Violations(%v) against %v in %s = %v, want %s", melody, against.Intervals, opts.Mode, got, RuleCounterpoint)
+		}
+	}
+}
+
+func TestGenerator_Mode(t *testing.T) {
+	realize := music.RealizeOptions{Minor: music.MinorHarmonic}
+	g, err := NewGenerator(WithLength(10), WithLeaps(2), WithMode(music.Minor, realize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Generate(9, Options{AllowedLeaps: []int{2}, Mode: music.Minor, Realize: realize})
+	if got := g.Generate(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate() returned %d melodies, want the %d free of augmented and diminished intervals", len(got), len(want))
+	}
+	if all := Generate(9, Options{AllowedLeaps: []int{2}}); len(want) >= len(all) {
+		t.Errorf("the mode pruned nothing: %d melodies of %d", len(want), len(all))
+	}
+}
+
+func TestGenerator_Ambitus(t *testing.T) {
+	var st Stats
+	g, err := NewGenerator(WithLength(10), WithLeaps(2), WithAmbitus(-1, 4), WithStats(&st))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all Stats
+	var want [][]int
+	for _, melody := range Generate(9, Options{AllowedLeaps: []int{2}, Stats: &all}) {
+		if heights := music.PartialSums(melody); slices.Min(heights) >= -1 && slices.Max(heights) <= 4 {
+			want = append(want, melody)
+		}
+	}
+	if len(want) == 0 {
+		t.Fatal("no melody lies within the ambitus")
+	}
+	if got := g.Generate(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Generate() returned %d melodies, want the %d within the ambitus", len(got), len(want))
+	}
+	if st.Nodes >= all.Nodes {
+		t.Errorf("the ambitus pruned nothing: %d nodes, %d without it", st.Nodes, all.Nodes)
+	}
+	if got := CountCantus(9, Options{AllowedLeaps: []int{2}, Ambitus: &Ambitus{Low: -1, High: 4}}); got != len(want) {
+		t.Errorf("CountCantus() = %d, want %d", got, len(want))
+	}
+
+	// The window is counted from the final, not from a first note on the fifth
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.SetOpening(4); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{AllowedLeaps: []int{2}, Rules: ruleSet, Ambitus: &Ambitus{Low: 0, High: 5}}
+	for _, melody := range Generate(9, opts) {
+		if heights := music.PartialSums(melody); slices.Min(heights) < -4 || slices.Max(heights) > 1 {
+			t.Fatalf("melody %v from the fifth leaves the ambitus", melody)
+		}
+	}
+}
+
+func TestGenerator_GenerateLimited(t *testing.T) {
+	total := len(Generate(9, Options{AllowedLeaps: []int{2}}))
+	tests := []struct {
+		name          string
+		options       []Option
+		want          int
+		wantTruncated bool
+	}{
+		{"unbounded", nil, total, false},
+		{"limit", []Option{WithLimit(3)}, 3, true},
+		{"limit of all melodies", []Option{WithLimit(total)}, total, false},
+		{"callback", []Option{WithCallback(func([]int) bool { return false })}, 1, true},
+		{"memory limit", []Option{WithMaxMemory(1)}, -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGenerator(append([]Option{WithLength(10), WithLeaps(2)}, tt.options...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, truncated := g.GenerateLimited()
+			if truncated != tt.wantTruncated {
+				t.Errorf("GenerateLimited() truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+			if tt.want >= 0 && len(got) != tt.want {
+				t.Errorf("GenerateLimited() returned %d melodies, want %d", len(got), tt.want)
+			}
+		})
+	}
+
+	if _, err := NewGenerator(WithLength(MaxNotes+1), WithLeaps(4), WithMaxMemory(1<<30)); err != nil {
+		t.Errorf("NewGenerator() with a memory limit: %v", err)
+	}
+}
+
+func TestGenerator_Pull(t *testing.T) {
+	g, err := NewGenerator(WithLength(10), WithLeaps(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := slices.Collect(g.Seq())
+
+	next, stop := g.Pull()
+	var got [][]int
+	for melody, ok := next(); ok; melody, ok = next() {
+		got = append(got, melody)
+	}
+	stop()
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Pull() yielded %d melodies, want the %d of Seq", len(got), len(want))
+	}
+
+	// Stopping early ends the search
+	next, stop = g.Pull()
+	first, ok := next()
+	if !ok || !slices.Equal(first, want[0]) {
+		t.Fatalf("next() = %v, %v, want %v", first, ok, want[0])
+	}
+	stop()
+	stop()
+	if melody, ok := next(); ok {
+		t.Errorf("next() after stop = %v, want no melody", melody)
+	}
+}
+
+func TestGenerator_Rand(t *testing.T) {
+	sample := func() [][]int {
+		g, err := NewGenerator(WithLength(12), WithLeaps(2, 3), WithRand(rand.New(rand.NewSource(1))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g.Sample(5)
+	}
+	if first, second := sample(), sample(); !slices.EqualFunc(first, second, slices.Equal) {
+		t.Errorf("the same seed sampled %v and %v", first, second)
+	}
+}
+
+func TestGenerator_LongMelody(t *testing.T) {
+	if _, err := NewGenerator(WithLength(MaxLongNotes), WithLeaps(5)); !errors.Is(err, ErrUnbounded) {
+		t.Errorf("NewGenerator() error = %v, want ErrUnbounded", err)
+	}
+
+	g, err := NewGenerator(WithLength(MaxLongNotes), WithLeaps(5), WithLimit(3), WithTimeout(10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := g.Generate()
+	if len(result) != 3 {
+		t.Fatalf("Generate() returned %d melodies, want 3", len(result))
+	}
+	for _, melody := range result {
+		if len(melody) != MaxLongNotes-1 || !IsValidCantus(melody, g.Options()) {
+			t.Errorf("invalid melody %v", melody)
+		}
+	}
+}
+
+func TestGenerator_Timeout(t *testing.T) {
+	g, err := NewGenerator(WithLength(MaxLongNotes), WithLeaps(6), WithTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	g.Generate()
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Generate() took %s with a timeout of 50ms", elapsed)
+	}
+}
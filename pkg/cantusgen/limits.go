@@ -0,0 +1,62 @@
+package cantusgen
+
+import (
+	"iter"
+	"time"
+)
+
+// memoryCheckInterval is the number of search nodes visited between two reads of the heap size.
+const memoryCheckInterval = 1 << 12
+
+// Limits bounds an exhaustive search, so that a service can run it on any parameters
+// (see GenerateLimited).
+//
+// Fields:
+//   - MaxResults: the number of melodies after which the search stops; 0 means no limit
+//   - MaxMemoryBytes: the size of the live heap objects, in bytes, above which the search stops;
+//     0 means no limit. The heap of the whole program counts, not only the melodies found, and
+//     it is read every few thousand nodes, so the limit may be slightly exceeded.
+type Limits struct {
+	MaxResults     int
+	MaxMemoryBytes uint64
+}
+
+// GenerateLimited works like Generate but stops cleanly once a limit is reached, returning the
+// melodies found so far. truncated reports whether the search stopped before it was complete:
+// reaching MaxResults only truncates the result if the search finds one more melody. The search
+// runs on a single goroutine and the melodies come in the order of GenerateSeq, ranked by
+// penalty if the rule set has soft rules.
+func GenerateLimited(n int, opts Options, limits Limits) (melodies [][]int, truncated bool) {
+	for melody := range limitedSeq(n, opts, limits.MaxMemoryBytes, nil, &truncated) {
+		if limits.MaxResults > 0 && len(melodies) == limits.MaxResults {
+			truncated = true
+			break
+		}
+		melodies = append(melodies, melody)
+	}
+	if ruleSet := opts.ruleSet(); len(ruleSet.Soft()) > 0 {
+		rankByPenalty(melodies, ruleSet)
+	}
+	return melodies, truncated
+}
+
+// limitedSeq yields the melodies of GenerateSeq until the search is complete, the live heap
+// exceeds maxMemory bytes or the deadline passes, limits that are ignored if zero, and sets
+// *truncated if one of them stopped it.
+func limitedSeq(n int, opts Options, maxMemory uint64, deadline *time.Time, truncated *bool) iter.Seq[[]int] {
+	if maxMemory == 0 && deadline == nil {
+		return GenerateSeq(n, opts)
+	}
+	nodes := 0
+	stop := func() bool {
+		nodes++
+		if maxMemory > 0 && nodes%memoryCheckInterval == 0 && heapSize() > maxMemory {
+			*truncated = true
+		}
+		if deadline != nil && nodes%deadlineCheckInterval == 0 && time.Now().After(*deadline) {
+			*truncated = true
+		}
+		return *truncated
+	}
+	return generateSeq(n, opts, stop)
+}
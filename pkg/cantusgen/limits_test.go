@@ -0,0 +1,45 @@
+package cantusgen
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGenerateLimited(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2, 3}}
+	all := slices.Collect(GenerateSeq(10, opts))
+	if len(all) < 10 {
+		t.Fatalf("GenerateSeq returned only %d melodies", len(all))
+	}
+
+	tests := []struct {
+		name          string
+		limits        Limits
+		want          int
+		wantTruncated bool
+	}{
+		{"no limits", Limits{}, len(all), false},
+		{"fewer results", Limits{MaxResults: 5}, 5, true},
+		{"all results", Limits{MaxResults: len(all)}, len(all), false},
+		{"more results", Limits{MaxResults: len(all) + 1}, len(all), false},
+		{"enough memory", Limits{MaxMemoryBytes: 1 << 40}, len(all), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := GenerateLimited(10, opts, tt.limits)
+			if truncated != tt.wantTruncated {
+				t.Errorf("GenerateLimited() truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+			if !slices.EqualFunc(got, all[:tt.want], slices.Equal) {
+				t.Errorf("GenerateLimited() returned %d melodies, want the first %d of GenerateSeq", len(got), tt.want)
+			}
+		})
+	}
+
+	// The heap is always larger than a byte: the search stops at the first reading
+	got, truncated := GenerateLimited(10, opts, Limits{MaxMemoryBytes: 1})
+	if !truncated || len(got) == len(all) {
+		t.Errorf("GenerateLimited() with no memory returned %d melodies, truncated = %v", len(got), truncated)
+	}
+}
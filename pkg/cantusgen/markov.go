@@ -0,0 +1,111 @@
+package cantusgen
+
+import (
+	"math/rand"
+	"slices"
+)
+
+// transitionSmoothing is the weight added to every transition, so that the intervals a
+// model has never seen after another one are still tried, only later.
+const transitionSmoothing = 0.5
+
+// Transitions weighs the choice of the next interval of a melody by the interval before it,
+// as a Markov chain: a randomized search with transitions (see Options.Transitions) tries the
+// candidate intervals in a random order favoring the heavier ones, so that it finds typical
+// melodies first instead of uniformly random ones. Weights are typically counts of the
+// transitions in a corpus (see LearnTransitions); every weight is increased by a small
+// constant, so that the transitions missing from the model are merely unlikely.
+//
+// Fields:
+//   - First: the weight of every interval opening the melody
+//   - Next: the weight of every interval following another one, e.g. Next[3][-1] for a step
+//     down after a fourth up
+type Transitions struct {
+	First map[int]float64
+	Next  map[int]map[int]float64
+}
+
+// fuxCantus holds the cantus firmi of Fux's Gradus ad Parnassum in the dorian, lydian,
+// mixolydian, aeolian and ionian modes, as intervals.
+var fuxCantus = [][]int{
+	{2, -1, -1, 3, -1, 2, -1, -1, -1, -1},           // D F E D G F A G F E D
+	{1, 1, -2, -2, 1, 1, 4, -2, -2, 1, -1},          // F G A F D E F C A F G F
+	{3, -1, -2, 3, 2, -1, 3, -2, -2, 1, -2, -1, -1}, // G C B G C E D G E C D B A G
+	{2, -1, 2, -1, 2, 1, -1, -1, -1, -1, -1},        // A C B D C E F E D C B A
+	{2, 1, 1, -2, 3, -1, -2, 1, -1, -1, -1},         // C E F G E A G E F E D C
+}
+
+// DefaultTransitions returns transitions learned from the cantus firmi of Fux's Gradus ad
+// Parnassum (see LearnTransitions).
+func DefaultTransitions() Transitions {
+	return LearnTransitions(fuxCantus)
+}
+
+// LearnTransitions returns the transitions of the melodies, given as intervals: the number of
+// melodies opening with every interval and the number of times every interval follows another.
+func LearnTransitions(melodies [][]int) Transitions {
+	t := Transitions{First: map[int]float64{}, Next: map[int]map[int]float64{}}
+	for _, m := range melodies {
+		if len(m) == 0 {
+			continue
+		}
+		t.First[m[0]]++
+		for i := 1; i < len(m); i++ {
+			if t.Next[m[i-1]] == nil {
+				t.Next[m[i-1]] = map[int]float64{}
+			}
+			t.Next[m[i-1]][m[i]]++
+		}
+	}
+	return t
+}
+
+// Weight returns the weight of the interval extending the prefix of a melody, smoothing included.
+func (t Transitions) Weight(prefix []int, next int) float64 {
+	if len(prefix) == 0 {
+		return t.First[next] + transitionSmoothing
+	}
+	return t.Next[prefix[len(prefix)-1]][next] + transitionSmoothing
+}
+
+// order returns the candidates extending the prefix in a random order drawn from rng: each
+// position is filled with one of the remaining candidates chosen with a probability
+// proportional to its weight.
+func (t Transitions) order(prefix, candidates []int, rng *rand.Rand) []int {
+	remaining := slices.Clone(candidates)
+	weights := make([]float64, len(remaining))
+	total := 0.0
+	for i, val := range remaining {
+		weights[i] = t.Weight(prefix, val)
+		total += weights[i]
+	}
+
+	result := make([]int, 0, len(candidates))
+	for len(remaining) > 0 {
+		r := rng.Float64() * total
+		i := 0
+		for ; i < len(remaining)-1 && r >= weights[i]; i++ {
+			r -= weights[i]
+		}
+		result = append(result, remaining[i])
+		total -= weights[i]
+		remaining = slices.Delete(remaining, i, i+1)
+		weights = slices.Delete(weights, i, i+1)
+	}
+	return result
+}
+
+// randomOrder returns the order in which the randomized searches try the candidate intervals:
+// weighted by opts.Transitions if set, or else uniformly random.
+func (opts Options) randomOrder() func(prefix, candidates []int) []int {
+	rng := opts.rng()
+	if opts.Transitions != nil {
+		t := *opts.Transitions
+		return func(prefix, candidates []int) []int {
+			return t.order(prefix, candidates, rng)
+		}
+	}
+	return func(_, candidates []int) []int {
+		return shuffle(candidates, rng)
+	}
+}
@@ -0,0 +1,85 @@
+package cantusgen
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestLearnTransitions(t *testing.T) {
+	tr := LearnTransitions([][]int{{2, -1, -1}, {2, -1, 1}, {}})
+	if tr.First[2] != 2 || tr.Next[2][-1] != 2 || tr.Next[-1][-1] != 1 || tr.Next[-1][1] != 1 {
+		t.Errorf("LearnTransitions() = %+v", tr)
+	}
+
+	tests := []struct {
+		prefix []int
+		next   int
+		want   float64
+	}{
+		{nil, 2, 2 + transitionSmoothing},
+		{nil, -1, transitionSmoothing},
+		{[]int{1, 2}, -1, 2 + transitionSmoothing},
+		{[]int{3}, 1, transitionSmoothing},
+	}
+	for _, tt := range tests {
+		if got := tr.Weight(tt.prefix, tt.next); got != tt.want {
+			t.Errorf("Weight(%v, %d) = %v, want %v", tt.prefix, tt.next, got, tt.want)
+		}
+	}
+}
+
+func TestTransitions_Order(t *testing.T) {
+	tr := Transitions{First: map[int]float64{3: 1000}}
+	rng := rand.New(rand.NewSource(1))
+	candidates := []int{-1, 1, 2, -2, 3}
+
+	first := 0
+	for range 100 {
+		got := tr.order(nil, candidates, rng)
+		if !slices.Equal(slices.Sorted(slices.Values(got)), slices.Sorted(slices.Values(candidates))) {
+			t.Fatalf("order() = %v, not a permutation of %v", got, candidates)
+		}
+		if got[0] == 3 {
+			first++
+		}
+	}
+	if first < 95 {
+		t.Errorf("the heaviest candidate came first %d times out of 100", first)
+	}
+}
+
+func TestGenerateRandom_Transitions(t *testing.T) {
+	transitions := DefaultTransitions()
+	opts := Options{AllowedLeaps: []int{2, 3}, Transitions: &transitions}
+
+	opts.Rand = rand.New(rand.NewSource(7))
+	melody := GenerateRandom(10, opts)
+	if melody == nil {
+		t.Fatal("no melody found")
+	}
+	if !slices.ContainsFunc(Generate(10, Options{AllowedLeaps: []int{2, 3}}), func(m []int) bool {
+		return slices.Equal(m, melody)
+	}) {
+		t.Errorf("GenerateRandom returned %v, not a valid melody", melody)
+	}
+
+	opts.Rand = rand.New(rand.NewSource(7))
+	if again := GenerateRandom(10, opts); !slices.Equal(again, melody) {
+		t.Errorf("same seed returned %v, then %v", melody, again)
+	}
+
+	// Opening with a step up is by far the most likely choice
+	skewed := Transitions{First: map[int]float64{1: 1000}}
+	opts = Options{AllowedLeaps: []int{2, 3}, Transitions: &skewed, Rand: rand.New(rand.NewSource(1))}
+	up := 0
+	samples := SampleCantus(10, 20, opts)
+	for _, m := range samples {
+		if m[0] == 1 {
+			up++
+		}
+	}
+	if up < len(samples)*3/4 {
+		t.Errorf("%d of %d sampled melodies open with a step up, want most", up, len(samples))
+	}
+}
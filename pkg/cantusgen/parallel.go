@@ -0,0 +1,185 @@
+package cantusgen
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// parallelDepth is the number of leading intervals the parallel search splits the search tree on.
+// Two intervals give a few dozen subtrees, enough to keep every core busy while each subtree
+// is still large enough to outweigh the cost of handing it to a worker.
+const parallelDepth = 2
+
+// subtree is the part of the search tree below a prefix of a melody.
+type subtree struct {
+	prefix []int
+	sum    int
+	leaps  int
+	// weight is the estimated share of the search tree below the prefix (see Progress)
+	weight float64
+}
+
+// subtrees returns the subtrees below the prefixes of depth intervals accepted by the
+// partial rules and still able to reach the final, in the order the search visits them.
+// Their weights, shared out like walk's, are scaled to sum to 1.
+func (s *search) subtrees(depth int) []subtree {
+	var result []subtree
+	total := 0.0
+	var extend func(prefix []int, sum, leaps int, weight float64)
+	extend = func(prefix []int, sum, leaps int, weight float64) {
+		if len(prefix) == depth {
+			result = append(result, subtree{prefix: slices.Clone(prefix), sum: sum, leaps: leaps, weight: weight})
+			total += weight
+			return
+		}
+		candidates := s.candidates(leaps)
+		for _, val := range candidates {
+			nextLeaps := leaps
+			if utils.Abs(val) > 1 {
+				nextLeaps++
+			}
+			if !s.reachable(len(prefix)+1, sum+val, nextLeaps) {
+				continue
+			}
+			if s.push(val) {
+				extend(append(prefix, val), sum+val, nextLeaps, weight/float64(len(candidates)))
+			}
+			s.pop()
+		}
+	}
+	extend(nil, 0, 0, 1)
+	for i := range result {
+		result[i].weight /= total
+	}
+	return result
+}
+
+// explore calls visit for every valid complete melody of the subtree, like walk, and reports
+// whether the whole subtree was explored.
+func (s *search) explore(t subtree, visit func([]int) bool) bool {
+	// The prefix was accepted when the subtree was found, so pushing it again rejects nothing
+	for _, val := range t.prefix {
+		s.push(val)
+	}
+	prefix := make([]int, len(t.prefix), s.n)
+	copy(prefix, t.prefix)
+	s.weight = t.weight
+	complete := s.walk(prefix, t.sum, t.leaps, visit)
+	for range t.prefix {
+		s.pop()
+	}
+	return complete
+}
+
+// splitSearch returns the subtrees the search for cantus firmi of n intervals is split into:
+// those below the prefixes of parallelDepth intervals, or the whole tree if the melody is
+// too short to split or only one worker may run. It returns nil if no melody can satisfy
+// the parameters.
+func splitSearch(n int, opts Options) []subtree {
+	if runtime.GOMAXPROCS(0) == 1 {
+		return splitSearchAt(n, opts, 0)
+	}
+	return splitSearchAt(n, opts, parallelDepth)
+}
+
+// splitSearchAt works like splitSearch, splitting the search tree on the first depth
+// intervals whatever the number of workers.
+func splitSearchAt(n int, opts Options, depth int) []subtree {
+	s := newSearch(n, opts)
+	if s == nil {
+		return nil
+	}
+	// The final two intervals are added by walk, so short melodies are not split
+	if n-2 < depth || depth == 0 {
+		return []subtree{{weight: 1}}
+	}
+	return s.subtrees(depth)
+}
+
+// exploreParallel explores the subtrees on up to GOMAXPROCS workers, each with its own
+// search, and calls visit(i, melody) for every valid complete melody of the i-th subtree.
+// Calls for the same subtree are sequential and follow the order of the search; calls for
+// different subtrees may run concurrently. If reuse is set, every worker writes the melodies
+// it finds into a single slice of its own, which visit must not retain. Every worker counts its rejections in
+// its own Stats, which are added to opts.Stats at the end, and reports its progress to
+// opts.Progress, if set, through a shared tracker.
+func exploreParallel(n int, opts Options, trees []subtree, reuse bool, visit func(tree int, melody []int)) {
+	exploreTrees(n, opts, trees, reuse, nil, visit, nil)
+}
+
+// exploreTrees works like exploreParallel, stopping every worker as soon as stop, if set,
+// returns true; stop must be safe for concurrent use. explored, if set, is called with the
+// index of every subtree once it is explored completely, after the calls to visit for its
+// melodies. exploreTrees reports whether every subtree was explored.
+func exploreTrees(n int, opts Options, trees []subtree, reuse bool, stop func() bool,
+	visit func(tree int, melody []int), explored func(tree int)) bool {
+	defer opts.Stats.since(time.Now())
+	workers := min(runtime.GOMAXPROCS(0), len(trees))
+	stats := make([]*Stats, workers)
+	tracker := newProgressTracker(opts.Progress)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	complete := true
+	for w := range workers {
+		workerOpts := opts
+		if opts.Stats != nil {
+			stats[w] = &Stats{}
+			workerOpts.Stats = stats[w]
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ws := newSearch(n, workerOpts)
+			if reuse {
+				ws.final = make([]int, n)
+			}
+			ws.stop = stop
+			ws.progress = tracker.counter()
+			for i := range jobs {
+				ok := ws.explore(trees[i], func(melody []int) bool {
+					visit(i, melody)
+					return true
+				})
+				if !ok {
+					mu.Lock()
+					complete = false
+					mu.Unlock()
+					continue
+				}
+				if explored != nil {
+					explored(i)
+				}
+			}
+			ws.progress.flush()
+		}()
+	}
+	for i := range trees {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if complete {
+		tracker.finish()
+	}
+
+	for _, st := range stats {
+		opts.Stats.add(st)
+	}
+	return complete
+}
+
+// generateParallel returns the melodies of Generate, unranked, in the order of the search:
+// the melodies of every subtree of splitSearch, concatenated in the order of the subtrees.
+func generateParallel(n int, opts Options) [][]int {
+	trees := splitSearch(n, opts)
+	found := make([][][]int, len(trees))
+	exploreParallel(n, opts, trees, false, func(i int, melody []int) {
+		found[i] = append(found[i], melody)
+	})
+	return slices.Concat(found...)
+}
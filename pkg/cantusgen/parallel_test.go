@@ -0,0 +1,39 @@
+package cantusgen
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestGenerateParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	tests := []struct {
+		name string
+		n    int
+		opts Options
+	}{
+		{"two leaps", 9, Options{AllowedLeaps: []int{2}}},
+		{"up to four leaps", 11, Options{AllowedLeaps: []int{2, 3, 4}}},
+		{"restricted degrees", 9, Options{AllowedLeaps: []int{1, 2}, Degrees: []int{1, 2, 3, 4, 5}}},
+		{"too short to split", 3, Options{AllowedLeaps: []int{0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequentialOpts, parallelOpts := tt.opts, tt.opts
+			sequentialOpts.Stats, parallelOpts.Stats = &Stats{}, &Stats{}
+
+			sequential := slices.Collect(GenerateSeq(tt.n, sequentialOpts))
+			parallel := generateParallel(tt.n, parallelOpts)
+			if !slices.EqualFunc(parallel, sequential, slices.Equal) {
+				t.Fatalf("parallel search found %d melodies, want the %d of the sequential search in the same order",
+					len(parallel), len(sequential))
+			}
+			if got, want := parallelOpts.Stats.Ranking(), sequentialOpts.Stats.Ranking(); !slices.Equal(got, want) {
+				t.Errorf("parallel rejections = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cantusgen
+
+import "sync"
+
+// progressInterval is the number of nodes a search explores between two progress reports.
+const progressInterval = 1 << 14
+
+// Progress reports how far a search has come (see Options.Progress).
+//
+// Fields:
+//   - Nodes: the number of candidate intervals tried so far
+//   - Found: the number of melodies found so far
+//   - Fraction: the estimated share of the search tree explored, from 0 to 1. Every node
+//     shares its part of the tree equally among its candidates, so the estimate moves
+//     evenly only when the subtrees are of similar sizes; it is 1 once the search is over.
+type Progress struct {
+	Nodes    int
+	Found    int
+	Fraction float64
+}
+
+// progressTracker gathers the progress of the searches exploring one search tree and
+// reports it to the callback of Options.Progress, one call at a time.
+type progressTracker struct {
+	report func(Progress)
+
+	mu    sync.Mutex
+	total Progress
+}
+
+// newProgressTracker returns a tracker reporting to report, or nil if report is nil.
+func newProgressTracker(report func(Progress)) *progressTracker {
+	if report == nil {
+		return nil
+	}
+	return &progressTracker{report: report}
+}
+
+// counter returns a counter for one of the searches, nil if the tracker is nil.
+func (t *progressTracker) counter() *progressCounter {
+	if t == nil {
+		return nil
+	}
+	return &progressCounter{tracker: t}
+}
+
+// finish reports the search as complete. It does nothing on a nil tracker.
+func (t *progressTracker) finish() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total.Fraction = 1
+	t.report(t.total)
+}
+
+// progressCounter counts the progress of a single search, adding it to the tracker every
+// progressInterval nodes. Its methods do nothing on a nil counter.
+type progressCounter struct {
+	tracker *progressTracker
+	nodes   int
+	found   int
+	done    float64
+}
+
+// node records a candidate interval tried.
+func (c *progressCounter) node() {
+	if c == nil {
+		return
+	}
+	c.nodes++
+	if c.nodes == progressInterval {
+		c.flush()
+	}
+}
+
+// melody records a melody found.
+func (c *progressCounter) melody() {
+	if c != nil {
+		c.found++
+	}
+}
+
+// advance records a part of the search tree, as a share of the whole, as explored.
+func (c *progressCounter) advance(share float64) {
+	if c != nil {
+		c.done += share
+	}
+}
+
+// flush adds the progress counted since the last flush to the tracker and reports the total.
+func (c *progressCounter) flush() {
+	if c == nil {
+		return
+	}
+	t := c.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total.Nodes += c.nodes
+	t.total.Found += c.found
+	t.total.Fraction = min(t.total.Fraction+c.done, 1)
+	c.nodes, c.found, c.done = 0, 0, 0
+	t.report(t.total)
+}
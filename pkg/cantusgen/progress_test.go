@@ -0,0 +1,68 @@
+package cantusgen
+
+import (
+	"runtime"
+	"testing"
+)
+
+// checkProgress checks that the reports never go back and that the last one reports the
+// complete search.
+func checkProgress(t *testing.T, name string, reports []Progress, found int) {
+	t.Helper()
+	if len(reports) == 0 {
+		t.Fatalf("%s: no progress reported", name)
+	}
+	for i := 1; i < len(reports); i++ {
+		prev, cur := reports[i-1], reports[i]
+		if cur.Nodes < prev.Nodes || cur.Found < prev.Found || cur.Fraction < prev.Fraction || cur.Fraction > 1 {
+			t.Fatalf("%s: progress went from %+v to %+v", name, prev, cur)
+		}
+	}
+	last := reports[len(reports)-1]
+	if last.Found != found || last.Fraction != 1 || last.Nodes == 0 {
+		t.Errorf("%s: last report %+v, want %d melodies found and the search complete", name, last, found)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, procs := range []int{1, 4} {
+		runtime.GOMAXPROCS(procs)
+
+		var reports []Progress
+		opts := Options{AllowedLeaps: []int{2, 3, 4}, Progress: func(p Progress) {
+			reports = append(reports, p)
+		}}
+		result := Generate(11, opts)
+		checkProgress(t, "Generate", reports, len(result))
+		if len(reports) < 3 {
+			t.Errorf("Generate reported progress %d times, want several", len(reports))
+		}
+
+		reports = nil
+		count := CountCantus(11, opts)
+		checkProgress(t, "CountCantus", reports, count)
+
+		reports = nil
+		found := 0
+		for range GenerateSeq(11, opts) {
+			found++
+		}
+		checkProgress(t, "GenerateSeq", reports, found)
+	}
+}
+
+func TestProgress_Stopped(t *testing.T) {
+	var reports []Progress
+	opts := Options{AllowedLeaps: []int{2, 3, 4}, Progress: func(p Progress) {
+		reports = append(reports, p)
+	}}
+	for range GenerateSeq(11, opts) {
+		break
+	}
+	for _, p := range reports {
+		if p.Fraction == 1 {
+			t.Errorf("an interrupted search reported %+v", p)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package cantusgen
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// randomNodeBudget is the number of search nodes a single randomized attempt may visit
+// before it is abandoned and the search restarts from scratch.
+const randomNodeBudget = 2000
+
+// nodeBudget returns the number of search nodes a randomized attempt for a melody of
+// n intervals may visit: randomNodeBudget up to MaxNotes notes, doubled for every two
+// notes beyond, as the barren subtrees of long melodies are deeper.
+func nodeBudget(n int) int {
+	return randomNodeBudget << max(0, (n+1-MaxNotes)/2)
+}
+
+// randomMaxRestarts bounds the number of randomized attempts made by GenerateRandom.
+const randomMaxRestarts = 500
+
+// GenerateRandom returns a single random cantus firmus satisfying the same conditions as Generate,
+// or nil if none was found.
+//
+// Instead of enumerating every melody and picking one, it performs randomized backtracking:
+// candidate intervals are tried in random order, and an attempt that visits more than
+// randomNodeBudget nodes (more for melodies longer than MaxNotes, see nodeBudget) without
+// success is abandoned and restarted with a new random order.
+// Restarts keep the search from getting stuck in large barren subtrees, so a melody of
+// up to 12 notes is typically found within a few milliseconds. Because the search gives up
+// after randomMaxRestarts attempts, nil does not prove that no melody exists.
+func GenerateRandom(n int, opts Options) []int {
+	defer opts.Stats.since(time.Now())
+	r := newRandomSearch(n, opts)
+	if r == nil {
+		return nil
+	}
+
+	for attempt := 0; attempt < randomMaxRestarts; attempt++ {
+		if found := r.attempt(); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// SampleCantus returns up to k distinct random cantus firmi satisfying the same conditions
+// as Generate, without enumerating them all. Every melody is found by a separate attempt
+// of the randomized backtracking of GenerateRandom, so the melodies do not share a common
+// beginning the way consecutive melodies of Generate do. The search gives up after
+// randomMaxRestarts attempts that found no new melody, so fewer than k melodies are returned
+// when there are not many more than k, and they are then likely, but not certain, to be all.
+// When the rule set has soft rules, the melodies are ranked by their penalty like Generate's.
+func SampleCantus(n, k int, opts Options) [][]int {
+	defer opts.Stats.since(time.Now())
+	r := newRandomSearch(n, opts)
+	if r == nil || k <= 0 {
+		return nil
+	}
+
+	var result [][]int
+	seen := make(map[string]bool)
+	for failures := 0; len(result) < k && failures < randomMaxRestarts; {
+		found := r.attempt()
+		key := fmt.Sprint(found)
+		if found == nil || seen[key] {
+			failures++
+			continue
+		}
+		seen[key] = true
+		result = append(result, found)
+	}
+
+	if ruleSet := opts.ruleSet(); len(ruleSet.Soft()) > 0 {
+		rankByPenalty(result, ruleSet)
+	}
+	return result
+}
+
+// randomSearch is a search trying the candidate intervals in random order and abandoning
+// an attempt after budget nodes.
+type randomSearch struct {
+	*search
+	nodes  int
+	budget int
+}
+
+// newRandomSearch prepares a randomized search for cantus firmi of n intervals.
+// It returns nil if no melody can satisfy the parameters.
+func newRandomSearch(n int, opts Options) *randomSearch {
+	s := newSearch(n, opts)
+	if s == nil {
+		return nil
+	}
+
+	r := &randomSearch{search: s, budget: nodeBudget(n)}
+	s.order = opts.randomOrder()
+	s.stop = func() bool {
+		r.nodes++
+		return r.nodes > r.budget
+	}
+	return r
+}
+
+// attempt runs one randomized attempt and returns the first melody it finds,
+// or nil if the attempt was abandoned.
+func (r *randomSearch) attempt() []int {
+	r.nodes = 0
+
+	var found []int
+	r.walk(make([]int, 0, r.n), 0, 0, func(finalSlice []int) bool {
+		found = finalSlice
+		return false
+	})
+	return found
+}
+
+// shuffle returns the candidates in random order, drawn from rng.
+func shuffle(candidates []int, rng *rand.Rand) []int {
+	shuffled := slices.Clone(candidates)
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
@@ -0,0 +1,143 @@
+package cantusgen
+
+import (
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestGenerateRandom(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		opts Options
+	}{
+		{"n=7 with 1-2 leaps", 7, Options{AllowedLeaps: []int{1, 2}}},
+		{"n=9 with 2-3 leaps", 9, Options{AllowedLeaps: []int{2, 3}}},
+		{"n=11 with 3 leaps", 11, Options{AllowedLeaps: []int{3}}},
+		{"n=11 with restricted degrees", 11, Options{AllowedLeaps: []int{2, 3}, Degrees: []int{1, 2, 3, 4, 5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequence := GenerateRandom(tt.n, tt.opts)
+			if sequence == nil {
+				t.Fatalf("GenerateRandom(%d, %+v) returned nil", tt.n, tt.opts)
+			}
+			if len(sequence) != tt.n {
+				t.Errorf("Expected sequence length %d, got %d", tt.n, len(sequence))
+			}
+
+			sum := 0
+			for _, val := range sequence {
+				sum += val
+			}
+			if sum != 0 {
+				t.Errorf("Expected sum 0, got %d for sequence %v", sum, sequence)
+			}
+
+			if !rules.AllRules(sequence, validators(append(tt.opts.partialRules(), tt.opts.completeRules()...))) {
+				t.Errorf("Sequence %v violates the generator rules", sequence)
+			}
+		})
+	}
+}
+
+func TestGenerateRandom_InvalidInput(t *testing.T) {
+	if result := GenerateRandom(1, Options{AllowedLeaps: []int{1}}); result != nil {
+		t.Errorf("Expected nil result for n=1, got %v", result)
+	}
+	if result := GenerateRandom(10, Options{}); result != nil {
+		t.Errorf("Expected nil result without allowed leaps, got %v", result)
+	}
+}
+
+func TestSampleCantus(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2, 3}}
+	sample := SampleCantus(11, 20, opts)
+	if len(sample) != 20 {
+		t.Fatalf("SampleCantus(11, 20) returned %d melodies, want 20", len(sample))
+	}
+	seen := make(map[string]bool)
+	for _, sequence := range sample {
+		if !IsValidCantus(sequence, opts) {
+			t.Errorf("Sequence %v is invalid", sequence)
+		}
+		if key := fmt.Sprint(sequence); seen[key] {
+			t.Errorf("Sequence %v was sampled twice", sequence)
+		} else {
+			seen[key] = true
+		}
+	}
+
+	// Asking for more melodies than exist stops after too many attempts without a new one
+	all := Generate(7, Options{AllowedLeaps: []int{1}})
+	got := SampleCantus(7, len(all)+10, Options{AllowedLeaps: []int{1}})
+	if len(got) == 0 || len(got) > len(all) {
+		t.Errorf("SampleCantus(7, %d) returned %d melodies, want 1 to %d", len(all)+10, len(got), len(all))
+	}
+	for _, sequence := range got {
+		if !slices.ContainsFunc(all, func(s []int) bool { return slices.Equal(s, sequence) }) {
+			t.Errorf("Sequence %v is not generated by Generate", sequence)
+		}
+	}
+
+	if got := SampleCantus(11, 0, opts); got != nil {
+		t.Errorf("SampleCantus(11, 0) = %v, want nil", got)
+	}
+	if got := SampleCantus(1, 5, opts); got != nil {
+		t.Errorf("SampleCantus(1, 5) = %v, want nil", got)
+	}
+}
+
+func TestSampleCantus_Seeded(t *testing.T) {
+	sample := func(seed int64) [][]int {
+		return SampleCantus(11, 10, Options{AllowedLeaps: []int{2, 3}, Rand: rand.New(rand.NewSource(seed))})
+	}
+	first, second := sample(7), sample(7)
+	if !slices.EqualFunc(first, second, slices.Equal) {
+		t.Errorf("the same seed sampled %v and %v", first, second)
+	}
+	if slices.EqualFunc(first, sample(8), slices.Equal) {
+		t.Error("different seeds sampled the same melodies")
+	}
+
+	random := func(seed int64) []int {
+		return GenerateRandom(11, Options{AllowedLeaps: []int{2, 3}, Rand: rand.New(rand.NewSource(seed))})
+	}
+	if a, b := random(7), random(7); !slices.Equal(a, b) {
+		t.Errorf("the same seed generated %v and %v", a, b)
+	}
+}
+
+func BenchmarkSampleCantus(b *testing.B) {
+	for b.Loop() {
+		SampleCantus(13, 20, Options{AllowedLeaps: []int{2, 3, 4}})
+	}
+}
+
+func BenchmarkGenerateRandom(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		GenerateRandom(11, Options{AllowedLeaps: []int{2, 3, 4}})
+	}
+}
+
+func TestNodeBudget(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{7, randomNodeBudget},
+		{15, randomNodeBudget},
+		{16, randomNodeBudget},
+		{17, 2 * randomNodeBudget},
+		{23, 16 * randomNodeBudget},
+	}
+	for _, tt := range tests {
+		if got := nodeBudget(tt.n); got != tt.want {
+			t.Errorf("nodeBudget(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
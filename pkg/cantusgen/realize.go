@@ -0,0 +1,203 @@
+package cantusgen
+
+import (
+	"fmt"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+)
+
+// minorLookahead is the number of following notes the alteration of a note may depend on in
+// a realization in melodic minor, or with augmented seconds avoided (see music.RealizeOptions):
+// a G is raised before F-G-A or within A-G-F-G-A, and an F next to a raised G.
+const minorLookahead = 4
+
+// realizationRules returns the rules on the realization in opts.Mode checked during the search,
+// both named RuleAugmentedDiminished: a partial rule rejecting the beginnings of melodies whose
+// realization cannot satisfy rules.IsFreeOfAugmentedDiminished (see
+// rules.FreeOfAugmentedDiminishedPrefix), and a complete rule checking it. With opts.Against
+// set, a partial and a complete rule named RuleCounterpoint check the vertical intervals with
+// the realized voice in the same way (see rules.ConsonantVerticals). All are nil when opts.Mode
+// is not set.
+func (opts Options) realizationRules() (partial, complete []rules.Rule) {
+	if opts.Mode == 0 {
+		return nil, nil
+	}
+
+	realizeOpts := opts.Realize
+	realizeOpts.Opening = opts.ruleSet().Opening()
+	realizeOpts.Range = nil
+	mode := opts.Mode.String()
+	realize := func(intervals []int) (music.Realization, bool) {
+		cf := make(music.CantusFirmus, len(intervals))
+		for i, val := range intervals {
+			cf[i] = music.Interval(val)
+		}
+		r, err := cf.RealizeWithOptions(mode, realizeOpts)
+		return r, err == nil
+	}
+	// Only the notes the following ones can no longer alter are checked on a beginning
+	settled := 0
+	if opts.Mode == music.Minor && (realizeOpts.Minor == music.MinorMelodic || realizeOpts.AvoidAugmentedSeconds) {
+		settled = minorLookahead
+	}
+
+	description := fmt.Sprintf("Augmented and diminished intervals of the realization in %s must be framed "+
+		"by stepwise motion and not outlined by a line in one direction.", mode)
+	partial = []rules.Rule{{
+		Name:        RuleAugmentedDiminished,
+		Description: description,
+		Partial:     true,
+		Check: func(intervals []int) bool {
+			r, ok := realize(intervals)
+			return ok && rules.FreeOfAugmentedDiminishedPrefix(r[:max(0, len(r)-settled)])
+		},
+		Incremental: func() rules.IncrementalRule {
+			return &qualityTracker{realize: realize, settled: settled}
+		},
+	}}
+	complete = []rules.Rule{{
+		Name:        RuleAugmentedDiminished,
+		Description: description,
+		Check: func(intervals []int) bool {
+			r, ok := realize(intervals)
+			return ok && rules.IsFreeOfAugmentedDiminished(r)
+		},
+	}}
+
+	if opts.Against != nil {
+		voice, err := opts.Against.Realize(opts.Mode, realizeOpts)
+		consonant := func(intervals []int, settled int) bool {
+			r, ok := realize(intervals)
+			return ok && err == nil && rules.ConsonantVerticals(r[:max(0, len(r)-settled)], voice)
+		}
+		description := fmt.Sprintf("The vertical intervals with the voice realized in %s must not be "+
+			"augmented or diminished.", mode)
+		partial = append(partial, rules.Rule{
+			Name:        RuleCounterpoint,
+			Description: description,
+			Partial:     true,
+			Check:       func(intervals []int) bool { return consonant(intervals, settled) },
+		})
+		complete = append(complete, rules.Rule{
+			Name:        RuleCounterpoint,
+			Description: description,
+			Check:       func(intervals []int) bool { return consonant(intervals, 0) },
+		})
+	}
+	return partial, complete
+}
+
+// qualityTracker checks the partial rule of realizationRules one interval at a time: each new
+// interval settles one more note, whose pairs and run are checked (see
+// rules.FreeOfAugmentedDiminishedAt).
+type qualityTracker struct {
+	realize   func(intervals []int) (music.Realization, bool)
+	settled   int
+	intervals []int
+}
+
+func (q *qualityTracker) Push(interval int) bool {
+	q.intervals = append(q.intervals, interval)
+	r, ok := q.realize(q.intervals)
+	if !ok {
+		return false
+	}
+	r = r[:max(0, len(r)-q.settled)]
+	return rules.FreeOfAugmentedDiminishedAt(r, len(r)-2)
+}
+
+func (q *qualityTracker) Pop() {
+	q.intervals = q.intervals[:len(q.intervals)-1]
+}
+
+// RealizationOptions configures the realization of generated melodies (see Realize and
+// GenerateRealizations).
+//
+// Fields:
+//   - Modes: the modes GenerateRealizations realizes the melodies in; nil selects all modes
+//   - Realize: how the melodies are realized (see music.RealizeOptions); the opening is
+//     always the one of the rule set
+//   - Range: if set, every realization is moved into it by whole octaves if it can be and
+//     must then fit it
+//   - Rules: rules on realized pitches checked in addition to those of the mode
+type RealizationOptions struct {
+	Modes   []music.Mode
+	Realize music.RealizeOptions
+	Range   *music.NoteRange
+	Rules   []rules.RealizationRule
+}
+
+// ModeRealizations holds the valid realizations of the generated melodies in one mode.
+type ModeRealizations struct {
+	Mode         music.Mode
+	Realizations []music.Realization
+}
+
+// Realize realizes the interval sequences in the mode and returns the realizations that
+// satisfy the rules on realized pitches, in the order of the sequences: the rules of the
+// mode for melodies framed as the rule set requires (see rules.RealizationRulesFrom),
+// the voice range if set and opts.Rules. A nil rule set selects all registered rules.
+// opts.Modes is ignored.
+func Realize(sequences [][]int, mode music.Mode, ruleSet *rules.RuleSet, opts RealizationOptions) []music.Realization {
+	if ruleSet == nil {
+		ruleSet = defaultRules
+	}
+	realizeOpts := opts.Realize
+	realizeOpts.Opening = ruleSet.Opening()
+
+	realizationRules := rules.RealizationRulesFrom(music.NewScale(mode), ruleSet.Opening(), ruleSet.Ending())
+	if opts.Range != nil {
+		realizationRules = append(realizationRules, rules.VoiceRangeRule(*opts.Range))
+	}
+	realizationRules = append(realizationRules, opts.Rules...)
+
+	var result []music.Realization
+	for _, seq := range sequences {
+		cf := make(music.CantusFirmus, len(seq))
+		for i, val := range seq {
+			cf[i] = music.Interval(val)
+		}
+
+		realization, err := cf.RealizeWithOptions(mode.String(), realizeOpts)
+		if err != nil {
+			continue
+		}
+		if opts.Range != nil {
+			realization = realization.ShiftInto(*opts.Range)
+		}
+		if rules.AllRealizationRules(realization, realizationRules) {
+			result = append(result, realization)
+		}
+	}
+	return result
+}
+
+// GenerateRealizations generates the cantus firmi of n intervals for every mode of
+// ropts.Modes and returns their valid realizations grouped by mode, in the order of the
+// modes. The melodies of each mode are those of Generate with the rule set adapted to the
+// mode (see rules.RuleSet.SetMode) and pruned on their realization in it (see Options.Mode,
+// which, like Options.Realize, is replaced by the mode and ropts.Realize), realized and
+// filtered by Realize; opts.Rules is not modified. A mode without valid realizations has
+// an empty group.
+func GenerateRealizations(n int, opts Options, ropts RealizationOptions) []ModeRealizations {
+	modes := ropts.Modes
+	if modes == nil {
+		modes = music.Modes()
+	}
+
+	result := make([]ModeRealizations, 0, len(modes))
+	for _, mode := range modes {
+		ruleSet := opts.ruleSet().Clone()
+		// A rule set without the rule on the leading tone does not depend on the mode
+		_ = ruleSet.SetMode(mode)
+		modeOpts := opts
+		modeOpts.Rules = ruleSet
+		modeOpts.Mode = mode
+		modeOpts.Realize = ropts.Realize
+
+		sequences := Generate(n, modeOpts)
+		result = append(result, ModeRealizations{Mode: mode, Realizations: Realize(sequences, mode, ruleSet, ropts)})
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package cantusgen
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+)
+
+func TestRealize(t *testing.T) {
+	// A fourth up from the final: a perfect fourth in major, an augmented one in lydian
+	fourth := []int{3, -1, -1, -1}
+	steps := []int{1, 1, -1, -1}
+	high, err := music.ParseNoteRange("C5-C6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	narrow, err := music.ParseNoteRange("C4-D4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		sequences [][]int
+		mode      music.Mode
+		opts      RealizationOptions
+		want      []string
+	}{
+		{"major", [][]int{fourth, steps}, music.Major, RealizationOptions{},
+			[]string{"C4 F4 E4 D4 C4", "C4 D4 E4 D4 C4"}},
+		{"augmented fourth", [][]int{fourth, steps}, music.Lydian, RealizationOptions{},
+			[]string{"F4 G4 A4 G4 F4"}},
+		{"moved into the range", [][]int{steps}, music.Major, RealizationOptions{Range: &high},
+			[]string{"C5 D5 E5 D5 C5"}},
+		{"outside the range", [][]int{steps}, music.Major, RealizationOptions{Range: &narrow}, nil},
+		{"extra rule", [][]int{fourth, steps}, music.Major, RealizationOptions{Rules: []rules.RealizationRule{{
+			Name: "NoLeaps",
+			Check: func(r music.Realization) []rules.Violation {
+				if r[1].Step-r[0].Step > 1 {
+					return []rules.Violation{{Rule: "NoLeaps"}}
+				}
+				return nil
+			},
+		}}}, []string{"C4 D4 E4 D4 C4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range Realize(tt.sequences, tt.mode, nil, tt.opts) {
+				got = append(got, fmt.Sprint(r))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Realize(%v, %s) = %v, want %v", tt.sequences, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealize_Opening(t *testing.T) {
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.SetOpening(2); err != nil {
+		t.Fatal(err)
+	}
+	got := Realize([][]int{{-1, -1}}, music.Dorian, ruleSet, RealizationOptions{})
+	if len(got) != 1 || fmt.Sprint(got[0]) != "F4 E4 D4" {
+		t.Errorf("Realize from the third = %v, want [F4 E4 D4]", got)
+	}
+}
+
+func TestGenerateRealizations(t *testing.T) {
+	opts := Options{AllowedLeaps: []int{2}}
+	groups := GenerateRealizations(8, opts, RealizationOptions{})
+
+	if len(groups) != len(music.Modes()) {
+		t.Fatalf("GenerateRealizations returned %d groups, want one per mode", len(groups))
+	}
+	for i, group := range groups {
+		mode := music.Modes()[i]
+		if group.Mode != mode {
+			t.Errorf("group %d has mode %s, want %s", i, group.Mode, mode)
+		}
+
+		ruleSet := rules.DefaultRuleSet()
+		if err := ruleSet.SetMode(mode); err != nil {
+			t.Fatal(err)
+		}
+		want := Realize(Generate(8, Options{AllowedLeaps: []int{2}, Rules: ruleSet}), mode, ruleSet, RealizationOptions{})
+		if len(want) == 0 {
+			t.Fatalf("no realization in %s", mode)
+		}
+		if fmt.Sprint(group.Realizations) != fmt.Sprint(want) {
+			t.Errorf("%s: GenerateRealizations differs from Realize of Generate with the rule set of the mode", mode)
+		}
+	}
+}
+
+func TestGenerateRealizations_Modes(t *testing.T) {
+	groups := GenerateRealizations(8, Options{AllowedLeaps: []int{2}}, RealizationOptions{Modes: []music.Mode{music.Phrygian, music.Major}})
+	if len(groups) != 2 || groups[0].Mode != music.Phrygian || groups[1].Mode != music.Major {
+		t.Fatalf("GenerateRealizations returned groups %v, want Phrygian then Major", groups)
+	}
+	for _, group := range groups {
+		tonic := music.NewScale(group.Mode).Tonic
+		for _, r := range group.Realizations {
+			if r[0] != tonic || r[len(r)-1] != tonic {
+				t.Errorf("%s realization %v does not begin and end on %v", group.Mode, r, tonic)
+			}
+		}
+	}
+}
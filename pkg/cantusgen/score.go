@@ -0,0 +1,219 @@
+package cantusgen
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/analysis"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"slices"
+	"sort"
+)
+
+// ScoreFunc rates a complete cantus firmus; higher is better.
+type ScoreFunc func(intervals []int) float64
+
+// DefaultScore prefers mostly stepwise melodies that still use a wide range:
+// the share of steps among the intervals (0-1) plus half the range in steps
+// relative to a tenth (capped at 1).
+func DefaultScore(intervals []int) float64 {
+	if len(intervals) == 0 {
+		return 0
+	}
+
+	stepCount := 0
+	height, low, high := 0, 0, 0
+	for _, val := range intervals {
+		if slices.Contains(steps, val) {
+			stepCount++
+		}
+		height += val
+		low = min(low, height)
+		high = max(high, height)
+	}
+
+	return float64(stepCount)/float64(len(intervals)) + 0.5*min(float64(high-low)/9, 1)
+}
+
+// fullVariety is the number of different intervals a melody needs for the highest
+// IntervalVariety, e.g. steps both ways and two leaps each way.
+const fullVariety = 6
+
+// IntervalVariety rates the variety of the intervals of a melody from 0 to 1: the number
+// of different intervals it uses, ascending and descending ones counted apart, relative to
+// fullVariety (capped at 1).
+func IntervalVariety(intervals []int) float64 {
+	distinct := slices.Compact(slices.Sorted(slices.Values(intervals)))
+	return min(float64(len(distinct))/fullVariety, 1)
+}
+
+// Smoothness rates how small the intervals of a melody are from 0 to 1: 1 for steps and
+// repeated notes only, less the larger its leaps, down to 0 for octaves only.
+func Smoothness(intervals []int) float64 {
+	if len(intervals) == 0 {
+		return 0
+	}
+
+	excess := 0
+	for _, val := range intervals {
+		excess += max(utils.Abs(val)-1, 0)
+	}
+	return 1 - float64(excess)/float64(6*len(intervals))
+}
+
+// ClimaxProminence rates the climax of a melody from 0 to 1: 0 if its highest note occurs
+// more than once, otherwise one half for a single climax plus up to one half for its height
+// above the first note, a fifth or more earning the full half.
+func ClimaxProminence(intervals []int) float64 {
+	heights := music.PartialSums(intervals)
+	high := slices.Max(heights)
+	count := 0
+	for _, h := range heights {
+		if h == high {
+			count++
+		}
+	}
+	if count > 1 {
+		return 0
+	}
+	return 0.5 + 0.5*min(float64(high)/4, 1)
+}
+
+// StepwiseRatio returns the share of steps among the intervals of a melody, from 0 to 1.
+func StepwiseRatio(intervals []int) float64 {
+	if len(intervals) == 0 {
+		return 0
+	}
+
+	stepCount := 0
+	for _, val := range intervals {
+		if slices.Contains(steps, val) {
+			stepCount++
+		}
+	}
+	return float64(stepCount) / float64(len(intervals))
+}
+
+// Consonance rates how consonant the notes of a melody realized in the mode are against
+// its final from 0 to 1: one less their mean tension (see analysis.TensionProfile).
+// Melodies that cannot be realized in the mode rate 0.
+func Consonance(intervals []int, mode music.Mode) float64 {
+	cf := make(music.CantusFirmus, len(intervals))
+	for i, val := range intervals {
+		cf[i] = music.Interval(val)
+	}
+	realization, err := cf.Realize(mode.String())
+	if err != nil {
+		return 0
+	}
+	profile, err := analysis.TensionProfile(realization, music.NewScale(mode).Tonic)
+	if err != nil {
+		return 0
+	}
+	return 1 - analysis.MeanTension(profile)
+}
+
+// ScoreWeights weighs the criteria of WeightedScore; a zero weight ignores a criterion.
+//
+// Fields:
+//   - Variety: the weight of IntervalVariety
+//   - Smoothness: the weight of Smoothness
+//   - Climax: the weight of ClimaxProminence
+//   - Stepwise: the weight of StepwiseRatio
+//   - Consonance: the weight of Consonance in Mode, ignored if Mode is not set
+//   - Mode: the mode the melodies are realized in to rate their Consonance
+type ScoreWeights struct {
+	Variety    float64
+	Smoothness float64
+	Climax     float64
+	Stepwise   float64
+	Consonance float64
+	Mode       music.Mode
+}
+
+// WeightedScore returns a score rating a melody by the weighted mean of IntervalVariety,
+// Smoothness, ClimaxProminence, StepwiseRatio and Consonance, from 0 to 1. Weights summing
+// to 0 rate every melody 0.
+func WeightedScore(w ScoreWeights) ScoreFunc {
+	if w.Mode == 0 {
+		w.Consonance = 0
+	}
+	total := w.Variety + w.Smoothness + w.Climax + w.Stepwise + w.Consonance
+	return func(intervals []int) float64 {
+		if total == 0 {
+			return 0
+		}
+		sum := w.Variety*IntervalVariety(intervals) + w.Smoothness*Smoothness(intervals) +
+			w.Climax*ClimaxProminence(intervals) + w.Stepwise*StepwiseRatio(intervals)
+		if w.Consonance != 0 {
+			sum += w.Consonance * Consonance(intervals, w.Mode)
+		}
+		return sum / total
+	}
+}
+
+// MelodicScore returns a score rating a melody by all criteria of WeightedScore, weighed
+// equally, its Consonance in the mode included.
+func MelodicScore(mode music.Mode) ScoreFunc {
+	return WeightedScore(ScoreWeights{Variety: 1, Smoothness: 1, Climax: 1, Stepwise: 1, Consonance: 1, Mode: mode})
+}
+
+// TopScoring returns up to n of the melodies with the highest scores, best first; melodies
+// with equal scores keep their order. A nil score uses DefaultScore. The penalty of the soft
+// rules a melody breaks under the rule set (see rules.RuleSet.Penalty) is subtracted from
+// its score; a nil rule set selects all registered rules.
+func TopScoring(melodies [][]int, n int, score ScoreFunc, ruleSet *rules.RuleSet) [][]int {
+	if ruleSet == nil {
+		ruleSet = defaultRules
+	}
+	best := newRanking(n, score, ruleSet)
+	for _, m := range melodies {
+		best.add(m)
+	}
+	return best.melodies()
+}
+
+// ranking keeps the melodies with the highest scores among those added, best first.
+type ranking struct {
+	limit   int
+	score   ScoreFunc
+	ruleSet *rules.RuleSet
+	best    []scored
+}
+
+// scored is a melody with its score, penalty included.
+type scored struct {
+	intervals []int
+	score     float64
+}
+
+// newRanking returns a ranking keeping up to limit melodies rated by the score, or by
+// DefaultScore if it is nil, less their penalty under the rule set.
+func newRanking(limit int, score ScoreFunc, ruleSet *rules.RuleSet) *ranking {
+	if score == nil {
+		score = DefaultScore
+	}
+	return &ranking{limit: limit, score: score, ruleSet: ruleSet}
+}
+
+// add rates a melody and keeps a copy of it if it ranks among the best so far.
+func (r *ranking) add(intervals []int) {
+	candidate := scored{intervals: intervals, score: r.score(intervals) - r.ruleSet.Penalty(intervals)}
+	i := sort.Search(len(r.best), func(i int) bool { return r.best[i].score < candidate.score })
+	if i >= r.limit {
+		return
+	}
+	candidate.intervals = slices.Clone(intervals)
+	r.best = slices.Insert(r.best, i, candidate)
+	if len(r.best) > r.limit {
+		r.best = r.best[:r.limit]
+	}
+}
+
+// melodies returns the melodies kept, best first.
+func (r *ranking) melodies() [][]int {
+	result := make([][]int, len(r.best))
+	for i, b := range r.best {
+		result[i] = b.intervals
+	}
+	return result
+}
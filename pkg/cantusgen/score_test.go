@@ -0,0 +1,96 @@
+package cantusgen
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+)
+
+func TestDefaultScore(t *testing.T) {
+	tests := []struct {
+		intervals []int
+		want      float64
+	}{
+		{nil, 0},
+		{[]int{1, -1}, 1 + 0.5/9},
+		{[]int{4, -1, -1, -1, -1}, 0.8 + 0.5*4.0/9},
+		{[]int{5, 4, -1, -1, -1, -1, -1, -1, -1, -1, -1}, 9.0/11 + 0.5},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultScore(tt.intervals); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("DefaultScore(%v) = %v, want %v", tt.intervals, got, tt.want)
+		}
+	}
+}
+
+func TestScoreCriteria(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		score     ScoreFunc
+		want      float64
+	}{
+		{"variety of steps", []int{1, 1, -1, -1}, IntervalVariety, 2.0 / 6},
+		{"variety of steps and leaps", []int{2, -3, 1, 4, -1, -2, -1}, IntervalVariety, 1},
+		{"smoothness of steps", []int{1, 0, -1}, Smoothness, 1},
+		{"smoothness of leaps", []int{2, -1, 3, -4}, Smoothness, 1 - 6.0/24},
+		{"smoothness of octaves", []int{7, -7}, Smoothness, 0},
+		{"smoothness of nothing", nil, Smoothness, 0},
+		{"repeated climax", []int{2, -1, 1, -1, -1}, ClimaxProminence, 0},
+		{"low single climax", []int{1, 1, -1, -1}, ClimaxProminence, 0.5 + 0.5*2/4},
+		{"high single climax", []int{4, 1, -1, -1, -1, -1, -1}, ClimaxProminence, 1},
+		{"climax on the first note", []int{-1, -1, 1}, ClimaxProminence, 0.5},
+		{"stepwise ratio", []int{2, -1, -1, 1, -1}, StepwiseRatio, 0.8},
+		{"stepwise ratio of nothing", nil, StepwiseRatio, 0},
+		{"weighted", []int{1, 1, -1, -1}, WeightedScore(ScoreWeights{Variety: 1, Stepwise: 3}), (2.0/6 + 3) / 4},
+		{"no weights", []int{1, 1, -1, -1}, WeightedScore(ScoreWeights{}), 0},
+		{"consonance of a third", []int{1, 1, -1, -1}, func(s []int) float64 { return Consonance(s, music.Major) }, 1 - (0.7+0.25+0.7)/5},
+		{"consonance of a tritone", []int{3, -3}, func(s []int) float64 { return Consonance(s, music.Lydian) }, 1 - 1.0/3},
+		{"consonance without a mode", []int{1, 1, -1, -1}, WeightedScore(ScoreWeights{Stepwise: 1, Consonance: 1}), 1},
+		{"melodic", []int{1, 1, -1, -1}, MelodicScore(music.Major), (2.0/6 + 1 + 0.75 + 1 + 1 - 1.65/5) / 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.score(tt.intervals); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("score(%v) = %v, want %v", tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopScoring(t *testing.T) {
+	melodies := [][]int{{1, -1}, {2, -1, -1}, {1, 1, -1, -1}, {3, -1, -1, -1}}
+	longest := func(intervals []int) float64 { return float64(len(intervals)) }
+
+	got := TopScoring(melodies, 2, longest, nil)
+	want := [][]int{{1, 1, -1, -1}, {3, -1, -1, -1}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("TopScoring() = %v, want %v", got, want)
+	}
+	if got := TopScoring(melodies, 10, longest, nil); len(got) != len(melodies) {
+		t.Errorf("TopScoring() kept %d melodies, want all %d", len(got), len(melodies))
+	}
+	if got := TopScoring(melodies, 0, longest, nil); len(got) != 0 {
+		t.Errorf("TopScoring() with n = 0 returned %v", got)
+	}
+
+	// The penalty of a soft rule outweighs the score
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.Add(rules.Rule{Name: "NoFourths", Check: func(intervals []int) bool {
+		return !slices.Contains(intervals, 3)
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ruleSet.SetSoft("NoFourths", 10); err != nil {
+		t.Fatal(err)
+	}
+	got = TopScoring(melodies, 1, longest, ruleSet)
+	if !slices.EqualFunc(got, [][]int{{1, 1, -1, -1}}, slices.Equal) {
+		t.Errorf("TopScoring() with a penalty = %v, want [[1 1 -1 -1]]", got)
+	}
+}
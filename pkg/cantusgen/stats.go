@@ -0,0 +1,133 @@
+package cantusgen
+
+import (
+	"cmp"
+	"runtime/metrics"
+	"slices"
+	"time"
+)
+
+// heapSampleInterval is the number of nodes a search explores between two samples of the
+// heap size.
+const heapSampleInterval = 1 << 14
+
+// heapMetric is the runtime metric sampled for Stats.PeakHeap.
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// Stats records what happened during generation, for performance work and to show which
+// rules constrain the search the most. Counts and durations accumulate over all generation
+// runs given the same Stats (see Options.Stats). The zero value is ready to use.
+//
+// Fields:
+//   - Rejections: the number of candidates rejected by each rule. Rules are checked in order
+//     and a candidate is counted against the first rule it breaks only: partial rules count
+//     rejected prefixes of melodies, complete rules rejected melodies
+//   - Nodes: the number of candidate intervals tried
+//   - Found: the number of melodies found, before any ranking or selection; a random search
+//     may find the same melody more than once
+//   - Duration: the time spent searching
+//   - PeakHeap: the largest size of the live heap objects sampled during the searches, in
+//     bytes. The heap is sampled every few thousand nodes and at the end of every search,
+//     so short peaks may be missed; it includes the memory of the whole program.
+type Stats struct {
+	Rejections map[string]int
+	Nodes      int
+	Found      int
+	Duration   time.Duration
+	PeakHeap   uint64
+}
+
+// RuleRejections is the number of candidates rejected by a rule.
+type RuleRejections struct {
+	Rule  string
+	Count int
+}
+
+// Ranking returns the rules that rejected candidates, most rejections first;
+// rules with equal counts are ordered by name.
+func (st *Stats) Ranking() []RuleRejections {
+	var result []RuleRejections
+	for name, count := range st.Rejections {
+		result = append(result, RuleRejections{name, count})
+	}
+	slices.SortFunc(result, func(a, b RuleRejections) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Rule, b.Rule)
+	})
+	return result
+}
+
+// reject records a candidate rejected by the named rule. It does nothing on a nil Stats.
+func (st *Stats) reject(rule string) {
+	if st == nil {
+		return
+	}
+	if st.Rejections == nil {
+		st.Rejections = make(map[string]int)
+	}
+	st.Rejections[rule]++
+}
+
+// node records a candidate interval tried. It does nothing on a nil Stats.
+func (st *Stats) node() {
+	if st == nil {
+		return
+	}
+	st.Nodes++
+	if st.Nodes%heapSampleInterval == 0 {
+		st.sampleHeap()
+	}
+}
+
+// melody records a melody found. It does nothing on a nil Stats.
+func (st *Stats) melody() {
+	if st != nil {
+		st.Found++
+	}
+}
+
+// since records the end of a search started at start: it adds its duration and samples the
+// heap. It does nothing on a nil Stats.
+func (st *Stats) since(start time.Time) {
+	if st == nil {
+		return
+	}
+	st.Duration += time.Since(start)
+	st.sampleHeap()
+}
+
+// sampleHeap raises PeakHeap to the current size of the live heap objects if it is larger.
+func (st *Stats) sampleHeap() {
+	st.PeakHeap = max(st.PeakHeap, heapSize())
+}
+
+// heapSize returns the current size of the live heap objects, or 0 if the runtime does not
+// report it.
+func heapSize() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// add adds the counts and durations recorded by other and keeps the larger peak heap size.
+// It does nothing on a nil Stats.
+func (st *Stats) add(other *Stats) {
+	if st == nil || other == nil {
+		return
+	}
+	st.Nodes += other.Nodes
+	st.Found += other.Found
+	st.Duration += other.Duration
+	st.PeakHeap = max(st.PeakHeap, other.PeakHeap)
+	for rule, count := range other.Rejections {
+		if st.Rejections == nil {
+			st.Rejections = make(map[string]int)
+		}
+		st.Rejections[rule] += count
+	}
+}
@@ -0,0 +1,124 @@
+package cantusgen
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+)
+
+func TestStats_Ranking(t *testing.T) {
+	stats := Stats{Rejections: map[string]int{"NoSequences": 3, "PreparedLeaps": 7, "Cadence": 3}}
+	want := []RuleRejections{{"PreparedLeaps", 7}, {"Cadence", 3}, {"NoSequences", 3}}
+	if got := stats.Ranking(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Ranking() = %v, want %v", got, want)
+	}
+
+	var empty Stats
+	if got := empty.Ranking(); len(got) != 0 {
+		t.Errorf("Ranking() of empty stats = %v, want none", got)
+	}
+}
+
+func TestStats_Add(t *testing.T) {
+	st := &Stats{}
+	st.add(&Stats{Rejections: map[string]int{"NoSequences": 2}})
+	st.add(&Stats{Rejections: map[string]int{"NoSequences": 1, "ValidateClimax": 4}})
+	st.add(nil)
+	if st.Rejections["NoSequences"] != 3 || st.Rejections["ValidateClimax"] != 4 {
+		t.Errorf("Rejections = %v, want NoSequences 3 and ValidateClimax 4", st.Rejections)
+	}
+
+	var none *Stats
+	none.add(st)
+
+	st = &Stats{Nodes: 10, Found: 2, Duration: time.Second, PeakHeap: 100}
+	st.add(&Stats{Nodes: 5, Found: 1, Duration: time.Second, PeakHeap: 300})
+	st.add(&Stats{PeakHeap: 200})
+	want := Stats{Nodes: 15, Found: 3, Duration: 2 * time.Second, PeakHeap: 300}
+	if !reflect.DeepEqual(*st, want) {
+		t.Errorf("after add: %+v, want %+v", *st, want)
+	}
+}
+
+func TestStats_Telemetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		generate func(opts Options) int
+	}{
+		{"Generate", func(opts Options) int { return len(Generate(10, opts)) }},
+		{"GenerateSeq", func(opts Options) int {
+			found := 0
+			for range GenerateSeq(10, opts) {
+				found++
+			}
+			return found
+		}},
+		{"CountCantus", func(opts Options) int { return CountCantus(10, opts) }},
+		{"SampleCantus", func(opts Options) int { return len(SampleCantus(10, 3, opts)) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &Stats{}
+			found := tt.generate(Options{AllowedLeaps: []int{2, 3}, Stats: stats})
+			if found == 0 {
+				t.Fatal("no melody found")
+			}
+			// A random search may find a melody more than once
+			if stats.Found < found || (tt.name != "SampleCantus" && stats.Found != found) {
+				t.Errorf("Found = %d, want %d", stats.Found, found)
+			}
+			if stats.Nodes <= stats.Found || stats.Duration <= 0 || stats.PeakHeap == 0 {
+				t.Errorf("stats %+v, want nodes, a duration and a heap size", stats)
+			}
+		})
+	}
+}
+
+func TestGenerate_Stats(t *testing.T) {
+	n := 9
+	ruleSet := rules.DefaultRuleSet()
+	if err := ruleSet.SetCadence(rules.CadenceSupertonic); err != nil {
+		t.Fatal(err)
+	}
+	stats := &Stats{}
+	opts := Options{AllowedLeaps: []int{2}, Rules: ruleSet, Stats: stats}
+
+	// Statistics must not change the result
+	withStats := Generate(n, opts)
+	opts.Stats = nil
+	if without := Generate(n, opts); !reflect.DeepEqual(withStats, without) {
+		t.Fatalf("Generate with stats found %d melodies, without %d", len(withStats), len(without))
+	}
+
+	known := map[string]bool{"LeapCount": true, "Cadence": true}
+	for _, name := range ruleSet.Names() {
+		known[name] = true
+	}
+	for rule, count := range stats.Rejections {
+		if !known[rule] {
+			t.Errorf("Rejections counted for unknown rule %q", rule)
+		}
+		if count <= 0 {
+			t.Errorf("Rejections[%q] = %d, want a positive count", rule, count)
+		}
+	}
+	if stats.Rejections["Cadence"] == 0 {
+		t.Error("Expected melodies rejected by the cadence rule")
+	}
+
+	total := 0
+	for _, count := range stats.Rejections {
+		total += count
+	}
+	Generate(n, Options{AllowedLeaps: []int{2}, Rules: ruleSet, Stats: stats})
+	accumulated := 0
+	for _, count := range stats.Rejections {
+		accumulated += count
+	}
+	if accumulated != 2*total {
+		t.Errorf("Expected counts to accumulate over runs: got %d after two runs, %d after one", accumulated, total)
+	}
+}
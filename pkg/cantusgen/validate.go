@@ -0,0 +1,138 @@
+package cantusgen
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"slices"
+)
+
+// IsValidCantus reports whether a complete interval sequence is a cantus firmus
+// the generator could have produced with the given options:
+//   - every interval is a step, or a leap or repeated note allowed by the rule set
+//   - the sum of all intervals equals 0, so that the melody ends on the final it began on,
+//     or leads from the opening to the ending of the rule set (see rules.RuleSet.SetOpening
+//     and rules.RuleSet.SetEnding), and the last two intervals are steps
+//   - every prefix satisfies the partial rules and the whole sequence satisfies the complete rules
+//
+// An empty opts.AllowedLeaps accepts any number of leaps.
+func IsValidCantus(intervals []int, opts Options) bool {
+	n := len(intervals)
+	if n < 2 {
+		return false
+	}
+
+	alphabet := opts.alphabet()
+	sum := 0
+	for _, val := range intervals {
+		if !slices.Contains(alphabet, val) {
+			return false
+		}
+		sum += val
+	}
+	if sum != opts.target() || !slices.Contains(steps, intervals[n-2]) || !slices.Contains(steps, intervals[n-1]) {
+		return false
+	}
+
+	leapPartial, leapComplete := opts.leapCountRules()
+	partialValidators := validators(append(append(leapPartial, opts.lengthRules(n)...), opts.partialRules()...))
+
+	// Partial rules are checked on every prefix, exactly as during generation
+	for i := 1; i <= n; i++ {
+		if !rules.AllRules(intervals[:i], partialValidators) {
+			return false
+		}
+	}
+	return rules.AllRules(intervals, validators(append(leapComplete, opts.completeRules()...)))
+}
+
+// Names of the structural requirements reported by Violations, and of the check on the
+// realization in Options.Mode
+const (
+	RuleIntervalAlphabet    = "IntervalAlphabet"
+	RuleReturnToFinal       = "ReturnToFinal"
+	RuleStepwiseEnding      = "StepwiseEnding"
+	RuleLeapCount           = "LeapCount"
+	RuleDegrees             = "RestrictToDegrees"
+	RulePrefix              = "StartsWith"
+	RuleEnding              = "EndsWith"
+	RuleAmbitus             = "Ambitus"
+	RuleCounterpoint        = "FirstSpecies"
+	RuleAugmentedDiminished = "IsFreeOfAugmentedDiminished"
+)
+
+// RuleNames returns the names of everything Violations can report, in the order it is checked:
+// the structural requirements, the registered rules (see rules.Registry) and the check on
+// the realization in Options.Mode.
+func RuleNames() []string {
+	names := []string{RuleIntervalAlphabet, RuleReturnToFinal, RuleStepwiseEnding, RuleLeapCount, RuleDegrees, RulePrefix, RuleEnding, RuleAmbitus, RuleCounterpoint}
+	return append(append(names, defaultRules.Names()...), RuleAugmentedDiminished)
+}
+
+// structuralRules describe the structural requirements of IsValidCantus in their violations.
+var structuralRules = map[string]rules.Rule{
+	RuleIntervalAlphabet: {Name: RuleIntervalAlphabet,
+		Description: "Every interval must be a step, or a leap or repeated note the rules allow."},
+	RuleReturnToFinal: {Name: RuleReturnToFinal,
+		Description: "The melody must end on the final, or on the ending note of the rule set."},
+	RuleStepwiseEnding: {Name: RuleStepwiseEnding, Description: "The last two intervals must be steps."},
+}
+
+// structuralViolation returns the violation of the structural requirement named rule at interval i.
+func structuralViolation(rule string, i int) rules.Violation {
+	return rules.NewViolation(structuralRules[rule], i, i, true)
+}
+
+// Violations returns the names of all requirements of IsValidCantus that a complete
+// interval sequence breaks (see RuleNames), in the order they are checked.
+func Violations(intervals []int, opts Options) []string {
+	var names []string
+	for _, v := range Check(intervals, opts) {
+		names = append(names, v.Rule)
+	}
+	return names
+}
+
+// Check returns all requirements of IsValidCantus that a complete interval sequence breaks,
+// in the order they are checked, with the intervals where they are broken (see rules.CheckRules).
+// The structural requirements are located at the interval breaking them: the first interval
+// outside of the allowed ones for RuleIntervalAlphabet, the last interval for RuleReturnToFinal
+// and the first of the last two intervals that is not a step for RuleStepwiseEnding.
+// Sequences shorter than two intervals violate RuleStepwiseEnding as a whole.
+func Check(intervals []int, opts Options) []rules.Violation {
+	var result []rules.Violation
+	n := len(intervals)
+
+	alphabet := opts.alphabet()
+	sum := 0
+	badInterval := -1
+	for i, val := range intervals {
+		if !slices.Contains(alphabet, val) && badInterval < 0 {
+			badInterval = i
+		}
+		sum += val
+	}
+
+	if badInterval >= 0 {
+		result = append(result, structuralViolation(RuleIntervalAlphabet, badInterval))
+	}
+	switch {
+	case sum == opts.target():
+	case n == 0:
+		result = append(result, rules.NewViolation(structuralRules[RuleReturnToFinal], 0, -1, false))
+	default:
+		result = append(result, structuralViolation(RuleReturnToFinal, n-1))
+	}
+	switch {
+	case n < 2:
+		result = append(result, rules.NewViolation(structuralRules[RuleStepwiseEnding], 0, n-1, false))
+	case !slices.Contains(steps, intervals[n-2]):
+		result = append(result, structuralViolation(RuleStepwiseEnding, n-2))
+	case !slices.Contains(steps, intervals[n-1]):
+		result = append(result, structuralViolation(RuleStepwiseEnding, n-1))
+	}
+	leapPartial, leapComplete := opts.leapCountRules()
+	result = append(result, rules.CheckRules(intervals, leapPartial)...)
+	result = append(result, rules.CheckRules(intervals, leapComplete)...)
+	result = append(result, rules.CheckRules(intervals, opts.lengthRules(n))...)
+	result = append(result, rules.CheckRules(intervals, opts.partialRules())...)
+	return append(result, rules.CheckRules(intervals, opts.completeRules())...)
+}
@@ -0,0 +1,166 @@
+package cantusgen
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/rules"
+	"slices"
+	"testing"
+)
+
+func TestIsValidCantus(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		opts      Options
+		want      bool
+	}{
+		{"begins with a leap of a sixth", []int{5, -1, -1, -1, -1, -1}, Options{}, false},
+		{"too short", []int{1}, Options{}, false},
+		{"does not return to the final", []int{1, 1, 1, -1, -1, 1}, Options{}, false},
+		{"ends with a leap", []int{1, 1, 1, -1, 2, -4}, Options{}, false},
+		{"octave leap", []int{7, -1, -1, -1, -1, -1, -1, -1}, Options{}, false},
+	}
+
+	// Every generated cantus must be valid with the same options
+	opts := Options{AllowedLeaps: []int{2}}
+	for _, s := range Generate(9, opts) {
+		tests = append(tests, struct {
+			name      string
+			intervals []int
+			opts      Options
+			want      bool
+		}{"generated", s, opts, true})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidCantus(tt.intervals, tt.opts); got != tt.want {
+				t.Errorf("IsValidCantus(%v) = %v, want %v", tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidCantus_LeapCount(t *testing.T) {
+	cantus := Generate(9, Options{AllowedLeaps: []int{2}})
+	if len(cantus) == 0 {
+		t.Fatal("Generate returned no cantus firmi")
+	}
+	if IsValidCantus(cantus[0], Options{AllowedLeaps: []int{3}}) {
+		t.Errorf("IsValidCantus(%v) accepted a cantus with a disallowed number of leaps", cantus[0])
+	}
+	if IsValidCantus(cantus[0], Options{AllowedLeaps: []int{2}, Degrees: []int{1}}) {
+		t.Errorf("IsValidCantus(%v) accepted a cantus outside the allowed degrees", cantus[0])
+	}
+}
+
+func TestViolations(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		opts      Options
+		want      []string
+	}{
+		{"begins with a sixth", []int{5, -1, -1, -1, -1, -1}, Options{}, []string{"NoBeginWithFive"}},
+		{"does not return and ends with a leap", []int{1, 1, 2}, Options{}, []string{RuleReturnToFinal, RuleStepwiseEnding}},
+		{"octave leap", []int{7, -1, -1, -1, -1, -1, -1, -1}, Options{}, []string{RuleIntervalAlphabet}},
+		{"other cadence", []int{1, 1, 1, -1, -1, -1}, Options{Cadences: [][]int{{1, -1}}}, []string{RuleEnding}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Violations(tt.intervals, tt.opts)
+			for _, name := range tt.want {
+				if !slices.Contains(got, name) {
+					t.Errorf("Violations(%v) = %v, want it to contain %s", tt.intervals, got, name)
+				}
+			}
+		})
+	}
+
+	// Generated melodies violate nothing, and every reported name is known
+	opts := Options{AllowedLeaps: []int{2}}
+	for _, s := range Generate(9, opts) {
+		if got := Violations(s, opts); len(got) != 0 {
+			t.Errorf("Violations(%v) = %v, want none", s, got)
+		}
+		if got := Violations(s, Options{AllowedLeaps: []int{3}, Degrees: []int{1}}); !slices.Equal(got, []string{RuleLeapCount, RuleDegrees}) {
+			t.Errorf("Violations(%v) with other options = %v, want [%s %s]", s, got, RuleLeapCount, RuleDegrees)
+		}
+	}
+	for _, name := range Violations([]int{7, 5, 2}, Options{AllowedLeaps: []int{0}, Degrees: []int{1}}) {
+		if !slices.Contains(RuleNames(), name) {
+			t.Errorf("Violations() reported unknown rule %s", name)
+		}
+	}
+}
+
+// located is the rule of a violation with the interval it ends at, or -1 when
+// it applies to the melody as a whole.
+type located struct {
+	Rule     string
+	Position int
+}
+
+func locate(violations []rules.Violation) []located {
+	var result []located
+	for _, v := range violations {
+		position := v.End
+		if !v.Located {
+			position = -1
+		}
+		result = append(result, located{v.Rule, position})
+	}
+	return result
+}
+
+func TestCheck_Positions(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		want      located
+	}{
+		{"leap of a sixth at the start", []int{5, -1, -1, -1, -1, -1}, located{"NoBeginWithFive", 0}},
+		{"octave leap", []int{1, 7, -1, -1, -1, -1, -1, -1, -1}, located{RuleIntervalAlphabet, 1}},
+		{"no return to the final", []int{1, 1, 1, -1, -1, 1}, located{RuleReturnToFinal, 5}},
+		{"penultimate leap", []int{1, 1, 1, -2, -1}, located{RuleStepwiseEnding, 3}},
+		{"last leap", []int{1, 1, 1, -1, -2}, located{RuleStepwiseEnding, 4}},
+		{"too few direction changes", []int{1, 1, 1, -1, -1, -1}, located{"MinDirectionChanges", -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := locate(Check(tt.intervals, Options{}))
+			if !slices.Contains(got, tt.want) {
+				t.Errorf("Check(%v) = %v, want it to contain %v", tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck_LeapCount(t *testing.T) {
+	// Fux's Dorian cantus firmus has three leaps
+	fux := []int{2, -1, -1, 3, -1, 2, -1, -1, -1, -1}
+	tests := []struct {
+		name    string
+		allowed []int
+		want    []located
+	}{
+		{"allowed", []int{3, 4}, nil},
+		{"any number", nil, nil},
+		{"too many leaps, located at the first excess leap", []int{1, 2}, []located{{RuleLeapCount, 5}}},
+		{"too few leaps", []int{4}, []located{{RuleLeapCount, -1}}},
+		{"between allowed numbers", []int{2, 4}, []located{{RuleLeapCount, -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{AllowedLeaps: tt.allowed}
+			if got := locate(Check(fux, opts)); !slices.Equal(got, tt.want) {
+				t.Errorf("Check(%v) with AllowedLeaps %v = %v, want %v", fux, tt.allowed, got, tt.want)
+			}
+			if got := IsValidCantus(fux, opts); got != (tt.want == nil) {
+				t.Errorf("IsValidCantus(%v) with AllowedLeaps %v = %v", fux, tt.allowed, got)
+			}
+		})
+	}
+}
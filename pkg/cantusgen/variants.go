@@ -0,0 +1,55 @@
+package cantusgen
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+// DropVariants returns the melodies that are not a variant of an earlier one: its inversion,
+// retrograde or retrograde inversion (see music.CantusFirmus.Inversion). Every family of
+// variants is thus represented by its first member, e.g. the best one of a ranked list.
+// The melodies kept are not copied.
+func DropVariants(melodies [][]int) [][]int {
+	var result [][]int
+	seen := make(variantSet)
+	for _, m := range melodies {
+		if seen.accept(m) {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// dropVariants works like DropVariants on a sequence of melodies, yielding every melody
+// kept as soon as it is found.
+func dropVariants(melodies iter.Seq[[]int]) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		seen := make(variantSet)
+		for m := range melodies {
+			if seen.accept(m) && !yield(m) {
+				return
+			}
+		}
+	}
+}
+
+// variantSet holds the melodies accepted so far together with their variants.
+type variantSet map[string]bool
+
+// accept reports whether a melody is neither one accepted before nor a variant of one, and
+// if so adds it and its variants to the set.
+func (s variantSet) accept(intervals []int) bool {
+	cf := make(music.CantusFirmus, len(intervals))
+	for i, val := range intervals {
+		cf[i] = music.Interval(val)
+	}
+	if s[fmt.Sprint(cf)] {
+		return false
+	}
+	for _, variant := range []music.CantusFirmus{cf, cf.Inversion(), cf.Retrograde(), cf.RetrogradeInversion()} {
+		s[fmt.Sprint(variant)] = true
+	}
+	return true
+}
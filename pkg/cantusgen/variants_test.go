@@ -0,0 +1,53 @@
+package cantusgen
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+func TestDropVariants(t *testing.T) {
+	melodies := [][]int{
+		{2, -1, -1, 1, -1},
+		{-2, 1, 1, -1, 1}, // inversion of the first
+		{1, -1, 1, 1, -2}, // retrograde of the first
+		{1, 1, -1, 2, -3},
+		{-1, 1, -1, -1, 2}, // retrograde inversion of the first
+		{2, -1, -1, 1, -1}, // the first again
+		{3, -1, -1, -1},
+	}
+	want := [][]int{{2, -1, -1, 1, -1}, {1, 1, -1, 2, -3}, {3, -1, -1, -1}}
+	if got := DropVariants(melodies); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("DropVariants() = %v, want %v", got, want)
+	}
+	if got := DropVariants(nil); got != nil {
+		t.Errorf("DropVariants(nil) = %v, want nil", got)
+	}
+}
+
+func TestDropVariants_Generated(t *testing.T) {
+	all := Generate(10, Options{AllowedLeaps: []int{2}})
+	kept := DropVariants(all)
+	if len(kept) == 0 || len(kept) >= len(all) {
+		t.Fatalf("DropVariants kept %d of %d melodies, want fewer but some", len(kept), len(all))
+	}
+	for i, a := range kept {
+		cf := toCantusFirmus(a)
+		for _, b := range kept[i+1:] {
+			other := toCantusFirmus(b)
+			if cf.EquivalentUnderInversion(other) || cf.EquivalentUnderRetrograde(other) ||
+				cf.EquivalentUnderRetrogradeInversion(other) {
+				t.Fatalf("DropVariants kept %v and its variant %v", a, b)
+			}
+		}
+	}
+}
+
+func toCantusFirmus(intervals []int) music.CantusFirmus {
+	cf := make(music.CantusFirmus, len(intervals))
+	for i, val := range intervals {
+		cf[i] = music.Interval(val)
+	}
+	return cf
+}
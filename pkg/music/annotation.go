@@ -0,0 +1,68 @@
+package music
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Standard labels attached by Annotate.
+const (
+	LabelClimax      = "climax"
+	LabelLeadingTone = "leading tone"
+)
+
+// Annotations attaches text labels to individual notes of a Realization,
+// keyed by note index. A note may carry several labels, e.g. its scale degree and "climax".
+// Exporters render the labels in order, for example as lyric lines under the notes.
+type Annotations map[int][]string
+
+// Add appends a label to the note at index i.
+func (a Annotations) Add(i int, label string) {
+	a[i] = append(a[i], label)
+}
+
+// Labels returns the labels of the note at index i, or nil if it has none.
+func (a Annotations) Labels(i int) []string {
+	return a[i]
+}
+
+// Indices returns the indices of the labeled notes in ascending order.
+func (a Annotations) Indices() []int {
+	return slices.Sorted(maps.Keys(a))
+}
+
+// Annotate labels every note of the realization with its scale degree (1-7) in the given scale,
+// the highest note with LabelClimax if it occurs only once, and every note a semitone below
+// the tonic that moves up to the tonic with LabelLeadingTone.
+func Annotate(r Realization, scale Scale) Annotations {
+	a := make(Annotations)
+	for i, n := range r {
+		a.Add(i, strconv.Itoa(scale.Degree(n)))
+	}
+
+	if len(r) > 0 {
+		climax := 0
+		count := 0
+		for i, n := range r {
+			switch n.Compare(r[climax]) {
+			case 1:
+				climax, count = i, 1
+			case 0:
+				count++
+			}
+		}
+		if count == 1 {
+			a.Add(climax, LabelClimax)
+		}
+	}
+
+	tonic := scale.Tonic.PitchClass()
+	for i := 0; i+1 < len(r); i++ {
+		if r[i+1].PitchClass().EnharmonicEqual(tonic) && r[i+1].Semitones()-r[i].Semitones() == 1 && r[i].Step != r[i+1].Step {
+			a.Add(i, LabelLeadingTone)
+		}
+	}
+
+	return a
+}
@@ -0,0 +1,75 @@
+package music
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnotations(t *testing.T) {
+	a := make(Annotations)
+	a.Add(3, "climax")
+	a.Add(0, "1")
+	a.Add(3, "5")
+
+	if got := a.Labels(3); !reflect.DeepEqual(got, []string{"climax", "5"}) {
+		t.Errorf("Labels(3) = %v, want [climax 5]", got)
+	}
+	if got := a.Labels(1); got != nil {
+		t.Errorf("Labels(1) = %v, want nil", got)
+	}
+	if got := a.Indices(); !reflect.DeepEqual(got, []int{0, 3}) {
+		t.Errorf("Indices() = %v, want [0 3]", got)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	tests := []struct {
+		name  string
+		notes []Note
+		scale Scale
+		want  Annotations
+	}{
+		{
+			name:  "A minor with a raised leading tone",
+			notes: []Note{{5, 4, 0}, {0, 5, 0}, {6, 4, 0}, {4, 4, 1}, {5, 4, 0}},
+			scale: NewScale(Minor),
+			want: Annotations{
+				0: {"1"},
+				1: {"3", LabelClimax},
+				2: {"2"},
+				3: {"7", LabelLeadingTone},
+				4: {"1"},
+			},
+		},
+		{
+			name:  "D dorian without a leading tone",
+			notes: []Note{{1, 4, 0}, {0, 4, 0}, {1, 4, 0}},
+			scale: NewScale(Dorian),
+			want:  Annotations{0: {"1"}, 1: {"7"}, 2: {"1"}},
+		},
+		{
+			name:  "repeated highest note is no climax",
+			notes: []Note{{0, 4, 0}, {4, 4, 0}, {2, 4, 0}, {4, 4, 0}, {6, 3, 0}, {0, 4, 0}},
+			scale: NewScale(Major),
+			want: Annotations{
+				0: {"1"}, 1: {"5"}, 2: {"3"}, 3: {"5"},
+				4: {"7", LabelLeadingTone}, 5: {"1"},
+			},
+		},
+		{
+			name:  "empty realization",
+			notes: nil,
+			scale: NewScale(Major),
+			want:  Annotations{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Annotate(tt.notes, tt.scale)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Annotate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package music
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CantusFirmus represents a melodic contour abstracted from rhythm, meter, key, or specific pitches.
+// It captures only the sequence of diatonic intervals between consecutive notes, serving as the foundation
+// for later elaboration into a complete melody by applying tonality, mode, and other musical parameters.
+//
+// Example: [third up, second down, second down] → "D4, F4, E4, D4" (if starting from D4).
+type CantusFirmus []Interval
+
+// MinorPolicy selects how the 6th and 7th degrees are treated when realizing in minor mode.
+type MinorPolicy int
+
+const (
+	// MinorMelodic raises the 6th and 7th degrees depending on the melodic context
+	// (see adjustMinorAlterations). This is the default.
+	MinorMelodic MinorPolicy = iota
+	// MinorNatural never alters any degree (natural minor).
+	MinorNatural
+	// MinorHarmonic always raises the 7th degree and never the 6th (harmonic minor).
+	MinorHarmonic
+)
+
+// minorPolicyNames holds the names of the policies in the order of their constants.
+var minorPolicyNames = []string{"melodic", "natural", "harmonic"}
+
+// String returns the name of the policy ("melodic", "natural" or "harmonic").
+func (p MinorPolicy) String() string {
+	if p < MinorMelodic || p > MinorHarmonic {
+		return fmt.Sprintf("MinorPolicy(%d)", int(p))
+	}
+	return minorPolicyNames[p]
+}
+
+// ParseMinorPolicy parses a policy name case-insensitively ("melodic", "natural" or "harmonic").
+func ParseMinorPolicy(s string) (MinorPolicy, error) {
+	for i, name := range minorPolicyNames {
+		if strings.EqualFold(s, name) {
+			return MinorPolicy(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown minor policy: %s", s)
+}
+
+// RealizeOptions configures how a CantusFirmus is realized.
+//
+// Fields:
+//   - Minor: treatment of the 6th and 7th degrees in minor mode (ignored in other modes)
+//   - Range: if set, every realized note must lie within it, otherwise a *RangeError is returned
+//   - ShiftIntoRange: move a realization that does not fit Range by as few whole octaves
+//     as needed instead of failing right away
+//   - AvoidAugmentedSeconds: in minor mode, raise the 6th degree next to a raised 7th
+//     so that the melody contains no augmented second (F–G# becomes F#–G# in A minor)
+//   - Opening: the height of the first note above the tonic in steps, e.g. 4 to begin on the
+//     fifth (A4 in D Dorian) or -3 on the fifth below (A3); 0 begins on the tonic
+type RealizeOptions struct {
+	Minor                 MinorPolicy
+	Range                 *NoteRange
+	ShiftIntoRange        bool
+	AvoidAugmentedSeconds bool
+	Opening               int
+}
+
+// Realize generates a concrete musical realization of the CantusFirmus in the specified mode.
+// The first note will be the tonic of the mode (C for Major, D for Dorian, E for Phrygian,
+// F for Lydian, G for Mixolydian, A for Minor, B for Locrian),
+// and subsequent notes will follow the intervals of the CantusFirmus.
+func (cf CantusFirmus) Realize(mode string) (Realization, error) {
+	return cf.RealizeWithOptions(mode, RealizeOptions{})
+}
+
+// RealizeWithOptions works like Realize with the given options. With opts.Opening the first
+// note lies above or below the tonic instead.
+func (cf CantusFirmus) RealizeWithOptions(mode string, opts RealizeOptions) (Realization, error) {
+	m, err := ParseMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	scale := NewScale(m)
+	startingNote := Transpose(scale.Tonic, Interval(opts.Opening))
+
+	realization := Realization{startingNote}
+
+	currentNote := startingNote
+	for _, interval := range cf {
+		currentNote = Transpose(currentNote, interval)
+		realization = append(realization, currentNote)
+	}
+
+	// Apply alteration rules for minor mode
+	if m == Minor {
+		switch opts.Minor {
+		case MinorMelodic:
+			realization = adjustMinorAlterations(realization)
+		case MinorHarmonic:
+			realization = raiseDegree(realization, scale, 7)
+		}
+		if opts.AvoidAugmentedSeconds {
+			realization = raiseSixthsBeforeLeadingTone(realization, scale)
+		}
+	}
+
+	if opts.Range != nil {
+		return fitRange(realization, *opts.Range, opts.ShiftIntoRange)
+	}
+	return realization, nil
+}
+
+// raiseDegree returns a copy of the Realization with every note of the given degree raised by a semitone.
+func raiseDegree(realization Realization, scale Scale, degree int) Realization {
+	adjusted := make(Realization, len(realization))
+	copy(adjusted, realization)
+
+	natural := scale.NoteForDegree(degree, 0).PitchClass()
+	for i, n := range adjusted {
+		if n.PitchClass() == natural {
+			adjusted[i].Alteration = natural.Alteration + 1
+		}
+	}
+
+	return adjusted
+}
+
+// raiseSixthsBeforeLeadingTone returns a copy of the Realization in which every natural 6th degree
+// forming an augmented second with an adjacent raised 7th degree is raised as well.
+func raiseSixthsBeforeLeadingTone(realization Realization, scale Scale) Realization {
+	adjusted := make(Realization, len(realization))
+	copy(adjusted, realization)
+
+	sixth := scale.NoteForDegree(6, 0).PitchClass()
+	for i := range adjusted {
+		if adjusted[i].PitchClass() != sixth {
+			continue
+		}
+		for _, j := range []int{i - 1, i + 1} {
+			if j >= 0 && j < len(adjusted) && IsAugmentedSecond(adjusted[i], adjusted[j]) {
+				adjusted[i].Alteration = sixth.Alteration + 1
+				break
+			}
+		}
+	}
+
+	return adjusted
+}
+
+// Realization represents a concrete musical realization of a CantusFirmus as a sequence of notes.
+// It transforms the abstract interval sequence of a CantusFirmus into actual pitches,
+// preserving the melodic contour while making the pitches explicit.
+type Realization []Note
+
+// Intervals returns the diatonic intervals between consecutive notes of the Realization
+// together with their qualities written as quality plus interval number
+// (e.g. "M2", "m3", "P5", "A4").
+//
+// Example: D4, F4, E4, D4 → [third up, second down, second down] and ["m3", "m2", "M2"].
+//
+// Returns an error if the quality of any interval cannot be determined.
+func (r Realization) Intervals() (CantusFirmus, []string, error) {
+	if len(r) < 2 {
+		return CantusFirmus{}, []string{}, nil
+	}
+
+	intervals := make(CantusFirmus, len(r)-1)
+	qualities := make([]string, len(r)-1)
+	for i := 1; i < len(r); i++ {
+		prev := r[i-1]
+		current := r[i]
+
+		iq, err := NewIntervalWithQuality(prev, current)
+		if err != nil {
+			return nil, nil, fmt.Errorf("interval %d (%s-%s): %w", i, prev, current, err)
+		}
+
+		intervals[i-1] = iq.Interval
+		qualities[i-1] = iq.ShortName()
+	}
+
+	return intervals, qualities, nil
+}
+
+// Sorted returns a copy of the realization sorted from lowest to highest pitch.
+func (r Realization) Sorted() Realization {
+	sorted := slices.Clone(r)
+	SortNotes(sorted)
+	return sorted
+}
+
+// Direction reports whether the realization moves strictly in one direction:
+// +1 if every note is higher than the previous one, -1 if every note is lower,
+// and 0 if the direction changes, a pitch is repeated or there are fewer than two notes.
+// Notes are compared chromatically (see Note.Compare).
+func (r Realization) Direction() int {
+	if len(r) < 2 {
+		return 0
+	}
+
+	direction := r[1].Compare(r[0])
+	for k := 1; k < len(r)-1; k++ {
+		if r[k+1].Compare(r[k]) != direction {
+			return 0
+		}
+	}
+	return direction
+}
+
+// adjustMinorAlterations adds necessary alteration marks to a Realization in minor mode.
+//
+// Rules:
+//   - Adds sharp to G when the configuration ..., A, G, A, ... appears
+//   - Adds sharps to both F and G when the configuration ..., F, G, A, ... appears
+//   - Adds sharps to G and F when the configuration ..., A, G, F, G, A, ... appears
+//   - In all other cases, no sharps are added
+func adjustMinorAlterations(realization Realization) Realization {
+	if len(realization) < 3 {
+		return realization // Not enough notes to analyze configurations
+	}
+
+	adjusted := make(Realization, len(realization))
+	copy(adjusted, realization)
+
+	// sharpen raises note j if it is still natural: a note is never altered twice
+	sharpen := func(j int) {
+		if natural := (PitchClass{Step: adjusted[j].Step}); adjusted[j].PitchClass() == natural {
+			adjusted[j].Alteration = 1
+		}
+	}
+	for i := 1; i < len(adjusted)-1; i++ {
+		prev := adjusted[i-1]
+		current := adjusted[i]
+		next := adjusted[i+1]
+
+		// Configuration ..., A, G, A, ...
+		if prev.Step == 5 && current.Step == 4 && next.Step == 5 {
+			sharpen(i)
+		}
+
+		// Configuration ..., F, G, A, ...
+		if prev.Step == 3 && current.Step == 4 && next.Step == 5 {
+			sharpen(i - 1)
+			sharpen(i)
+		}
+		// Configuration ..., A, G, F, G, A, ... (requires at least 5 notes)
+		if i >= 2 && i < len(adjusted)-2 {
+			prevPrev := adjusted[i-2]
+			nextNext := adjusted[i+2]
+
+			if prevPrev.Step == 5 && // A
+				prev.Step == 4 && // G
+				current.Step == 3 && // F
+				next.Step == 4 && // G
+				nextNext.Step == 5 { // A
+
+				sharpen(i - 1) // G
+				sharpen(i)     // F
+				sharpen(i + 1) // G
+			}
+		}
+	}
+
+	return adjusted
+}
+
+// IsNoteSurroundedByLinearMotion determines if the note at the given index `i` in a Realization is surrounded by linear motion.
+// This means that notes at `i-1`, `i`, and `i+1` must form a consecutive ascending or descending sequence,
+// where the interval between successive notes is a second (stepwise).
+//
+// Returns:
+//   - true if the notes are in linear (stepwise) motion
+//   - false otherwise (including when the index `i` is out of bounds for checking surrounding notes)
+func IsNoteSurroundedByLinearMotion(r Realization, i int) bool {
+	if i <= 0 || i >= len(r)-1 {
+		return false // Not enough notes to check for surrounding linear motion
+	}
+
+	nPrev := r[i-1]
+	nCurrent := r[i]
+	nNext := r[i+1]
+
+	// Calculate the total step count for each note including octaves
+	nPrevTotalStep := nPrev.DiatonicValue()
+	nCurrentTotalStep := nCurrent.DiatonicValue()
+	nNextTotalStep := nNext.DiatonicValue()
+
+	// Check if the steps are consecutive and in the same direction
+	// Case 1: Ascending linear motion (e.g., C4, D4, E4 or B3, C4, D4)
+	if nCurrentTotalStep == nPrevTotalStep+1 && nNextTotalStep == nCurrentTotalStep+1 {
+		return true
+	}
+
+	// Case 2: Descending linear motion (e.g., E4, D4, C4 or D4, C4, B3)
+	if nCurrentTotalStep == nPrevTotalStep-1 && nNextTotalStep == nCurrentTotalStep-1 {
+		return true
+	}
+
+	return false
+}
@@ -1,6 +1,10 @@
 package music
 
-import "testing"
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
 
 func TestCantusFirmus_Realize(t *testing.T) {
 	tests := []struct {
@@ -94,6 +98,166 @@ func TestCantusFirmus_Realize(t *testing.T) {
 	}
 }
 
+func TestCantusFirmus_RealizeWithOptions(t *testing.T) {
+	cf := CantusFirmus{-1, -1, 2, 1, 1, -1, 1, -2} // A G F A B C B C A
+	tests := []struct {
+		name      string
+		mode      string
+		opts      RealizeOptions
+		wantNotes []string
+	}{
+		{
+			name:      "melodic minor (default)",
+			mode:      "Minor",
+			opts:      RealizeOptions{},
+			wantNotes: []string{"A4", "G4", "F4", "A4", "B4", "C5", "B4", "C5", "A4"},
+		},
+		{
+			name:      "natural minor",
+			mode:      "Minor",
+			opts:      RealizeOptions{Minor: MinorNatural},
+			wantNotes: []string{"A4", "G4", "F4", "A4", "B4", "C5", "B4", "C5", "A4"},
+		},
+		{
+			name:      "harmonic minor raises every seventh",
+			mode:      "Minor",
+			opts:      RealizeOptions{Minor: MinorHarmonic},
+			wantNotes: []string{"A4", "G#4", "F4", "A4", "B4", "C5", "B4", "C5", "A4"},
+		},
+		{
+			name:      "harmonic minor re-spelled without augmented seconds",
+			mode:      "Minor",
+			opts:      RealizeOptions{Minor: MinorHarmonic, AvoidAugmentedSeconds: true},
+			wantNotes: []string{"A4", "G#4", "F#4", "A4", "B4", "C5", "B4", "C5", "A4"},
+		},
+		{
+			name:      "policy is ignored outside minor",
+			mode:      "Dorian",
+			opts:      RealizeOptions{Minor: MinorHarmonic},
+			wantNotes: []string{"D4", "C4", "B3", "D4", "E4", "F4", "E4", "F4", "D4"},
+		},
+		{
+			name:      "opening on the fifth",
+			mode:      "Dorian",
+			opts:      RealizeOptions{Opening: 4},
+			wantNotes: []string{"A4", "G4", "F4", "A4", "B4", "C5", "B4", "C5", "A4"},
+		},
+		{
+			name:      "opening on the fifth below",
+			mode:      "Minor",
+			opts:      RealizeOptions{Opening: -3, Minor: MinorNatural},
+			wantNotes: []string{"E4", "D4", "C4", "E4", "F4", "G4", "F4", "G4", "E4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cf.RealizeWithOptions(tt.mode, tt.opts)
+			if err != nil {
+				t.Fatalf("RealizeWithOptions() unexpected error: %v", err)
+			}
+
+			var gotNotes []string
+			for _, n := range got {
+				gotNotes = append(gotNotes, n.String())
+			}
+			if !reflect.DeepEqual(gotNotes, tt.wantNotes) {
+				t.Errorf("RealizeWithOptions() = %v, want %v", gotNotes, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestCantusFirmus_RealizeWithOptions_MelodicVsNatural(t *testing.T) {
+	cf := CantusFirmus{-2, 1, 1} // A F G A
+
+	melodic, _ := cf.RealizeWithOptions("Minor", RealizeOptions{Minor: MinorMelodic})
+	natural, _ := cf.RealizeWithOptions("Minor", RealizeOptions{Minor: MinorNatural})
+
+	if melodic[1].Alteration != 1 || melodic[2].Alteration != 1 {
+		t.Errorf("melodic minor should raise F and G before A, got %v", melodic)
+	}
+	if natural[1].Alteration != 0 || natural[2].Alteration != 0 {
+		t.Errorf("natural minor should not alter any note, got %v", natural)
+	}
+}
+
+func TestParseMinorPolicy(t *testing.T) {
+	for _, p := range []MinorPolicy{MinorMelodic, MinorNatural, MinorHarmonic} {
+		got, err := ParseMinorPolicy(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParseMinorPolicy(%q) = %v, %v; want %v", p.String(), got, err, p)
+		}
+	}
+	if _, err := ParseMinorPolicy("dorian"); err == nil {
+		t.Errorf("ParseMinorPolicy(\"dorian\") expected error")
+	}
+}
+
+func TestRealization_Intervals(t *testing.T) {
+	tests := []struct {
+		name          string
+		r             Realization
+		wantIntervals CantusFirmus
+		wantQualities []string
+	}{
+		{
+			name:          "empty realization",
+			r:             Realization{},
+			wantIntervals: CantusFirmus{},
+			wantQualities: []string{},
+		},
+		{
+			name: "dorian opening",
+			r: Realization{
+				{Step: 1, Octave: 4}, // D4
+				{Step: 3, Octave: 4}, // F4
+				{Step: 2, Octave: 4}, // E4
+				{Step: 1, Octave: 4}, // D4
+			},
+			wantIntervals: CantusFirmus{2, -1, -1},
+			wantQualities: []string{"m3", "m2", "M2"},
+		},
+		{
+			name: "leaps across the octave",
+			r: Realization{
+				{Step: 0, Octave: 4}, // C4
+				{Step: 4, Octave: 4}, // G4
+				{Step: 3, Octave: 4}, // F4
+				{Step: 6, Octave: 4}, // B4
+				{Step: 0, Octave: 5}, // C5
+			},
+			wantIntervals: CantusFirmus{4, -1, 3, 1},
+			wantQualities: []string{"P5", "M2", "A4", "m2"},
+		},
+		{
+			name: "altered notes",
+			r: Realization{
+				{Step: 3, Octave: 4},                // F4
+				{Step: 4, Octave: 4, Alteration: 1}, // G#4
+				{Step: 5, Octave: 4},                // A4
+			},
+			wantIntervals: CantusFirmus{1, 1},
+			wantQualities: []string{"A2", "m2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals, qualities, err := tt.r.Intervals()
+			if err != nil {
+				t.Fatalf("Intervals() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(intervals, tt.wantIntervals) {
+				t.Errorf("Intervals() intervals = %v, want %v", intervals, tt.wantIntervals)
+			}
+			if !reflect.DeepEqual(qualities, tt.wantQualities) {
+				t.Errorf("Intervals() qualities = %v, want %v", qualities, tt.wantQualities)
+			}
+		})
+	}
+}
+
 // Helper function to check if string contains substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && s[:len(substr)] == substr
@@ -360,3 +524,101 @@ func TestIsNoteSurroundedByLinearMotion(t *testing.T) {
 		})
 	}
 }
+
+func TestRealization_Direction(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Realization
+		want int
+	}{
+		{"empty", Realization{}, 0},
+		{"single note", Realization{{0, 4, 0}}, 0},
+		{"ascending", Realization{{0, 4, 0}, {1, 4, 0}, {3, 4, 1}}, 1},
+		{"descending", Realization{{0, 5, 0}, {6, 4, 0}, {4, 4, 0}}, -1},
+		{"repeated pitch", Realization{{0, 4, 0}, {1, 4, 0}, {1, 4, 0}}, 0},
+		{"enharmonic repetition", Realization{{3, 4, 1}, {4, 4, -1}}, 0},
+		{"change of direction", Realization{{0, 4, 0}, {2, 4, 0}, {1, 4, 0}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Direction(); got != tt.want {
+				t.Errorf("Direction() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealization_Sorted(t *testing.T) {
+	r := Realization{{2, 4, 0}, {0, 4, 0}, {1, 4, 0}}
+	sorted := r.Sorted()
+
+	want := Realization{{0, 4, 0}, {1, 4, 0}, {2, 4, 0}}
+	for i := range want {
+		if sorted[i] != want[i] {
+			t.Fatalf("Sorted() = %v, want %v", sorted, want)
+		}
+	}
+	if r[0] != (Note{2, 4, 0}) {
+		t.Errorf("Sorted() modified the original realization: %v", r)
+	}
+}
+
+func TestCantusFirmus_RealizeWithRange(t *testing.T) {
+	cf := CantusFirmus{4, 1, 1, -1, -1, -1, -1, -1} // D4 A4 B4 C5 B4 A4 G4 F4 E4
+	tests := []struct {
+		name      string
+		opts      RealizeOptions
+		wantNotes []string
+		wantIndex int // index in the *RangeError, or -2 for success
+	}{
+		{
+			name:      "fits the range",
+			opts:      RealizeOptions{Range: &DefaultRange},
+			wantNotes: []string{"D4", "A4", "B4", "C5", "B4", "A4", "G4", "F4", "E4"},
+			wantIndex: -2,
+		},
+		{
+			name:      "rejected when too high",
+			opts:      RealizeOptions{Range: &NoteRange{Low: Note{0, 3, 0}, High: Note{5, 4, 0}}},
+			wantIndex: 2,
+		},
+		{
+			name:      "shifted down an octave",
+			opts:      RealizeOptions{Range: &NoteRange{Low: Note{0, 3, 0}, High: Note{5, 4, 0}}, ShiftIntoRange: true},
+			wantNotes: []string{"D3", "A3", "B3", "C4", "B3", "A3", "G3", "F3", "E3"},
+			wantIndex: -2,
+		},
+		{
+			name:      "too narrow to shift into",
+			opts:      RealizeOptions{Range: &NoteRange{Low: Note{1, 4, 0}, High: Note{6, 4, 0}}, ShiftIntoRange: true},
+			wantIndex: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cf.RealizeWithOptions("Dorian", tt.opts)
+			if tt.wantIndex != -2 {
+				var rangeErr *RangeError
+				if !errors.As(err, &rangeErr) {
+					t.Fatalf("RealizeWithOptions() error = %v, want a *RangeError", err)
+				}
+				if rangeErr.Index != tt.wantIndex {
+					t.Errorf("RangeError.Index = %d, want %d", rangeErr.Index, tt.wantIndex)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RealizeWithOptions() unexpected error: %v", err)
+			}
+			var gotNotes []string
+			for _, n := range got {
+				gotNotes = append(gotNotes, n.String())
+			}
+			if !reflect.DeepEqual(gotNotes, tt.wantNotes) {
+				t.Errorf("RealizeWithOptions() = %v, want %v", gotNotes, tt.wantNotes)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package music
+
+import "github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+
+// Distance returns how different the contours of two interval sequences are: the sum of the
+// differences in height between corresponding notes, in diatonic steps from the first note,
+// plus the number of corresponding intervals that differ. Sequences of different lengths are
+// compared on their common beginning, every extra interval adding 1.
+//
+// Example: [1, 1, -1, -1] and [1, -1, 1, -1] differ by 2 at the third note and in the middle
+// two intervals, so their distance is 4.
+func (cf CantusFirmus) Distance(other CantusFirmus) int {
+	common := min(len(cf), len(other))
+	distance := utils.Abs(len(cf) - len(other))
+	height, otherHeight := 0, 0
+	for i := range common {
+		height += int(cf[i])
+		otherHeight += int(other[i])
+		distance += utils.Abs(height - otherHeight)
+		if cf[i] != other[i] {
+			distance++
+		}
+	}
+	return distance
+}
+
+// Distance works like CantusFirmus.Distance on the diatonic heights of the notes (see
+// Note.DiatonicValue), so that realizations beginning on different notes also differ
+// by the distance between them at every note.
+func (r Realization) Distance(other Realization) int {
+	common := min(len(r), len(other))
+	distance := utils.Abs(len(r) - len(other))
+	for i := range common {
+		distance += utils.Abs(r[i].DiatonicValue() - other[i].DiatonicValue())
+		if i > 0 && r[i].DiatonicValue()-r[i-1].DiatonicValue() != other[i].DiatonicValue()-other[i-1].DiatonicValue() {
+			distance++
+		}
+	}
+	return distance
+}
@@ -0,0 +1,55 @@
+package music
+
+import "testing"
+
+func TestCantusFirmus_Distance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b CantusFirmus
+		want int
+	}{
+		{"same", CantusFirmus{2, -1, -1}, CantusFirmus{2, -1, -1}, 0},
+		{"swapped steps", CantusFirmus{1, 1, -1, -1}, CantusFirmus{1, -1, 1, -1}, 4},
+		{"inversion", CantusFirmus{2, -1, -1}, CantusFirmus{-2, 1, 1}, 4 + 2 + 0 + 3},
+		{"different lengths", CantusFirmus{1, -1}, CantusFirmus{1, -1, 1, -1}, 2},
+		{"empty", nil, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Distance(tt.b); got != tt.want {
+				t.Errorf("%v.Distance(%v) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := tt.b.Distance(tt.a); got != tt.want {
+				t.Errorf("%v.Distance(%v) = %d, want %d", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealization_Distance(t *testing.T) {
+	cf := CantusFirmus{1, 1, -1, -1}
+	c, err := cf.Realize("Major")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := cf.Realize("Dorian")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := CantusFirmus{1, -1, 1, -1}.Realize("Major")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.Distance(c); got != 0 {
+		t.Errorf("Distance to itself = %d, want 0", got)
+	}
+	if got, want := c.Distance(other), cf.Distance(CantusFirmus{1, -1, 1, -1}); got != want {
+		t.Errorf("Distance of realizations from the same note = %d, want the distance of their intervals %d", got, want)
+	}
+	// D Dorian lies a step above C major at every one of the 5 notes
+	if got := c.Distance(d); got != 5 {
+		t.Errorf("Distance between the same contour from C and D = %d, want 5", got)
+	}
+}
@@ -0,0 +1,126 @@
+package music
+
+// EquivalentUnderTransposition reports whether other has the same interval sequence.
+// A CantusFirmus carries no pitch, so two contours are transpositions of each other
+// exactly when they are equal.
+func (cf CantusFirmus) EquivalentUnderTransposition(other CantusFirmus) bool {
+	if len(cf) != len(other) {
+		return false
+	}
+	for i := range cf {
+		if cf[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EquivalentUnderInversion reports whether other is the melodic inversion of cf:
+// every interval has the same size in the opposite direction.
+func (cf CantusFirmus) EquivalentUnderInversion(other CantusFirmus) bool {
+	if len(cf) != len(other) {
+		return false
+	}
+	for i := range cf {
+		if cf[i] != -other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EquivalentUnderRetrograde reports whether other is cf read backwards:
+// the intervals come in reverse order with opposite directions.
+func (cf CantusFirmus) EquivalentUnderRetrograde(other CantusFirmus) bool {
+	if len(cf) != len(other) {
+		return false
+	}
+	for i := range cf {
+		if cf[i] != -other[len(other)-1-i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EquivalentUnderRetrogradeInversion reports whether other is the inversion of cf read backwards:
+// the intervals come in reverse order with the same directions.
+func (cf CantusFirmus) EquivalentUnderRetrogradeInversion(other CantusFirmus) bool {
+	if len(cf) != len(other) {
+		return false
+	}
+	for i := range cf {
+		if cf[i] != other[len(other)-1-i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Inversion returns the melodic inversion of cf (see EquivalentUnderInversion).
+func (cf CantusFirmus) Inversion() CantusFirmus {
+	result := make(CantusFirmus, len(cf))
+	for i, interval := range cf {
+		result[i] = -interval
+	}
+	return result
+}
+
+// Retrograde returns cf read backwards (see EquivalentUnderRetrograde).
+func (cf CantusFirmus) Retrograde() CantusFirmus {
+	result := make(CantusFirmus, len(cf))
+	for i, interval := range cf {
+		result[len(cf)-1-i] = -interval
+	}
+	return result
+}
+
+// RetrogradeInversion returns the inversion of cf read backwards
+// (see EquivalentUnderRetrogradeInversion).
+func (cf CantusFirmus) RetrogradeInversion() CantusFirmus {
+	return cf.Retrograde().Inversion()
+}
+
+// EquivalentUnderTransposition reports whether other is an exact (chromatic) transposition of r:
+// every note of other is the same number of semitones away from the corresponding note of r.
+// Use CantusFirmus.EquivalentUnderTransposition for diatonic (tonal) transposition.
+func (r Realization) EquivalentUnderTransposition(other Realization) bool {
+	if len(r) != len(other) {
+		return false
+	}
+	for i := 1; i < len(r); i++ {
+		if other[i].Semitones()-r[i].Semitones() != other[0].Semitones()-r[0].Semitones() {
+			return false
+		}
+	}
+	return true
+}
+
+// EquivalentUnderInversion reports whether other is an exact (chromatic) inversion of r
+// around any axis: every melodic interval has the same number of semitones
+// in the opposite direction.
+func (r Realization) EquivalentUnderInversion(other Realization) bool {
+	if len(r) != len(other) {
+		return false
+	}
+	for i := 1; i < len(r); i++ {
+		if other[i].Semitones()+r[i].Semitones() != other[0].Semitones()+r[0].Semitones() {
+			return false
+		}
+	}
+	return true
+}
+
+// EquivalentUnderRetrograde reports whether other consists of the pitches of r in reverse order.
+// Enharmonic notes are treated as equal.
+func (r Realization) EquivalentUnderRetrograde(other Realization) bool {
+	if len(r) != len(other) {
+		return false
+	}
+	for i := range r {
+		if !r[i].EqualPitch(other[len(other)-1-i]) {
+			return false
+		}
+	}
+	return true
+}
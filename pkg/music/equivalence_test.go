@@ -0,0 +1,87 @@
+package music
+
+import "testing"
+
+func TestCantusFirmus_Equivalence(t *testing.T) {
+	cf := CantusFirmus{2, -1, -1, 3, -1}
+
+	tests := []struct {
+		name                                 string
+		other                                CantusFirmus
+		transposition, inversion, retrograde bool
+		retrogradeInversion                  bool
+	}{
+		{"same contour", CantusFirmus{2, -1, -1, 3, -1}, true, false, false, false},
+		{"inversion", CantusFirmus{-2, 1, 1, -3, 1}, false, true, false, false},
+		{"retrograde", CantusFirmus{1, -3, 1, 1, -2}, false, false, true, false},
+		{"retrograde inversion", CantusFirmus{-1, 3, -1, -1, 2}, false, false, false, true},
+		{"different length", CantusFirmus{2, -1, -1}, false, false, false, false},
+		{"unrelated", CantusFirmus{1, 1, 1, -2, -1}, false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cf.EquivalentUnderTransposition(tt.other); got != tt.transposition {
+				t.Errorf("EquivalentUnderTransposition(%v) = %v, want %v", tt.other, got, tt.transposition)
+			}
+			if got := cf.EquivalentUnderInversion(tt.other); got != tt.inversion {
+				t.Errorf("EquivalentUnderInversion(%v) = %v, want %v", tt.other, got, tt.inversion)
+			}
+			if got := cf.EquivalentUnderRetrograde(tt.other); got != tt.retrograde {
+				t.Errorf("EquivalentUnderRetrograde(%v) = %v, want %v", tt.other, got, tt.retrograde)
+			}
+			if got := cf.EquivalentUnderRetrogradeInversion(tt.other); got != tt.retrogradeInversion {
+				t.Errorf("EquivalentUnderRetrogradeInversion(%v) = %v, want %v", tt.other, got, tt.retrogradeInversion)
+			}
+		})
+	}
+}
+
+func TestCantusFirmus_Variants(t *testing.T) {
+	cf := CantusFirmus{2, -1, -1, 3, -1}
+	if inv := cf.Inversion(); !cf.EquivalentUnderInversion(inv) {
+		t.Errorf("Inversion() = %v is not equivalent under inversion", inv)
+	}
+	if retro := cf.Retrograde(); !cf.EquivalentUnderRetrograde(retro) {
+		t.Errorf("Retrograde() = %v is not equivalent under retrograde", retro)
+	}
+	if ri := cf.RetrogradeInversion(); !cf.EquivalentUnderRetrogradeInversion(ri) {
+		t.Errorf("RetrogradeInversion() = %v is not equivalent under retrograde inversion", ri)
+	}
+	if cf[0] != 2 {
+		t.Errorf("the variants modified the melody: %v", cf)
+	}
+}
+
+func TestRealization_Equivalence(t *testing.T) {
+	// C4 E4 D4 G4
+	r := Realization{{0, 4, 0}, {2, 4, 0}, {1, 4, 0}, {4, 4, 0}}
+
+	tests := []struct {
+		name                                 string
+		other                                Realization
+		transposition, inversion, retrograde bool
+	}{
+		{"itself", r, true, false, false},
+		{"up a whole tone", Realization{{1, 4, 0}, {3, 4, 1}, {2, 4, 0}, {5, 4, 0}}, true, false, false},
+		{"diatonic transposition is not exact", Realization{{1, 4, 0}, {3, 4, 0}, {2, 4, 0}, {5, 4, 0}}, false, false, false},
+		{"inversion around C4", Realization{{0, 4, 0}, {5, 3, -1}, {6, 3, -1}, {3, 3, 0}}, false, true, false},
+		{"enharmonic inversion", Realization{{0, 4, 0}, {4, 3, 1}, {6, 3, -1}, {3, 3, 0}}, false, true, false},
+		{"retrograde", Realization{{4, 4, 0}, {1, 4, 0}, {2, 4, 0}, {0, 4, 0}}, false, false, true},
+		{"different length", Realization{{0, 4, 0}}, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.EquivalentUnderTransposition(tt.other); got != tt.transposition {
+				t.Errorf("EquivalentUnderTransposition(%v) = %v, want %v", tt.other, got, tt.transposition)
+			}
+			if got := r.EquivalentUnderInversion(tt.other); got != tt.inversion {
+				t.Errorf("EquivalentUnderInversion(%v) = %v, want %v", tt.other, got, tt.inversion)
+			}
+			if got := r.EquivalentUnderRetrograde(tt.other); got != tt.retrograde {
+				t.Errorf("EquivalentUnderRetrograde(%v) = %v, want %v", tt.other, got, tt.retrograde)
+			}
+		})
+	}
+}
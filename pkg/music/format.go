@@ -0,0 +1,37 @@
+package music
+
+import "strings"
+
+// NoteNamer returns the name under which a note is printed.
+type NoteNamer func(n Note) string
+
+// ScientificName names a note in scientific pitch notation, e.g. "F#4" (see Note.String).
+func ScientificName(n Note) string {
+	return n.String()
+}
+
+// PitchClassName names a note without its octave, e.g. "F#".
+func PitchClassName(n Note) string {
+	return n.PitchClass().String()
+}
+
+// String returns the notes in scientific pitch notation separated by spaces, e.g. "D4 F4 E4 D4".
+func (r Realization) String() string {
+	return r.Format(" ", ScientificName)
+}
+
+// Format returns the names of the notes given by namer, separated by sep.
+// A nil namer uses ScientificName.
+//
+// Example: Format(", ", PitchClassName) → "D, F, E, D".
+func (r Realization) Format(sep string, namer NoteNamer) string {
+	if namer == nil {
+		namer = ScientificName
+	}
+
+	names := make([]string, len(r))
+	for i, n := range r {
+		names[i] = namer(n)
+	}
+	return strings.Join(names, sep)
+}
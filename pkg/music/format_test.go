@@ -0,0 +1,50 @@
+package music
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRealization_Format(t *testing.T) {
+	r := Realization{{1, 4, 0}, {3, 4, 1}, {2, 4, 0}, {1, 4, 0}}
+
+	tests := []struct {
+		name  string
+		sep   string
+		namer NoteNamer
+		want  string
+	}{
+		{"default namer", " ", nil, "D4 F#4 E4 D4"},
+		{"scientific with commas", ", ", ScientificName, "D4, F#4, E4, D4"},
+		{"pitch classes", "-", PitchClassName, "D-F#-E-D"},
+		{"custom namer", " ", func(n Note) string { return fmt.Sprint(n.Semitones()) }, "50 54 52 50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Format(tt.sep, tt.namer); got != tt.want {
+				t.Errorf("Format(%q) = %q, want %q", tt.sep, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealization_String(t *testing.T) {
+	tests := []struct {
+		r    Realization
+		want string
+	}{
+		{Realization{}, ""},
+		{Realization{{0, 4, 0}}, "C4"},
+		{Realization{{1, 4, 0}, {3, 4, 0}, {2, 4, 0}, {1, 4, 0}}, "D4 F4 E4 D4"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+		if got := fmt.Sprint(tt.r); got != tt.want {
+			t.Errorf("fmt.Sprint() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -2,7 +2,8 @@ package music
 
 import (
 	"fmt"
-	"go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"strconv"
 )
 
 // Mod7 returns the non-negative remainder of division of n by 7.
@@ -85,6 +86,90 @@ func (i Interval) String() string {
 	return fmt.Sprintf("%d%s %s", intervalNum, suffix, direction)
 }
 
+// MarshalText implements encoding.TextMarshaler.
+// The interval is written as its signed Taneyev number (e.g. "2" for a third up, "-1" for a second down).
+func (i Interval) MarshalText() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(i), 10), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for the form written by MarshalText.
+// A leading "+" is accepted for ascending intervals.
+func (i *Interval) UnmarshalText(text []byte) error {
+	v, err := strconv.Atoi(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid interval %q: must be a signed number of diatonic steps", text)
+	}
+	*i = Interval(v)
+	return nil
+}
+
+// IntervalWithQuality bundles a diatonic interval with its quality,
+// e.g. an augmented fourth up or a minor third down.
+//
+// Fields:
+//   - Interval: signed diatonic interval in Taneyev notation (see Interval)
+//   - Quality: "P" (perfect), "M" (major), "m" (minor), "A" (augmented) or "d" (diminished)
+type IntervalWithQuality struct {
+	Interval Interval
+	Quality  string
+}
+
+// qualityNames maps interval qualities to their full names
+var qualityNames = map[string]string{
+	"P": "perfect",
+	"M": "major",
+	"m": "minor",
+	"A": "augmented",
+	"d": "diminished",
+}
+
+// NewIntervalWithQuality returns the interval from n1 to n2 with its quality.
+// Returns an error if the quality cannot be determined.
+func NewIntervalWithQuality(n1, n2 Note) (IntervalWithQuality, error) {
+	quality, err := CalculateIntervalQuality(n1, n2)
+	if err != nil {
+		return IntervalWithQuality{}, err
+	}
+	diff := n2.DiatonicValue() - n1.DiatonicValue()
+	return IntervalWithQuality{Interval: Interval(diff), Quality: quality}, nil
+}
+
+// String returns the full name of the interval, e.g. "augmented fourth up",
+// "minor third down" or "perfect unison".
+func (iq IntervalWithQuality) String() string {
+	name, ok := qualityNames[iq.Quality]
+	if !ok {
+		name = iq.Quality
+	}
+	return fmt.Sprintf("%s %s", name, iq.Interval)
+}
+
+// ShortName returns the quality and the interval number without direction,
+// e.g. "A4", "m3" or "P8".
+func (iq IntervalWithQuality) ShortName() string {
+	return fmt.Sprintf("%s%d", iq.Quality, utils.Abs(int(iq.Interval))+1)
+}
+
+// semitones returns the size of the interval in semitones, ignoring its direction.
+func (iq IntervalWithQuality) semitones() (int, error) {
+	steps := utils.Abs(int(iq.Interval))
+	simple := steps % 7
+	base := []int{0, 2, 4, 5, 7, 9, 11}[simple] + 12*(steps/7)
+	perfect := simple == 0 || simple == 3 || simple == 4
+
+	switch {
+	case iq.Quality == "A":
+		return base + 1, nil
+	case iq.Quality == "P" && perfect, iq.Quality == "M" && !perfect:
+		return base, nil
+	case iq.Quality == "m" && !perfect, iq.Quality == "d" && perfect:
+		return base - 1, nil
+	case iq.Quality == "d" && !perfect:
+		return base - 2, nil
+	}
+	return 0, fmt.Errorf("invalid quality %q for a %s", iq.Quality, iq.Interval)
+}
+
 // noteToSemitones converts a Note to its absolute semitone value,
 // assuming C0 as the reference point (0 semitones).
 // This function helps in calculating the exact pitch distance between notes.
@@ -104,6 +189,28 @@ func noteToSemitones(n Note) int {
 	return semitones
 }
 
+// standardSemitones holds the semitone counts of the perfect (P) and major (M) ascending
+// intervals used by CalculateIntervalQuality.
+// Key: numerical interval (1 for unison, 2 for second, etc.)
+// Value: [perfect_semitones, major_semitones] (perfect for P intervals, major for M/m intervals)
+var standardSemitones = map[int][2]int{
+	1:  {0, 0},  // Unison (P1)
+	2:  {0, 2},  // Second (M2)
+	3:  {0, 4},  // Third (M3)
+	4:  {5, 0},  // Fourth (P4)
+	5:  {7, 0},  // Fifth (P5)
+	6:  {0, 9},  // Sixth (M6)
+	7:  {0, 11}, // Seventh (M7)
+	8:  {12, 0}, // Octave (P8)
+	9:  {0, 14}, // Major Ninth (Octave + Major Second)
+	10: {0, 16}, // Major Tenth (Octave + Major Third)
+	11: {17, 0}, // Perfect Eleventh (Octave + Perfect Fourth)
+	12: {19, 0}, // Perfect Twelfth (Octave + Perfect Fifth)
+	13: {0, 21}, // Major Thirteenth (Octave + Major Sixth)
+	14: {0, 23}, // Major Fourteenth (Octave + Major Seventh)
+	15: {24, 0}, // Perfect Fifteenth (Double Octave)
+}
+
 // CalculateIntervalQuality determines the quality of the interval between two notes.
 // It returns "P" for perfect, "A" for augmented, "M" for major, or "m" for minor.
 // The order of notes (n1, n2) determines whether the interval is ascending or descending,
@@ -125,38 +232,12 @@ func CalculateIntervalQuality(n1, n2 Note) (string, error) {
 	// This effectively treats C, D, E, F, G, A, B as a continuous scale.
 	// C4 = 0, D4 = 1, E4 = 2, F4 = 3, G4 = 4, A4 = 5, B4 = 6
 	// C5 = 7, D5 = 8, etc.
-	n1TotalStep := n1.Step + n1.Octave*7
-	n2TotalStep := n2.Step + n2.Octave*7
-
 	// The raw diatonic step difference
-	rawStepDiff := utils.Abs(n2TotalStep - n1TotalStep)
+	rawStepDiff := utils.Abs(n2.DiatonicValue() - n1.DiatonicValue())
 
 	// The numerical interval is rawStepDiff + 1 (unison is 1, second is 2, etc.)
 	numericalInterval := rawStepDiff + 1
 
-	// Define the semitone counts for standard perfect and major/minor intervals
-	// This map stores the semitone values for perfect (P) and major (M) intervals
-	// Key: numerical interval (1 for unison, 2 for second, etc.)
-	// Value: [perfect_semitones, major_semitones] (perfect for P intervals, major for M/m intervals)
-	// These values are for ascending intervals.
-	standardSemitones := map[int][2]int{
-		1:  {0, 0},  // Unison (P1)
-		2:  {0, 2},  // Second (M2)
-		3:  {0, 4},  // Third (M3)
-		4:  {5, 0},  // Fourth (P4)
-		5:  {7, 0},  // Fifth (P5)
-		6:  {0, 9},  // Sixth (M6)
-		7:  {0, 11}, // Seventh (M7)
-		8:  {12, 0}, // Octave (P8)
-		9:  {0, 14}, // Major Ninth (Octave + Major Second)
-		10: {0, 16}, // Major Tenth (Octave + Major Third)
-		11: {17, 0}, // Perfect Eleventh (Octave + Perfect Fourth)
-		12: {19, 0}, // Perfect Twelfth (Octave + Perfect Fifth)
-		13: {0, 21}, // Major Thirteenth (Octave + Major Sixth)
-		14: {0, 23}, // Major Fourteenth (Octave + Major Seventh)
-		15: {24, 0}, // Perfect Fifteenth (Double Octave)
-	}
-
 	// Get the expected semitones for the numerical interval
 	expected, ok := standardSemitones[numericalInterval]
 	if !ok {
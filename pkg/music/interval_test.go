@@ -179,3 +179,64 @@ func TestIntervalString(t *testing.T) {
 		})
 	}
 }
+
+func TestNewIntervalWithQuality(t *testing.T) {
+	tests := []struct {
+		name      string
+		n1, n2    Note
+		want      IntervalWithQuality
+		wantStr   string
+		wantShort string
+	}{
+		{"augmented fourth up", Note{3, 4, 0}, Note{6, 4, 0}, IntervalWithQuality{3, "A"}, "augmented fourth up", "A4"},
+		{"minor third down", Note{3, 4, 0}, Note{1, 4, 0}, IntervalWithQuality{-2, "m"}, "minor third down", "m3"},
+		{"diminished fifth down", Note{3, 5, 0}, Note{6, 4, 0}, IntervalWithQuality{-4, "d"}, "diminished fifth down", "d5"},
+		{"perfect unison", Note{0, 4, 0}, Note{0, 4, 0}, IntervalWithQuality{0, "P"}, "perfect unison", "P1"},
+		{"perfect octave up", Note{1, 4, 0}, Note{1, 5, 0}, IntervalWithQuality{7, "P"}, "perfect octave up", "P8"},
+		{"major tenth up", Note{0, 4, 0}, Note{2, 5, 0}, IntervalWithQuality{9, "M"}, "major 10th up", "M10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewIntervalWithQuality(tt.n1, tt.n2)
+			if err != nil {
+				t.Fatalf("NewIntervalWithQuality(%v, %v) unexpected error: %v", tt.n1, tt.n2, err)
+			}
+			if got != tt.want {
+				t.Errorf("NewIntervalWithQuality(%v, %v) = %+v, want %+v", tt.n1, tt.n2, got, tt.want)
+			}
+			if got.String() != tt.wantStr {
+				t.Errorf("String() = %q, want %q", got.String(), tt.wantStr)
+			}
+			if got.ShortName() != tt.wantShort {
+				t.Errorf("ShortName() = %q, want %q", got.ShortName(), tt.wantShort)
+			}
+		})
+	}
+}
+
+func TestInterval_TextMarshaling(t *testing.T) {
+	for _, i := range []Interval{0, 1, -1, 4, -7, 12} {
+		text, err := i.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%d) error = %v", i, err)
+		}
+		var got Interval
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q) error = %v", text, err)
+		}
+		if got != i {
+			t.Errorf("UnmarshalText(MarshalText(%d)) = %d", i, got)
+		}
+	}
+
+	var i Interval
+	if err := i.UnmarshalText([]byte("+3")); err != nil || i != 3 {
+		t.Errorf("UnmarshalText(\"+3\") = %d, %v; want 3", i, err)
+	}
+	for _, text := range []string{"", "third up", "1.5"} {
+		if err := i.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) should return an error", text)
+		}
+	}
+}
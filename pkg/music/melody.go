@@ -0,0 +1,102 @@
+package music
+
+import "fmt"
+
+// Duration represents a note value measured in eighth notes,
+// the smallest value used in strict-style species counterpoint.
+type Duration int
+
+const (
+	Eighth  Duration = 1
+	Quarter Duration = 2
+	Half    Duration = 4
+	Whole   Duration = 8
+	Breve   Duration = 16
+	Longa   Duration = 32
+)
+
+// durationNames maps plain (undotted) note values to their names.
+var durationNames = map[Duration]string{
+	Eighth:  "eighth",
+	Quarter: "quarter",
+	Half:    "half",
+	Whole:   "whole",
+	Breve:   "breve",
+	Longa:   "long",
+}
+
+// Dotted returns the duration lengthened by half of its value (e.g. a dotted breve).
+func (d Duration) Dotted() Duration {
+	return d + d/2
+}
+
+// Name returns the note type of the duration (as used by MusicXML: "whole", "breve", "long", ...)
+// and whether it is dotted. ok is false if the duration cannot be written as a single
+// plain or dotted note value.
+func (d Duration) Name() (noteType string, dotted bool, ok bool) {
+	if name, found := durationNames[d]; found {
+		return name, false, true
+	}
+	if d%3 == 0 {
+		if name, found := durationNames[d/3*2]; found && d/3*2 >= Quarter {
+			return name, true, true
+		}
+	}
+	return "", false, false
+}
+
+// String returns the human-readable name of the duration (e.g. "whole", "dotted breve").
+// Durations that cannot be written as a single note are shown in eighth notes.
+func (d Duration) String() string {
+	name, dotted, ok := d.Name()
+	if !ok {
+		return fmt.Sprintf("%d eighths", int(d))
+	}
+	if dotted {
+		return "dotted " + name
+	}
+	return name
+}
+
+// MelodyNote is a note together with its duration.
+type MelodyNote struct {
+	Note     Note
+	Duration Duration
+}
+
+// String returns the note and its duration, e.g. "D4 whole".
+func (mn MelodyNote) String() string {
+	return fmt.Sprintf("%s %s", mn.Note, mn.Duration)
+}
+
+// Melody is a sequence of notes with durations. Unlike a Realization, which only
+// fixes pitches, a Melody also carries rhythm, which is needed for the other
+// species of counterpoint and for exports that are not all in whole notes.
+type Melody []MelodyNote
+
+// NewMelody converts a Realization into a Melody in which every note has the given duration.
+func NewMelody(r Realization, d Duration) Melody {
+	m := make(Melody, len(r))
+	for i, n := range r {
+		m[i] = MelodyNote{Note: n, Duration: d}
+	}
+	return m
+}
+
+// Notes returns the pitches of the melody as a Realization.
+func (m Melody) Notes() Realization {
+	r := make(Realization, len(m))
+	for i, mn := range m {
+		r[i] = mn.Note
+	}
+	return r
+}
+
+// TotalDuration returns the sum of the durations of all notes.
+func (m Melody) TotalDuration() Duration {
+	var total Duration
+	for _, mn := range m {
+		total += mn.Duration
+	}
+	return total
+}
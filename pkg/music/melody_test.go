@@ -0,0 +1,64 @@
+package music
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDuration_Name(t *testing.T) {
+	tests := []struct {
+		duration   Duration
+		wantType   string
+		wantDotted bool
+		wantOK     bool
+		wantString string
+	}{
+		{Whole, "whole", false, true, "whole"},
+		{Breve, "breve", false, true, "breve"},
+		{Longa, "long", false, true, "long"},
+		{Breve.Dotted(), "breve", true, true, "dotted breve"},
+		{Half.Dotted(), "half", true, true, "dotted half"},
+		{Eighth, "eighth", false, true, "eighth"},
+		{Duration(5), "", false, false, "5 eighths"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantString, func(t *testing.T) {
+			noteType, dotted, ok := tt.duration.Name()
+			if noteType != tt.wantType || dotted != tt.wantDotted || ok != tt.wantOK {
+				t.Errorf("Name() = (%q, %v, %v), want (%q, %v, %v)",
+					noteType, dotted, ok, tt.wantType, tt.wantDotted, tt.wantOK)
+			}
+			if got := tt.duration.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestNewMelody(t *testing.T) {
+	r := Realization{
+		{Step: 1, Octave: 4}, // D4
+		{Step: 3, Octave: 4}, // F4
+		{Step: 1, Octave: 4}, // D4
+	}
+
+	m := NewMelody(r, Whole)
+	if len(m) != len(r) {
+		t.Fatalf("NewMelody() length = %d, want %d", len(m), len(r))
+	}
+	for i, mn := range m {
+		if mn.Duration != Whole {
+			t.Errorf("note %d duration = %v, want whole", i, mn.Duration)
+		}
+	}
+	if got := m.TotalDuration(); got != 3*Whole {
+		t.Errorf("TotalDuration() = %d, want %d", got, 3*Whole)
+	}
+	if got := m.Notes(); !reflect.DeepEqual(got, r) {
+		t.Errorf("Notes() = %v, want %v", got, r)
+	}
+	if got := m[1].String(); got != "F4 whole" {
+		t.Errorf("MelodyNote.String() = %q, want %q", got, "F4 whole")
+	}
+}
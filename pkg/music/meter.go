@@ -0,0 +1,98 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TimeSignature represents a meter such as 4/2 or alla breve (2/2).
+//
+// Fields:
+//   - Beats: number of beats per measure (numerator)
+//   - BeatType: note value of one beat (denominator): 1 = whole, 2 = half, 4 = quarter, 8 = eighth
+type TimeSignature struct {
+	Beats    int
+	BeatType int
+}
+
+// ParseTimeSignature parses a time signature written as "beats/beat-type" (e.g. "4/2").
+func ParseTimeSignature(s string) (TimeSignature, error) {
+	var ts TimeSignature
+	var rest string
+	n, _ := fmt.Sscanf(s, "%d/%d%s", &ts.Beats, &ts.BeatType, &rest)
+	if n != 2 {
+		return TimeSignature{}, fmt.Errorf("invalid time signature: %q", s)
+	}
+	if err := ts.Validate(); err != nil {
+		return TimeSignature{}, err
+	}
+	return ts, nil
+}
+
+// String returns the time signature as "beats/beat-type".
+func (ts TimeSignature) String() string {
+	return fmt.Sprintf("%d/%d", ts.Beats, ts.BeatType)
+}
+
+// Validate checks that the time signature has a positive number of beats
+// and a supported beat type.
+func (ts TimeSignature) Validate() error {
+	if ts.Beats <= 0 {
+		return fmt.Errorf("invalid number of beats: %d", ts.Beats)
+	}
+	switch ts.BeatType {
+	case 1, 2, 4, 8:
+		return nil
+	}
+	return fmt.Errorf("unsupported beat type: %d", ts.BeatType)
+}
+
+// BeatDuration returns the duration of one beat.
+func (ts TimeSignature) BeatDuration() Duration {
+	return Whole / Duration(ts.BeatType)
+}
+
+// MeasureDuration returns the total duration of one measure.
+func (ts TimeSignature) MeasureDuration() Duration {
+	return Duration(ts.Beats) * ts.BeatDuration()
+}
+
+// Bars splits the melody into measures of the given time signature.
+// Every note must fit inside a single measure (notes tied across barlines are not supported),
+// while the last measure may be incomplete.
+func (m Melody) Bars(ts TimeSignature) ([]Melody, error) {
+	if err := ts.Validate(); err != nil {
+		return nil, err
+	}
+	if len(m) == 0 {
+		return nil, errors.New("cannot bar an empty melody")
+	}
+
+	measureDuration := ts.MeasureDuration()
+	var bars []Melody
+	var current Melody
+	var filled Duration
+
+	for i, mn := range m {
+		if mn.Duration <= 0 {
+			return nil, fmt.Errorf("note %d has invalid duration %d", i+1, mn.Duration)
+		}
+		if filled+mn.Duration > measureDuration {
+			return nil, fmt.Errorf("note %d (%s) crosses the barline of measure %d in %s",
+				i+1, mn, len(bars)+1, ts)
+		}
+
+		current = append(current, mn)
+		filled += mn.Duration
+		if filled == measureDuration {
+			bars = append(bars, current)
+			current = nil
+			filled = 0
+		}
+	}
+	if len(current) > 0 {
+		bars = append(bars, current)
+	}
+
+	return bars, nil
+}
@@ -0,0 +1,126 @@
+package music
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTimeSignature(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    TimeSignature
+		wantErr bool
+	}{
+		{"4/2", TimeSignature{Beats: 4, BeatType: 2}, false},
+		{"2/2", TimeSignature{Beats: 2, BeatType: 2}, false},
+		{"3/1", TimeSignature{Beats: 3, BeatType: 1}, false},
+		{"4/3", TimeSignature{}, true},
+		{"0/4", TimeSignature{}, true},
+		{"4/2x", TimeSignature{}, true},
+		{"four", TimeSignature{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTimeSignature(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimeSignature(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTimeSignature(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.input {
+				t.Errorf("String() = %q, want %q", got.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestTimeSignature_MeasureDuration(t *testing.T) {
+	tests := []struct {
+		ts   TimeSignature
+		want Duration
+	}{
+		{TimeSignature{Beats: 4, BeatType: 2}, Breve},
+		{TimeSignature{Beats: 2, BeatType: 2}, Whole},
+		{TimeSignature{Beats: 3, BeatType: 1}, Breve.Dotted()},
+		{TimeSignature{Beats: 6, BeatType: 8}, Half.Dotted()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ts.String(), func(t *testing.T) {
+			if got := tt.ts.MeasureDuration(); got != tt.want {
+				t.Errorf("MeasureDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMelody_Bars(t *testing.T) {
+	c4 := Note{Step: 0, Octave: 4}
+	tests := []struct {
+		name        string
+		melody      Melody
+		ts          TimeSignature
+		wantBars    []int // number of notes per bar
+		errContains string
+	}{
+		{
+			name:     "whole notes in 4/2",
+			melody:   NewMelody(Realization{c4, c4, c4, c4, c4}, Whole),
+			ts:       TimeSignature{Beats: 4, BeatType: 2},
+			wantBars: []int{2, 2, 1},
+		},
+		{
+			name: "final breve fills the measure",
+			melody: Melody{
+				{Note: c4, Duration: Whole},
+				{Note: c4, Duration: Whole},
+				{Note: c4, Duration: Breve},
+			},
+			ts:       TimeSignature{Beats: 4, BeatType: 2},
+			wantBars: []int{2, 1},
+		},
+		{
+			name:        "note crossing a barline",
+			melody:      NewMelody(Realization{c4, c4}, Whole),
+			ts:          TimeSignature{Beats: 3, BeatType: 2},
+			errContains: "crosses the barline of measure 1",
+		},
+		{
+			name:        "empty melody",
+			melody:      Melody{},
+			ts:          TimeSignature{Beats: 2, BeatType: 2},
+			errContains: "empty melody",
+		},
+		{
+			name:        "invalid time signature",
+			melody:      NewMelody(Realization{c4}, Whole),
+			ts:          TimeSignature{Beats: 2, BeatType: 3},
+			errContains: "unsupported beat type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bars, err := tt.melody.Bars(tt.ts)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Bars() error = %v, want containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Bars() unexpected error: %v", err)
+			}
+			if len(bars) != len(tt.wantBars) {
+				t.Fatalf("Bars() returned %d bars, want %d", len(bars), len(tt.wantBars))
+			}
+			for i, bar := range bars {
+				if len(bar) != tt.wantBars[i] {
+					t.Errorf("bar %d has %d notes, want %d", i+1, len(bar), tt.wantBars[i])
+				}
+			}
+		})
+	}
+}
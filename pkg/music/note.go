@@ -0,0 +1,397 @@
+package music
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Note represents a musical note
+//
+// Fields:
+//   - Step: diatonic step number (0 = C, 1 = D, ..., 6 = B)
+//   - Octave: octave number (4 is the middle octave)
+//   - Alteration: accidental for the note (-2 = double flat, -1 = flat, 0 = natural, 1 = sharp, 2 = double sharp)
+//
+// Use NewNote to construct notes from untrusted values.
+type Note struct {
+	Step       int
+	Octave     int
+	Alteration int
+}
+
+// MaxAlteration is the largest accidental a note may carry, in semitones (a double sharp or double flat).
+const MaxAlteration = 2
+
+// NewNote returns a note after checking that the step is within 0-6
+// and the alteration is at most a double sharp or double flat.
+func NewNote(step, octave, alteration int) (Note, error) {
+	n := Note{Step: step, Octave: octave, Alteration: alteration}
+	if err := n.Validate(); err != nil {
+		return Note{}, err
+	}
+	return n, nil
+}
+
+// MustNote is like NewNote but panics if the note is invalid.
+// It is intended for tests and for notes known at compile time.
+func MustNote(step, octave, alteration int) Note {
+	n, err := NewNote(step, octave, alteration)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Validate checks that the step is within 0-6 and the alteration within ±MaxAlteration.
+func (n Note) Validate() error {
+	if n.Step < 0 || n.Step > 6 {
+		return fmt.Errorf("invalid note step %d: must be between 0 (C) and 6 (B)", n.Step)
+	}
+	if utils.Abs(n.Alteration) > MaxAlteration {
+		return fmt.Errorf("invalid note alteration %d: must be between -%d and %d", n.Alteration, MaxAlteration, MaxAlteration)
+	}
+	return nil
+}
+
+// String returns the string representation of the note in standard musical notation.
+// The step numbers are mapped to diatonic note names (0=C, 1=D, ..., 6=B).
+// Octave numbers follow scientific pitch notation.
+// Alteration affects the note name:
+//
+//	-2 → double flat (represented as "bb")
+//	-1 → flat (represented as "b")
+//	 0 → natural (no symbol)
+//	 1 → sharp (represented as "#")
+//	 2 → double sharp (represented as "##")
+//
+// Invalid notes (see Validate) are printed as "Note(step,octave,alteration)".
+//
+// Examples:
+//   - Note{0, 4, 0}  → "C4" (Middle C)
+//   - Note{0, 4, 1}  → "C#4" (C sharp)
+//   - Note{1, 4, -1} → "Db4" (D flat)
+//   - Note{6, 3, 0}  → "B3" (B below Middle C)
+func (n Note) String() string {
+	if n.Validate() != nil {
+		return fmt.Sprintf("Note(%d,%d,%d)", n.Step, n.Octave, n.Alteration)
+	}
+
+	noteNames := []string{"C", "D", "E", "F", "G", "A", "B"}
+	alterationSymbol := ""
+	switch n.Alteration {
+	case 2:
+		alterationSymbol = "##"
+	case 1:
+		alterationSymbol = "#"
+	case -1:
+		alterationSymbol = "b"
+	case -2:
+		alterationSymbol = "bb"
+	}
+	return fmt.Sprintf("%s%s%d", noteNames[n.Step], alterationSymbol, n.Octave)
+}
+
+// Errors wrapped by *ParseError, identifying the part of a note name that is invalid.
+// Use errors.Is to tell them apart.
+var (
+	ErrBadStep       = errors.New("invalid note character")
+	ErrBadAccidental = errors.New("invalid accidental")
+	ErrBadOctave     = errors.New("invalid octave")
+)
+
+// ParseError reports a note name that could not be parsed.
+// Err wraps one of ErrBadStep, ErrBadAccidental or ErrBadOctave.
+type ParseError struct {
+	Input string // the text that was parsed
+	Err   error  // the reason
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid note %q: %v", e.Input, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// accidentals maps accidental symbols to alterations in semitones.
+var accidentals = map[rune]int{
+	'#': 1, '♯': 1, 'x': 2, '𝄪': 2,
+	'b': -1, '♭': -1, '𝄫': -2,
+	'♮': 0,
+}
+
+// ParseNote parses a string representation of a musical note into a Note struct.
+// Accidentals may be written in ASCII ("#", "b", "x" or "##" for a double sharp, "bb" for a double flat)
+// or with Unicode symbols (♯, ♭, 𝄪, 𝄫, ♮), and the octave may be negative.
+// Parsing is lenient: the note letter may be lowercase and anything after the octave number is ignored.
+// Use ParseNoteStrict to accept only the canonical spelling.
+//
+// Examples of valid input:
+//   - "C4" (Middle C)
+//   - "C#4" or "C♯4" (C sharp)
+//   - "Db4" or "D♭4" (D flat)
+//   - "Fx5", "F##5" or "F𝄪5" (F double sharp)
+//   - "Bbb3" or "B𝄫3" (B double flat)
+//   - "A-1" (A in octave -1)
+//
+// Returns:
+//   - Note struct if parsing is successful
+//   - *ParseError if the format is invalid (with specific reason)
+func ParseNote(s string) (Note, error) {
+	n, rest, err := parseStepAndAlteration(s)
+	if err != nil {
+		return Note{}, &ParseError{Input: s, Err: err}
+	}
+
+	// Parse octave
+	if len(rest) == 0 {
+		return Note{}, &ParseError{Input: s, Err: fmt.Errorf("%w: missing", ErrBadOctave)}
+	}
+	if _, err := fmt.Sscanf(rest, "%d", &n.Octave); err != nil {
+		return Note{}, &ParseError{Input: s, Err: fmt.Errorf("%w: %v", ErrBadOctave, err)}
+	}
+
+	return n, nil
+}
+
+// ParseNoteStrict parses exactly the names produced by Note.String: an uppercase letter,
+// an optional "#", "##", "b" or "bb" and an octave number without a plus sign or leading zeros
+// (e.g. "C4", "F#3", "Bbb-1"). Anything else is rejected with a *ParseError.
+func ParseNoteStrict(s string) (Note, error) {
+	if s != "" && (s[0] < 'A' || s[0] > 'G') {
+		r, _ := utf8.DecodeRuneInString(s)
+		return Note{}, &ParseError{Input: s, Err: fmt.Errorf("%w: %c (must be an uppercase letter A-G)", ErrBadStep, r)}
+	}
+
+	n, rest, err := parseStepAndAlteration(s)
+	if err != nil {
+		return Note{}, &ParseError{Input: s, Err: err}
+	}
+
+	accidental := s[1 : len(s)-len(rest)]
+	if n.Alteration != 0 && accidental != n.PitchClass().String()[1:] || n.Alteration == 0 && accidental != "" {
+		return Note{}, &ParseError{Input: s, Err: fmt.Errorf("%w: %q (must be #, ##, b or bb)", ErrBadAccidental, accidental)}
+	}
+
+	octave, err := strconv.Atoi(rest)
+	if err != nil || strconv.Itoa(octave) != rest {
+		return Note{}, &ParseError{Input: s, Err: fmt.Errorf("%w: %q (must be an integer such as 4 or -1)", ErrBadOctave, rest)}
+	}
+	n.Octave = octave
+
+	return n, nil
+}
+
+// parseStepAndAlteration parses the note letter and the accidentals at the start of s
+// and returns the note (in octave 0) and the remaining text.
+func parseStepAndAlteration(s string) (Note, string, error) {
+	if s == "" {
+		return Note{}, "", fmt.Errorf("%w: empty string", ErrBadStep)
+	}
+
+	// Extract note character (first character)
+	noteChar := s[0]
+	if noteChar < 'A' || noteChar > 'G' && noteChar < 'a' || noteChar > 'g' {
+		r, _ := utf8.DecodeRuneInString(s)
+		return Note{}, "", fmt.Errorf("%w: %c", ErrBadStep, r)
+	}
+
+	// Convert to uppercase for consistency
+	if noteChar >= 'a' && noteChar <= 'g' {
+		noteChar -= 'a' - 'A'
+	}
+
+	// Determine step
+	var step int
+	switch noteChar {
+	case 'C':
+		step = 0
+	case 'D':
+		step = 1
+	case 'E':
+		step = 2
+	case 'F':
+		step = 3
+	case 'G':
+		step = 4
+	case 'A':
+		step = 5
+	case 'B':
+		step = 6
+	}
+
+	// Collect accidentals up to the octave number
+	alteration := 0
+	var sharps, flats, natural bool
+	rest := s[1:]
+	for rest != "" {
+		r, size := utf8.DecodeRuneInString(rest)
+		value, ok := accidentals[r]
+		if !ok {
+			break
+		}
+		if natural || (r == '♮' && len(rest) < len(s)-1) {
+			return Note{}, "", fmt.Errorf("%w: natural sign combined with other accidentals", ErrBadAccidental)
+		}
+		alteration += value
+		sharps = sharps || value > 0
+		flats = flats || value < 0
+		natural = r == '♮'
+		rest = rest[size:]
+	}
+
+	if sharps && flats {
+		return Note{}, "", fmt.Errorf("%w: mixed sharps and flats", ErrBadAccidental)
+	}
+	if utils.Abs(alteration) > MaxAlteration {
+		return Note{}, "", fmt.Errorf("%w: %+d exceeds a double sharp or double flat", ErrBadAccidental, alteration)
+	}
+
+	return Note{Step: step, Alteration: alteration}, rest, nil
+}
+
+// MarshalText implements encoding.TextMarshaler using the String form (e.g. "F#4").
+// Invalid notes (see Validate) cannot be marshaled.
+func (n Note) MarshalText() ([]byte, error) {
+	if err := n.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseNote.
+func (n *Note) UnmarshalText(text []byte) error {
+	parsed, err := ParseNote(string(text))
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
+
+// Transpose transposes a note by the given interval.
+func Transpose(n Note, i Interval) Note {
+	stepDelta := int(i)
+	totalSteps := n.Step + stepDelta
+	newStep := Mod7(totalSteps)
+
+	octaveDelta := totalSteps / 7
+	if totalSteps < 0 && newStep != 0 {
+		octaveDelta -= 1
+	}
+
+	return Note{
+		Step:   newStep,
+		Octave: n.Octave + octaveDelta,
+	}
+}
+
+// TransposeChromatic transposes a note by an interval with quality and spells the result
+// diatonically, keeping the accidental the interval requires.
+// For example, E4 up a minor third is G4, up a major third G#4, and F4 up an augmented fourth B4.
+//
+// Returns an error if the quality does not exist for the interval (e.g. a major fifth or a perfect third)
+// or if the result would need more than a double sharp or double flat.
+func TransposeChromatic(n Note, interval IntervalWithQuality) (Note, error) {
+	size, err := interval.semitones()
+	if err != nil {
+		return Note{}, err
+	}
+	if interval.Interval < 0 {
+		size = -size
+	}
+
+	target := Transpose(n, interval.Interval)
+	target.Alteration = n.Semitones() + size - target.Semitones()
+	if err := target.Validate(); err != nil {
+		return Note{}, fmt.Errorf("%s %s: %w", n, interval, err)
+	}
+	return target, nil
+}
+
+// IsLeap determines whether the interval between two notes is a leap (larger than a second).
+// Returns true if the interval is larger than a second (i.e., a third or greater).
+func IsLeap(n1, n2 Note) bool {
+	// Calculate the absolute difference in steps
+	stepDiff := utils.Abs(n2.DiatonicValue() - n1.DiatonicValue())
+
+	// A leap is any interval larger than a second (step difference > 1)
+	return stepDiff > 1
+}
+
+// IsAugmentedSecond reports whether two notes, in either order, form an augmented second:
+// adjacent diatonic steps three semitones apart (e.g. F and G#).
+func IsAugmentedSecond(n1, n2 Note) bool {
+	steps := n2.DiatonicValue() - n1.DiatonicValue()
+	semitones := n2.Semitones() - n1.Semitones()
+	return (steps == 1 && semitones == 3) || (steps == -1 && semitones == -3)
+}
+
+// Semitones returns the number of semitones from C0 (0 semitones)
+// This can be used to compare note pitches
+func (n Note) Semitones() int {
+	// Diatonic steps from C to B (C=0, D=2, E=4, F=5, G=7, A=9, B=11)
+	diatonicSemitones := []int{0, 2, 4, 5, 7, 9, 11}
+
+	// Calculate semitones from diatonic step and alteration
+	semitones := diatonicSemitones[n.Step] + n.Alteration
+
+	// Add octave (12 semitones per octave)
+	return semitones + n.Octave*12
+}
+
+// DiatonicValue returns the position of the note on the staff counted in diatonic steps from C0
+// (step + 7*octave), ignoring the alteration: C4 = 28, D4 = 29, B3 = 27.
+// The difference of the diatonic values of two notes is the Interval between them,
+// and C#4 and C4 have the same diatonic value while B#3 and C4 do not.
+func (n Note) DiatonicValue() int {
+	return n.Step + n.Octave*7
+}
+
+// Compare compares the notes chromatically (by semitones) and returns
+// -1 if n is lower than other, 0 if they have the same pitch and +1 if n is higher.
+// Enharmonic notes (e.g. F#4 and Gb4) compare as equal.
+func (n Note) Compare(other Note) int {
+	return cmp.Compare(n.Semitones(), other.Semitones())
+}
+
+// CompareDiatonic compares the notes diatonically (by step and octave, ignoring alteration) and returns
+// -1 if n is on a lower staff position than other, 0 if they share it and +1 if n is higher.
+// For example, C#4 and C4 compare as equal, while B#3 is lower than C4.
+func (n Note) CompareDiatonic(other Note) int {
+	return cmp.Compare(n.DiatonicValue(), other.DiatonicValue())
+}
+
+// Less returns true if this note is lower in pitch than the other note
+func (n Note) Less(other Note) bool {
+	return n.Compare(other) < 0
+}
+
+// Greater returns true if this note is higher in pitch than the other note
+func (n Note) Greater(other Note) bool {
+	return n.Compare(other) > 0
+}
+
+// EqualPitch returns true if the notes have the same pitch (same semitones value)
+func (n Note) EqualPitch(other Note) bool {
+	return n.Semitones() == other.Semitones()
+}
+
+// SortNotes sorts notes from lowest to highest pitch (see Note.Compare).
+// The sort is stable, so enharmonic notes keep their relative order.
+// A Realization can be passed directly.
+func SortNotes(notes []Note) {
+	slices.SortStableFunc(notes, Note.Compare)
+}
+
+// SortNotesDiatonic sorts notes by staff position (see Note.CompareDiatonic).
+// The sort is stable, so notes differing only in alteration keep their relative order.
+func SortNotesDiatonic(notes []Note) {
+	slices.SortStableFunc(notes, Note.CompareDiatonic)
+}
@@ -0,0 +1,615 @@
+package music
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		name     string
+		note     Note
+		interval Interval
+		want     Note
+	}{
+		{
+			name:     "unison",
+			note:     Note{Step: 0, Octave: 4}, // C4
+			interval: 0,
+			want:     Note{Step: 0, Octave: 4}, // C4
+		},
+		{
+			name:     "second up",
+			note:     Note{Step: 0, Octave: 4}, // C4
+			interval: 1,
+			want:     Note{Step: 1, Octave: 4}, // D4
+		},
+		{
+			name:     "third down",
+			note:     Note{Step: 2, Octave: 4}, // E4
+			interval: -2,
+			want:     Note{Step: 0, Octave: 4}, // C4
+		},
+		{
+			name:     "octave up",
+			note:     Note{Step: 0, Octave: 4}, // C4
+			interval: 7,
+			want:     Note{Step: 0, Octave: 5}, // C5
+		},
+		{
+			name:     "octave down",
+			note:     Note{Step: 0, Octave: 4}, // C4
+			interval: -7,
+			want:     Note{Step: 0, Octave: 3}, // C3
+		},
+		{
+			name:     "octave up from D",
+			note:     Note{Step: 1, Octave: 4}, // D4
+			interval: 7,
+			want:     Note{Step: 1, Octave: 5}, // D5
+		},
+		{
+			name:     "octave down from E",
+			note:     Note{Step: 2, Octave: 4}, // E4
+			interval: -7,
+			want:     Note{Step: 2, Octave: 3}, // E3
+		},
+		{
+			name:     "crossing octave boundary up",
+			note:     Note{Step: 6, Octave: 4}, // B4
+			interval: 1,
+			want:     Note{Step: 0, Octave: 5}, // C5
+		},
+		{
+			name:     "crossing octave boundary down",
+			note:     Note{Step: 1, Octave: 4}, // D4
+			interval: -2,
+			want:     Note{Step: 6, Octave: 3}, // B3
+		},
+		{
+			name:     "large interval up",
+			note:     Note{Step: 0, Octave: 4}, // C4
+			interval: 15,
+			want:     Note{Step: 1, Octave: 6}, // D6
+		},
+		{
+			name:     "large interval down",
+			note:     Note{Step: 3, Octave: 4}, // F4
+			interval: -10,
+			want:     Note{Step: 0, Octave: 3}, // C3
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Transpose(tt.note, tt.interval)
+			if got != tt.want {
+				t.Errorf("Transpose(%v, %v) = %v, want %v", tt.note, tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNote_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		note     Note
+		expected string
+	}{
+		{
+			name:     "Middle C",
+			note:     Note{Step: 0, Octave: 4},
+			expected: "C4",
+		},
+		{
+			name:     "B below Middle C",
+			note:     Note{Step: 6, Octave: 3},
+			expected: "B3",
+		},
+		{
+			name:     "High G",
+			note:     Note{Step: 4, Octave: 6},
+			expected: "G6",
+		},
+		{
+			name:     "C sharp",
+			note:     Note{Step: 0, Octave: 4, Alteration: 1},
+			expected: "C#4",
+		},
+		{
+			name:     "D flat",
+			note:     Note{Step: 1, Octave: 4, Alteration: -1},
+			expected: "Db4",
+		},
+		{
+			name:     "F sharp high octave",
+			note:     Note{Step: 3, Octave: 5, Alteration: 1},
+			expected: "F#5",
+		},
+		{
+			name:     "G flat low octave",
+			note:     Note{Step: 4, Octave: 2, Alteration: -1},
+			expected: "Gb2",
+		},
+		{
+			name:     "Natural A after alteration",
+			note:     Note{Step: 5, Octave: 4, Alteration: 0},
+			expected: "A4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.note.String(); got != tt.expected {
+				t.Errorf("Note.String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseNote(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Note
+		wantErr bool
+	}{
+		// Valid notes without alteration
+		{"C4", "C4", Note{0, 4, 0}, false},
+		{"D5", "D5", Note{1, 5, 0}, false},
+		{"lowercase a4", "a4", Note{5, 4, 0}, false},
+		{"E2", "E2", Note{2, 2, 0}, false},
+
+		// Valid notes with alteration
+		{"C#4", "C#4", Note{0, 4, 1}, false},
+		{"Db5", "Db5", Note{1, 5, -1}, false},
+		{"F#3", "F#3", Note{3, 3, 1}, false},
+		{"Gb2", "Gb2", Note{4, 2, -1}, false},
+		{"lowercase with alteration", "ab4", Note{5, 4, -1}, false},
+
+		// Invalid formats
+		{"empty string", "", Note{}, true},
+		{"too short", "C", Note{}, true},
+		{"invalid note char", "H4", Note{}, true},
+		{"invalid alteration", "Cz4", Note{}, true},
+		{"missing octave after alteration", "C#", Note{}, true},
+		{"invalid octave", "CA", Note{}, true},
+		{"triple sharp", "C###4", Note{}, true},
+		{"double sharp and sharp", "Cx#4", Note{}, true},
+		{"mixed accidentals", "C#b4", Note{}, true},
+		{"natural with sharp", "C♮#4", Note{}, true},
+		{"sharp with natural", "C#♮4", Note{}, true},
+		{"unicode accidental without octave", "E♭", Note{}, true},
+
+		// Double and Unicode accidentals, negative octaves
+		{"double sharp x", "Cx4", Note{0, 4, 2}, false},
+		{"double sharp ##", "C##4", Note{0, 4, 2}, false},
+		{"double flat bb", "Bbb3", Note{6, 3, -2}, false},
+		{"unicode sharp", "F♯4", Note{3, 4, 1}, false},
+		{"unicode flat", "E♭5", Note{2, 5, -1}, false},
+		{"unicode double sharp", "G𝄪2", Note{4, 2, 2}, false},
+		{"unicode double flat", "A𝄫4", Note{5, 4, -2}, false},
+		{"unicode double flat as two flats", "D♭♭4", Note{1, 4, -2}, false},
+		{"natural sign", "B♮3", Note{6, 3, 0}, false},
+		{"negative octave", "A-1", Note{5, -1, 0}, false},
+		{"negative octave with alteration", "C#-2", Note{0, -2, 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNote(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseNote() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseNote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Additional tests for edge cases
+func TestParseNote_ParseError(t *testing.T) {
+	_, err := ParseNote("H4")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("ParseNote(\"H4\") error = %v, want a *ParseError", err)
+	}
+	if parseErr.Input != "H4" {
+		t.Errorf("ParseError.Input = %q, want %q", parseErr.Input, "H4")
+	}
+	if want := `invalid note "H4": invalid note character: H`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestParseNote_SentinelErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{"", ErrBadStep},
+		{"H4", ErrBadStep},
+		{"♯4", ErrBadStep},
+		{"C#b4", ErrBadAccidental},
+		{"C###4", ErrBadAccidental},
+		{"C", ErrBadOctave},
+		{"Cz4", ErrBadOctave},
+		{"D#", ErrBadOctave},
+	}
+
+	for _, tt := range tests {
+		if _, err := ParseNote(tt.input); !errors.Is(err, tt.want) {
+			t.Errorf("ParseNote(%q) error = %v, want %v", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestParseNoteStrict(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Note
+		wantErr error
+	}{
+		{"C4", Note{0, 4, 0}, nil},
+		{"F#3", Note{3, 3, 1}, nil},
+		{"Bbb-1", Note{6, -1, -2}, nil},
+		{"G##10", Note{4, 10, 2}, nil},
+		{"A0", Note{5, 0, 0}, nil},
+
+		{"c4", Note{}, ErrBadStep},
+		{" C4", Note{}, ErrBadStep},
+		{"", Note{}, ErrBadStep},
+		{"Cx4", Note{}, ErrBadAccidental},
+		{"C♯4", Note{}, ErrBadAccidental},
+		{"C♮4", Note{}, ErrBadAccidental},
+		{"C#b4", Note{}, ErrBadAccidental},
+		{"C4 ", Note{}, ErrBadOctave},
+		{"C4x", Note{}, ErrBadOctave},
+		{"C+4", Note{}, ErrBadOctave},
+		{"C04", Note{}, ErrBadOctave},
+		{"C-0", Note{}, ErrBadOctave},
+		{"C", Note{}, ErrBadOctave},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseNoteStrict(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseNoteStrict(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseNoteStrict(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNote_RoundTrip(t *testing.T) {
+	for step := 0; step < 7; step++ {
+		for alteration := -MaxAlteration; alteration <= MaxAlteration; alteration++ {
+			n := Note{step, -1, alteration}
+			got, err := ParseNote(n.String())
+			if err != nil || got != n {
+				t.Errorf("ParseNote(%q) = %v, %v; want %v", n.String(), got, err, n)
+			}
+			got, err = ParseNoteStrict(n.String())
+			if err != nil || got != n {
+				t.Errorf("ParseNoteStrict(%q) = %v, %v; want %v", n.String(), got, err, n)
+			}
+		}
+	}
+}
+
+func TestParseNoteEdgeCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Note
+	}{
+		{"B# highest octave", "B#9", Note{6, 9, 1}},
+		{"Cb lowest octave", "Cb0", Note{0, 0, -1}},
+		{"mixed case with alteration", "Ab3", Note{5, 3, -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNote(tt.input)
+			if err != nil {
+				t.Errorf("ParseNote() unexpected error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseNote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLeap(t *testing.T) {
+	tests := []struct {
+		name     string
+		n1, n2   Note
+		expected bool
+	}{
+		// Unisons
+		{
+			name:     "unison",
+			n1:       Note{Step: 0, Octave: 4}, // C4
+			n2:       Note{Step: 0, Octave: 4}, // C4
+			expected: false,
+		},
+		// Seconds
+		{
+			name:     "ascending second",
+			n1:       Note{Step: 0, Octave: 4}, // C4
+			n2:       Note{Step: 1, Octave: 4}, // D4
+			expected: false,
+		},
+		{
+			name:     "descending second",
+			n1:       Note{Step: 1, Octave: 4}, // D4
+			n2:       Note{Step: 0, Octave: 4}, // C4
+			expected: false,
+		},
+		{
+			name:     "second across octave",
+			n1:       Note{Step: 6, Octave: 4}, // B4
+			n2:       Note{Step: 0, Octave: 5}, // C5
+			expected: false,
+		},
+
+		// Thirds (leaps)
+		{
+			name:     "ascending third",
+			n1:       Note{Step: 0, Octave: 4}, // C4
+			n2:       Note{Step: 2, Octave: 4}, // E4
+			expected: true,
+		},
+		{
+			name:     "descending third",
+			n1:       Note{Step: 2, Octave: 4}, // E4
+			n2:       Note{Step: 0, Octave: 4}, // C4
+			expected: true,
+		},
+
+		// Larger intervals
+		{
+			name:     "octave leap",
+			n1:       Note{Step: 0, Octave: 4}, // C4
+			n2:       Note{Step: 0, Octave: 5}, // C5
+			expected: true,
+		},
+		{
+			name:     "compound third",
+			n1:       Note{Step: 0, Octave: 4}, // C4
+			n2:       Note{Step: 2, Octave: 5}, // E5
+			expected: true,
+		},
+		{
+			name:     "large descending leap",
+			n1:       Note{Step: 0, Octave: 5}, // C5
+			n2:       Note{Step: 3, Octave: 3}, // F3
+			expected: true,
+		},
+
+		// With alterations (shouldn't affect step calculation)
+		{
+			name:     "augmented second still step",
+			n1:       Note{Step: 0, Octave: 4, Alteration: 0}, // C4
+			n2:       Note{Step: 1, Octave: 4, Alteration: 1}, // D#4
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsLeap(tt.n1, tt.n2)
+			if result != tt.expected {
+				t.Errorf("IsLeap(%v, %v) = %v, want %v", tt.n1, tt.n2, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNote_Compare(t *testing.T) {
+	tests := []struct {
+		name         string
+		n1, n2       Note
+		wantChrom    int
+		wantDiatonic int
+	}{
+		{"same note", Note{0, 4, 0}, Note{0, 4, 0}, 0, 0},
+		{"step up", Note{0, 4, 0}, Note{1, 4, 0}, -1, -1},
+		{"octave down", Note{0, 5, 0}, Note{0, 4, 0}, 1, 1},
+		{"enharmonic F# and Gb", Note{3, 4, 1}, Note{4, 4, -1}, 0, -1},
+		{"C# above C diatonically equal", Note{0, 4, 1}, Note{0, 4, 0}, 1, 0},
+		{"B#3 and C4", Note{6, 3, 1}, Note{0, 4, 0}, 0, -1},
+		{"Cb4 and B3", Note{0, 4, -1}, Note{6, 3, 0}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.n1.Compare(tt.n2); got != tt.wantChrom {
+				t.Errorf("%v.Compare(%v) = %d, want %d", tt.n1, tt.n2, got, tt.wantChrom)
+			}
+			if got := tt.n2.Compare(tt.n1); got != -tt.wantChrom {
+				t.Errorf("%v.Compare(%v) = %d, want %d", tt.n2, tt.n1, got, -tt.wantChrom)
+			}
+			if got := tt.n1.CompareDiatonic(tt.n2); got != tt.wantDiatonic {
+				t.Errorf("%v.CompareDiatonic(%v) = %d, want %d", tt.n1, tt.n2, got, tt.wantDiatonic)
+			}
+		})
+	}
+}
+
+func TestSortNotes(t *testing.T) {
+	notes := []Note{{4, 4, -1}, {0, 5, 0}, {3, 4, 1}, {6, 3, 0}, {0, 4, 0}}
+
+	chromatic := append([]Note{}, notes...)
+	SortNotes(chromatic)
+	wantChromatic := []Note{{6, 3, 0}, {0, 4, 0}, {4, 4, -1}, {3, 4, 1}, {0, 5, 0}}
+	for i := range wantChromatic {
+		if chromatic[i] != wantChromatic[i] {
+			t.Errorf("SortNotes() = %v, want %v", chromatic, wantChromatic)
+			break
+		}
+	}
+
+	diatonic := append([]Note{}, notes...)
+	SortNotesDiatonic(diatonic)
+	wantDiatonic := []Note{{6, 3, 0}, {0, 4, 0}, {3, 4, 1}, {4, 4, -1}, {0, 5, 0}}
+	for i := range wantDiatonic {
+		if diatonic[i] != wantDiatonic[i] {
+			t.Errorf("SortNotesDiatonic() = %v, want %v", diatonic, wantDiatonic)
+			break
+		}
+	}
+}
+
+func TestNewNote(t *testing.T) {
+	tests := []struct {
+		name                     string
+		step, octave, alteration int
+		wantErr                  bool
+		wantString               string
+	}{
+		{"C4", 0, 4, 0, false, "C4"},
+		{"B3 flat", 6, 3, -1, false, "Bb3"},
+		{"double sharp", 3, 4, 2, false, "F##4"},
+		{"double flat", 6, 3, -2, false, "Bbb3"},
+		{"negative step", -1, 4, 0, true, "Note(-1,4,0)"},
+		{"step too large", 7, 4, 0, true, "Note(7,4,0)"},
+		{"triple flat", 1, 4, -3, true, "Note(1,4,-3)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewNote(tt.step, tt.octave, tt.alteration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewNote(%d, %d, %d) error = %v, wantErr %v", tt.step, tt.octave, tt.alteration, err, tt.wantErr)
+			}
+			if !tt.wantErr && n != (Note{tt.step, tt.octave, tt.alteration}) {
+				t.Errorf("NewNote() = %+v", n)
+			}
+
+			// String must not panic on invalid notes
+			raw := Note{Step: tt.step, Octave: tt.octave, Alteration: tt.alteration}
+			if got := raw.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestMustNote(t *testing.T) {
+	if n := MustNote(4, 4, 1); n != (Note{4, 4, 1}) {
+		t.Errorf("MustNote(4, 4, 1) = %+v", n)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustNote(9, 4, 0) did not panic")
+		}
+	}()
+	MustNote(9, 4, 0)
+}
+
+func TestNote_TextMarshaling(t *testing.T) {
+	tests := []struct {
+		note Note
+		text string
+	}{
+		{Note{0, 4, 0}, "C4"},
+		{Note{3, 4, 1}, "F#4"},
+		{Note{6, 2, -1}, "Bb2"},
+		{Note{1, 5, -2}, "Dbb5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			text, err := tt.note.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error = %v", err)
+			}
+			if string(text) != tt.text {
+				t.Errorf("MarshalText() = %q, want %q", text, tt.text)
+			}
+		})
+	}
+
+	var n Note
+	if err := n.UnmarshalText([]byte("g#3")); err != nil || n != (Note{4, 3, 1}) {
+		t.Errorf("UnmarshalText(\"g#3\") = %v, %v; want G#3", n, err)
+	}
+	if err := n.UnmarshalText([]byte("H4")); err == nil {
+		t.Error("UnmarshalText(\"H4\") should return an error")
+	}
+	if _, err := (Note{7, 4, 0}).MarshalText(); err == nil {
+		t.Error("MarshalText() of an invalid note should return an error")
+	}
+}
+
+func TestTransposeChromatic(t *testing.T) {
+	tests := []struct {
+		name     string
+		note     Note
+		interval IntervalWithQuality
+		want     Note
+		wantErr  bool
+	}{
+		{"E4 up a minor third", Note{2, 4, 0}, IntervalWithQuality{2, "m"}, Note{4, 4, 0}, false},
+		{"E4 up a major third", Note{2, 4, 0}, IntervalWithQuality{2, "M"}, Note{4, 4, 1}, false},
+		{"F4 up an augmented fourth", Note{3, 4, 0}, IntervalWithQuality{3, "A"}, Note{6, 4, 0}, false},
+		{"B4 up a perfect fifth", Note{6, 4, 0}, IntervalWithQuality{4, "P"}, Note{3, 5, 1}, false},
+		{"C4 down a minor second", Note{0, 4, 0}, IntervalWithQuality{-1, "m"}, Note{6, 3, 0}, false},
+		{"D4 down a major third", Note{1, 4, 0}, IntervalWithQuality{-2, "M"}, Note{6, 3, -1}, false},
+		{"C#4 up a diminished seventh", Note{0, 4, 1}, IntervalWithQuality{6, "d"}, Note{6, 4, -1}, false},
+		{"Eb4 up a major tenth", Note{2, 4, -1}, IntervalWithQuality{9, "M"}, Note{4, 5, 0}, false},
+		{"G4 perfect unison", Note{4, 4, 0}, IntervalWithQuality{0, "P"}, Note{4, 4, 0}, false},
+		{"A4 up a perfect octave", Note{5, 4, 0}, IntervalWithQuality{7, "P"}, Note{5, 5, 0}, false},
+		{"major fifth", Note{0, 4, 0}, IntervalWithQuality{4, "M"}, Note{}, true},
+		{"perfect third", Note{0, 4, 0}, IntervalWithQuality{2, "P"}, Note{}, true},
+		{"unknown quality", Note{0, 4, 0}, IntervalWithQuality{2, "x"}, Note{}, true},
+		{"triple sharp", Note{6, 4, 2}, IntervalWithQuality{3, "A"}, Note{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TransposeChromatic(tt.note, tt.interval)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TransposeChromatic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("TransposeChromatic(%v, %v) = %v, want %v", tt.note, tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNote_DiatonicValue(t *testing.T) {
+	tests := []struct {
+		note Note
+		want int
+	}{
+		{Note{0, 0, 0}, 0},
+		{Note{0, 4, 0}, 28},
+		{Note{0, 4, 1}, 28},
+		{Note{6, 3, 1}, 27},
+		{Note{1, 5, -1}, 36},
+		{Note{5, -1, 0}, -2},
+	}
+
+	for _, tt := range tests {
+		if got := tt.note.DiatonicValue(); got != tt.want {
+			t.Errorf("%v.DiatonicValue() = %d, want %d", tt.note, got, tt.want)
+		}
+	}
+
+	// The difference of diatonic values is the interval between the notes
+	d4, a4 := Note{1, 4, 0}, Note{5, 4, 0}
+	if got := Transpose(d4, Interval(a4.DiatonicValue()-d4.DiatonicValue())); got != a4 {
+		t.Errorf("Transpose(D4, A4-D4) = %v, want A4", got)
+	}
+}
@@ -0,0 +1,53 @@
+package music
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PitchClass is a note without its octave: a diatonic step with an alteration.
+// Pitch classes with the same spelling compare equal with ==; use EnharmonicEqual
+// to also treat differently spelled pitch classes (e.g. F# and Gb) as equal.
+//
+// Fields:
+//   - Step: diatonic step number (0 = C, 1 = D, ..., 6 = B)
+//   - Alteration: accidental (-2 = double flat, ..., 2 = double sharp)
+type PitchClass struct {
+	Step       int
+	Alteration int
+}
+
+// PitchClass returns the pitch class of the note.
+func (n Note) PitchClass() PitchClass {
+	return PitchClass{Step: n.Step, Alteration: n.Alteration}
+}
+
+// Note returns the note of the pitch class in the given octave.
+func (pc PitchClass) Note(octave int) Note {
+	return Note{Step: pc.Step, Octave: octave, Alteration: pc.Alteration}
+}
+
+// String returns the name of the pitch class, e.g. "F#" or "Bb".
+func (pc PitchClass) String() string {
+	n := pc.Note(0)
+	if n.Validate() != nil {
+		return fmt.Sprintf("PitchClass(%d,%d)", pc.Step, pc.Alteration)
+	}
+	return strings.TrimSuffix(n.String(), "0")
+}
+
+// Semitone returns the position of the pitch class within the octave in semitones (0-11, C = 0).
+func (pc PitchClass) Semitone() int {
+	return ((pc.Note(0).Semitones() % 12) + 12) % 12
+}
+
+// EnharmonicEqual reports whether both pitch classes sound the same, e.g. F# and Gb or B# and C.
+func (pc PitchClass) EnharmonicEqual(other PitchClass) bool {
+	return pc.Semitone() == other.Semitone()
+}
+
+// ContainsPitchClass reports whether the pitch class belongs to the scale with exactly
+// the alteration the scale prescribes for its degree (see Scale.Contains).
+func (s Scale) ContainsPitchClass(pc PitchClass) bool {
+	return s.Contains(pc.Note(s.Tonic.Octave))
+}
@@ -0,0 +1,82 @@
+package music
+
+import "testing"
+
+func TestPitchClass(t *testing.T) {
+	tests := []struct {
+		name         string
+		note         Note
+		wantString   string
+		wantSemitone int
+	}{
+		{"C", Note{0, 4, 0}, "C", 0},
+		{"F sharp", Note{3, 2, 1}, "F#", 6},
+		{"B flat", Note{6, 5, -1}, "Bb", 10},
+		{"B sharp wraps to 0", Note{6, 3, 1}, "B#", 0},
+		{"C flat wraps to 11", Note{0, 4, -1}, "Cb", 11},
+		{"double sharp", Note{4, 4, 2}, "G##", 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := tt.note.PitchClass()
+			if got := pc.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+			if got := pc.Semitone(); got != tt.wantSemitone {
+				t.Errorf("Semitone() = %d, want %d", got, tt.wantSemitone)
+			}
+			if got := pc.Note(tt.note.Octave); got != tt.note {
+				t.Errorf("Note(%d) = %v, want %v", tt.note.Octave, got, tt.note)
+			}
+		})
+	}
+
+	if got := (PitchClass{Step: 8}).String(); got != "PitchClass(8,0)" {
+		t.Errorf("String() of an invalid pitch class = %q", got)
+	}
+}
+
+func TestPitchClass_EnharmonicEqual(t *testing.T) {
+	tests := []struct {
+		a, b PitchClass
+		want bool
+	}{
+		{PitchClass{3, 1}, PitchClass{4, -1}, true},
+		{PitchClass{6, 1}, PitchClass{0, 0}, true},
+		{PitchClass{0, -1}, PitchClass{6, 0}, true},
+		{PitchClass{3, 1}, PitchClass{3, 0}, false},
+		{PitchClass{1, 0}, PitchClass{1, 0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a.String()+"-"+tt.b.String(), func(t *testing.T) {
+			if got := tt.a.EnharmonicEqual(tt.b); got != tt.want {
+				t.Errorf("%v.EnharmonicEqual(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScale_ContainsPitchClass(t *testing.T) {
+	dMajor := Scale{Mode: Major, Tonic: Note{1, 4, 0}}
+
+	tests := []struct {
+		pc   PitchClass
+		want bool
+	}{
+		{PitchClass{3, 1}, true},   // F#
+		{PitchClass{3, 0}, false},  // F
+		{PitchClass{4, -1}, false}, // Gb is enharmonic to F# but not in the scale
+		{PitchClass{0, 1}, true},   // C#
+		{PitchClass{6, 0}, true},   // B
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pc.String(), func(t *testing.T) {
+			if got := dMajor.ContainsPitchClass(tt.pc); got != tt.want {
+				t.Errorf("ContainsPitchClass(%v) = %v, want %v", tt.pc, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package music
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NoteRange is an inclusive range of absolute pitches, compared chromatically (see Note.Compare).
+type NoteRange struct {
+	Low  Note
+	High Note
+}
+
+// DefaultRange is a practical vocal and instrumental range for a cantus firmus, C2 to C7.
+var DefaultRange = NoteRange{Low: Note{Step: 0, Octave: 2}, High: Note{Step: 0, Octave: 7}}
+
+// Contains reports whether the note lies within the range, inclusive.
+func (r NoteRange) Contains(n Note) bool {
+	return n.Compare(r.Low) >= 0 && n.Compare(r.High) <= 0
+}
+
+// String returns the range as "low-high", e.g. "C2-C7".
+func (r NoteRange) String() string {
+	return fmt.Sprintf("%s-%s", r.Low, r.High)
+}
+
+// ParseNoteRange parses a range written as two notes joined by a hyphen, low first,
+// e.g. "C3-G4" or "A-1-C1" (see ParseNote).
+func ParseNoteRange(s string) (NoteRange, error) {
+	// Negative octaves contain hyphens too, so try every hyphen as the separator
+	for i := 0; i < len(s); i++ {
+		if s[i] != '-' {
+			continue
+		}
+		low, errLow := ParseNote(strings.TrimSpace(s[:i]))
+		high, errHigh := ParseNote(strings.TrimSpace(s[i+1:]))
+		if errLow != nil || errHigh != nil {
+			continue
+		}
+		if low.Compare(high) > 0 {
+			return NoteRange{}, fmt.Errorf("invalid range %q: %s is above %s", s, low, high)
+		}
+		return NoteRange{Low: low, High: high}, nil
+	}
+	return NoteRange{}, fmt.Errorf("invalid range %q: want low-high, e.g. C3-G4", s)
+}
+
+// ShiftInto returns the realization moved by as few whole octaves as needed to fit
+// the range, or the realization itself if it fits already or cannot fit at all.
+func (r Realization) ShiftInto(rng NoteRange) Realization {
+	if len(r) == 0 {
+		return r
+	}
+	sorted := r.Sorted()
+	low, high := sorted[0].Semitones(), sorted[len(sorted)-1].Semitones()
+
+	// Octave shifts k with low+12k >= rng.Low and high+12k <= rng.High
+	minShift := ceilDiv(rng.Low.Semitones()-low, 12)
+	maxShift := floorDiv(rng.High.Semitones()-high, 12)
+	if minShift > maxShift {
+		return r
+	}
+	k := min(max(0, minShift), maxShift)
+	if k == 0 {
+		return r
+	}
+	shifted := make(Realization, len(r))
+	for i, n := range r {
+		n.Octave += k
+		shifted[i] = n
+	}
+	return shifted
+}
+
+// RangeError reports a note outside a NoteRange.
+//
+// Fields:
+//   - Index: position of the note in the realization, or -1 for a single transposed note
+//   - Note: the offending note
+//   - Range: the range it violates
+type RangeError struct {
+	Index int
+	Note  Note
+	Range NoteRange
+}
+
+func (e *RangeError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("note %s is outside the range %s", e.Note, e.Range)
+	}
+	return fmt.Sprintf("note %d (%s) is outside the range %s", e.Index+1, e.Note, e.Range)
+}
+
+// TransposeInRange works like Transpose but returns a *RangeError
+// if the resulting note lies outside the range.
+func TransposeInRange(n Note, i Interval, r NoteRange) (Note, error) {
+	target := Transpose(n, i)
+	if !r.Contains(target) {
+		return Note{}, &RangeError{Index: -1, Note: target, Range: r}
+	}
+	return target, nil
+}
+
+// fitRange checks that every note of the realization lies within r.
+// If shift is true, a realization that does not fit is first moved by whole octaves,
+// by as few as possible, so that it does. It returns a *RangeError for the first note
+// that still lies outside the range.
+func fitRange(realization Realization, r NoteRange, shift bool) (Realization, error) {
+	if len(realization) == 0 {
+		return realization, nil
+	}
+
+	if shift {
+		realization = realization.ShiftInto(r)
+	}
+
+	for i, n := range realization {
+		if !r.Contains(n) {
+			return nil, &RangeError{Index: i, Note: n, Range: r}
+		}
+	}
+	return realization, nil
+}
+
+// floorDiv returns a/b rounded towards negative infinity for b > 0.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// ceilDiv returns a/b rounded towards positive infinity for b > 0.
+func ceilDiv(a, b int) int {
+	return -floorDiv(-a, b)
+}
@@ -0,0 +1,102 @@
+package music
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNoteRange_Contains(t *testing.T) {
+	r := NoteRange{Low: Note{0, 3, 0}, High: Note{0, 5, 0}} // C3-C5
+	tests := []struct {
+		note Note
+		want bool
+	}{
+		{Note{0, 3, 0}, true},
+		{Note{0, 5, 0}, true},
+		{Note{4, 4, 1}, true},
+		{Note{6, 2, 0}, false},
+		{Note{0, 3, -1}, false}, // Cb3 sounds as B2
+		{Note{6, 4, 1}, true},   // B#4 sounds as C5
+		{Note{1, 5, 0}, false},
+	}
+
+	for _, tt := range tests {
+		if got := r.Contains(tt.note); got != tt.want {
+			t.Errorf("%v.Contains(%v) = %v, want %v", r, tt.note, got, tt.want)
+		}
+	}
+
+	if got := DefaultRange.String(); got != "C2-C7" {
+		t.Errorf("DefaultRange.String() = %q, want %q", got, "C2-C7")
+	}
+}
+
+func TestTransposeInRange(t *testing.T) {
+	got, err := TransposeInRange(Note{0, 6, 0}, 4, DefaultRange)
+	if err != nil || got != (Note{4, 6, 0}) {
+		t.Errorf("TransposeInRange(C6, fifth up) = %v, %v; want G6", got, err)
+	}
+
+	_, err = TransposeInRange(Note{0, 6, 0}, 8, DefaultRange)
+	var rangeErr *RangeError
+	if !errors.As(err, &rangeErr) {
+		t.Fatalf("TransposeInRange(C6, ninth up) error = %v, want a *RangeError", err)
+	}
+	if rangeErr.Note != (Note{1, 7, 0}) {
+		t.Errorf("RangeError.Note = %v, want D7", rangeErr.Note)
+	}
+	if want := "note D7 is outside the range C2-C7"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRealization_ShiftInto(t *testing.T) {
+	melody := Realization{{1, 4, 0}, {3, 4, 0}, {2, 4, 0}, {1, 4, 0}} // D4 F4 E4 D4
+	tests := []struct {
+		name  string
+		rng   string
+		shift int
+	}{
+		{"fits already", "C4-G5", 0},
+		{"one octave down", "F2-C4", -1},
+		{"two octaves up", "C6-C7", 2},
+		{"too narrow", "D4-E4", 0},
+		{"no octave fits", "A4-E5", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng, err := ParseNoteRange(tt.rng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := melody.ShiftInto(rng)
+			for i, n := range got {
+				if n.Octave != melody[i].Octave+tt.shift || n.Step != melody[i].Step {
+					t.Fatalf("ShiftInto(%s) = %v, want %v moved by %d octaves", tt.rng, got, melody, tt.shift)
+				}
+			}
+		})
+	}
+}
+
+func TestFloorCeilDiv(t *testing.T) {
+	tests := []struct {
+		a, b, floor, ceil int
+	}{
+		{7, 12, 0, 1},
+		{-7, 12, -1, 0},
+		{24, 12, 2, 2},
+		{-24, 12, -2, -2},
+		{0, 12, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := floorDiv(tt.a, tt.b); got != tt.floor {
+			t.Errorf("floorDiv(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.floor)
+		}
+		if got := ceilDiv(tt.a, tt.b); got != tt.ceil {
+			t.Errorf("ceilDiv(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.ceil)
+		}
+	}
+}
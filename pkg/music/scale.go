@@ -0,0 +1,125 @@
+package music
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode represents one of the diatonic modes a cantus firmus can be realized in.
+// The zero value is not a valid mode.
+type Mode int
+
+const (
+	Major Mode = iota + 1
+	Dorian
+	Phrygian
+	Lydian
+	Mixolydian
+	Minor
+	Locrian
+)
+
+// modeNames holds the names of the modes in the order of their constants.
+var modeNames = []string{"Major", "Dorian", "Phrygian", "Lydian", "Mixolydian", "Minor", "Locrian"}
+
+// Modes returns all supported modes in order.
+func Modes() []Mode {
+	return []Mode{Major, Dorian, Phrygian, Lydian, Mixolydian, Minor, Locrian}
+}
+
+// String returns the name of the mode (e.g. "Dorian").
+func (m Mode) String() string {
+	if m < Major || m > Locrian {
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+	return modeNames[m-1]
+}
+
+// ParseMode parses a mode name case-insensitively (e.g. "dorian", "Dorian").
+func ParseMode(s string) (Mode, error) {
+	for i, name := range modeNames {
+		if strings.EqualFold(s, name) {
+			return Mode(i + 1), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown mode: %s", s)
+}
+
+// MarshalText implements encoding.TextMarshaler using the mode name (e.g. "Dorian").
+// The zero value and other invalid modes cannot be marshaled.
+func (m Mode) MarshalText() ([]byte, error) {
+	if m < Major || m > Locrian {
+		return nil, fmt.Errorf("invalid mode %d", int(m))
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseMode.
+func (m *Mode) UnmarshalText(text []byte) error {
+	parsed, err := ParseMode(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// finalStep returns the diatonic step of the mode's final on the white keys
+// (C for Major, D for Dorian, ..., B for Locrian).
+func (m Mode) finalStep() int {
+	return int(m) - 1
+}
+
+// Scale represents a diatonic scale built from a mode on a given tonic (final).
+// The tonic's octave is the reference octave: degree 1 in that octave is the tonic itself.
+type Scale struct {
+	Mode  Mode
+	Tonic Note
+}
+
+// NewScale returns the untransposed scale of the mode, with its final in the 4th octave
+// (C4 for Major, D4 for Dorian, ..., B4 for Locrian).
+func NewScale(mode Mode) Scale {
+	return Scale{Mode: mode, Tonic: Note{Step: mode.finalStep(), Octave: 4}}
+}
+
+// semitonePattern returns the distances in semitones from the tonic to each degree of the mode.
+func (s Scale) semitonePattern() [7]int {
+	diatonicSemitones := []int{0, 2, 4, 5, 7, 9, 11}
+	final := s.Mode.finalStep()
+
+	var pattern [7]int
+	for i := range pattern {
+		step := final + i
+		pattern[i] = diatonicSemitones[step%7] + 12*(step/7) - diatonicSemitones[final]
+	}
+	return pattern
+}
+
+// Degree returns the scale degree (1-7) of the note, determined by its diatonic step
+// relative to the tonic. Alterations are ignored, so G and G# are both the 7th degree of A minor.
+func (s Scale) Degree(n Note) int {
+	return Mod7(n.Step-s.Tonic.Step) + 1
+}
+
+// NoteForDegree returns the note of the given degree (1-7) in the given octave of the scale,
+// spelled with the alteration required by the mode and tonic.
+// The octave counts from the tonic, so NoteForDegree(7, 4) in D Dorian is C5.
+func (s Scale) NoteForDegree(degree, octave int) Note {
+	totalStep := s.Tonic.Step + Mod7(degree-1)
+	note := Note{
+		Step:   totalStep % 7,
+		Octave: octave + totalStep/7,
+	}
+
+	target := Note{Step: s.Tonic.Step, Octave: octave, Alteration: s.Tonic.Alteration}.Semitones() +
+		s.semitonePattern()[Mod7(degree-1)]
+	note.Alteration = target - note.Semitones()
+	return note
+}
+
+// Contains reports whether the note belongs to the scale with exactly the alteration
+// the scale prescribes for its degree.
+func (s Scale) Contains(n Note) bool {
+	return s.NoteForDegree(s.Degree(n), s.Tonic.Octave).PitchClass() == n.PitchClass()
+}
@@ -0,0 +1,137 @@
+package music
+
+import "testing"
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Mode
+		wantErr bool
+	}{
+		{"Major", Major, false},
+		{"dorian", Dorian, false},
+		{"PHRYGIAN", Phrygian, false},
+		{"Locrian", Locrian, false},
+		{"Blues", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMode(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMode_String(t *testing.T) {
+	for _, mode := range Modes() {
+		parsed, err := ParseMode(mode.String())
+		if err != nil || parsed != mode {
+			t.Errorf("ParseMode(%q) = %v, %v; want %v", mode.String(), parsed, err, mode)
+		}
+	}
+	if got := Mode(0).String(); got != "Mode(0)" {
+		t.Errorf("Mode(0).String() = %q, want %q", got, "Mode(0)")
+	}
+}
+
+func TestScale_Degree(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale Scale
+		note  Note
+		want  int
+	}{
+		{"tonic of major", NewScale(Major), Note{Step: 0, Octave: 5}, 1},
+		{"leading tone of major", NewScale(Major), Note{Step: 6, Octave: 3}, 7},
+		{"fifth of dorian", NewScale(Dorian), Note{Step: 5, Octave: 4}, 5},
+		{"raised seventh of minor", NewScale(Minor), Note{Step: 4, Octave: 5, Alteration: 1}, 7},
+		{"second of locrian", NewScale(Locrian), Note{Step: 0, Octave: 5}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scale.Degree(tt.note); got != tt.want {
+				t.Errorf("Degree(%s) = %d, want %d", tt.note, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScale_NoteForDegree(t *testing.T) {
+	tests := []struct {
+		name   string
+		scale  Scale
+		degree int
+		octave int
+		want   string
+	}{
+		{"major tonic", NewScale(Major), 1, 4, "C4"},
+		{"major seventh", NewScale(Major), 7, 4, "B4"},
+		{"dorian seventh crosses octave", NewScale(Dorian), 7, 4, "C5"},
+		{"phrygian second", NewScale(Phrygian), 2, 3, "F3"},
+		{"minor sixth", NewScale(Minor), 6, 4, "F5"},
+		{"transposed dorian on G", Scale{Mode: Dorian, Tonic: Note{Step: 4, Octave: 4}}, 3, 4, "Bb4"},
+		{"transposed major on D", Scale{Mode: Major, Tonic: Note{Step: 1, Octave: 4}}, 7, 4, "C#5"},
+		{"transposed major on Bb", Scale{Mode: Major, Tonic: Note{Step: 6, Octave: 3, Alteration: -1}}, 4, 3, "Eb4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scale.NoteForDegree(tt.degree, tt.octave).String(); got != tt.want {
+				t.Errorf("NoteForDegree(%d, %d) = %s, want %s", tt.degree, tt.octave, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScale_Contains(t *testing.T) {
+	scale := Scale{Mode: Major, Tonic: Note{Step: 4, Octave: 4}} // G major
+	tests := []struct {
+		note Note
+		want bool
+	}{
+		{Note{Step: 3, Octave: 5, Alteration: 1}, true}, // F#5
+		{Note{Step: 3, Octave: 4}, false},               // F4
+		{Note{Step: 0, Octave: 3}, true},                // C3
+		{Note{Step: 1, Octave: 4, Alteration: -1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.note.String(), func(t *testing.T) {
+			if got := scale.Contains(tt.note); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.note, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMode_TextMarshaling(t *testing.T) {
+	for _, m := range Modes() {
+		text, err := m.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v) error = %v", m, err)
+		}
+		var got Mode
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q) error = %v", text, err)
+		}
+		if got != m {
+			t.Errorf("UnmarshalText(MarshalText(%v)) = %v", m, got)
+		}
+	}
+
+	if _, err := Mode(0).MarshalText(); err == nil {
+		t.Error("MarshalText() of the zero mode should return an error")
+	}
+	var m Mode
+	if err := m.UnmarshalText([]byte("blues")); err == nil {
+		t.Error("UnmarshalText(\"blues\") should return an error")
+	}
+}
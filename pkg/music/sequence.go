@@ -0,0 +1,47 @@
+package music
+
+import "iter"
+
+// PartialSums returns the heights of the notes of an interval sequence relative to its first note,
+// in diatonic steps: the result has len(intervals)+1 elements, starting with 0, and element i+1
+// is element i plus intervals[i].
+//
+// Example: [2, -1, -1] → [0, 2, 1, 0].
+func PartialSums(intervals []int) []int {
+	sums := make([]int, len(intervals)+1)
+	for i, interval := range intervals {
+		sums[i+1] = sums[i] + interval
+	}
+	return sums
+}
+
+// Windows yields every contiguous sub-slice of s with the given size, from left to right,
+// together with the index of its first element. It yields nothing if size is not positive
+// or larger than s. The yielded slices share memory with s and must not be modified.
+//
+// Example: Windows([]int{1, 2, 3, 4}, 3) yields (0, [1 2 3]) and (1, [2 3 4]).
+func Windows(s []int, size int) iter.Seq2[int, []int] {
+	return func(yield func(int, []int) bool) {
+		if size <= 0 {
+			return
+		}
+		for i := 0; i+size <= len(s); i++ {
+			if !yield(i, s[i:i+size:i+size]) {
+				return
+			}
+		}
+	}
+}
+
+// Extrema returns the indices of the interior local extrema of a sequence of heights
+// (see PartialSums): the elements strictly higher or strictly lower than both neighbors.
+// The first and last elements are never included.
+func Extrema(heights []int) []int {
+	var result []int
+	for i, w := range Windows(heights, 3) {
+		if (w[1] > w[0] && w[1] > w[2]) || (w[1] < w[0] && w[1] < w[2]) {
+			result = append(result, i+1)
+		}
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package music
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPartialSums(t *testing.T) {
+	tests := []struct {
+		intervals []int
+		want      []int
+	}{
+		{nil, []int{0}},
+		{[]int{2, -1, -1}, []int{0, 2, 1, 0}},
+		{[]int{-4, 1, 1, 1, 1}, []int{0, -4, -3, -2, -1, 0}},
+	}
+
+	for _, tt := range tests {
+		if got := PartialSums(tt.intervals); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PartialSums(%v) = %v, want %v", tt.intervals, got, tt.want)
+		}
+	}
+}
+
+func TestWindows(t *testing.T) {
+	tests := []struct {
+		s         []int
+		size      int
+		wantStart []int
+		want      [][]int
+	}{
+		{[]int{1, 2, 3, 4}, 3, []int{0, 1}, [][]int{{1, 2, 3}, {2, 3, 4}}},
+		{[]int{1, 2, 3}, 1, []int{0, 1, 2}, [][]int{{1}, {2}, {3}}},
+		{[]int{1, 2, 3}, 3, []int{0}, [][]int{{1, 2, 3}}},
+		{[]int{1, 2}, 3, nil, nil},
+		{[]int{1, 2}, 0, nil, nil},
+		{nil, 2, nil, nil},
+	}
+
+	for _, tt := range tests {
+		var gotStart []int
+		var got [][]int
+		for i, w := range Windows(tt.s, tt.size) {
+			gotStart = append(gotStart, i)
+			got = append(got, w)
+		}
+		if !reflect.DeepEqual(gotStart, tt.wantStart) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Windows(%v, %d) yielded %v at %v, want %v at %v", tt.s, tt.size, got, gotStart, tt.want, tt.wantStart)
+		}
+	}
+
+	// Appending to a window must not overwrite the underlying slice
+	s := []int{1, 2, 3}
+	for _, w := range Windows(s, 2) {
+		_ = append(w, 9)
+		break
+	}
+	if !reflect.DeepEqual(s, []int{1, 2, 3}) {
+		t.Errorf("appending to a window modified the slice: %v", s)
+	}
+
+	// Breaking out of the loop stops the iteration
+	count := 0
+	for range Windows([]int{1, 2, 3, 4}, 2) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("iteration continued after break: %d windows", count)
+	}
+}
+
+func TestExtrema(t *testing.T) {
+	tests := []struct {
+		heights []int
+		want    []int
+	}{
+		{[]int{0, 2, 1, 0}, []int{1}},
+		{[]int{0, 1, 2, 3}, nil},
+		{[]int{0, -2, 1, 1, 0}, []int{1}},
+		{[]int{0, 3, 1, 4, -1, 0}, []int{1, 2, 3, 4}},
+		{[]int{0}, nil},
+	}
+
+	for _, tt := range tests {
+		if got := Extrema(tt.heights); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Extrema(%v) = %v, want %v", tt.heights, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package music
+
+import "github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+
+// fifthsPositions holds the positions of the natural steps C-B on the line of fifths,
+// measured in fifths from C (F = -1, C = 0, G = 1, ..., B = 5).
+var fifthsPositions = [7]int{0, 2, 4, -1, 1, 3, 5}
+
+// FifthsPosition returns the position of the pitch class on the line of fifths, in fifths from C:
+// ... Bb = -2, F = -1, C = 0, G = 1, ..., F# = 6, C# = 7 ...
+// Each sharp adds 7 and each flat subtracts 7.
+func (pc PitchClass) FifthsPosition() int {
+	return fifthsPositions[pc.Step] + 7*pc.Alteration
+}
+
+// SpellNote returns the note re-spelled as the scale would write it, keeping its pitch.
+// Notes of the scale get the scale's spelling (Gb becomes F# in G major); other notes get
+// the spelling closest to the scale on the line of fifths, so chromatic notes follow the key
+// (Eb rather than D# in F major, C# rather than Db as the leading tone of D minor).
+// When two spellings are equally close, the sharp one is chosen.
+// Alterations beyond a double sharp or double flat are never produced.
+func (s Scale) SpellNote(n Note) Note {
+	low, high := s.fifthsRange()
+	center2 := low + high // twice the center, to stay in integers
+
+	semitone := n.PitchClass().Semitone()
+	best := n
+	bestDistance := -1
+	bestPosition := 0
+	for step := 0; step < 7; step++ {
+		for alteration := -MaxAlteration; alteration <= MaxAlteration; alteration++ {
+			pc := PitchClass{Step: step, Alteration: alteration}
+			if pc.Semitone() != semitone {
+				continue
+			}
+
+			position := pc.FifthsPosition()
+			distance := utils.Abs(2*position - center2)
+			if bestDistance < 0 || distance < bestDistance || distance == bestDistance && position > bestPosition {
+				bestDistance, bestPosition = distance, position
+				best = respell(n, pc)
+			}
+		}
+	}
+	return best
+}
+
+// Spell returns a copy of the realization with every note re-spelled by SpellNote.
+func (s Scale) Spell(r Realization) Realization {
+	spelled := make(Realization, len(r))
+	for i, n := range r {
+		spelled[i] = s.SpellNote(n)
+	}
+	return spelled
+}
+
+// fifthsRange returns the lowest and highest line-of-fifths positions of the notes of the scale.
+func (s Scale) fifthsRange() (int, int) {
+	low, high := 0, 0
+	for degree := 1; degree <= 7; degree++ {
+		position := s.NoteForDegree(degree, s.Tonic.Octave).PitchClass().FifthsPosition()
+		if degree == 1 || position < low {
+			low = position
+		}
+		if degree == 1 || position > high {
+			high = position
+		}
+	}
+	return low, high
+}
+
+// respell returns the note with the same pitch as n written as the pitch class pc,
+// adjusting the octave when the spelling crosses the B-C boundary (e.g. B#3 and C4).
+func respell(n Note, pc PitchClass) Note {
+	result := pc.Note(n.Octave)
+	result.Octave += (n.Semitones() - result.Semitones()) / 12
+	return result
+}
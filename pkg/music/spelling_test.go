@@ -0,0 +1,77 @@
+package music
+
+import "testing"
+
+func TestPitchClass_FifthsPosition(t *testing.T) {
+	tests := []struct {
+		pc   PitchClass
+		want int
+	}{
+		{PitchClass{0, 0}, 0},
+		{PitchClass{3, 0}, -1},
+		{PitchClass{6, 0}, 5},
+		{PitchClass{3, 1}, 6},
+		{PitchClass{6, -1}, -2},
+		{PitchClass{4, -1}, -6},
+		{PitchClass{3, 2}, 13},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pc.FifthsPosition(); got != tt.want {
+			t.Errorf("%v.FifthsPosition() = %d, want %d", tt.pc, got, tt.want)
+		}
+	}
+}
+
+func TestScale_SpellNote(t *testing.T) {
+	gMajor := Scale{Mode: Major, Tonic: Note{4, 4, 0}}
+	fMajor := Scale{Mode: Major, Tonic: Note{3, 4, 0}}
+	dMinor := Scale{Mode: Minor, Tonic: Note{1, 4, 0}}
+	gDorian := Scale{Mode: Dorian, Tonic: Note{4, 4, 0}}
+	ebMajor := Scale{Mode: Major, Tonic: Note{2, 4, -1}}
+
+	tests := []struct {
+		name  string
+		scale Scale
+		note  Note
+		want  Note
+	}{
+		{"Gb in G major is F#", gMajor, Note{4, 4, -1}, Note{3, 4, 1}},
+		{"scale note is kept", gMajor, Note{3, 4, 1}, Note{3, 4, 1}},
+		{"Gb in G dorian is F#", gDorian, Note{4, 4, -1}, Note{3, 4, 1}},
+		{"A# in G dorian is Bb", gDorian, Note{5, 4, 1}, Note{6, 4, -1}},
+		{"D# in F major is Eb", fMajor, Note{1, 5, 1}, Note{2, 5, -1}},
+		{"Cb in F major is B", fMajor, Note{0, 5, -1}, Note{6, 4, 0}},
+		{"Db in D minor is C#", dMinor, Note{1, 4, -1}, Note{0, 4, 1}},
+		{"Fx in C major is G", NewScale(Major), Note{3, 4, 2}, Note{4, 4, 0}},
+		{"Gb in C major is F#", NewScale(Major), Note{4, 4, -1}, Note{3, 4, 1}},
+		{"B#3 in C major is C4", NewScale(Major), Note{6, 3, 1}, Note{0, 4, 0}},
+		{"C4 in C# minor is B#3", Scale{Mode: Minor, Tonic: Note{0, 4, 1}}, Note{0, 4, 0}, Note{6, 3, 1}},
+		{"G# in Eb major is Ab", ebMajor, Note{4, 4, 1}, Note{5, 4, -1}},
+		{"E in Eb major is kept", ebMajor, Note{2, 4, 0}, Note{2, 4, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.scale.SpellNote(tt.note)
+			if got != tt.want {
+				t.Errorf("SpellNote(%v) = %v, want %v", tt.note, got, tt.want)
+			}
+			if !got.EqualPitch(tt.note) {
+				t.Errorf("SpellNote(%v) = %v changes the pitch", tt.note, got)
+			}
+		})
+	}
+}
+
+func TestScale_Spell(t *testing.T) {
+	r := Realization{{4, 4, 0}, {4, 4, -1}, {5, 4, 0}, {0, 5, 0}, {4, 4, 0}}
+	got := Scale{Mode: Major, Tonic: Note{4, 4, 0}}.Spell(r)
+	want := "G4 F#4 A4 C5 G4"
+	if got.String() != want {
+		t.Errorf("Spell() = %s, want %s", got, want)
+	}
+	if r[1] != (Note{4, 4, -1}) {
+		t.Error("Spell() modified its input")
+	}
+}
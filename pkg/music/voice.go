@@ -0,0 +1,44 @@
+package music
+
+import (
+	"fmt"
+	"strings"
+)
+
+// voices lists the choral voices with their ranges, as commonly taught for strict-style
+// exercises, from the highest voice to the lowest.
+var voices = []struct {
+	name   string
+	bounds NoteRange
+}{
+	{"soprano", NoteRange{Low: Note{Step: 0, Octave: 4}, High: Note{Step: 4, Octave: 5}}}, // C4-G5
+	{"alto", NoteRange{Low: Note{Step: 4, Octave: 3}, High: Note{Step: 0, Octave: 5}}},    // G3-C5
+	{"tenor", NoteRange{Low: Note{Step: 0, Octave: 3}, High: Note{Step: 4, Octave: 4}}},   // C3-G4
+	{"bass", NoteRange{Low: Note{Step: 3, Octave: 2}, High: Note{Step: 0, Octave: 4}}},    // F2-C4
+}
+
+// VoiceNames returns the names of the choral voices, from the highest to the lowest.
+func VoiceNames() []string {
+	names := make([]string, len(voices))
+	for i, v := range voices {
+		names[i] = v.name
+	}
+	return names
+}
+
+// ParseVoiceRange parses the range of a voice, written as the name of a choral voice
+// ("soprano", "alto", "tenor" or "bass", case-insensitive) or as custom boundaries
+// such as "D3-A4" (see ParseNoteRange).
+func ParseVoiceRange(s string) (NoteRange, error) {
+	for _, v := range voices {
+		if strings.EqualFold(s, v.name) {
+			return v.bounds, nil
+		}
+	}
+	r, err := ParseNoteRange(s)
+	if err != nil {
+		return NoteRange{}, fmt.Errorf("invalid voice %q: want %s or a range such as C3-G4",
+			s, strings.Join(VoiceNames(), ", "))
+	}
+	return r, nil
+}
@@ -0,0 +1,39 @@
+package music
+
+import "testing"
+
+func TestParseVoiceRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"soprano", "C4-G5", false},
+		{"Alto", "G3-C5", false},
+		{"tenor", "C3-G4", false},
+		{"BASS", "F2-C4", false},
+		{"D3-A4", "D3-A4", false},
+		{"Bb2 - Eb4", "Bb2-Eb4", false},
+		{"A-1-C1", "A-1-C1", false},
+		{"baritone", "", true},
+		{"G4-C3", "", true},
+		{"C3", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVoiceRange(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVoiceRange(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("ParseVoiceRange(%q) = %v, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if names := VoiceNames(); len(names) != 4 || names[0] != "soprano" || names[3] != "bass" {
+		t.Errorf("VoiceNames() = %v, want soprano to bass", names)
+	}
+}
@@ -0,0 +1,57 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseAmbitus parses a window of heights in steps relative to the final, written low..high,
+// e.g. "-2..7" for a melody staying between a third below and an octave above the final.
+func ParseAmbitus(s string) (low, high int, err error) {
+	lowText, highText, ok := strings.Cut(s, "..")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid ambitus %q: want low..high in steps from the final, e.g. -2..7", s)
+	}
+	low, errLow := strconv.Atoi(strings.TrimSpace(lowText))
+	high, errHigh := strconv.Atoi(strings.TrimSpace(highText))
+	if errLow != nil || errHigh != nil {
+		return 0, 0, fmt.Errorf("invalid ambitus %q: want low..high in steps from the final, e.g. -2..7", s)
+	}
+	if low > high {
+		return 0, 0, fmt.Errorf("invalid ambitus %q: the lowest note is above the highest", s)
+	}
+	return low, high, nil
+}
+
+// AmbitusRule returns a partial rule keeping every note of a melody beginning opening steps
+// above the final (see RuleSet.SetOpening) between low and high steps from the final
+// (see WithinAmbitus), for exercises written for a given voice.
+func AmbitusRule(low, high, opening int) Rule {
+	return Rule{
+		Name:        "Ambitus",
+		Description: fmt.Sprintf("Every note must lie from %d to %d steps above the final.", low, high),
+		Partial:     true,
+		Check:       WithinAmbitus(low-opening, high-opening),
+		Incremental: IncrementalWithinAmbitus(low-opening, high-opening),
+	}
+}
+
+// WithinAmbitus returns a rule requiring every note, the first one included, to lie between
+// low and high steps above the first note (below it if negative). Works with partial slices
+// during generation.
+func WithinAmbitus(low, high int) ValidationFunc {
+	return func(intervals []int) bool {
+		if low > 0 || high < 0 {
+			return false
+		}
+		height := 0
+		for _, interval := range intervals {
+			height += interval
+			if height < low || height > high {
+				return false
+			}
+		}
+		return true
+	}
+}
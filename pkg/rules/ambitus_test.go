@@ -0,0 +1,58 @@
+package rules
+
+import "testing"
+
+func TestParseAmbitus(t *testing.T) {
+	tests := []struct {
+		input     string
+		low, high int
+		wantErr   bool
+	}{
+		{"-2..7", -2, 7, false},
+		{" 0 .. 4 ", 0, 4, false},
+		{"-3..-3", -3, -3, false},
+		{"7..-2", 0, 0, true},
+		{"-2-7", 0, 0, true},
+		{"third..octave", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			low, high, err := ParseAmbitus(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAmbitus(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if low != tt.low || high != tt.high {
+				t.Errorf("ParseAmbitus(%q) = %d, %d, want %d, %d", tt.input, low, high, tt.low, tt.high)
+			}
+		})
+	}
+}
+
+func TestAmbitusRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		opening   int
+		intervals []int
+		want      bool
+	}{
+		{"within the window", 0, []int{1, 1, 3, -2, -1, -2, 1, -1}, true},
+		{"above the window", 0, []int{1, 1, 3, 2, 1}, false},
+		{"below the window", 0, []int{-1, -1, -1}, false},
+		{"at the lowest note", 0, []int{-1, -1, 1}, true},
+		{"opening on the fifth", 4, []int{1, 1, 1, 1}, false},
+		{"opening on the fifth within the window", 4, []int{1, -1, -1, -1, -1, -1}, true},
+		{"opening outside the window", 8, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := AmbitusRule(-2, 7, tt.opening)
+			if got := r.Check(tt.intervals); got != tt.want {
+				t.Errorf("Ambitus(-2..7) from %d on %v = %v, want %v", tt.opening, tt.intervals, got, tt.want)
+			}
+		})
+	}
+
+	checkIncremental(t, AmbitusRule(-3, 5, 1))
+}
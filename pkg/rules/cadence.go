@@ -0,0 +1,144 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// cadenceNames names the directions from which the final may be approached, for ParseCadence.
+var cadenceNames = map[string][]int{
+	"above":  {CadenceSupertonic},
+	"below":  {CadenceLeadingTone},
+	"either": {CadenceSupertonic, CadenceLeadingTone},
+}
+
+// ParseCadence parses the direction from which the final must be approached (case-insensitive):
+// "above" for the descending second 2–1 most textbooks prefer, "below" for the leading tone 7–1,
+// or "either". It returns the scale degrees to pass to RuleSet.SetCadence.
+func ParseCadence(s string) ([]int, error) {
+	degrees, ok := cadenceNames[strings.ToLower(s)]
+	if !ok {
+		return nil, fmt.Errorf("invalid cadence %q: want above, below or either", s)
+	}
+	return slices.Clone(degrees), nil
+}
+
+// cadenceRule returns the Cadence rule approaching the final from the given degrees.
+func cadenceRule(degrees ...int) Rule {
+	var from []string
+	if slices.Contains(degrees, CadenceSupertonic) {
+		from = append(from, "the supertonic")
+	}
+	if slices.Contains(degrees, CadenceLeadingTone) {
+		from = append(from, "the leading tone")
+	}
+	return Rule{
+		Name:        "Cadence",
+		Description: fmt.Sprintf("The final must be approached by step from %s.", strings.Join(from, " or ")),
+		Check:       Cadence(degrees...),
+	}
+}
+
+// EndsWith returns a rule requiring a melody of n intervals to end with one of the given
+// interval patterns, e.g. [][]int{{-1, -1}, {1, -1}, {-2, 1, -1}} for the endings 3-2-1,
+// 1-2-1 and 3-1-2-1.
+// Prefixes must agree with one of the patterns on the intervals they share with its place at
+// the end of the melody, so that the search prunes other endings as soon as it reaches them;
+// a pattern longer than the melody matches nothing, and so does a sequence of more than n
+// intervals. Works with partial slices during generation.
+func EndsWith(n int, patterns [][]int) ValidationFunc {
+	patterns = clonePatterns(patterns)
+	return func(intervals []int) bool {
+		return endsWith(n, patterns, intervals)
+	}
+}
+
+// IncrementalEndsWith returns incremental checkers equivalent to EndsWith(n, patterns).
+func IncrementalEndsWith(n int, patterns [][]int) func() IncrementalRule {
+	patterns = clonePatterns(patterns)
+	longest := 0
+	for _, p := range patterns {
+		longest = max(longest, len(p))
+	}
+	return func() IncrementalRule {
+		return &endingTracker{n: n, patterns: patterns, start: n - longest}
+	}
+}
+
+// endsWith reports whether the prefix of a melody of n intervals agrees with one of the patterns.
+// Longer sequences are not such prefixes.
+func endsWith(n int, patterns [][]int, intervals []int) bool {
+	if len(intervals) > n {
+		return false
+	}
+	for _, p := range patterns {
+		start := n - len(p)
+		if start < 0 {
+			continue
+		}
+		matches := true
+		for i := start; i < len(intervals); i++ {
+			if intervals[i] != p[i-start] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// clonePatterns returns a deep copy of the patterns.
+func clonePatterns(patterns [][]int) [][]int {
+	result := make([][]int, len(patterns))
+	for i, p := range patterns {
+		result[i] = slices.Clone(p)
+	}
+	return result
+}
+
+// endingTracker checks the prefixes of a melody against the patterns of EndsWith once they
+// reach the place of the longest one, which starts at interval start.
+type endingTracker struct {
+	n         int
+	patterns  [][]int
+	start     int
+	intervals []int
+}
+
+func (e *endingTracker) Push(interval int) bool {
+	e.intervals = append(e.intervals, interval)
+	return len(e.intervals) <= e.start || endsWith(e.n, e.patterns, e.intervals)
+}
+
+func (e *endingTracker) Pop() {
+	e.intervals = e.intervals[:len(e.intervals)-1]
+}
+
+// AppendSummary appends the intervals placed where the longest pattern lies, which decide
+// the patterns the melody can still end with.
+func (e *endingTracker) AppendSummary(b []byte) []byte {
+	return appendInts(b, e.intervals[min(max(e.start, 0), len(e.intervals)):]...)
+}
+
+// ParseCadencePatterns parses ending patterns for EndsWith: patterns separated by semicolons,
+// each a comma-separated list of intervals in steps, e.g. "-1,-1;1,-1;-2,1,-1".
+func ParseCadencePatterns(s string) ([][]int, error) {
+	var result [][]int
+	for _, field := range strings.Split(s, ";") {
+		var pattern []int
+		for _, value := range strings.Split(field, ",") {
+			interval, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid cadence pattern %q: want comma-separated intervals", field)
+			}
+			pattern = append(pattern, interval)
+		}
+		result = append(result, pattern)
+	}
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCadence(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{"above", []int{CadenceSupertonic}, false},
+		{"Below", []int{CadenceLeadingTone}, false},
+		{"EITHER", []int{CadenceSupertonic, CadenceLeadingTone}, false},
+		{"down", nil, true},
+		{"", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCadence(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCadence(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseCadence(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndsWith(t *testing.T) {
+	patterns := [][]int{{-1, -1}, {-2, 1, -1}}
+	tests := []struct {
+		name      string
+		intervals []int
+		want      bool
+	}{
+		{"before the ending", []int{1, 2, 1}, true},
+		{"third before the ending", []int{1, 2, 1, -2}, true},
+		{"step before the ending", []int{1, 2, 1, -1}, true},
+		{"leap before the ending", []int{1, 2, 1, -2, 3}, false},
+		{"too long", []int{1, 2, 1, 1, -1, -1, 1}, false},
+		{"first pattern", []int{1, 2, 1, 1, -1, -1}, true},
+		{"second pattern", []int{1, 2, 1, -2, 1, -1}, true},
+		{"no pattern", []int{1, 2, 1, -2, -1, 1}, false},
+		{"mixed patterns", []int{1, 2, 1, -2, -1, -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EndsWith(6, patterns)(tt.intervals); got != tt.want {
+				t.Errorf("EndsWith(6, %v)(%v) = %v, want %v", patterns, tt.intervals, got, tt.want)
+			}
+		})
+	}
+
+	if EndsWith(2, [][]int{{-2, 1, -1}})([]int{1, -1}) {
+		t.Error("a pattern longer than the melody must match nothing")
+	}
+	checkIncremental(t, Rule{Name: "EndsWith", Check: EndsWith(8, patterns), Incremental: IncrementalEndsWith(8, patterns)})
+}
+
+func TestParseCadencePatterns(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    [][]int
+		wantErr bool
+	}{
+		{"-1,-1", [][]int{{-1, -1}}, false},
+		{"-1,-1; 1,-1;-2, 1, -1", [][]int{{-1, -1}, {1, -1}, {-2, 1, -1}}, false},
+		{"-1,-1;", nil, true},
+		{"third,-1", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCadencePatterns(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCadencePatterns(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("ParseCadencePatterns(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+)
+
+// Catalog translates the messages of violations (see Violation.Message and Explain) into
+// another language. It maps each English message to its translation, gettext style:
+//
+//   - rule descriptions, e.g. "The range must not exceed a tenth." (see Rule.Description)
+//   - the format strings of located messages and explanations, e.g. "notes %d-%d: %s" or
+//     "The %s is not prepared by motion in the opposite direction.", whose translations
+//     must keep the verbs in order
+//   - interval names with their article, e.g. "a sixth up" or "an octave down"
+//
+// Messages missing from the catalog stay in English, so a catalog may be partial.
+type Catalog map[string]string
+
+// catalog is the catalog in use, nil for English.
+var catalog Catalog
+
+// SetCatalog sets the catalog all violation messages are translated with from then on;
+// nil restores English. Like Register, it is not safe for concurrent use and should be
+// called before any rule is checked, typically from main.
+func SetCatalog(c Catalog) {
+	catalog = maps.Clone(c)
+}
+
+// ReadCatalog reads a catalog written as a JSON object mapping English messages to their
+// translations, e.g. {"The melody must not contain melodic sequences.": "..."}.
+func ReadCatalog(r io.Reader) (Catalog, error) {
+	var c Catalog
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return nil, fmt.Errorf("catalog: %w", err)
+	}
+	return c, nil
+}
+
+// tr returns the translation of an English message or format string in the catalog in use,
+// or the message itself if it has none.
+func tr(message string) string {
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+	return message
+}
@@ -0,0 +1,49 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadCatalog(t *testing.T) {
+	c, err := ReadCatalog(strings.NewReader(`{"notes %d-%d: %s": "Noten %d-%d: %s"}`))
+	if err != nil {
+		t.Fatalf("ReadCatalog returned %v", err)
+	}
+	if got := c["notes %d-%d: %s"]; got != "Noten %d-%d: %s" {
+		t.Errorf("catalog maps the location format to %q", got)
+	}
+	if _, err := ReadCatalog(strings.NewReader(`["not", "an", "object"]`)); err == nil {
+		t.Error("ReadCatalog of an array must fail")
+	}
+}
+
+func TestSetCatalog(t *testing.T) {
+	t.Cleanup(func() { SetCatalog(nil) })
+	SetCatalog(Catalog{
+		locationFormat: "Noten %d-%d: %s",
+		"The melody must not begin with a leap of a sixth up.":        "Die Melodie darf nicht mit einer Sexte aufwärts beginnen.",
+		"The %s is not prepared by motion in the opposite direction.": "Der %s wird nicht durch Gegenbewegung vorbereitet.",
+		"leap of %s at %s": "Sprung um %s bei %s",
+		"a sixth up":       "eine Sexte aufwärts",
+		"notes %d-%d (%s)": "Noten %d-%d (%s)",
+	})
+
+	v := CheckRules([]int{5, -1}, []Rule{mustRule(t, "NoBeginWithFive")})
+	if len(v) != 1 || v[0].Message != "Noten 1-2: Die Melodie darf nicht mit einer Sexte aufwärts beginnen." {
+		t.Errorf("CheckRules() = %v, want a translated message", v)
+	}
+
+	intervals := []int{1, 1, 1, 5, -1}
+	notes := realize(t, "D4", "E4", "F4", "G4", "E5", "D5")
+	got := Explain(CheckRules(intervals, []Rule{mustRule(t, "PreparedLeaps")})[0], intervals, notes)
+	if want := "Der Sprung um eine Sexte aufwärts bei Noten 4-5 (G4 E5) wird nicht durch Gegenbewegung vorbereitet."; got != want {
+		t.Errorf("Explain() = %q, want %q", got, want)
+	}
+
+	// Messages missing from the catalog stay in English
+	v = CheckRules([]int{1, 1, -1, 1, -1, -1}, []Rule{mustRule(t, "ValidateClimax")})
+	if len(v) != 1 || v[0].Message != "The highest (and lowest) pitch must occur only once." {
+		t.Errorf("CheckRules() = %v, want the English description", v)
+	}
+}
@@ -0,0 +1,83 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseClimaxApproach parses the largest intervals into and out of the climax (see ClimaxApproach),
+// either a single size for both sides ("step" or "third") or comma-separated in=size and out=size
+// pairs, e.g. "in=step" or "in=step,out=third". Sizes are named "step" or from second to octave;
+// a side that is not given is unrestricted (0).
+func ParseClimaxApproach(s string) (approach, departure int, err error) {
+	if !strings.Contains(s, "=") {
+		size, err := parseIntervalSize(s)
+		if err != nil {
+			return 0, 0, err
+		}
+		return size, size, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		side, name, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return 0, 0, fmt.Errorf("invalid climax approach %q: want in=size or out=size, e.g. in=step", item)
+		}
+		size, err := parseIntervalSize(name)
+		if err != nil {
+			return 0, 0, err
+		}
+		switch strings.TrimSpace(side) {
+		case "in":
+			approach = size
+		case "out":
+			departure = size
+		default:
+			return 0, 0, fmt.Errorf("invalid climax approach %q: want in=size or out=size, e.g. in=step", item)
+		}
+	}
+	return approach, departure, nil
+}
+
+// ClimaxApproachRule returns a complete rule restricting the motion into and out of the climax
+// (see ClimaxApproach), e.g. ClimaxApproachRule(1, 2) to approach it by step and leave it by
+// a third at most.
+func ClimaxApproachRule(approach, departure int) Rule {
+	var parts []string
+	if approach > 0 {
+		parts = append(parts, "approached by "+sizeLimit(approach))
+	}
+	if departure > 0 {
+		parts = append(parts, "left by "+sizeLimit(departure))
+	}
+	description := "The climax may be approached and left by any interval."
+	if len(parts) > 0 {
+		description = fmt.Sprintf("The climax must be %s.", strings.Join(parts, " and "))
+	}
+	return Rule{
+		Name:        "ClimaxApproach",
+		Description: description,
+		Check:       ClimaxApproach(approach, departure),
+	}
+}
+
+// parseIntervalSize parses an interval size without direction, "step" or from second to octave.
+func parseIntervalSize(name string) (int, error) {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(name, "step") {
+		return 1, nil
+	}
+	for size := 1; size <= RangeOctave; size++ {
+		if strings.EqualFold(name, intervalName(size)) {
+			return size, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid interval size %q: want step, second, third, fourth, fifth, sixth, seventh or octave", name)
+}
+
+// sizeLimit describes intervals of at most size steps, e.g. "step" or "a third at most".
+func sizeLimit(size int) string {
+	if size == 1 {
+		return "step"
+	}
+	return withArticle(intervalName(size)) + " at most"
+}
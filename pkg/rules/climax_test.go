@@ -0,0 +1,60 @@
+package rules
+
+import "testing"
+
+func TestParseClimaxApproach(t *testing.T) {
+	tests := []struct {
+		input               string
+		approach, departure int
+		wantErr             bool
+	}{
+		{"step", 1, 1, false},
+		{"Third", 2, 2, false},
+		{"in=step", 1, 0, false},
+		{"out=third", 0, 2, false},
+		{"in=step, out=third", 1, 2, false},
+		{"in=second,out=fourth", 1, 3, false},
+		{"over=step", 0, 0, true},
+		{"in=ninth", 0, 0, true},
+		{"in", 0, 0, true},
+		{"", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			approach, departure, err := ParseClimaxApproach(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClimaxApproach(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && (approach != tt.approach || departure != tt.departure) {
+				t.Errorf("ParseClimaxApproach(%q) = %d, %d; want %d, %d", tt.input, approach, departure, tt.approach, tt.departure)
+			}
+		})
+	}
+}
+
+func TestClimaxApproachRule(t *testing.T) {
+	tests := []struct {
+		approach, departure int
+		want                string
+	}{
+		{1, 1, "The climax must be approached by step and left by step."},
+		{1, 2, "The climax must be approached by step and left by a third at most."},
+		{0, 3, "The climax must be left by a fourth at most."},
+		{0, 0, "The climax may be approached and left by any interval."},
+	}
+	for _, tt := range tests {
+		r := ClimaxApproachRule(tt.approach, tt.departure)
+		if r.Name != "ClimaxApproach" || r.Partial {
+			t.Errorf("unexpected rule %+v", r)
+		}
+		if r.Description != tt.want {
+			t.Errorf("ClimaxApproachRule(%d, %d).Description = %q, want %q", tt.approach, tt.departure, r.Description, tt.want)
+		}
+	}
+
+	r := ClimaxApproachRule(1, 0)
+	if !r.Check([]int{1, 2, 1, -1, -2, -1}) || r.Check([]int{1, 1, 3, -1, -2, -1, -1}) {
+		t.Error("Check must reject a climax approached by a leap only")
+	}
+}
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseClimaxHeight parses the bounds on the height of the climax above the final (see
+// ClimaxHeightRules), either a single interval fixing it ("sixth") or comma-separated
+// min=interval and max=interval pairs, e.g. "max=octave" or "min=fifth,max=sixth".
+// Intervals are named from second to tenth; a bound that is not given is unrestricted (0).
+func ParseClimaxHeight(s string) (low, high int, err error) {
+	if !strings.Contains(s, "=") {
+		height, err := parseHeight(s)
+		if err != nil {
+			return 0, 0, err
+		}
+		return height, height, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		bound, name, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return 0, 0, fmt.Errorf("invalid climax height %q: want min=interval or max=interval, e.g. max=octave", item)
+		}
+		height, err := parseHeight(name)
+		if err != nil {
+			return 0, 0, err
+		}
+		switch strings.TrimSpace(bound) {
+		case "min":
+			low = height
+		case "max":
+			high = height
+		default:
+			return 0, 0, fmt.Errorf("invalid climax height %q: want min=interval or max=interval, e.g. max=octave", item)
+		}
+	}
+	if high > 0 && low > high {
+		return 0, 0, fmt.Errorf("invalid climax height %q: the minimum exceeds the maximum", s)
+	}
+	return low, high, nil
+}
+
+// parseHeight parses the name of an interval from second to tenth into its size in steps.
+func parseHeight(name string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "ninth":
+		return 8, nil
+	case "tenth":
+		return RangeTenth, nil
+	}
+	size, err := parseIntervalSize(name)
+	if err != nil {
+		return 0, fmt.Errorf("invalid climax height %q: want an interval from second to tenth", name)
+	}
+	return size, nil
+}
+
+// ClimaxHeightRules returns the rules bounding the height of the climax above the final
+// between low and high steps (see MinClimaxHeight and MaxClimaxHeight), e.g.
+// ClimaxHeightRules(5, 5) for a climax exactly a sixth above the final: a partial rule
+// named "MaxClimaxHeight" if high is positive, and a complete rule named "MinClimaxHeight"
+// if low is. It returns no rule if both are 0.
+func ClimaxHeightRules(low, high int) []Rule {
+	return ClimaxHeightRulesFrom(low, high, 0)
+}
+
+// ClimaxHeightRulesFrom works like ClimaxHeightRules for melodies beginning opening steps
+// above the final (see RuleSet.SetOpening), whose climax is still measured from the final.
+func ClimaxHeightRulesFrom(low, high, opening int) []Rule {
+	var result []Rule
+	if high > 0 {
+		result = append(result, Rule{
+			Name:        "MaxClimaxHeight",
+			Description: fmt.Sprintf("The climax must not be higher than %s above the final.", withArticle(intervalName(high))),
+			Partial:     true,
+			Check:       MaxClimaxHeight(high - opening),
+			Incremental: IncrementalMaxClimaxHeight(high - opening),
+		})
+	}
+	if low > 0 {
+		result = append(result, Rule{
+			Name:        "MinClimaxHeight",
+			Description: fmt.Sprintf("The climax must be at least %s above the final.", withArticle(intervalName(low))),
+			Check:       MinClimaxHeight(low - opening),
+		})
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package rules
+
+import "testing"
+
+func TestParseClimaxHeight(t *testing.T) {
+	tests := []struct {
+		input     string
+		low, high int
+		wantErr   bool
+	}{
+		{"sixth", 5, 5, false},
+		{"Octave", 7, 7, false},
+		{"max=tenth", 0, 9, false},
+		{"min=fifth", 4, 0, false},
+		{"min=fifth, max=ninth", 4, 8, false},
+		{"min=sixth,max=fifth", 0, 0, true},
+		{"above=sixth", 0, 0, true},
+		{"max=eleventh", 0, 0, true},
+		{"min", 0, 0, true},
+		{"", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			low, high, err := ParseClimaxHeight(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClimaxHeight(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && (low != tt.low || high != tt.high) {
+				t.Errorf("ParseClimaxHeight(%q) = %d, %d; want %d, %d", tt.input, low, high, tt.low, tt.high)
+			}
+		})
+	}
+}
+
+func TestClimaxHeightRules(t *testing.T) {
+	if got := ClimaxHeightRules(0, 0); len(got) != 0 {
+		t.Errorf("ClimaxHeightRules(0, 0) = %v, want no rule", got)
+	}
+
+	exact := ClimaxHeightRules(5, 5)
+	if len(exact) != 2 || exact[0].Name != "MaxClimaxHeight" || !exact[0].Partial ||
+		exact[1].Name != "MinClimaxHeight" || exact[1].Partial {
+		t.Fatalf("unexpected rules %+v", exact)
+	}
+	if want := "The climax must not be higher than a sixth above the final."; exact[0].Description != want {
+		t.Errorf("Description = %q, want %q", exact[0].Description, want)
+	}
+
+	tests := []struct {
+		name      string
+		intervals []int
+		want      bool
+	}{
+		{"climax a sixth above", []int{1, 2, 2, -1, -1, -1, -1, -1}, true},
+		{"climax a fifth above", []int{1, 2, 1, -1, -1, -1, -1}, false},
+		{"climax a seventh above", []int{1, 2, 3, -1, -2, -1, -1, -1}, false},
+		{"lower climax only", []int{-1, -2, 1, 1, 1}, false},
+	}
+	for _, tt := range tests {
+		got := exact[0].Check(tt.intervals) && exact[1].Check(tt.intervals)
+		if got != tt.want {
+			t.Errorf("%s: %v satisfies the rules = %v, want %v", tt.name, tt.intervals, got, tt.want)
+		}
+	}
+
+	// The maximum prunes prefixes already too high
+	if exact[0].Check([]int{2, 2, 2}) {
+		t.Error("MaxClimaxHeight must reject a prefix rising a seventh")
+	}
+}
+
+func TestClimaxHeightRulesFrom(t *testing.T) {
+	// Opening on the fifth, the climax a sixth above the final is a step above the first note
+	exact := ClimaxHeightRulesFrom(5, 5, 4)
+	tests := []struct {
+		name      string
+		intervals []int
+		want      bool
+	}{
+		{"climax a sixth above the final", []int{1, -2, -1, -1, -1}, true},
+		{"climax a sixth above the first note", []int{1, 2, 2, -1, -1, -1, -1, -1, -1, -1, -1, -1}, false},
+		{"climax on the first note", []int{-1, -1, -1, -1}, false},
+	}
+	for _, tt := range tests {
+		got := exact[0].Check(tt.intervals) && exact[1].Check(tt.intervals)
+		if got != tt.want {
+			t.Errorf("%s: %v satisfies the rules = %v, want %v", tt.name, tt.intervals, got, tt.want)
+		}
+	}
+	if exact[0].Description != ClimaxHeightRules(5, 5)[0].Description {
+		t.Errorf("Description = %q, want the one of ClimaxHeightRules", exact[0].Description)
+	}
+}
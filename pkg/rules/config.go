@@ -0,0 +1,381 @@
+package rules
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReadConfig reads a rule set from a configuration file written in a subset of TOML,
+// as produced by WriteConfig:
+//
+//	preset = "salzer"            # strictness preset to start from (default "fux")
+//	range = "octave"             # see ParseRange; a number of steps is accepted too
+//	leaps = [-4, -3, -2, 2, 3, 4]
+//	cadence = "above"            # see ParseCadence
+//	repeated_notes = true        # see RuleSet.SetRepeatedNotes
+//	opening = "fifth"            # see ParseOpening (default "final")
+//	ending = "octave below"      # see ParseEnding (default "final")
+//
+//	[rules.NoSequences]
+//	enabled = false
+//
+//	[rules.ValidateClimax]
+//	weight = 2                   # soft with this weight; 0 makes the rule hard
+//
+//	[rules.NoCloseLargeLeaps]
+//	severity = "warning"         # see Severity; a warning is soft with WarningWeight unless weighted
+//
+// Every key is optional: the file only needs to list what differs from the preset.
+// Rule names must be in the rule set (see Names), and rules added with RuleSet.Add
+// must be added again after reading. Unknown keys are errors, so that typos do not
+// silently change the rule set.
+func ReadConfig(r io.Reader) (*RuleSet, error) {
+	tables, err := parseConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	root := tables[""]
+	for name := range tables {
+		if name != "" && name != "rules" && !strings.HasPrefix(name, "rules.") {
+			return nil, fmt.Errorf("config: unknown table [%s]", name)
+		}
+	}
+	if err := checkKeys("", root, "preset", "range", "leaps", "cadence", "repeated_notes", "opening", "ending"); err != nil {
+		return nil, err
+	}
+
+	preset := PresetFux
+	if v, ok := root["preset"]; ok {
+		if preset, ok = v.(string); !ok {
+			return nil, fmt.Errorf("config: preset must be a string, got %v", v)
+		}
+	}
+	s, err := Preset(preset)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	if v, ok := root["range"]; ok {
+		var limit int
+		switch v := v.(type) {
+		case int:
+			limit = v
+		case string:
+			if limit, err = ParseRange(v); err != nil {
+				return nil, fmt.Errorf("config: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("config: range must be a string or an integer, got %v", v)
+		}
+		if err := s.SetMaxRange(limit); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	}
+	if v, ok := root["leaps"]; ok {
+		leaps, err := intList("leaps", v)
+		if err != nil {
+			return nil, err
+		}
+		s.SetLeaps(leaps...)
+	}
+	if v, ok := root["cadence"]; ok {
+		name, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("config: cadence must be a string, got %v", v)
+		}
+		degrees, err := ParseCadence(name)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		if err := s.SetCadence(degrees...); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	}
+	if v, ok := root["repeated_notes"]; ok {
+		allow, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("config: repeated_notes must be true or false, got %v", v)
+		}
+		s.SetRepeatedNotes(allow)
+	}
+	if v, ok := root["opening"]; ok {
+		name, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("config: opening must be a string, got %v", v)
+		}
+		height, err := ParseOpening(name)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		if err := s.SetOpening(height); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	}
+	if v, ok := root["ending"]; ok {
+		name, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("config: ending must be a string, got %v", v)
+		}
+		height, err := ParseEnding(name)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		if err := s.SetEnding(height); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	}
+
+	if err := checkKeys("rules", tables["rules"]); err != nil {
+		return nil, err
+	}
+	for _, table := range slices.Sorted(maps.Keys(tables)) {
+		name, ok := strings.CutPrefix(table, "rules.")
+		if !ok {
+			continue
+		}
+		if err := applyRuleConfig(s, name, tables[table]); err != nil {
+			return nil, fmt.Errorf("config: [%s]: %w", table, err)
+		}
+	}
+	return s, nil
+}
+
+// applyRuleConfig applies the keys of a [rules.<name>] table to the rule set.
+func applyRuleConfig(s *RuleSet, name string, values map[string]any) error {
+	if !s.contains(name) {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, name)
+	}
+	if err := checkKeys("rules."+name, values, "enabled", "weight", "severity"); err != nil {
+		return err
+	}
+	if v, ok := values["enabled"]; ok {
+		enabled, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("enabled must be true or false, got %v", v)
+		}
+		if err := s.Enable(name); err != nil {
+			return err
+		}
+		if !enabled {
+			if err := s.Disable(name); err != nil {
+				return err
+			}
+		}
+	}
+	severity := SeverityError
+	if v, ok := values["severity"]; ok {
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("severity must be a string, got %v", v)
+		}
+		var err error
+		if severity, err = ParseSeverity(str); err != nil {
+			return err
+		}
+	}
+	v, ok := values["weight"]
+	if !ok {
+		if _, ok := values["severity"]; ok {
+			return s.SetSeverity(name, severity)
+		}
+		return nil
+	}
+	var weight float64
+	switch w := v.(type) {
+	case int:
+		weight = float64(w)
+	case float64:
+		weight = w
+	default:
+		return fmt.Errorf("weight must be a number, got %v", v)
+	}
+	if weight == 0 {
+		if severity == SeverityWarning {
+			return fmt.Errorf("a warning must have a positive weight, got %g", weight)
+		}
+		return s.SetHard(name)
+	}
+	if _, ok := values["severity"]; ok && severity == SeverityError {
+		return fmt.Errorf("an error cannot have a weight, got %g", weight)
+	}
+	return s.SetSoft(name, weight)
+}
+
+// WriteConfig writes the rule set as a configuration file that ReadConfig turns back
+// into an equivalent rule set, listing every rule in order.
+func WriteConfig(w io.Writer, s *RuleSet) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Cantus firmus rule set")
+	if name, ok := rangeNames[s.maxRange]; ok {
+		fmt.Fprintf(bw, "range = %q\n", name)
+	} else {
+		fmt.Fprintf(bw, "range = %d\n", s.maxRange)
+	}
+	leaps := make([]string, len(s.leaps))
+	for i, leap := range s.leaps {
+		leaps[i] = strconv.Itoa(leap)
+	}
+	fmt.Fprintf(bw, "leaps = [%s]\n", strings.Join(leaps, ", "))
+	for name, degrees := range cadenceNames {
+		if slices.Equal(slices.Sorted(slices.Values(s.cadence)), degrees) {
+			fmt.Fprintf(bw, "cadence = %q\n", name)
+		}
+	}
+	fmt.Fprintf(bw, "repeated_notes = %t\n", s.repeats)
+	if s.opening != 0 {
+		fmt.Fprintf(bw, "opening = %q\n", frameName(s.opening))
+	}
+	if s.ending != 0 {
+		fmt.Fprintf(bw, "ending = %q\n", frameName(s.ending))
+	}
+
+	for _, r := range s.rules {
+		fmt.Fprintf(bw, "\n[rules.%s]\n", r.Name)
+		fmt.Fprintf(bw, "enabled = %t\n", !s.disabled[r.Name])
+		if weight, ok := s.soft[r.Name]; ok {
+			fmt.Fprintf(bw, "weight = %s\n", strconv.FormatFloat(weight, 'g', -1, 64))
+		}
+	}
+	return bw.Flush()
+}
+
+// checkKeys reports keys of a table that are not among the allowed ones.
+func checkKeys(table string, values map[string]any, allowed ...string) error {
+	for key := range values {
+		if !slices.Contains(allowed, key) {
+			if table == "" {
+				return fmt.Errorf("config: unknown key %q", key)
+			}
+			return fmt.Errorf("config: unknown key %q in [%s]", key, table)
+		}
+	}
+	return nil
+}
+
+// intList converts an array value to integers.
+func intList(key string, v any) ([]int, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("config: %s must be an array, got %v", key, v)
+	}
+	result := make([]int, len(items))
+	for i, item := range items {
+		if result[i], ok = item.(int); !ok {
+			return nil, fmt.Errorf("config: %s must contain integers, got %v", key, item)
+		}
+	}
+	return result, nil
+}
+
+// parseConfig parses the TOML subset of configuration files into tables of values keyed
+// by table name, the top level being "". It supports comments, [table] headers with dotted
+// names, and key = value pairs whose value is a string, integer, float, boolean, or an
+// array of numbers on a single line.
+func parseConfig(r io.Reader) (map[string]map[string]any, error) {
+	tables := map[string]map[string]any{"": {}}
+	table := ""
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" {
+			continue
+		}
+		if header, ok := strings.CutPrefix(text, "["); ok {
+			header, ok = strings.CutSuffix(header, "]")
+			header = strings.TrimSpace(header)
+			if !ok || header == "" {
+				return nil, fmt.Errorf("config line %d: invalid table header %q", line, text)
+			}
+			if _, ok := tables[header]; ok {
+				return nil, fmt.Errorf("config line %d: table [%s] defined twice", line, header)
+			}
+			table = header
+			tables[table] = map[string]any{}
+			continue
+		}
+
+		key, raw, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("config line %d: want key = value, got %q", line, text)
+		}
+		if _, ok := tables[table][key]; ok {
+			return nil, fmt.Errorf("config line %d: key %q defined twice", line, key)
+		}
+		value, err := parseValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("config line %d: %w", line, err)
+		}
+		tables[table][key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return tables, nil
+}
+
+// parseValue parses a string, integer, float, boolean or single-line array value.
+func parseValue(s string) (any, error) {
+	switch {
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case strings.HasPrefix(s, `"`):
+		value, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", s)
+		}
+		return value, nil
+	case strings.HasPrefix(s, "["):
+		inner, ok := strings.CutSuffix(s, "]")
+		if !ok {
+			return nil, fmt.Errorf("invalid array %s", s)
+		}
+		inner = strings.TrimSpace(strings.TrimPrefix(inner, "["))
+		items := []any{}
+		if inner == "" {
+			return items, nil
+		}
+		for _, item := range strings.Split(strings.TrimSuffix(inner, ","), ",") {
+			value, err := parseValue(strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	}
+	if i, err := strconv.Atoi(strings.TrimPrefix(s, "+")); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("invalid value %q", s)
+}
+
+// stripComment removes a # comment outside of strings from the line.
+func stripComment(line string) string {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case '#':
+			if !quoted {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
@@ -0,0 +1,188 @@
+package rules
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteConfig_RoundTrip(t *testing.T) {
+	s, err := Preset(PresetSalzer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetMaxRange(12); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCadence(CadenceSupertonic); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Disable("NoSequences"); err != nil {
+		t.Fatal(err)
+	}
+	s.SetRepeatedNotes(true)
+	if err := s.SetOpening(-3); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetSoft("ValidateClimax", 2.5); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteConfig(&buf, s); err != nil {
+		t.Fatalf("WriteConfig returned %v", err)
+	}
+	read, err := ReadConfig(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("ReadConfig returned %v for\n%s", err, buf.String())
+	}
+
+	if !slices.Equal(read.Names(), s.Names()) {
+		t.Errorf("Names() = %v, want %v", read.Names(), s.Names())
+	}
+	for _, name := range s.Names() {
+		if read.Enabled(name) != s.Enabled(name) || read.Weight(name) != s.Weight(name) {
+			t.Errorf("rule %s: enabled %v weight %g, want enabled %v weight %g",
+				name, read.Enabled(name), read.Weight(name), s.Enabled(name), s.Weight(name))
+		}
+	}
+	if !read.RepeatedNotes() {
+		t.Error("repeated notes must be allowed")
+	}
+	if read.Opening() != -3 {
+		t.Errorf("read opening %d, want -3", read.Opening())
+	}
+	if read.MaxRange() != 12 || !slices.Equal(read.Leaps(), s.Leaps()) || !slices.Equal(read.Cadence(), []int{CadenceSupertonic}) {
+		t.Errorf("read range %d, leaps %v, cadence %v; want 12, %v, [2]", read.MaxRange(), read.Leaps(), read.Cadence(), s.Leaps())
+	}
+
+	var again bytes.Buffer
+	if err := WriteConfig(&again, read); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != buf.String() {
+		t.Errorf("writing the read rule set gave\n%s\nwant\n%s", again.String(), buf.String())
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	config := `# Narrow lines, relaxed climax
+preset = "salzer"
+range = "tenth"     # wider than the preset
+leaps = [-3, -2, 2, 3]
+
+[rules]
+
+[rules.NoSequences]
+enabled = false
+
+[rules.ValidateClimax]
+weight = 2
+
+[rules.NoCloseLargeLeaps]
+severity = "warning"
+
+[rules.PreparedLeaps]
+severity = "warning"
+weight = 3
+`
+	s, err := ReadConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("ReadConfig returned %v", err)
+	}
+	if s.MaxRange() != RangeTenth || !slices.Equal(s.Leaps(), []int{-3, -2, 2, 3}) {
+		t.Errorf("range %d, leaps %v; want %d, [-3 -2 2 3]", s.MaxRange(), s.Leaps(), RangeTenth)
+	}
+	if s.Enabled("NoSequences") || s.Weight("ValidateClimax") != 2 || !s.Enabled("PreparedLeaps") {
+		t.Error("rule tables not applied")
+	}
+	if s.Severity("NoCloseLargeLeaps") != SeverityWarning || s.Weight("NoCloseLargeLeaps") != WarningWeight || s.Weight("PreparedLeaps") != 3 {
+		t.Error("severities not applied")
+	}
+	if !slices.Equal(s.Cadence(), DefaultRuleSet().Cadence()) {
+		t.Errorf("Cadence() = %v, want the default", s.Cadence())
+	}
+
+	empty, err := ReadConfig(strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("ReadConfig of an empty file returned %v", err)
+	}
+	if !slices.Equal(empty.Names(), DefaultRuleSet().Names()) {
+		t.Error("an empty file must give the default rule set")
+	}
+}
+
+func TestReadConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   error
+	}{
+		{"unknown rule", "[rules.NoSuchRule]\nenabled = false", ErrUnknownRule},
+		{"unknown preset", `preset = "palestrina"`, ErrUnknownPreset},
+		{"unknown key", "ranges = 9", nil},
+		{"unknown rule key", "[rules.NoSequences]\nenable = false", nil},
+		{"unknown table", "[rule.NoSequences]", nil},
+		{"wrong type", "[rules.NoSequences]\nenabled = 0", nil},
+		{"negative weight", "[rules.NoSequences]\nweight = -1", nil},
+		{"invalid severity", "[rules.NoSequences]\nseverity = \"fatal\"", nil},
+		{"weighted error", "[rules.NoSequences]\nseverity = \"error\"\nweight = 2", nil},
+		{"unweighted warning", "[rules.NoSequences]\nseverity = \"warning\"\nweight = 0", nil},
+		{"invalid range", `range = "eleventh"`, nil},
+		{"invalid cadence", `cadence = "sideways"`, nil},
+		{"leaps not integers", "leaps = [2, 3.5]", nil},
+		{"repeated notes not boolean", `repeated_notes = "yes"`, nil},
+		{"duplicate key", "range = 9\nrange = 7", nil},
+		{"duplicate table", "[rules.NoSequences]\n[rules.NoSequences]", nil},
+		{"missing value", "range", nil},
+		{"unterminated string", `preset = "fux`, nil},
+		{"unterminated header", "[rules.NoSequences", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadConfig(strings.NewReader(tt.config))
+			if err == nil {
+				t.Fatalf("ReadConfig(%q) must fail", tt.config)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("ReadConfig(%q) = %v, want %v", tt.config, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want any
+	}{
+		{"true", true},
+		{"false", false},
+		{"42", 42},
+		{"-3", -3},
+		{"+3", 3},
+		{"2.5", 2.5},
+		{`"a # b"`, "a # b"},
+		{"[]", []any{}},
+		{"[1, -2, 3,]", []any{1, -2, 3}},
+	}
+	for _, tt := range tests {
+		got, err := parseValue(tt.in)
+		if err != nil {
+			t.Errorf("parseValue(%q) returned %v", tt.in, err)
+			continue
+		}
+		if a, ok := got.([]any); ok {
+			if !slices.Equal(a, tt.want.([]any)) {
+				t.Errorf("parseValue(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		} else if got != tt.want {
+			t.Errorf("parseValue(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if got := stripComment(`name = "a # b" # comment`); got != `name = "a # b" ` {
+		t.Errorf("stripComment kept %q", got)
+	}
+}
@@ -0,0 +1,59 @@
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ParseConsecutiveLimits parses limits on runs of intervals of the same size (see
+// MaxConsecutiveIntervals), written as comma-separated size=count pairs naming the
+// interval without direction, e.g. "third=2,fourth=1". Sizes are named "step" or from second to octave.
+func ParseConsecutiveLimits(s string) (map[int]int, error) {
+	limits := make(map[int]int)
+	for _, item := range strings.Split(s, ",") {
+		name, count, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid interval limit %q: want size=count, e.g. third=2", item)
+		}
+		size, err := parseIntervalSize(name)
+		if err != nil {
+			return nil, err
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid limit of consecutive %ss %q: want a positive number", name, count)
+		}
+		limits[size] = limit
+	}
+	return limits, nil
+}
+
+// ConsecutiveIntervalsRule returns a partial rule limiting runs of intervals of the same size
+// (see MaxConsecutiveIntervals). It generalizes NoMoreThanTwoConsecutiveThirds to any sizes.
+func ConsecutiveIntervalsRule(limits map[int]int) Rule {
+	var parts []string
+	window := 1
+	for _, size := range slices.Sorted(maps.Keys(limits)) {
+		parts = append(parts, fmt.Sprintf("%d %s", limits[size], plural(intervalName(size), limits[size])))
+		window = max(window, limits[size]+1)
+	}
+	return Rule{
+		Name:        "MaxConsecutiveIntervals",
+		Description: fmt.Sprintf("At most %s in a row.", strings.Join(parts, ", ")),
+		Partial:     true,
+		Local:       true,
+		Check:       MaxConsecutiveIntervals(limits),
+		Incremental: Windowed(MaxConsecutiveIntervals(limits), window),
+	}
+}
+
+// plural returns the name of count intervals, e.g. "third" or "thirds".
+func plural(name string, count int) string {
+	if count == 1 {
+		return name
+	}
+	return name + "s"
+}
@@ -0,0 +1,56 @@
+package rules
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseConsecutiveLimits(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    map[int]int
+		wantErr bool
+	}{
+		{"third=2", map[int]int{2: 2}, false},
+		{"second=4, Fourth=1,octave=1", map[int]int{1: 4, 3: 1, 7: 1}, false},
+		{"third", nil, true},
+		{"third=0", nil, true},
+		{"third=two", nil, true},
+		{"ninth=1", nil, true},
+		{"unison=2", nil, true},
+		{"", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseConsecutiveLimits(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConsecutiveLimits(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("ParseConsecutiveLimits(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsecutiveIntervalsRule(t *testing.T) {
+	r := ConsecutiveIntervalsRule(map[int]int{3: 1, 2: 2})
+	if r.Name != "MaxConsecutiveIntervals" || !r.Partial || !r.Local {
+		t.Errorf("unexpected rule %+v", r)
+	}
+	if want := "At most 2 thirds, 1 fourth in a row."; r.Description != want {
+		t.Errorf("Description = %q, want %q", r.Description, want)
+	}
+
+	// The thirds limit of the registered rule, as a generalized rule
+	thirds := ConsecutiveIntervalsRule(map[int]int{2: 2})
+	for _, intervals := range [][]int{{2, 2, 2}, {2, -2, 1, 2, 2}, {-1, 2, -2, -2, 1}} {
+		if thirds.Check(intervals) != NoMoreThanTwoConsecutiveThirds(intervals) {
+			t.Errorf("rule disagrees with NoMoreThanTwoConsecutiveThirds on %v", intervals)
+		}
+	}
+	if v := CheckRules([]int{1, 3, -3, -1}, []Rule{r}); len(v) != 1 || v[0].Start != 1 || v[0].End != 2 {
+		t.Errorf("CheckRules() = %v, want a violation over intervals 1-2", v)
+	}
+}
@@ -0,0 +1,16 @@
+package rules
+
+import "fmt"
+
+// DirectionChangesRule returns a partial rule allowing the melody to change direction at most
+// limit times (see MaxDirectionChanges), to filter out nervous, zigzagging contours.
+func DirectionChangesRule(limit int) Rule {
+	return Rule{
+		Name:        "MaxDirectionChanges",
+		Description: fmt.Sprintf("The melody must change direction at most %d times.", limit),
+		Partial:     true,
+		Local:       true,
+		Check:       MaxDirectionChanges(limit),
+		Incremental: IncrementalMaxDirectionChanges(limit),
+	}
+}
@@ -0,0 +1,19 @@
+package rules
+
+import "testing"
+
+func TestDirectionChangesRule(t *testing.T) {
+	r := DirectionChangesRule(3)
+	if r.Name != "MaxDirectionChanges" || !r.Partial || !r.Local {
+		t.Errorf("unexpected rule %+v", r)
+	}
+	if want := "The melody must change direction at most 3 times."; r.Description != want {
+		t.Errorf("Description = %q, want %q", r.Description, want)
+	}
+
+	// The fourth change, at the last interval, spans the intervals from the one before the first change
+	intervals := []int{1, 1, -1, 1, 1, -1, 1}
+	if v := CheckRules(intervals, []Rule{r}); len(v) != 1 || v[0].Start != 1 || v[0].End != 6 {
+		t.Errorf("CheckRules(%v) = %v, want a violation over intervals 1-6", intervals, v)
+	}
+}
@@ -0,0 +1,161 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+// Explain turns a violation of the interval sequence (see CheckRules) into a sentence
+// pointing at the intervals that break the rule, e.g. "The leap of a sixth up at notes 4-5
+// (D4 B4) is not prepared by motion in the opposite direction."
+//
+// notes, if not nil, is the melody realized as pitches (one note more than intervals)
+// and is used to name the notes; otherwise they are referred to by position only.
+// Rules without a specific explanation are described by the violation's Message.
+// Sentences are translated with the catalog in use (see SetCatalog).
+func Explain(v Violation, intervals []int, notes music.Realization) string {
+	e := explainer{intervals: intervals, notes: notes}
+	if len(notes) != len(intervals)+1 {
+		e.notes = nil
+	}
+	if explain, ok := explanations[v.Rule]; ok && v.Start >= 0 && v.Start <= v.End && v.End < len(intervals) {
+		if sentence := explain(e, v); sentence != "" {
+			return sentence
+		}
+	}
+
+	prefix := fmt.Sprintf(tr(locationFormat), v.Start+1, v.End+2, "")
+	if !strings.HasPrefix(v.Message, prefix) {
+		return v.Message
+	}
+	return capitalize(e.span(v.Start, v.End+1)) + ": " + strings.TrimPrefix(v.Message, prefix)
+}
+
+// explanations describe violations of the registered rules; an empty result falls back
+// to the violation's Message.
+var explanations = map[string]func(e explainer, v Violation) string{
+	"NoBeginWithFive": func(e explainer, v Violation) string {
+		return fmt.Sprintf(tr("The melody begins with a %s."), e.leap(0))
+	},
+	"NoExcessiveNoteRepetition": func(e explainer, v Violation) string {
+		return fmt.Sprintf(tr("%s is the fourth occurrence of the same pitch; no pitch may occur more than three times."),
+			capitalize(e.note(v.End+1)))
+	},
+	"LimitDirectionalMotion": func(e explainer, v Violation) string {
+		direction := "up"
+		if e.intervals[v.End] < 0 {
+			direction = "down"
+		}
+		if count := v.End - v.Start + 1; count >= 5 {
+			return fmt.Sprintf(tr("%s move %s %d times in a row; at most four intervals may move in one direction."),
+				capitalize(e.span(v.Start, v.End+1)), tr(direction), count)
+		}
+		span := 0
+		for _, interval := range e.intervals[v.Start : v.End+1] {
+			span += interval
+		}
+		return fmt.Sprintf(tr("%s move %s by %s in one direction; at most a sixth is allowed."),
+			capitalize(e.span(v.Start, v.End+1)), tr(direction), tr(withArticle(intervalName(utils.Abs(span)))))
+	},
+	"PreparedLeaps": func(e explainer, v Violation) string {
+		return fmt.Sprintf(tr("The %s is not prepared by motion in the opposite direction."), e.leap(v.End))
+	},
+	"ValidateLeapResolution": func(e explainer, v Violation) string {
+		for i := v.Start; i < v.End; i++ {
+			if utils.Abs(e.intervals[i]) > 2 {
+				return fmt.Sprintf(tr("The %s is not resolved by motion in the opposite direction."), e.leap(i))
+			}
+		}
+		return ""
+	},
+	"NoNoteRepetitionAfterLeap": func(e explainer, v Violation) string {
+		if v.End < 1 {
+			return ""
+		}
+		return fmt.Sprintf(tr("The leaps of %s and %s at %s return to the same pitch."),
+			e.size(v.End-1), e.size(v.End), e.span(v.End-1, v.End+1))
+	},
+	"NoCloseLargeLeaps": func(e explainer, v Violation) string {
+		if v.End < 2 {
+			return ""
+		}
+		return fmt.Sprintf(tr("The leaps of %s and %s at %s are separated by a single interval."),
+			e.size(v.End-2), e.size(v.End), e.span(v.End-2, v.End+1))
+	},
+	"NoDissonantLeapPair": func(e explainer, v Violation) string {
+		if v.End < 1 {
+			return ""
+		}
+		outline := music.Interval(e.intervals[v.End-1] + e.intervals[v.End])
+		return fmt.Sprintf(tr("The leaps of %s and %s at %s outline %s."),
+			e.size(v.End-1), e.size(v.End), e.span(v.End-1, v.End+1), tr(withArticle(outline.String())))
+	},
+	"NoMoreThanTwoConsecutiveThirds": func(e explainer, v Violation) string {
+		return fmt.Sprintf(tr("%s move by %d thirds in a row; at most two are allowed."),
+			capitalize(e.span(v.Start, v.End+1)), v.End-v.Start+1)
+	},
+	"Cadence": func(e explainer, v Violation) string {
+		last := len(e.intervals) - 1
+		return fmt.Sprintf(tr("The final is approached by %s at %s. %s"),
+			e.size(last), e.span(last, last+1), v.Message)
+	},
+}
+
+// explainer names the notes and intervals of a melody for Explain.
+type explainer struct {
+	intervals []int
+	notes     music.Realization
+}
+
+// note refers to the note with the given index, e.g. "note 4 (D4)".
+func (e explainer) note(i int) string {
+	if e.notes == nil {
+		return fmt.Sprintf(tr("note %d"), i+1)
+	}
+	return fmt.Sprintf(tr("note %d (%s)"), i+1, e.notes[i])
+}
+
+// span refers to the notes from index first to last, e.g. "notes 4-6 (D4 F4 E4)".
+func (e explainer) span(first, last int) string {
+	if e.notes == nil {
+		return fmt.Sprintf(tr("notes %d-%d"), first+1, last+1)
+	}
+	return fmt.Sprintf(tr("notes %d-%d (%s)"), first+1, last+1, e.notes[first:last+1])
+}
+
+// size names the interval with the given index with its article, e.g. "a sixth up".
+func (e explainer) size(i int) string {
+	return tr(withArticle(music.Interval(e.intervals[i]).String()))
+}
+
+// leap describes the interval with the given index and where it lies,
+// e.g. "leap of a sixth up at notes 4-5 (D4 B4)".
+func (e explainer) leap(i int) string {
+	return fmt.Sprintf(tr("leap of %s at %s"), e.size(i), e.span(i, i+1))
+}
+
+// intervalName returns the name of a diatonic interval of the given number of steps, without direction.
+func intervalName(steps int) string {
+	name := music.Interval(steps).String()
+	return strings.TrimSuffix(name, " up")
+}
+
+// withArticle prefixes an interval name with "a" or "an".
+func withArticle(name string) string {
+	if strings.HasPrefix(name, "octave") || strings.HasPrefix(name, "8") || strings.HasPrefix(name, "11") ||
+		strings.HasPrefix(name, "18") {
+		return "an " + name
+	}
+	return "a " + name
+}
+
+// capitalize returns s with its first letter in upper case.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,101 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		intervals []int
+		withNotes bool
+		want      string
+	}{
+		{
+			name:      "unprepared leap with note names",
+			rule:      "PreparedLeaps",
+			intervals: []int{1, 1, 1, 5, -1},
+			withNotes: true,
+			want:      "The leap of a sixth up at notes 4-5 (G4 E5) is not prepared by motion in the opposite direction.",
+		},
+		{
+			name:      "unprepared leap by position",
+			rule:      "PreparedLeaps",
+			intervals: []int{1, 1, 1, 5, -1},
+			want:      "The leap of a sixth up at notes 4-5 is not prepared by motion in the opposite direction.",
+		},
+		{
+			name:      "opening leap",
+			rule:      "NoBeginWithFive",
+			intervals: []int{5, -1, -1},
+			withNotes: true,
+			want:      "The melody begins with a leap of a sixth up at notes 1-2 (D4 B4).",
+		},
+		{
+			name:      "too many intervals in one direction",
+			rule:      "LimitDirectionalMotion",
+			intervals: []int{-1, 1, 1, 1, 1, 1, -1},
+			want:      "Notes 2-7 move up 5 times in a row; at most four intervals may move in one direction.",
+		},
+		{
+			name:      "unresolved leap",
+			rule:      "ValidateLeapResolution",
+			intervals: []int{-1, 3, 1},
+			withNotes: true,
+			want:      "The leap of a fourth up at notes 2-3 (C4 F4) is not resolved by motion in the opposite direction.",
+		},
+		{
+			name:      "leaps outlining a seventh",
+			rule:      "NoDissonantLeapPair",
+			intervals: []int{-1, 4, 2},
+			withNotes: true,
+			want:      "The leaps of a fifth up and a third up at notes 2-4 (C4 G4 B4) outline a seventh up.",
+		},
+		{
+			name:      "pitch repeated too often",
+			rule:      "NoExcessiveNoteRepetition",
+			intervals: []int{1, -1, 1, -1, 1, -1},
+			withNotes: true,
+			want:      "Note 7 (D4) is the fourth occurrence of the same pitch; no pitch may occur more than three times.",
+		},
+		{
+			name:      "rule without a specific explanation",
+			rule:      "NoRangeExceedsDecima",
+			intervals: []int{5, 5},
+			withNotes: true,
+			want:      "Notes 1-3 (D4 B4 G5): The range must not exceed a tenth.",
+		},
+		{
+			name:      "complete rule without a specific explanation",
+			rule:      "MinDirectionChanges",
+			intervals: []int{1, 1, -1, -1},
+			withNotes: true,
+			want:      "The melody must change direction at least twice.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := LookupByName(tt.rule)
+			violations := CheckRules(tt.intervals, []Rule{r})
+			if len(violations) != 1 {
+				t.Fatalf("CheckRules(%v) = %v, want one violation of %s", tt.intervals, violations, tt.rule)
+			}
+
+			var notes music.Realization
+			if tt.withNotes {
+				cf := make(music.CantusFirmus, len(tt.intervals))
+				for i, interval := range tt.intervals {
+					cf[i] = music.Interval(interval)
+				}
+				notes, _ = cf.Realize("Dorian")
+			}
+			if got := Explain(violations[0], tt.intervals, notes); got != tt.want {
+				t.Errorf("Explain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Fingerprint returns a short hash identifying the rule set: its range, leaps, cadence, opening,
+// ending and repeated notes, and the name, state, weight and description of every rule in order, so that
+// rule sets differing in any constraint, including the parameters of added rules, have different
+// fingerprints. It is meant to be saved with generated melodies to trace them back to the
+// constraints that produced them.
+func (s *RuleSet) Fingerprint() string {
+	var b strings.Builder
+	WriteConfig(&b, s)
+	for _, r := range s.rules {
+		fmt.Fprintf(&b, "%s: %s\n", r.Name, r.Description)
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:6])
+}
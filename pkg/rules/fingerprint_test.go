@@ -0,0 +1,48 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+func TestRuleSet_Fingerprint(t *testing.T) {
+	base := DefaultRuleSet().Fingerprint()
+	if len(base) != 12 {
+		t.Errorf("Fingerprint() = %q, want 12 hex digits", base)
+	}
+	if got := DefaultRuleSet().Fingerprint(); got != base {
+		t.Errorf("Fingerprint() = %q and %q for equal rule sets", base, got)
+	}
+	if got := DefaultRuleSet().Clone().Fingerprint(); got != base {
+		t.Errorf("the clone has fingerprint %q, want %q", got, base)
+	}
+
+	variants := map[string]func(s *RuleSet){
+		"disabled rule":  func(s *RuleSet) { s.Disable("NoSequences") },
+		"soft rule":      func(s *RuleSet) { s.SetSoft("ValidateClimax", 2) },
+		"range":          func(s *RuleSet) { s.SetMaxRange(RangeOctave) },
+		"leaps":          func(s *RuleSet) { s.SetLeaps(2, -2) },
+		"repeated notes": func(s *RuleSet) { s.SetRepeatedNotes(true) },
+		"mode":           func(s *RuleSet) { s.SetMode(music.Phrygian) },
+		"added rule":     func(s *RuleSet) { s.Add(DirectionChangesRule(5)) },
+	}
+	seen := map[string]string{base: "default"}
+	for name, change := range variants {
+		s := DefaultRuleSet()
+		change(s)
+		got := s.Fingerprint()
+		if other, ok := seen[got]; ok {
+			t.Errorf("%s: fingerprint %q equals the one of %s", name, got, other)
+		}
+		seen[got] = name
+	}
+
+	// Parameters of added rules count
+	a, b := DefaultRuleSet(), DefaultRuleSet()
+	a.Add(DirectionChangesRule(5))
+	b.Add(DirectionChangesRule(6))
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Error("rule sets differing in a rule parameter must have different fingerprints")
+	}
+}
@@ -0,0 +1,494 @@
+package rules
+
+import (
+	"encoding/binary"
+	"slices"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+)
+
+// IncrementalRule checks a partial rule while a melody is built one interval at a time.
+// It keeps state across the steps of a backtracking search, so that extending a melody
+// does not re-scan the whole prefix as Rule.Check does.
+//
+// Push is only meaningful while every shorter prefix satisfied the rule, which is always
+// the case during generation: a prefix breaking a partial rule is never extended.
+type IncrementalRule interface {
+	// Push appends an interval to the melody and reports whether the extended melody
+	// satisfies the rule, provided the melody before it did.
+	Push(interval int) bool
+	// Pop removes the interval appended last. Every Push must be undone by a Pop,
+	// whatever it returned.
+	Pop()
+}
+
+// Summarizer is implemented by incremental checkers whose verdicts depend on a bounded
+// summary of the melody rather than on all of it: two melodies of the same length with
+// equal summaries satisfy the rule with exactly the same continuations. Searches that only
+// count melodies merge the beginnings with equal summaries instead of extending each of them.
+type Summarizer interface {
+	// AppendSummary appends the summary of the melody so far to b and returns the extended slice.
+	AppendSummary(b []byte) []byte
+}
+
+// appendInts appends the values to b as varints.
+func appendInts(b []byte, values ...int) []byte {
+	for _, v := range values {
+		b = binary.AppendVarint(b, int64(v))
+	}
+	return b
+}
+
+// NewIncremental returns a fresh incremental checker for the rule: the rule's own
+// (see Rule.Incremental) or, when it has none, one re-running Check on the whole prefix.
+func NewIncremental(r Rule) IncrementalRule {
+	if r.Incremental != nil {
+		return r.Incremental()
+	}
+	return &prefixRule{check: r.Check}
+}
+
+// prefixRule adapts a validation function by checking the whole prefix on every Push.
+type prefixRule struct {
+	check     ValidationFunc
+	intervals []int
+}
+
+func (p *prefixRule) Push(interval int) bool {
+	p.intervals = append(p.intervals, interval)
+	return p.check(p.intervals)
+}
+
+func (p *prefixRule) Pop() {
+	p.intervals = p.intervals[:len(p.intervals)-1]
+}
+
+// Windowed returns incremental checkers that run check on the last size intervals only.
+// It is equivalent to check for Local rules whose violations always lie within size
+// consecutive intervals, e.g. Windowed(NoCloseLargeLeaps, 3).
+func Windowed(check ValidationFunc, size int) func() IncrementalRule {
+	return func() IncrementalRule {
+		return &windowRule{prefixRule{check: check}, size}
+	}
+}
+
+// windowRule is a prefixRule looking only at the last size intervals.
+type windowRule struct {
+	prefixRule
+	size int
+}
+
+func (w *windowRule) Push(interval int) bool {
+	w.intervals = append(w.intervals, interval)
+	return w.check(w.intervals[max(0, len(w.intervals)-w.size):])
+}
+
+// AppendSummary appends the last size-1 intervals, the only ones a new interval is checked with.
+func (w *windowRule) AppendSummary(b []byte) []byte {
+	return appendInts(b, w.intervals[max(0, len(w.intervals)-w.size+1):]...)
+}
+
+// heights tracks the height of every note of the melody relative to the first one.
+type heights []int
+
+func (h *heights) push(interval int) int {
+	height := interval
+	if len(*h) > 0 {
+		height += (*h)[len(*h)-1]
+	}
+	*h = append(*h, height)
+	return height
+}
+
+// last returns the height of the last note, 0 before the first interval.
+func (h heights) last() int {
+	if len(h) == 0 {
+		return 0
+	}
+	return h[len(h)-1]
+}
+
+func (h *heights) pop() int {
+	height := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return height
+}
+
+// IncrementalWithinAmbitus returns incremental checkers equivalent to WithinAmbitus(low, high).
+func IncrementalWithinAmbitus(low, high int) func() IncrementalRule {
+	return func() IncrementalRule {
+		return &ambitusTracker{low: low, high: high}
+	}
+}
+
+// ambitusTracker checks the height of every new note.
+type ambitusTracker struct {
+	low, high int
+	heights   heights
+}
+
+func (a *ambitusTracker) Push(interval int) bool {
+	height := a.heights.push(interval)
+	return height >= a.low && height <= a.high
+}
+
+func (a *ambitusTracker) Pop() {
+	a.heights.pop()
+}
+
+// AppendSummary appends the height of the last note.
+func (a *ambitusTracker) AppendSummary(b []byte) []byte {
+	return appendInts(b, a.heights.last())
+}
+
+// IncrementalMaxRange returns incremental checkers equivalent to MaxRange(limit).
+func IncrementalMaxRange(limit int) func() IncrementalRule {
+	return func() IncrementalRule {
+		return &rangeTracker{limit: limit, low: []int{0}, high: []int{0}}
+	}
+}
+
+// rangeTracker keeps the lowest and highest notes of every prefix.
+type rangeTracker struct {
+	limit     int
+	heights   heights
+	low, high []int
+}
+
+func (r *rangeTracker) Push(interval int) bool {
+	height := r.heights.push(interval)
+	low := min(r.low[len(r.low)-1], height)
+	high := max(r.high[len(r.high)-1], height)
+	r.low = append(r.low, low)
+	r.high = append(r.high, high)
+	return high-low <= r.limit
+}
+
+func (r *rangeTracker) Pop() {
+	r.heights.pop()
+	r.low = r.low[:len(r.low)-1]
+	r.high = r.high[:len(r.high)-1]
+}
+
+// AppendSummary appends the lowest and highest notes relative to the last one.
+func (r *rangeTracker) AppendSummary(b []byte) []byte {
+	last := r.heights.last()
+	return appendInts(b, r.low[len(r.low)-1]-last, r.high[len(r.high)-1]-last)
+}
+
+// IncrementalMaxLeaps returns incremental checkers equivalent to MaxLeaps(max).
+func IncrementalMaxLeaps(max int) func() IncrementalRule {
+	return func() IncrementalRule {
+		return &leapCounter{max: max}
+	}
+}
+
+// leapCounter counts the leaps of the melody.
+type leapCounter struct {
+	max   int
+	leaps []bool
+	count int
+}
+
+func (c *leapCounter) Push(interval int) bool {
+	leap := utils.Abs(interval) > 1
+	c.leaps = append(c.leaps, leap)
+	if leap {
+		c.count++
+	}
+	return c.count <= c.max
+}
+
+func (c *leapCounter) Pop() {
+	if c.leaps[len(c.leaps)-1] {
+		c.count--
+	}
+	c.leaps = c.leaps[:len(c.leaps)-1]
+}
+
+// AppendSummary appends the number of leaps.
+func (c *leapCounter) AppendSummary(b []byte) []byte {
+	return appendInts(b, c.count)
+}
+
+// IncrementalRestrictToDegrees returns incremental checkers equivalent to RestrictToDegrees(degrees).
+// Like RestrictToDegrees, it cannot reject the first note: a melody whose tonic is not
+// among the degrees must be rejected before the search starts.
+func IncrementalRestrictToDegrees(degrees []int) func() IncrementalRule {
+	degrees = slices.Clone(degrees)
+	return func() IncrementalRule {
+		return &degreeTracker{degrees: degrees}
+	}
+}
+
+// degreeTracker checks the degree of every new note.
+type degreeTracker struct {
+	degrees []int
+	heights heights
+}
+
+func (d *degreeTracker) Push(interval int) bool {
+	return slices.Contains(d.degrees, degreeOf(d.heights.push(interval)))
+}
+
+func (d *degreeTracker) Pop() {
+	d.heights.pop()
+}
+
+// AppendSummary appends the degree of the last note.
+func (d *degreeTracker) AppendSummary(b []byte) []byte {
+	return appendInts(b, degreeOf(d.heights.last()))
+}
+
+// newNoteCounter returns an incremental checker equivalent to NoExcessiveNoteRepetition.
+func newNoteCounter() IncrementalRule {
+	return &noteCounter{counts: map[int]int{0: 1}}
+}
+
+// noteCounter counts how often every pitch occurs in the melody.
+type noteCounter struct {
+	heights heights
+	counts  map[int]int
+}
+
+func (c *noteCounter) Push(interval int) bool {
+	height := c.heights.push(interval)
+	c.counts[height]++
+	return c.counts[height] <= 3
+}
+
+func (c *noteCounter) Pop() {
+	c.counts[c.heights.pop()]--
+}
+
+// AppendSummary appends the lowest note relative to the last one and the number of
+// occurrences of every pitch from the lowest note to the highest.
+func (c *noteCounter) AppendSummary(b []byte) []byte {
+	low, high := 0, 0
+	for _, height := range c.heights {
+		low, high = min(low, height), max(high, height)
+	}
+	b = appendInts(b, low-c.heights.last())
+	for height := low; height <= high; height++ {
+		b = append(b, byte(c.counts[height]))
+	}
+	return b
+}
+
+// newSequenceDetector returns an incremental checker equivalent to NoSequences.
+func newSequenceDetector() IncrementalRule {
+	return &sequenceDetector{}
+}
+
+// sequenceDetector looks for sequences ending with the last interval: the alternating and
+// separated patterns of NoSequences within the last seven intervals, and a group of intervals
+// starting with a leap pattern repeated right before the last one.
+type sequenceDetector struct {
+	intervals []int
+}
+
+func (d *sequenceDetector) Push(interval int) bool {
+	d.intervals = append(d.intervals, interval)
+	n := len(d.intervals)
+	tail := d.intervals[max(0, n-7):]
+	if hasAlternatingPattern(tail) || hasConsecutivePatterns(tail) {
+		return false
+	}
+	for size := 3; 2*size <= n; size++ {
+		first, second := d.intervals[n-2*size:n-size], d.intervals[n-size:]
+		if isLeapPattern(first[0], first[1], first[2]) && slices.Equal(first, second) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *sequenceDetector) Pop() {
+	d.intervals = d.intervals[:len(d.intervals)-1]
+}
+
+// newSeventhTracker returns an incremental checker equivalent to AvoidSeventhBetweenExtrema.
+func newSeventhTracker() IncrementalRule {
+	return &seventhTracker{heights: heights{0}}
+}
+
+// seventhTracker compares every new note with the turning point before it. The other pairs
+// of adjacent turning points were compared when their second note was the last one.
+type seventhTracker struct {
+	heights heights
+}
+
+func (s *seventhTracker) Push(interval int) bool {
+	s.heights.push(interval)
+	h := s.heights
+	last := len(h) - 1
+	turn := last - 1
+	for turn > 0 && !isExtremum(h[turn-1], h[turn], h[turn+1]) {
+		turn--
+	}
+	return utils.Abs(h[last]-h[turn]) != 6
+}
+
+func (s *seventhTracker) Pop() {
+	s.heights.pop()
+}
+
+// AppendSummary appends the last note relative to the last turning point before it, or to the
+// first note if there is none, and the last interval: whether the last note turns depends on
+// the next one.
+func (s *seventhTracker) AppendSummary(b []byte) []byte {
+	h := s.heights
+	last := len(h) - 1
+	if last == 0 {
+		return appendInts(b, 0, 0)
+	}
+	turn := last - 1
+	for turn > 0 && !isExtremum(h[turn-1], h[turn], h[turn+1]) {
+		turn--
+	}
+	return appendInts(b, h[last]-h[turn], h[last]-h[last-1])
+}
+
+// isExtremum reports whether b is strictly higher or strictly lower than both its neighbors.
+func isExtremum(a, b, c int) bool {
+	return (b > a && b > c) || (b < a && b < c)
+}
+
+// newFirstInterval returns an incremental checker equivalent to NoBeginWithFive.
+func newFirstInterval() IncrementalRule {
+	return &firstInterval{}
+}
+
+// firstInterval checks the first interval of the melody only.
+type firstInterval struct {
+	length int
+}
+
+func (f *firstInterval) Push(interval int) bool {
+	f.length++
+	return f.length > 1 || interval != 5
+}
+
+func (f *firstInterval) Pop() {
+	f.length--
+}
+
+// AppendSummary appends nothing: only the length of the melody matters.
+func (f *firstInterval) AppendSummary(b []byte) []byte {
+	return b
+}
+
+// newRepeatCounter returns an incremental checker equivalent to SingleRepeatedNote.
+func newRepeatCounter() IncrementalRule {
+	return &repeatCounter{limit: 1}
+}
+
+// IncrementalMaxRepeatedNotes returns incremental checkers equivalent to MaxRepeatedNotes(limit).
+func IncrementalMaxRepeatedNotes(limit int) func() IncrementalRule {
+	return func() IncrementalRule {
+		return &repeatCounter{limit: limit}
+	}
+}
+
+// repeatCounter counts the repeated notes of the melody, at most limit of which are allowed.
+type repeatCounter struct {
+	limit   int
+	repeats []bool
+	count   int
+}
+
+func (c *repeatCounter) Push(interval int) bool {
+	repeat := interval == 0
+	c.repeats = append(c.repeats, repeat)
+	if repeat {
+		c.count++
+	}
+	return c.count <= c.limit && !c.repeats[0]
+}
+
+func (c *repeatCounter) Pop() {
+	if c.repeats[len(c.repeats)-1] {
+		c.count--
+	}
+	c.repeats = c.repeats[:len(c.repeats)-1]
+}
+
+// AppendSummary appends the number of repeated notes.
+func (c *repeatCounter) AppendSummary(b []byte) []byte {
+	return appendInts(b, c.count)
+}
+
+// IncrementalMaxDirectionChanges returns incremental checkers equivalent to MaxDirectionChanges(limit).
+func IncrementalMaxDirectionChanges(limit int) func() IncrementalRule {
+	return func() IncrementalRule {
+		return &directionCounter{limit: limit}
+	}
+}
+
+// directionCounter counts the direction changes of the melody, remembering after each interval
+// the direction it last moved in and the number of changes so far.
+type directionCounter struct {
+	limit   int
+	signs   []int
+	changes []int
+}
+
+func (c *directionCounter) Push(interval int) bool {
+	prevSign, changes := 0, 0
+	if n := len(c.signs); n > 0 {
+		prevSign, changes = c.signs[n-1], c.changes[n-1]
+	}
+	currentSign := prevSign
+	if interval != 0 {
+		currentSign = sign(interval)
+		if prevSign != 0 && currentSign != prevSign {
+			changes++
+		}
+	}
+	c.signs = append(c.signs, currentSign)
+	c.changes = append(c.changes, changes)
+	return changes <= c.limit
+}
+
+func (c *directionCounter) Pop() {
+	c.signs = c.signs[:len(c.signs)-1]
+	c.changes = c.changes[:len(c.changes)-1]
+}
+
+// AppendSummary appends the direction the melody last moved in and the number of changes.
+func (c *directionCounter) AppendSummary(b []byte) []byte {
+	if n := len(c.signs); n > 0 {
+		return appendInts(b, c.signs[n-1], c.changes[n-1])
+	}
+	return appendInts(b, 0, 0)
+}
+
+// IncrementalMaxClimaxHeight returns incremental checkers equivalent to MaxClimaxHeight(height).
+func IncrementalMaxClimaxHeight(height int) func() IncrementalRule {
+	return func() IncrementalRule {
+		return &climaxTracker{limit: height, high: []int{0}}
+	}
+}
+
+// climaxTracker keeps the highest note of every prefix.
+type climaxTracker struct {
+	limit   int
+	heights heights
+	high    []int
+}
+
+func (c *climaxTracker) Push(interval int) bool {
+	high := max(c.high[len(c.high)-1], c.heights.push(interval))
+	c.high = append(c.high, high)
+	return high <= c.limit
+}
+
+func (c *climaxTracker) Pop() {
+	c.heights.pop()
+	c.high = c.high[:len(c.high)-1]
+}
+
+// AppendSummary appends the highest note and the last one.
+func (c *climaxTracker) AppendSummary(b []byte) []byte {
+	return appendInts(b, c.high[len(c.high)-1], c.heights.last())
+}
@@ -0,0 +1,126 @@
+package rules
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkIncremental builds random melodies one interval at a time and compares every
+// Push of the incremental checker with Check on the same prefix, until the rule is broken.
+func checkIncremental(t *testing.T, r Rule) {
+	t.Helper()
+	alphabet := []int{-7, -4, -3, -2, -1, -1, -1, 0, 1, 1, 1, 2, 3, 4, 5, 7}
+	rng := rand.New(rand.NewSource(1))
+
+	inc := NewIncremental(r)
+	for range 3000 {
+		var intervals []int
+		for len(intervals) < 16 {
+			interval := alphabet[rng.Intn(len(alphabet))]
+			intervals = append(intervals, interval)
+			got, want := inc.Push(interval), r.Check(intervals)
+			if got != want {
+				t.Fatalf("%s: Push after %v = %v, Check = %v", r.Name, intervals, got, want)
+			}
+			if !got {
+				break
+			}
+		}
+		for range intervals {
+			inc.Pop()
+		}
+	}
+}
+
+func TestIncremental_Registry(t *testing.T) {
+	for _, r := range Registry() {
+		if r.Partial {
+			t.Run(r.Name, func(t *testing.T) { checkIncremental(t, r) })
+		}
+	}
+}
+
+func TestIncremental_Parameterized(t *testing.T) {
+	tests := []Rule{
+		{Name: "MaxRange", Check: MaxRange(RangeOctave), Incremental: IncrementalMaxRange(RangeOctave)},
+		{Name: "MaxLeaps", Check: MaxLeaps(3), Incremental: IncrementalMaxLeaps(3)},
+		RepeatedNotesRule(2),
+		{Name: "RestrictToDegrees", Check: RestrictToDegrees([]int{1, 2, 3, 4, 5}), Incremental: IncrementalRestrictToDegrees([]int{1, 2, 3, 4, 5})},
+		{Name: "Windowed", Check: NoCloseLargeLeaps, Incremental: Windowed(NoCloseLargeLeaps, 3)},
+		{Name: "Prefix", Check: OctaveLeap},
+		ConsecutiveIntervalsRule(map[int]int{1: 3, 3: 1}),
+		DirectionChangesRule(4),
+		LeapOutlineRule([]int{3, 6, 8}),
+		ClimaxHeightRules(0, RangeOctave)[0],
+	}
+
+	for _, r := range tests {
+		t.Run(r.Name, func(t *testing.T) { checkIncremental(t, r) })
+	}
+}
+
+// continuations appends to b the verdicts of the checker on every continuation of up to
+// depth intervals from the alphabet, not extending those it rejects.
+func continuations(b []byte, inc IncrementalRule, alphabet []int, depth int) []byte {
+	if depth == 0 {
+		return b
+	}
+	for _, interval := range alphabet {
+		if inc.Push(interval) {
+			b = append(b, '1')
+			b = continuations(b, inc, alphabet, depth-1)
+		} else {
+			b = append(b, '0')
+		}
+		inc.Pop()
+	}
+	return b
+}
+
+func TestIncremental_Summary(t *testing.T) {
+	alphabet := []int{-4, -2, -1, 0, 1, 2, 3, 5}
+	tests := []Rule{
+		{Name: "MaxRange", Incremental: IncrementalMaxRange(5)},
+		{Name: "MaxLeaps", Incremental: IncrementalMaxLeaps(3)},
+		{Name: "RestrictToDegrees", Incremental: IncrementalRestrictToDegrees([]int{1, 2, 3, 4, 5})},
+		DirectionChangesRule(2),
+		ClimaxHeightRules(0, 4)[0],
+	}
+	for _, r := range Registry() {
+		if r.Partial && r.Incremental != nil {
+			tests = append(tests, r)
+		}
+	}
+
+	for _, r := range tests {
+		t.Run(r.Name, func(t *testing.T) {
+			inc := r.Incremental()
+			summarizer, ok := inc.(Summarizer)
+			if !ok {
+				t.Skip("no summary")
+			}
+			// Every melody of four accepted intervals must accept the same continuations
+			// as the first one found with the same summary
+			verdicts := map[string]string{}
+			var extend func(intervals []int)
+			extend = func(intervals []int) {
+				if len(intervals) == 4 {
+					summary := string(summarizer.AppendSummary(nil))
+					got := string(continuations(nil, inc, alphabet, 2))
+					if want, ok := verdicts[summary]; ok && got != want {
+						t.Fatalf("%v has summary %q but accepts other continuations than an earlier melody", intervals, summary)
+					}
+					verdicts[summary] = got
+					return
+				}
+				for _, interval := range alphabet {
+					if inc.Push(interval) {
+						extend(append(intervals, interval))
+					}
+					inc.Pop()
+				}
+			}
+			extend(nil)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+// HasLeadingTone reports whether the 7th degree of the mode is a leading tone, a half step
+// below the final, throughout a cantus firmus: natively in Major and Lydian, and raised in
+// Minor (see music.MinorPolicy). In Dorian and Mixolydian the 7th degree is a whole step below
+// the final and is raised at the cadence only; Phrygian and Locrian never raise it.
+func HasLeadingTone(mode music.Mode) bool {
+	return mode == music.Major || mode == music.Lydian || mode == music.Minor
+}
+
+// LeadingToneRule returns the ValidateLeadingTone rule suited to the mode: ValidateLeadingTone
+// itself if the mode has a leading tone (see HasLeadingTone), or else a rule every melody
+// satisfies, since a subtonic a whole step below the final may be used freely.
+func LeadingToneRule(mode music.Mode) Rule {
+	r := Rule{
+		Name:        "ValidateLeadingTone",
+		Description: "The note a step below the final may only appear in stepwise figures around the final.",
+		Check:       ValidateLeadingTone,
+	}
+	if !HasLeadingTone(mode) {
+		r.Description = "The note a step below the final is a subtonic and may be used freely in " + mode.String() + "."
+		r.Check = func([]int) bool { return true }
+	}
+	return r
+}
+
+// SetMode adapts the rule set to the mode the melodies will be realized in by replacing the
+// ValidateLeadingTone rule with the one suited to the mode (see LeadingToneRule) and the opening
+// (see SetOpening), keeping whether it is enabled or soft. It fails if the rule set has no
+// ValidateLeadingTone rule.
+func (s *RuleSet) SetMode(mode music.Mode) error {
+	i := slices.IndexFunc(s.rules, func(r Rule) bool { return r.Name == "ValidateLeadingTone" })
+	if i < 0 {
+		return fmt.Errorf("%w: ValidateLeadingTone", ErrUnknownRule)
+	}
+	r := LeadingToneRule(mode)
+	s.subtonic = !HasLeadingTone(mode)
+	if !s.subtonic {
+		r.Check = s.leadingToneCheck()
+	}
+	s.rules[i] = r
+	return nil
+}
@@ -0,0 +1,64 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+func TestLeadingToneRule(t *testing.T) {
+	// The 7th degree left by a leap: D E D C# F E D in Dorian terms
+	subtonicLeap := []int{1, -1, -1, 3, -1, -1}
+
+	tests := []struct {
+		mode music.Mode
+		want bool
+	}{
+		{music.Major, false},
+		{music.Lydian, false},
+		{music.Minor, false},
+		{music.Dorian, true},
+		{music.Mixolydian, true},
+		{music.Phrygian, true},
+		{music.Locrian, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			r := LeadingToneRule(tt.mode)
+			if r.Name != "ValidateLeadingTone" || r.Partial {
+				t.Errorf("unexpected rule %+v", r)
+			}
+			if got := r.Check(subtonicLeap); got != tt.want {
+				t.Errorf("Check(%v) = %v in %v, want %v", subtonicLeap, got, tt.mode, tt.want)
+			}
+			if HasLeadingTone(tt.mode) == tt.want {
+				t.Errorf("HasLeadingTone(%v) = %v", tt.mode, HasLeadingTone(tt.mode))
+			}
+		})
+	}
+}
+
+func TestRuleSet_SetMode(t *testing.T) {
+	rs := DefaultRuleSet()
+	subtonicLeap := []int{1, -1, -1, 3, -1, -1}
+	rs.SetSoft("ValidateLeadingTone", 2)
+	if err := rs.SetMode(music.Phrygian); err != nil {
+		t.Fatalf("SetMode(Phrygian) returned %v", err)
+	}
+	if rs.Weight("ValidateLeadingTone") != 2 {
+		t.Error("SetMode must keep the rule soft")
+	}
+	for _, v := range rs.Check(subtonicLeap) {
+		if v.Rule == "ValidateLeadingTone" {
+			t.Errorf("the subtonic of Phrygian must be free, got %v", v)
+		}
+	}
+	if DefaultRuleSet().Check(subtonicLeap) == nil {
+		t.Error("SetMode must not change the default rule set")
+	}
+
+	if err := NewRuleSet().SetMode(music.Major); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("SetMode on an empty rule set = %v, want ErrUnknownRule", err)
+	}
+}
@@ -0,0 +1,33 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseLeapRatio parses the largest share of leaps among the intervals of a melody,
+// written as a fraction ("1/3") or a whole number of leaps per interval ("0" or "1").
+// The ratio must lie between 0 and 1.
+func ParseLeapRatio(s string) (num, den int, err error) {
+	numStr, denStr, ok := strings.Cut(s, "/")
+	if !ok {
+		denStr = "1"
+	}
+	num, err1 := strconv.Atoi(strings.TrimSpace(numStr))
+	den, err2 := strconv.Atoi(strings.TrimSpace(denStr))
+	if err1 != nil || err2 != nil || den <= 0 || num < 0 || num > den {
+		return 0, 0, fmt.Errorf("invalid leap ratio %q: want a fraction between 0 and 1 such as 1/3", s)
+	}
+	return num, den, nil
+}
+
+// LeapRatioRule returns a complete rule allowing at most num leaps per den intervals
+// (see MaxLeapRatio). Unlike a fixed number of leaps, the ratio suits melodies of any length.
+func LeapRatioRule(num, den int) Rule {
+	return Rule{
+		Name:        "MaxLeapRatio",
+		Description: fmt.Sprintf("At most %d in %d intervals may be leaps.", num, den),
+		Check:       MaxLeapRatio(num, den),
+	}
+}
@@ -0,0 +1,33 @@
+package rules
+
+import "testing"
+
+func TestParseLeapRatio(t *testing.T) {
+	tests := []struct {
+		input    string
+		num, den int
+		wantErr  bool
+	}{
+		{"1/3", 1, 3, false},
+		{" 2 / 5 ", 2, 5, false},
+		{"0", 0, 1, false},
+		{"1", 1, 1, false},
+		{"4/3", 0, 0, true},
+		{"1/0", 0, 0, true},
+		{"-1/3", 0, 0, true},
+		{"0.3", 0, 0, true},
+		{"", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			num, den, err := ParseLeapRatio(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLeapRatio(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if num != tt.num || den != tt.den {
+				t.Errorf("ParseLeapRatio(%q) = %d/%d, want %d/%d", tt.input, num, den, tt.num, tt.den)
+			}
+		})
+	}
+}
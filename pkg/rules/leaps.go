@@ -0,0 +1,36 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ParseLeaps parses the leaps a melody may use (see RuleSet.SetLeaps), written as a
+// comma-separated list of interval names from third to octave: a name alone allows the
+// leap in both directions, a name preceded by + or - only upward or downward, e.g.
+// "third,fourth,-sixth,octave" allows thirds, fourths and octaves both ways and the
+// descending sixth only. The leaps are returned in the order given, without duplicates.
+func ParseLeaps(s string) ([]int, error) {
+	var leaps []int
+	for _, item := range strings.Split(s, ",") {
+		name := strings.TrimSpace(item)
+		directions := []int{1, -1}
+		switch {
+		case strings.HasPrefix(name, "+"):
+			directions = []int{1}
+		case strings.HasPrefix(name, "-"):
+			directions = []int{-1}
+		}
+		size, err := parseIntervalSize(strings.TrimLeft(name, "+-"))
+		if err != nil || size == 1 {
+			return nil, fmt.Errorf("invalid leap %q: want third, fourth, fifth, sixth, seventh or octave, optionally preceded by + or -", name)
+		}
+		for _, direction := range directions {
+			if leap := direction * size; !slices.Contains(leaps, leap) {
+				leaps = append(leaps, leap)
+			}
+		}
+	}
+	return leaps, nil
+}
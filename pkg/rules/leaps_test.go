@@ -0,0 +1,34 @@
+package rules
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseLeaps(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{"third", []int{2, -2}, false},
+		{"third, fourth, -sixth, octave", []int{2, -2, 3, -3, -5, 7, -7}, false},
+		{"+Fifth,-fifth,fifth", []int{4, -4}, false},
+		{"step", nil, true},
+		{"second", nil, true},
+		{"ninth", nil, true},
+		{"third,", nil, true},
+		{"", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLeaps(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLeaps(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseLeaps(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package rules
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+// RealizationFunc defines the type for a validation function on realized pitches.
+// It returns true if the realization satisfies the rule, false otherwise.
+type RealizationFunc func(r music.Realization) bool
+
+// BeginsAndEndsOnFinal returns a rule on realized pitches checking that the first and last
+// notes are the final of the scale's mode, spelled as in the scale (D in D Dorian, not C##).
+// With sameOctave both notes must also lie in the same octave; otherwise the melody may end
+// in another octave than it began. An empty realization breaks the rule.
+func BeginsAndEndsOnFinal(scale music.Scale, sameOctave bool) RealizationFunc {
+	final := scale.Tonic.PitchClass()
+	return func(r music.Realization) bool {
+		if len(r) == 0 {
+			return false
+		}
+		first, last := r[0], r[len(r)-1]
+		if first.PitchClass() != final || last.PitchClass() != final {
+			return false
+		}
+		return !sameOctave || first.Octave == last.Octave
+	}
+}
+
+// FramedByFinal returns a rule on realized pitches checking that the first and last notes lie
+// opening and ending steps above a final of the scale's mode (4 for the fifth, -7 for the
+// final an octave lower), for melodies that do not begin and end on the same final (see
+// RuleSet.SetOpening and RuleSet.SetEnding). The final is spelled as in the scale.
+// An empty realization breaks the rule.
+func FramedByFinal(scale music.Scale, opening, ending int) RealizationFunc {
+	last := music.Transpose(scale.Tonic, music.Interval(ending)).PitchClass()
+	return func(r music.Realization) bool {
+		if len(r) == 0 {
+			return false
+		}
+		first, end := r[0], r[len(r)-1]
+		return end.PitchClass() == last && first.DiatonicValue()-end.DiatonicValue() == opening-ending
+	}
+}
+
+// InVoiceRange returns a rule on realized pitches checking that every note lies within
+// the range of the intended voice, inclusive (see music.ParseVoiceRange).
+func InVoiceRange(voice music.NoteRange) RealizationFunc {
+	return func(r music.Realization) bool {
+		for _, n := range r {
+			if !voice.Contains(n) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// IsFreeOfAugmentedDiminished checks a Realization for specific conditions related to augmented or diminished intervals.
+func IsFreeOfAugmentedDiminished(r music.Realization) bool {
+	return rule1(r) && rule2(r)
+}
+
+// FreeOfAugmentedDiminishedPrefix checks the beginning of a melody against
+// IsFreeOfAugmentedDiminished and reports false only if no continuation can satisfy it,
+// so that a search may discard the prefix. Pairs of notes and monotonic runs reaching the
+// last note are not judged yet: the next note may still surround them by stepwise motion
+// or extend them.
+func FreeOfAugmentedDiminishedPrefix(r music.Realization) bool {
+	for k := 1; k < len(r)-1; k++ {
+		if !FreeOfAugmentedDiminishedAt(r, k) {
+			return false
+		}
+	}
+	return true
+}
+
+// FreeOfAugmentedDiminishedAt checks what the note after note k settles of
+// IsFreeOfAugmentedDiminished: the pairs of notes at most two apart ending at note k, and the
+// monotonic run ending at note k if the next note turns or repeats. A search adding one note
+// at a time only needs to check the note before the new one (see FreeOfAugmentedDiminishedPrefix).
+// It reports true if note k is the first or the last note.
+func FreeOfAugmentedDiminishedAt(r music.Realization, k int) bool {
+	if k < 1 || k >= len(r)-1 {
+		return true
+	}
+
+	for i := max(0, k-2); i < k; i++ {
+		if augmentedOrDiminished(r[i], r[k]) &&
+			!music.IsNoteSurroundedByLinearMotion(r, i) && !music.IsNoteSurroundedByLinearMotion(r, k) {
+			return false
+		}
+	}
+
+	direction := r[k].Compare(r[k-1])
+	if direction == 0 || r[k+1].Compare(r[k]) == direction {
+		return true
+	}
+	start := k - 1
+	for start > 0 && r[start].Compare(r[start-1]) == direction {
+		start--
+	}
+	return !augmentedOrDiminished(r[start], r[k])
+}
+
+// augmentedOrDiminished reports whether two notes form an augmented or diminished interval,
+// or an interval whose quality cannot be determined, which rule1 and rule2 reject alike.
+func augmentedOrDiminished(n1, n2 music.Note) bool {
+	quality, err := music.CalculateIntervalQuality(n1, n2)
+	return err != nil || quality == "A" || quality == "d"
+}
+
+// NoTritoneOutline checks that no two adjacent turning points of the melody (its local
+// extrema, including the first and last notes) outline a tritone in actual pitch: an augmented
+// fourth or diminished fifth, or one of their compounds. Because it looks at realized pitches,
+// the result depends on the mode: F–B is a tritone in C major, while F–B♭ in F major is not.
+// It complements the diatonic checks of AvoidSeventhBetweenExtrema and AvoidSeventhNinthBetweenExtremes.
+func NoTritoneOutline(r music.Realization) bool {
+	if len(r) < 2 {
+		return true
+	}
+
+	heights := make([]int, len(r))
+	for i, n := range r {
+		heights[i] = n.DiatonicValue()
+	}
+	turningPoints := append([]int{0}, music.Extrema(heights)...)
+	turningPoints = append(turningPoints, len(r)-1)
+
+	for _, w := range music.Windows(turningPoints, 2) {
+		if isTritone(r[w[0]], r[w[1]]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NoTritoneLeapPair checks that no two successive leaps in the same direction outline
+// a tritone in actual pitch, such as B–D–F in C major. It is the counterpart on realized
+// pitches of NoDissonantLeapPair, which cannot tell a tritone from a perfect fourth or fifth.
+func NoTritoneLeapPair(r music.Realization) bool {
+	for i := 2; i < len(r); i++ {
+		first := r[i-1].DiatonicValue() - r[i-2].DiatonicValue()
+		second := r[i].DiatonicValue() - r[i-1].DiatonicValue()
+		if utils.Abs(first) <= 1 || utils.Abs(second) <= 1 || sign(first) != sign(second) {
+			continue
+		}
+		if isTritone(r[i-2], r[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NoTritoneLeapOutline checks that no leap, and no two adjacent leaps in either direction,
+// outline a tritone in actual pitch. It is the counterpart on realized pitches of NoLeapOutline
+// and, unlike NoTritoneLeapPair, also covers single leaps and leaps in opposite directions.
+func NoTritoneLeapOutline(r music.Realization) bool {
+	for i := 1; i < len(r); i++ {
+		leap := r[i].DiatonicValue() - r[i-1].DiatonicValue()
+		if utils.Abs(leap) <= 1 {
+			continue
+		}
+		if isTritone(r[i-1], r[i]) {
+			return false
+		}
+		if i >= 2 && utils.Abs(r[i-1].DiatonicValue()-r[i-2].DiatonicValue()) > 1 && isTritone(r[i-2], r[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NoAugmentedSecond checks that no two adjacent notes form a melodic augmented second,
+// such as F–G# when the leading tone of A minor is raised next to the natural 6th degree.
+// See music.RealizeOptions.AvoidAugmentedSeconds for re-spelling such melodies instead.
+func NoAugmentedSecond(r music.Realization) bool {
+	for i := 1; i < len(r); i++ {
+		if music.IsAugmentedSecond(r[i-1], r[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTritone reports whether two notes form an augmented fourth or diminished fifth,
+// or a compound of either.
+func isTritone(n1, n2 music.Note) bool {
+	steps := music.Mod7(n2.DiatonicValue() - n1.DiatonicValue())
+	semitones := ((n2.Semitones()-n1.Semitones())%12 + 12) % 12
+	return semitones == 6 && (steps == 3 || steps == 4)
+}
+
+// rule1 checks every pair of notes n1 and n2 within a distance of 1 or fewer other notes
+// (i.e., indices differ by 2 or less), if the interval between n1 and n2 is augmented ("A")
+// or diminished ("d"), then at least one of n1 or n2 must be surrounded by linear motion
+// (as determined by IsNoteSurroundedByLinearMotion). If this condition is not met for any pair,
+// the function immediately returns false. If all such pairs satisfy the condition, it returns true.
+func rule1(r music.Realization) bool {
+	for i := range r {
+		for j := i + 1; j < len(r); j++ {
+			if j-i <= 2 {
+				n1 := r[i]
+				n2 := r[j]
+
+				quality, err := music.CalculateIntervalQuality(n1, n2)
+				if err != nil {
+					return false
+				}
+
+				if quality == "A" || quality == "d" {
+					n1LinearMotion := music.IsNoteSurroundedByLinearMotion(r, i)
+					n2LinearMotion := music.IsNoteSurroundedByLinearMotion(r, j)
+
+					if !(n1LinearMotion || n2LinearMotion) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// rule2 checks a Realization for ascending or descending sequences of notes.
+// It returns false immediately if the interval between the first and last notes
+// of such a sequence is augmented ("A") or diminished ("d").
+// This rule considers any change in step as part of the sequence as long as the direction is maintained.
+func rule2(r music.Realization) bool {
+	if len(r) < 2 {
+		return true
+	}
+
+	for i := 0; i < len(r)-1; i++ {
+		for j := i + 1; j < len(r); j++ {
+			if r[i:j+1].Direction() == 0 {
+				continue
+			}
+
+			// Only maximal monotonic runs are checked
+			isMaximalByLeftExtension := i == 0 || r[i-1:j+1].Direction() == 0
+			isMaximalByRightExtension := j == len(r)-1 || r[i:j+2].Direction() == 0
+
+			if isMaximalByLeftExtension && isMaximalByRightExtension {
+				quality, err := music.CalculateIntervalQuality(r[i], r[j])
+				if err != nil {
+					return false
+				}
+
+				if quality == "A" || quality == "d" {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,617 @@
+package rules
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"testing"
+)
+
+func TestIsFreeOfAugmentedDiminished(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    music.Realization
+		expected bool
+	}{
+		{
+			name: "All perfect/major/minor intervals",
+			input: music.Realization{
+				{Step: 0, Octave: 4}, // C4
+				{Step: 1, Octave: 4}, // D4
+				{Step: 2, Octave: 4}, // E4
+				{Step: 0, Octave: 5}, // C5
+			},
+			expected: true,
+		},
+		{
+			name: "Contains augmented interval",
+			input: music.Realization{
+				{Step: 0, Octave: 4},                // C4
+				{Step: 3, Octave: 4, Alteration: 1}, // F#4
+			},
+			expected: false,
+		},
+		{
+			name: "Contains diminished interval",
+			input: music.Realization{
+				{Step: 3, Octave: 4},                 // F4
+				{Step: 0, Octave: 5, Alteration: -1}, // Cb5
+			},
+			expected: false,
+		},
+		{
+			name: "Single note (no intervals)",
+			input: music.Realization{
+				{Step: 0, Octave: 4},
+			},
+			expected: true,
+		},
+		{
+			name:     "Empty realization",
+			input:    music.Realization{},
+			expected: true,
+		},
+		{
+			name: "Mixed valid and invalid intervals",
+			input: music.Realization{
+				{Step: 0, Octave: 4},                // C4
+				{Step: 1, Octave: 4},                // D4
+				{Step: 3, Octave: 4, Alteration: 1}, // F#4
+				{Step: 0, Octave: 5},                // C5
+			},
+			expected: false,
+		},
+		{
+			name: "All minor intervals",
+			input: music.Realization{
+				{Step: 5, Octave: 4}, // A4
+				{Step: 0, Octave: 5}, // C5
+				{Step: 3, Octave: 5}, // F5
+			},
+			expected: true,
+		},
+		{
+			name: "All perfect intervals",
+			input: music.Realization{
+				{Step: 0, Octave: 4}, // C4
+				{Step: 4, Octave: 4}, // G4
+				{Step: 0, Octave: 5}, // C5
+			},
+			expected: true,
+		},
+		{
+			name: "Valid step-2 intervals",
+			input: music.Realization{
+				{Step: 0, Octave: 4}, // C4
+				{Step: 1, Octave: 4}, // D4
+				{Step: 3, Octave: 4}, // F4
+			},
+			expected: true,
+		},
+		{
+			name: "Invalid augmented step-2 interval",
+			input: music.Realization{
+				{Step: 0, Octave: 4},                // C4
+				{Step: 1, Octave: 4},                // D4
+				{Step: 3, Octave: 4, Alteration: 1}, // F#4
+			},
+			expected: false,
+		},
+		{
+			name: "Invalid diminished step-2 interval",
+			input: music.Realization{
+				{Step: 3, Octave: 4},                 // F4
+				{Step: 4, Octave: 4},                 // G4
+				{Step: 0, Octave: 5, Alteration: -1}, // Cb5
+			},
+			expected: false,
+		},
+		{
+			name: "Valid step-2 intervals with larger sequence",
+			input: music.Realization{
+				{Step: 0, Octave: 4}, // C4
+				{Step: 1, Octave: 4}, // D4
+				{Step: 3, Octave: 4}, // F4
+				{Step: 4, Octave: 4}, // G4
+				{Step: 5, Octave: 4}, // A4
+			},
+			expected: true,
+		},
+		{
+			name: "Valid extremum intervals",
+			input: music.Realization{
+				{Step: 0, Octave: 4}, // C4 (extremum)
+				{Step: 1, Octave: 4}, // D4
+				{Step: 2, Octave: 4}, // E4 (extremum)
+				{Step: 1, Octave: 4}, // D4
+				{Step: 0, Octave: 4}, // C4 (extremum)
+			},
+			expected: true,
+		},
+		{
+			name: "Invalid augmented interval between extremums",
+			input: music.Realization{
+				{Step: 0, Octave: 4},                // C4 (extremum)
+				{Step: 1, Octave: 4},                // D4
+				{Step: 3, Octave: 4, Alteration: 1}, // F#4 (extremum)
+			},
+			expected: false,
+		},
+		{
+			name: "Invalid diminished interval between extremums",
+			input: music.Realization{
+				{Step: 3, Octave: 4},                 // F4 (extremum)
+				{Step: 2, Octave: 4},                 // E4
+				{Step: 0, Octave: 5, Alteration: -1}, // Cb5 (extremum)
+			},
+			expected: false,
+		},
+		{
+			name: "Invalid augmented step-2 interval between extremums",
+			input: music.Realization{
+				{Step: 0, Octave: 4},                // C4 (extremum)
+				{Step: 1, Octave: 4},                // D4
+				{Step: 2, Octave: 4},                // E4
+				{Step: 3, Octave: 4, Alteration: 1}, // F#4 (extremum)
+			},
+			expected: false,
+		},
+		{
+			name: "Complex valid case with extremums",
+			input: music.Realization{
+				{Step: 0, Octave: 4}, // C4 (extremum)
+				{Step: 1, Octave: 4}, // D4
+				{Step: 2, Octave: 4}, // E4 (extremum)
+				{Step: 1, Octave: 4}, // D4
+				{Step: 0, Octave: 4}, // C4 (extremum)
+				{Step: 4, Octave: 4}, // G4 (extremum)
+				{Step: 3, Octave: 4}, // F4
+				{Step: 2, Octave: 4}, // E4 (extremum)
+			},
+			expected: true,
+		},
+		{
+			name: "Complex invalid case with extremums",
+			input: music.Realization{
+				{Step: 0, Octave: 4},                 // C4 (extremum)
+				{Step: 1, Octave: 4},                 // D4
+				{Step: 3, Octave: 4, Alteration: 1},  // F#4 (extremum)
+				{Step: 2, Octave: 4},                 // E4
+				{Step: 0, Octave: 5, Alteration: -1}, // Cb5 (extremum)
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsFreeOfAugmentedDiminished(tt.input)
+			if result != tt.expected {
+				t.Errorf("IsFreeOfAugmentedDiminished() = %v, want %v for case %q", result, tt.expected, tt.name)
+			}
+		})
+	}
+}
+
+func TestFreeOfAugmentedDiminishedPrefix(t *testing.T) {
+	notes := func(names ...string) music.Realization {
+		r := make(music.Realization, len(names))
+		for i, name := range names {
+			n, err := music.ParseNote(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r[i] = n
+		}
+		return r
+	}
+
+	tests := []struct {
+		name  string
+		input music.Realization
+		want  bool
+	}{
+		{"empty", notes(), true},
+		{"tritone leap to the last note", notes("F4", "B4"), true},
+		{"tritone leap not framed by steps", notes("F4", "B4", "C5"), false},
+		{"tritone framed by steps", notes("F4", "A4", "B4", "C5", "A4"), true},
+		{"run still reaching the last note", notes("F4", "G4", "A4", "B4"), true},
+		{"run outlining a tritone", notes("F4", "G4", "A4", "B4", "A4"), false},
+		{"run extended to a fifth", notes("F4", "G4", "A4", "B4", "C5"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FreeOfAugmentedDiminishedPrefix(tt.input); got != tt.want {
+				t.Errorf("FreeOfAugmentedDiminishedPrefix(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFreeOfAugmentedDiminishedPrefix_Sound checks on every melody of a few intervals in
+// lydian that no prefix of a melody satisfying IsFreeOfAugmentedDiminished is rejected.
+func TestFreeOfAugmentedDiminishedPrefix_Sound(t *testing.T) {
+	alphabet := []int{-3, -2, -1, 1, 2, 3}
+	const length = 6
+	rejected := 0
+	cf := make(music.CantusFirmus, length)
+	var walk func(i int)
+	walk = func(i int) {
+		if i < length {
+			for _, val := range alphabet {
+				cf[i] = music.Interval(val)
+				walk(i + 1)
+			}
+			return
+		}
+		r, err := cf.Realize("Lydian")
+		if err != nil {
+			t.Fatal(err)
+		}
+		free := IsFreeOfAugmentedDiminished(r)
+		for end := 1; end <= len(r); end++ {
+			if !FreeOfAugmentedDiminishedPrefix(r[:end]) {
+				rejected++
+				if free {
+					t.Fatalf("FreeOfAugmentedDiminishedPrefix rejects %v, a prefix of %v", r[:end], r)
+				}
+				break
+			}
+		}
+	}
+	walk(0)
+	if rejected == 0 {
+		t.Error("FreeOfAugmentedDiminishedPrefix rejected no prefix")
+	}
+}
+
+func TestBeginsAndEndsOnFinal(t *testing.T) {
+	dorian := music.NewScale(music.Dorian)
+	tests := []struct {
+		name       string
+		input      music.Realization
+		sameOctave bool
+		expected   bool
+	}{
+		{
+			name:     "begins and ends on the final",
+			input:    music.Realization{{Step: 1, Octave: 4}, {Step: 3, Octave: 4}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}}, // D4 F4 E4 D4
+			expected: true,
+		},
+		{
+			name:     "begins on another degree",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}}, // F4 E4 D4
+			expected: false,
+		},
+		{
+			name:     "ends on another degree",
+			input:    music.Realization{{Step: 1, Octave: 4}, {Step: 2, Octave: 4}}, // D4 E4
+			expected: false,
+		},
+		{
+			name:     "enharmonic spelling of the final",
+			input:    music.Realization{{Step: 0, Octave: 4, Alteration: 2}, {Step: 1, Octave: 4}}, // C##4 D4
+			expected: false,
+		},
+		{
+			name:     "ends an octave higher",
+			input:    music.Realization{{Step: 1, Octave: 4}, {Step: 0, Octave: 5}, {Step: 1, Octave: 5}}, // D4 C5 D5
+			expected: true,
+		},
+		{
+			name:       "ends an octave higher in the same octave",
+			input:      music.Realization{{Step: 1, Octave: 4}, {Step: 0, Octave: 5}, {Step: 1, Octave: 5}}, // D4 C5 D5
+			sameOctave: true,
+			expected:   false,
+		},
+		{
+			name:     "empty realization",
+			input:    music.Realization{},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BeginsAndEndsOnFinal(dorian, tt.sameOctave)(tt.input)
+			if got != tt.expected {
+				t.Errorf("BeginsAndEndsOnFinal(%v, %v)(%v) = %v, want %v", dorian.Tonic, tt.sameOctave, tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFramedByFinal(t *testing.T) {
+	dorian := music.NewScale(music.Dorian)
+	tests := []struct {
+		name     string
+		opening  int
+		ending   int
+		input    music.Realization
+		expected bool
+	}{
+		{
+			name:     "opens on the fifth above",
+			opening:  4,
+			input:    music.Realization{{Step: 5, Octave: 4}, {Step: 3, Octave: 4}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}}, // A4 F4 E4 D4
+			expected: true,
+		},
+		{
+			name:     "opens on the fifth below",
+			opening:  -3,
+			input:    music.Realization{{Step: 5, Octave: 3}, {Step: 0, Octave: 4}, {Step: 1, Octave: 4}}, // A3 C4 D4
+			expected: true,
+		},
+		{
+			name:     "fifth in the wrong octave",
+			opening:  4,
+			input:    music.Realization{{Step: 5, Octave: 3}, {Step: 0, Octave: 4}, {Step: 1, Octave: 4}}, // A3 C4 D4
+			expected: false,
+		},
+		{
+			name:     "ends on another degree",
+			opening:  4,
+			input:    music.Realization{{Step: 0, Octave: 5}, {Step: 5, Octave: 4}, {Step: 2, Octave: 4}}, // C5 A4 E4
+			expected: false,
+		},
+		{
+			name:     "ends on the final an octave lower",
+			ending:   -7,
+			input:    music.Realization{{Step: 1, Octave: 4}, {Step: 0, Octave: 4}, {Step: 5, Octave: 3}, {Step: 2, Octave: 3}, {Step: 1, Octave: 3}}, // D4 C4 A3 E3 D3
+			expected: true,
+		},
+		{
+			name:     "ends on the final an octave higher",
+			ending:   -7,
+			input:    music.Realization{{Step: 1, Octave: 4}, {Step: 2, Octave: 4}, {Step: 1, Octave: 4}}, // D4 E4 D4
+			expected: false,
+		},
+		{
+			name:     "ends on the fifth",
+			ending:   4,
+			input:    music.Realization{{Step: 1, Octave: 4}, {Step: 3, Octave: 4}, {Step: 5, Octave: 4}}, // D4 F4 A4
+			expected: true,
+		},
+		{
+			name:     "empty realization",
+			opening:  4,
+			input:    music.Realization{},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FramedByFinal(dorian, tt.opening, tt.ending)(tt.input); got != tt.expected {
+				t.Errorf("FramedByFinal(%v, %d, %d)(%v) = %v, want %v", dorian.Tonic, tt.opening, tt.ending, tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInVoiceRange(t *testing.T) {
+	tenor := music.NoteRange{Low: music.Note{Step: 0, Octave: 3}, High: music.Note{Step: 4, Octave: 4}} // C3-G4
+	tests := []struct {
+		name     string
+		input    music.Realization
+		expected bool
+	}{
+		{
+			name:     "within the range, boundaries included",
+			input:    music.Realization{{Step: 0, Octave: 3}, {Step: 2, Octave: 3}, {Step: 4, Octave: 4}, {Step: 0, Octave: 3}}, // C3 E3 G4 C3
+			expected: true,
+		},
+		{
+			name:     "note above the range",
+			input:    music.Realization{{Step: 1, Octave: 4}, {Step: 5, Octave: 4}, {Step: 1, Octave: 4}}, // D4 A4 D4
+			expected: false,
+		},
+		{
+			name:     "note below the range",
+			input:    music.Realization{{Step: 1, Octave: 3}, {Step: 6, Octave: 2}, {Step: 1, Octave: 3}}, // D3 B2 D3
+			expected: false,
+		},
+		{
+			name:     "empty realization",
+			input:    music.Realization{},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InVoiceRange(tenor)(tt.input); got != tt.expected {
+				t.Errorf("InVoiceRange(%v)(%v) = %v, want %v", tenor, tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNoTritoneOutline(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    music.Realization
+		expected bool
+	}{
+		{
+			name:     "F up to B outlines an augmented fourth",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 4, Octave: 4}, {Step: 5, Octave: 4}, {Step: 6, Octave: 4}, {Step: 5, Octave: 4}}, // F4 G4 A4 B4 A4
+			expected: false,
+		},
+		{
+			name:     "F up to Bb is a perfect fourth",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 4, Octave: 4}, {Step: 5, Octave: 4}, {Step: 6, Octave: 4, Alteration: -1}, {Step: 5, Octave: 4}}, // F4 G4 A4 Bb4 A4
+			expected: true,
+		},
+		{
+			name:     "B passed on the way from C down to F",
+			input:    music.Realization{{Step: 0, Octave: 5}, {Step: 6, Octave: 4}, {Step: 3, Octave: 4}, {Step: 4, Octave: 4}}, // C5 B4 F4 G4
+			expected: true,
+		},
+		{
+			name:     "peak B and valley F",
+			input:    music.Realization{{Step: 5, Octave: 4}, {Step: 6, Octave: 4}, {Step: 4, Octave: 4}, {Step: 3, Octave: 4}, {Step: 4, Octave: 4}}, // A4 B4 G4 F4 G4
+			expected: false,
+		},
+		{
+			name:     "tritone between notes that are not turning points",
+			input:    music.Realization{{Step: 2, Octave: 4}, {Step: 3, Octave: 4}, {Step: 4, Octave: 4}, {Step: 5, Octave: 4}, {Step: 6, Octave: 4}, {Step: 0, Octave: 5}}, // E4 F4 G4 A4 B4 C5
+			expected: true,
+		},
+		{
+			name:     "compound tritone",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 6, Octave: 5}}, // F4 B5
+			expected: false,
+		},
+		{
+			name:     "single note",
+			input:    music.Realization{{Step: 3, Octave: 4}},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NoTritoneOutline(tt.input)
+			if got != tt.expected {
+				t.Errorf("NoTritoneOutline(%v) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNoAugmentedSecond(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    music.Realization
+		expected bool
+	}{
+		{
+			name:     "F up to G# in harmonic minor",
+			input:    music.Realization{{Step: 5, Octave: 4}, {Step: 3, Octave: 4}, {Step: 4, Octave: 4, Alteration: 1}, {Step: 5, Octave: 4}}, // A4 F4 G#4 A4
+			expected: false,
+		},
+		{
+			name:     "G# down to F",
+			input:    music.Realization{{Step: 5, Octave: 4}, {Step: 4, Octave: 4, Alteration: 1}, {Step: 3, Octave: 4}, {Step: 2, Octave: 4}}, // A4 G#4 F4 E4
+			expected: false,
+		},
+		{
+			name:     "F# up to G# in melodic minor",
+			input:    music.Realization{{Step: 5, Octave: 4}, {Step: 3, Octave: 4, Alteration: 1}, {Step: 4, Octave: 4, Alteration: 1}, {Step: 5, Octave: 4}}, // A4 F#4 G#4 A4
+			expected: true,
+		},
+		{
+			name:     "F leaping to G# an octave higher",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 4, Octave: 5, Alteration: 1}}, // F4 G#5
+			expected: true,
+		},
+		{
+			name:     "minor third is not an augmented second",
+			input:    music.Realization{{Step: 4, Octave: 4, Alteration: 1}, {Step: 6, Octave: 4}}, // G#4 B4
+			expected: true,
+		},
+		{
+			name:     "empty realization",
+			input:    music.Realization{},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NoAugmentedSecond(tt.input)
+			if got != tt.expected {
+				t.Errorf("NoAugmentedSecond(%v) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNoTritoneLeapPair(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    music.Realization
+		expected bool
+	}{
+		{
+			name:     "B up to D up to F outlines a diminished fifth",
+			input:    music.Realization{{Step: 6, Octave: 3}, {Step: 1, Octave: 4}, {Step: 3, Octave: 4}, {Step: 2, Octave: 4}}, // B3 D4 F4 E4
+			expected: false,
+		},
+		{
+			name:     "F down to D down to B",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 1, Octave: 4}, {Step: 6, Octave: 3}, {Step: 0, Octave: 4}}, // F4 D4 B3 C4
+			expected: false,
+		},
+		{
+			name:     "D up to F up to A is a perfect fifth",
+			input:    music.Realization{{Step: 1, Octave: 4}, {Step: 3, Octave: 4}, {Step: 5, Octave: 4}}, // D4 F4 A4
+			expected: true,
+		},
+		{
+			name:     "B up to D up to F# is a perfect fifth",
+			input:    music.Realization{{Step: 6, Octave: 3}, {Step: 1, Octave: 4}, {Step: 3, Octave: 4, Alteration: 1}}, // B3 D4 F#4
+			expected: true,
+		},
+		{
+			name:     "leaps in opposite directions",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 5, Octave: 4}, {Step: 6, Octave: 3}}, // F4 A4 B3
+			expected: true,
+		},
+		{
+			name:     "tritone filled in by steps",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 4, Octave: 4}, {Step: 6, Octave: 4}}, // F4 G4 B4
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NoTritoneLeapPair(tt.input)
+			if got != tt.expected {
+				t.Errorf("NoTritoneLeapPair(%v) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNoTritoneLeapOutline(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    music.Realization
+		expected bool
+	}{
+		{
+			name:     "leap of an augmented fourth",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 6, Octave: 4}, {Step: 5, Octave: 4}}, // F4 B4 A4
+			expected: false,
+		},
+		{
+			name:     "B up to D up to F",
+			input:    music.Realization{{Step: 6, Octave: 3}, {Step: 1, Octave: 4}, {Step: 3, Octave: 4}}, // B3 D4 F4
+			expected: false,
+		},
+		{
+			name:     "leaps in opposite directions",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 5, Octave: 4}, {Step: 6, Octave: 3}}, // F4 A4 B3
+			expected: false,
+		},
+		{
+			name:     "perfect fourth leap",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 6, Octave: 4, Alteration: -1}}, // F4 Bb4
+			expected: true,
+		},
+		{
+			name:     "tritone filled in by steps",
+			input:    music.Realization{{Step: 3, Octave: 4}, {Step: 4, Octave: 4}, {Step: 6, Octave: 4}}, // F4 G4 B4
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NoTritoneLeapOutline(tt.input); got != tt.expected {
+				t.Errorf("NoTritoneLeapOutline(%v) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseOpening parses the note a melody begins on as its height above the final (see
+// RuleSet.SetOpening): "final", an interval from second to octave above the final such as
+// "fifth", or one below it such as "fourth below" (case-insensitive).
+func ParseOpening(s string) (int, error) {
+	return parseFrame("opening", s)
+}
+
+// ParseEnding parses the note a melody ends on as its height above the final (see
+// RuleSet.SetEnding), like ParseOpening: "octave below" ends on the final an octave lower.
+func ParseEnding(s string) (int, error) {
+	return parseFrame("ending", s)
+}
+
+// parseFrame parses the height of the first or last note above the final for ParseOpening
+// and ParseEnding, which name it kind in errors.
+func parseFrame(kind, s string) (int, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "final" {
+		return 0, nil
+	}
+	sign := 1
+	if rest, ok := strings.CutSuffix(name, " below"); ok {
+		name, sign = rest, -1
+	}
+	size, err := parseIntervalSize(name)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: want final, or an interval such as fifth or octave below", kind, s)
+	}
+	return sign * size, nil
+}
+
+// frameName names the height of the first or last note above the final the way ParseOpening
+// and ParseEnding read it.
+func frameName(height int) string {
+	switch {
+	case height == 0:
+		return "final"
+	case height < 0:
+		return intervalName(-height) + " below"
+	}
+	return intervalName(height)
+}
+
+// Ending returns the height of the last note of a melody above the final, in steps
+// (see SetEnding).
+func (s *RuleSet) Ending() int {
+	return s.ending
+}
+
+// SetEnding sets the note melodies end on as its height above the final, e.g. -7 to finish
+// on the final an octave lower, as in exercises in the plagal (hypo-) modes; 0, the default,
+// ends on the final the melody is measured from. The closing steps and the cadence approach
+// whichever note the melody ends on, and AvoidSeventhNinthBetweenExtremes is replaced by its
+// counterpart measuring the extremes from that note, while the leading tone is still measured
+// from the final. The ending must lie within an octave of the final.
+func (s *RuleSet) SetEnding(height int) error {
+	if height < -RangeOctave || height > RangeOctave {
+		return fmt.Errorf("ending must lie within an octave of the final, got %d steps", height)
+	}
+	s.ending = height
+	s.replaceCheck("AvoidSeventhNinthBetweenExtremes", s.extremesCheck())
+	return nil
+}
+
+// Opening returns the height of the first note of a melody above the final, in steps
+// (see SetOpening).
+func (s *RuleSet) Opening() int {
+	return s.opening
+}
+
+// SetOpening sets the note melodies begin on as its height above the final, e.g. 4 for the
+// fifth some modal repertoires open on, or -3 for the fifth below; 0, the default, opens on
+// the final. The rules measuring notes from the final, ValidateLeadingTone and
+// AvoidSeventhNinthBetweenExtremes, are replaced by their counterparts for the opening,
+// keeping whether they are enabled or soft. The generator reads the opening from the rule
+// set, so that melodies end on the final (see SetEnding) wherever they begin. The opening must lie within
+// an octave of the final.
+func (s *RuleSet) SetOpening(height int) error {
+	if height < -RangeOctave || height > RangeOctave {
+		return fmt.Errorf("opening must lie within an octave of the final, got %d steps", height)
+	}
+	s.opening = height
+	if !s.subtonic {
+		s.replaceCheck("ValidateLeadingTone", s.leadingToneCheck())
+	}
+	s.replaceCheck("AvoidSeventhNinthBetweenExtremes", s.extremesCheck())
+	return nil
+}
+
+// extremesCheck returns the check of AvoidSeventhNinthBetweenExtremes measuring the extremes
+// from the last note of a melody framed by the opening and the ending of the rule set.
+func (s *RuleSet) extremesCheck() ValidationFunc {
+	if s.opening == s.ending {
+		return AvoidSeventhNinthBetweenExtremes
+	}
+	return AvoidSeventhNinthBetweenExtremesFrom(s.opening - s.ending)
+}
+
+// leadingToneCheck returns the check of ValidateLeadingTone for the opening of the rule set.
+func (s *RuleSet) leadingToneCheck() ValidationFunc {
+	if s.opening == 0 {
+		return ValidateLeadingTone
+	}
+	return ValidateLeadingToneFrom(s.opening)
+}
+
+// replaceCheck replaces the check of the rule with the given name, if the rule set has it,
+// keeping its name, description and state. The incremental checker is dropped, since it
+// would no longer match the check.
+func (s *RuleSet) replaceCheck(name string, check ValidationFunc) {
+	for i, r := range s.rules {
+		if r.Name == name {
+			s.rules[i].Check = check
+			s.rules[i].Incremental = nil
+		}
+	}
+}
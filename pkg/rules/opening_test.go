@@ -0,0 +1,148 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+func TestParseOpening(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"final", 0, false},
+		{"fifth", 4, false},
+		{" Fourth below ", -3, false},
+		{"octave below", -7, false},
+		{"third", 2, false},
+		{"ninth", 0, true},
+		{"below", 0, true},
+		{"dominant", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseOpening(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOpening(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseOpening(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+		if !tt.wantErr && tt.want != 0 {
+			if back, _ := ParseOpening(frameName(got)); back != got {
+				t.Errorf("frameName(%d) = %q does not parse back", got, frameName(got))
+			}
+		}
+	}
+}
+
+func TestParseEnding(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"final", 0, false},
+		{"octave below", -7, false},
+		{"Octave", 7, false},
+		{"fifth", 4, false},
+		{"tenth below", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEnding(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEnding(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseEnding(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRuleSet_SetEnding(t *testing.T) {
+	// D E F E D C B A G F E D C# D: the leading tone of the lower final lies a ninth below
+	// the first note, but the extremes lie a tenth and a second from the last note
+	melody := []int{1, 1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1}
+
+	s := DefaultRuleSet()
+	if err := s.SetSoft("AvoidSeventhNinthBetweenExtremes", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Penalty(melody); got != 1 {
+		t.Errorf("Penalty() = %g before SetEnding, want 1", got)
+	}
+	if err := s.SetEnding(-7); err != nil {
+		t.Fatal(err)
+	}
+	if s.Ending() != -7 || s.Clone().Ending() != -7 {
+		t.Errorf("Ending() = %d, want -7", s.Ending())
+	}
+	if got := s.Penalty(melody); got != 0 {
+		t.Errorf("Penalty() = %g, want 0 with the extremes measured from the lower final", got)
+	}
+	if s.Fingerprint() == DefaultRuleSet().Fingerprint() {
+		t.Error("the ending must change the fingerprint")
+	}
+	if err := s.SetEnding(-8); err == nil {
+		t.Error("SetEnding(-8) succeeded, want an error")
+	}
+}
+
+func TestRuleSet_SetOpening(t *testing.T) {
+	// A G B A G F E D, opening on the fifth of D: G is no leading tone
+	stepwise := []int{-1, 2, -1, -1, -1, -1, -1}
+	// A F C# D: the leading tone is approached by leap
+	leaping := []int{-2, -3, 1}
+
+	s := DefaultRuleSet()
+	if err := s.SetSoft("ValidateLeadingTone", 1); err != nil {
+		t.Fatal(err)
+	}
+	if s.Penalty(stepwise) != 1 || s.Penalty(leaping) != 0 {
+		t.Errorf("Penalty() = %g, %g before SetOpening, want 1, 0: the first note is taken for the final",
+			s.Penalty(stepwise), s.Penalty(leaping))
+	}
+	if err := s.SetOpening(4); err != nil {
+		t.Fatal(err)
+	}
+	if s.Opening() != 4 {
+		t.Errorf("Opening() = %d, want 4", s.Opening())
+	}
+	if s.Weight("ValidateLeadingTone") != 1 {
+		t.Error("SetOpening must keep the rule soft")
+	}
+	if s.Penalty(stepwise) != 0 || s.Penalty(leaping) != 1 {
+		t.Errorf("Penalty() = %g, %g, want 0, 1", s.Penalty(stepwise), s.Penalty(leaping))
+	}
+
+	// Modes without a leading tone keep their rule every melody satisfies
+	if err := s.SetMode(music.Dorian); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetOpening(3); err != nil {
+		t.Fatal(err)
+	}
+	if s.Penalty(leaping) != 0 {
+		t.Errorf("Penalty() = %g in Dorian, want 0", s.Penalty(leaping))
+	}
+
+	clone := s.Clone()
+	if clone.Opening() != 3 {
+		t.Errorf("Clone().Opening() = %d, want 3", clone.Opening())
+	}
+	if s.Fingerprint() == DefaultRuleSet().Fingerprint() {
+		t.Error("the opening must change the fingerprint")
+	}
+
+	for _, height := range []int{8, -8} {
+		if err := s.SetOpening(height); err == nil {
+			t.Errorf("SetOpening(%d) succeeded, want an error", height)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ParseOutlineIntervals parses the intervals that leaps must not outline (see NoLeapOutline),
+// written as comma-separated names, e.g. "seventh,ninth,tritone". Sizes are named from second
+// to octave or "ninth"; "tritone" sets tritone instead, since it can only be checked on
+// realized pitches (see TritoneLeapOutlineRule).
+func ParseOutlineIntervals(s string) (sizes []int, tritone bool, err error) {
+	for _, item := range strings.Split(s, ",") {
+		name := strings.TrimSpace(item)
+		switch strings.ToLower(name) {
+		case "tritone":
+			tritone = true
+			continue
+		case "ninth":
+			sizes = append(sizes, 8)
+			continue
+		}
+		size, err := parseIntervalSize(name)
+		if err != nil || size == 1 {
+			return nil, false, fmt.Errorf("invalid outline interval %q: want a leap from third to octave, ninth or tritone", name)
+		}
+		sizes = append(sizes, size)
+	}
+	slices.Sort(sizes)
+	return slices.Compact(sizes), tritone, nil
+}
+
+// LeapOutlineRule returns a partial rule rejecting leaps and pairs of adjacent leaps that
+// outline one of the given interval sizes (see NoLeapOutline).
+func LeapOutlineRule(sizes []int) Rule {
+	names := make([]string, len(sizes))
+	for i, size := range sizes {
+		names[i] = outlineName(size)
+	}
+	return Rule{
+		Name:        "NoLeapOutline",
+		Description: fmt.Sprintf("No leap or pair of adjacent leaps may outline %s.", strings.Join(names, " or ")),
+		Partial:     true,
+		Local:       true,
+		Check:       NoLeapOutline(sizes),
+		Incremental: Windowed(NoLeapOutline(sizes), 2),
+	}
+}
+
+// TritoneLeapOutlineRule returns the rule on realized pitches rejecting leaps and pairs of
+// adjacent leaps that outline a tritone (see NoTritoneLeapOutline). It is not registered,
+// since NoTritoneLeapPair covers the usual case of two leaps in one direction.
+func TritoneLeapOutlineRule() RealizationRule {
+	return newRealizationRule("NoTritoneLeapOutline", "No leap or pair of adjacent leaps may outline a tritone.",
+		NoTritoneLeapOutline, true)
+}
+
+// outlineName names an interval size with its article, e.g. "a seventh" or "a ninth".
+func outlineName(size int) string {
+	if size == 8 {
+		return "a ninth"
+	}
+	return withArticle(intervalName(size))
+}
@@ -0,0 +1,60 @@
+package rules
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+func TestParseOutlineIntervals(t *testing.T) {
+	tests := []struct {
+		input   string
+		sizes   []int
+		tritone bool
+		wantErr bool
+	}{
+		{"seventh,ninth", []int{6, 8}, false, false},
+		{"Ninth, seventh, tritone", []int{6, 8}, true, false},
+		{"tritone", nil, true, false},
+		{"fourth,fourth", []int{3}, false, false},
+		{"second", nil, false, true},
+		{"step", nil, false, true},
+		{"eleventh", nil, false, true},
+		{"", nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sizes, tritone, err := ParseOutlineIntervals(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOutlineIntervals(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && (!slices.Equal(sizes, tt.sizes) || tritone != tt.tritone) {
+				t.Errorf("ParseOutlineIntervals(%q) = %v, %v; want %v, %v", tt.input, sizes, tritone, tt.sizes, tt.tritone)
+			}
+		})
+	}
+}
+
+func TestLeapOutlineRule(t *testing.T) {
+	r := LeapOutlineRule([]int{6, 8})
+	if r.Name != "NoLeapOutline" || !r.Partial || !r.Local {
+		t.Errorf("unexpected rule %+v", r)
+	}
+	if want := "No leap or pair of adjacent leaps may outline a seventh or a ninth."; r.Description != want {
+		t.Errorf("Description = %q, want %q", r.Description, want)
+	}
+	if v := CheckRules([]int{1, -1, 2, 4, -1}, []Rule{r}); len(v) != 1 || v[0].Start != 2 || v[0].End != 3 {
+		t.Errorf("CheckRules() = %v, want a violation over intervals 2-3", v)
+	}
+}
+
+func TestTritoneLeapOutlineRule(t *testing.T) {
+	r := TritoneLeapOutlineRule()
+	// C major: C4 F4 B4 A4, the leap F4-B4 at notes 2-3
+	melody := music.Realization{{Step: 0, Octave: 4}, {Step: 3, Octave: 4}, {Step: 6, Octave: 4}, {Step: 5, Octave: 4}}
+	if v := r.Check(melody); len(v) != 1 || v[0].Rule != "NoTritoneLeapOutline" || v[0].Start != 1 || v[0].End != 1 {
+		t.Errorf("Check(%v) = %v, want a violation at interval 1", melody, v)
+	}
+}
@@ -0,0 +1,172 @@
+package rules
+
+import (
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Pin fixes the interval or the note at one position of the melody, for fill-in-the-blank
+// exercises (see PinRule and ParsePins).
+//
+// Fields:
+//   - Note: true if the pin fixes a note, false if it fixes an interval
+//   - Position: the 1-based number of the note or interval; interval 1 joins notes 1 and 2
+//   - Values: the allowed intervals in steps (e.g. -2 for a third down), or the allowed
+//     scale degrees (1-7) of the note, counted from the final
+type Pin struct {
+	Note     bool
+	Position int
+	Values   []int
+}
+
+// PinRule returns the partial rule enforcing the pin (see PinnedInterval and PinnedDegree),
+// named after the position, e.g. "PinInterval3" or "PinNote5", so that a melody may carry
+// one pin per position.
+func PinRule(p Pin) Rule {
+	if p.Note {
+		degrees := make([]string, len(p.Values))
+		for i, degree := range p.Values {
+			degrees[i] = strconv.Itoa(degree)
+		}
+		return Rule{
+			Name:        fmt.Sprintf("PinNote%d", p.Position),
+			Description: fmt.Sprintf("Note %d must be on degree %s.", p.Position, strings.Join(degrees, " or ")),
+			Partial:     true,
+			Check:       PinnedDegree(p.Position, p.Values),
+		}
+	}
+	return Rule{
+		Name:        fmt.Sprintf("PinInterval%d", p.Position),
+		Description: fmt.Sprintf("Interval %d must be %s.", p.Position, describePinnedIntervals(p.Values)),
+		Partial:     true,
+		Check:       PinnedInterval(p.Position, p.Values),
+	}
+}
+
+// describePinnedIntervals names the allowed intervals of a pin, e.g. "a third down or a fourth down",
+// or "a leap down" for all downward leaps up to an octave.
+func describePinnedIntervals(values []int) string {
+	var up, down []int
+	for size := 2; size <= RangeOctave; size++ {
+		up, down = append(up, size), append(down, -size)
+	}
+	contains := func(leaps []int) bool {
+		return !slices.ContainsFunc(leaps, func(leap int) bool { return !slices.Contains(values, leap) })
+	}
+
+	allUp, allDown := contains(up), contains(down)
+	var names []string
+	switch {
+	case allUp && allDown:
+		names = append(names, "a leap")
+	case allUp:
+		names = append(names, "a leap up")
+	case allDown:
+		names = append(names, "a leap down")
+	}
+	for _, interval := range values {
+		covered := utils.Abs(interval) > 1 && (interval > 0 && allUp || interval < 0 && allDown)
+		if !covered {
+			names = append(names, withArticle(music.Interval(interval).String()))
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+// ParsePins parses comma-separated pins written as noteN=degrees or intervalN=intervals,
+// with alternatives separated by "|". Degrees are numbers from 1 to 7, e.g. "note5=5" for
+// the dominant. Intervals are "step", "leap" or an interval name from second to octave,
+// optionally preceded by + or - to require upward or downward motion, e.g. "interval3=-leap"
+// for a descending leap or "interval1=+third|+fourth".
+func ParsePins(s string) ([]Pin, error) {
+	var pins []Pin
+	for _, item := range strings.Split(s, ",") {
+		target, values, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid pin %q: want noteN=degrees or intervalN=intervals, e.g. note5=5", item)
+		}
+
+		var p Pin
+		var number string
+		switch {
+		case strings.HasPrefix(target, "note"):
+			p.Note, number = true, strings.TrimPrefix(target, "note")
+		case strings.HasPrefix(target, "interval"):
+			number = strings.TrimPrefix(target, "interval")
+		default:
+			return nil, fmt.Errorf("invalid pin %q: want noteN=degrees or intervalN=intervals, e.g. note5=5", item)
+		}
+		position, err := strconv.Atoi(number)
+		if err != nil || position < 1 {
+			return nil, fmt.Errorf("invalid pin %q: positions are numbered from 1", item)
+		}
+		p.Position = position
+
+		for _, value := range strings.Split(values, "|") {
+			var allowed []int
+			if p.Note {
+				allowed, err = parsePinnedDegree(value)
+			} else {
+				allowed, err = parsePinnedInterval(value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid pin %q: %w", item, err)
+			}
+			for _, v := range allowed {
+				if !slices.Contains(p.Values, v) {
+					p.Values = append(p.Values, v)
+				}
+			}
+		}
+		pins = append(pins, p)
+	}
+	return pins, nil
+}
+
+// parsePinnedDegree parses a scale degree from 1 to 7.
+func parsePinnedDegree(s string) ([]int, error) {
+	degree, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || degree < 1 || degree > 7 {
+		return nil, fmt.Errorf("invalid degree %q: want 1 to 7", s)
+	}
+	return []int{degree}, nil
+}
+
+// parsePinnedInterval parses "step", "leap" or an interval name from second to octave, optionally
+// preceded by + or -, into the intervals it allows.
+func parsePinnedInterval(s string) ([]int, error) {
+	name := strings.TrimSpace(s)
+	directions := []int{1, -1}
+	switch {
+	case strings.HasPrefix(name, "+"):
+		directions = []int{1}
+	case strings.HasPrefix(name, "-"):
+		directions = []int{-1}
+	}
+	name = strings.ToLower(strings.TrimLeft(name, "+-"))
+
+	var sizes []int
+	if name == "leap" {
+		for size := 2; size <= RangeOctave; size++ {
+			sizes = append(sizes, size)
+		}
+	} else {
+		size, err := parseIntervalSize(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q: want step, leap or second to octave, optionally preceded by + or -", s)
+		}
+		sizes = []int{size}
+	}
+
+	var result []int
+	for _, direction := range directions {
+		for _, size := range sizes {
+			result = append(result, direction*size)
+		}
+	}
+	return result, nil
+}
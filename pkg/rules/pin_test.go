@@ -0,0 +1,81 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePins(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []Pin
+		wantErr bool
+	}{
+		{"note5=5", []Pin{{Note: true, Position: 5, Values: []int{5}}}, false},
+		{"note3=3|5, interval1=+step", []Pin{{Note: true, Position: 3, Values: []int{3, 5}}, {Position: 1, Values: []int{1}}}, false},
+		{"interval3=-leap", []Pin{{Position: 3, Values: []int{-2, -3, -4, -5, -6, -7}}}, false},
+		{"interval2=third|-third", []Pin{{Position: 2, Values: []int{2, -2}}}, false},
+		{"note0=1", nil, true},
+		{"note2=8", nil, true},
+		{"interval2=ninth", nil, true},
+		{"chord2=5", nil, true},
+		{"note2", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePins(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePins(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePins(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPinRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		pin       Pin
+		intervals []int
+		want      bool
+	}{
+		{"descending leap pinned", Pin{Position: 3, Values: []int{-2, -3}}, []int{1, 1, -3, 1}, true},
+		{"step instead of leap", Pin{Position: 3, Values: []int{-2, -3}}, []int{1, 1, -1, 1}, false},
+		{"prefix before the pin", Pin{Position: 3, Values: []int{-2, -3}}, []int{1, 1}, true},
+		{"dominant pinned", Pin{Note: true, Position: 4, Values: []int{5}}, []int{2, 1, 1}, true},
+		{"dominant an octave lower", Pin{Note: true, Position: 3, Values: []int{5}}, []int{-2, -1}, true},
+		{"subdominant instead", Pin{Note: true, Position: 4, Values: []int{5}}, []int{2, 1, -1}, false},
+		{"first note", Pin{Note: true, Position: 1, Values: []int{1}}, nil, true},
+	}
+
+	for _, tt := range tests {
+		r := PinRule(tt.pin)
+		if !r.Partial {
+			t.Fatalf("%s: %s must be partial", tt.name, r.Name)
+		}
+		if got := r.Check(tt.intervals); got != tt.want {
+			t.Errorf("%s: %s.Check(%v) = %v, want %v", tt.name, r.Name, tt.intervals, got, tt.want)
+		}
+	}
+
+	if r := PinRule(Pin{Note: true, Position: 5, Values: []int{1, 5}}); r.Name != "PinNote5" || r.Description != "Note 5 must be on degree 1 or 5." {
+		t.Errorf("unexpected rule %s: %q", r.Name, r.Description)
+	}
+	descriptions := []struct {
+		values []int
+		want   string
+	}{
+		{[]int{-2}, "Interval 2 must be a third down."},
+		{[]int{2, 3}, "Interval 2 must be a third up or a fourth up."},
+		{[]int{-2, -3, -4, -5, -6, -7}, "Interval 2 must be a leap down."},
+		{[]int{1, 2, 3, 4, 5, 6, 7, -2}, "Interval 2 must be a leap up or a second up or a third down."},
+		{[]int{2, 3, 4, 5, 6, 7, -2, -3, -4, -5, -6, -7}, "Interval 2 must be a leap."},
+	}
+	for _, tt := range descriptions {
+		if r := PinRule(Pin{Position: 2, Values: tt.values}); r.Name != "PinInterval2" || r.Description != tt.want {
+			t.Errorf("PinRule(%v) = %s: %q, want PinInterval2: %q", tt.values, r.Name, r.Description, tt.want)
+		}
+	}
+}
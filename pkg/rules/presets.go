@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownPreset is returned by Preset for a name that is not a strictness preset.
+var ErrUnknownPreset = errors.New("unknown preset")
+
+// Names of the strictness presets (see Preset)
+const (
+	PresetFux      = "fux"
+	PresetJeppesen = "jeppesen"
+	PresetSchenker = "schenker"
+	PresetSalzer   = "salzer"
+)
+
+// preset is a textbook tradition expressed as changes to the default rule set.
+type preset struct {
+	name        string
+	description string
+	apply       func(s *RuleSet)
+}
+
+// presets lists the strictness presets, the default one first.
+var presets = []preset{
+	{PresetFux, "All registered rules, range up to a tenth, leaps up to a sixth.", func(*RuleSet) {}},
+	{PresetJeppesen, "As Fux, but a single octave leap framed by contrary motion is allowed.", func(s *RuleSet) {
+		s.SetLeaps(append(s.Leaps(), -7, 7)...)
+	}},
+	{PresetSchenker, "As Fux, but the range may reach a twelfth.", func(s *RuleSet) {
+		s.SetMaxRange(RangeTwelfth)
+	}},
+	{PresetSalzer, "Narrow vocal lines: range within an octave, leaps up to a fifth.", func(s *RuleSet) {
+		s.SetLeaps(-4, -3, -2, 2, 3, 4)
+		s.SetMaxRange(RangeOctave)
+	}},
+}
+
+// PresetNames returns the names of the strictness presets, the default (PresetFux) first.
+func PresetNames() []string {
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.name
+	}
+	return names
+}
+
+// PresetDescription returns a one-line summary of the preset with the given name,
+// or an empty string if there is no such preset.
+func PresetDescription(name string) string {
+	for _, p := range presets {
+		if p.name == strings.ToLower(name) {
+			return p.description
+		}
+	}
+	return ""
+}
+
+// Preset returns a new rule set reflecting the textbook tradition with the given name
+// (case-insensitive, see PresetNames). The rule set may be modified further.
+func Preset(name string) (*RuleSet, error) {
+	for _, p := range presets {
+		if p.name == strings.ToLower(name) {
+			s := DefaultRuleSet()
+			p.apply(s)
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q (available: %s)", ErrUnknownPreset, name, strings.Join(PresetNames(), ", "))
+}
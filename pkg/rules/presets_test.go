@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPreset(t *testing.T) {
+	for _, name := range PresetNames() {
+		t.Run(name, func(t *testing.T) {
+			s, err := Preset(name)
+			if err != nil {
+				t.Fatalf("Preset(%q) returned %v", name, err)
+			}
+			if len(s.Rules()) == 0 || len(s.Leaps()) == 0 {
+				t.Errorf("Preset(%q) has no rules or no leaps", name)
+			}
+			if PresetDescription(name) == "" {
+				t.Errorf("preset %q has no description", name)
+			}
+		})
+	}
+
+	if _, err := Preset("Jeppesen"); err != nil {
+		t.Errorf("Preset names must be case-insensitive, got %v", err)
+	}
+	if _, err := Preset("palestrina"); !errors.Is(err, ErrUnknownPreset) {
+		t.Errorf("Preset(palestrina) = %v, want ErrUnknownPreset", err)
+	}
+}
+
+func TestPreset_Differences(t *testing.T) {
+	fux, _ := Preset(PresetFux)
+	if !slices.Equal(fux.Names(), DefaultRuleSet().Names()) || !slices.Equal(fux.Leaps(), DefaultRuleSet().Leaps()) {
+		t.Error("the Fux preset must equal the default rule set")
+	}
+
+	jeppesen, _ := Preset(PresetJeppesen)
+	if !slices.Contains(jeppesen.Leaps(), 7) || slices.Contains(fux.Leaps(), 7) {
+		t.Error("only the Jeppesen preset must allow octave leaps")
+	}
+
+	wide := []int{5, 5, -1, -1, -1, -1, -1, -1, -1, -1}
+	schenker, _ := Preset(PresetSchenker)
+	if got := schenker.Check(wide); slices.ContainsFunc(got, func(v Violation) bool { return v.Rule == "NoRangeExceedsTwelfth" }) {
+		t.Errorf("the Schenker preset must accept a range of an eleventh, got %v", got)
+	}
+	if got := fux.Check(wide); !slices.ContainsFunc(got, func(v Violation) bool { return v.Rule == "NoRangeExceedsDecima" }) {
+		t.Errorf("the Fux preset must reject a range of an eleventh, got %v", got)
+	}
+
+	salzer, _ := Preset(PresetSalzer)
+	ninth := []int{4, 4, -1, -1, -1, -1, -1, -1, -1, -1}
+	if got := salzer.Check(ninth); !slices.ContainsFunc(got, func(v Violation) bool { return v.Rule == "NoRangeExceedsOctave" }) {
+		t.Errorf("the Salzer preset must reject a range of a ninth, got %v", got)
+	}
+	if slices.Contains(salzer.Leaps(), 5) {
+		t.Error("the Salzer preset must not allow a leap of a sixth")
+	}
+}
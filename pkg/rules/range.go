@@ -0,0 +1,59 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Common limits of the melodic range, in steps (see MaxRange and RuleSet.SetMaxRange)
+const (
+	RangeOctave  = 7
+	RangeTenth   = 9
+	RangeTwelfth = 11
+)
+
+// rangeNames names the common range limits for ParseRange and rule names.
+var rangeNames = map[int]string{
+	RangeOctave:  "octave",
+	RangeTenth:   "tenth",
+	RangeTwelfth: "twelfth",
+}
+
+// ParseRange parses a range limit written as the name of a common limit ("octave", "tenth"
+// or "twelfth", case-insensitive) or as a positive number of steps (e.g. "9" for a tenth).
+func ParseRange(s string) (int, error) {
+	for limit, name := range rangeNames {
+		if strings.EqualFold(s, name) {
+			return limit, nil
+		}
+	}
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid range %q: want octave, tenth, twelfth or a positive number of steps", s)
+	}
+	return limit, nil
+}
+
+// rangeRule returns the rule limiting the range to limit steps. The limit of a tenth
+// is the registered NoRangeExceedsDecima rule.
+func rangeRule(limit int) Rule {
+	if limit == RangeTenth {
+		r, _ := LookupByName("NoRangeExceedsDecima")
+		return r
+	}
+
+	r := Rule{Partial: true, Local: true, Check: MaxRange(limit), Incremental: IncrementalMaxRange(limit)}
+	if name, ok := rangeNames[limit]; ok {
+		article := "a"
+		if limit == RangeOctave {
+			article = "an"
+		}
+		r.Name = "NoRangeExceeds" + strings.ToUpper(name[:1]) + name[1:]
+		r.Description = fmt.Sprintf("The range must not exceed %s %s.", article, name)
+	} else {
+		r.Name = fmt.Sprintf("NoRangeExceeds%dSteps", limit)
+		r.Description = fmt.Sprintf("The range must not exceed %d steps.", limit)
+	}
+	return r
+}
@@ -0,0 +1,57 @@
+package rules
+
+import "testing"
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"octave", RangeOctave, false},
+		{"Tenth", RangeTenth, false},
+		{"TWELFTH", RangeTwelfth, false},
+		{"5", 5, false},
+		{"0", 0, true},
+		{"-3", 0, true},
+		{"eleventh", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRange(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRange(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRange(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		intervals []int
+		expected  bool
+	}{
+		{"octave within limit", RangeOctave, []int{4, 3, -7}, true},
+		{"ninth exceeds octave", RangeOctave, []int{4, 4, -8}, false},
+		{"descending ninth exceeds octave", RangeOctave, []int{-4, -4}, false},
+		{"eleventh within twelfth", RangeTwelfth, []int{5, 5, -1}, true},
+		{"thirteenth exceeds twelfth", RangeTwelfth, []int{5, 5, 2}, false},
+		{"empty slice", RangeOctave, []int{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MaxRange(tt.limit)(tt.intervals)
+			if got != tt.expected {
+				t.Errorf("MaxRange(%d)(%v) = %v, want %v", tt.limit, tt.intervals, got, tt.expected)
+			}
+		})
+	}
+}
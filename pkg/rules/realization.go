@@ -0,0 +1,147 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+// RealizationRule is a rule on realized pitches together with the metadata needed to refer
+// to it, the counterpart of Rule for checks that depend on the mode, such as augmented intervals.
+//
+// Fields:
+//   - Name: stable identifier of the rule, equal to the name of its function (e.g. "NoAugmentedSecond")
+//   - Description: one-line human-readable summary of what the rule forbids or requires
+//   - Check: returns the violations of the rule by a realization, none if it is satisfied.
+//     Their Start and End are indices of intervals, as for rules on interval sequences.
+type RealizationRule struct {
+	Name        string
+	Description string
+	Check       func(r music.Realization) []Violation
+}
+
+// realizationRegistry lists the rules on realized pitches that do not depend on the scale,
+// in the order they are checked.
+var realizationRegistry = []RealizationRule{
+	newRealizationRule("IsFreeOfAugmentedDiminished",
+		"Augmented and diminished intervals must be framed by stepwise motion and not outlined by a line in one direction.",
+		IsFreeOfAugmentedDiminished, false),
+	newRealizationRule("NoAugmentedSecond", "Adjacent notes must not form an augmented second, such as F–G# in minor.",
+		NoAugmentedSecond, true),
+	newRealizationRule("NoTritoneLeapPair", "Two successive leaps in one direction must not outline a tritone.",
+		NoTritoneLeapPair, true),
+	newRealizationRule("NoTritoneOutline", "Adjacent turning points must not outline a tritone.",
+		NoTritoneOutline, false),
+}
+
+// RealizationRegistry returns the registered rules on realized pitches in the order they
+// are checked. BeginsAndEndsOnFinal, which depends on the scale, is not included (see
+// RealizationRules). The returned slice is a copy and may be modified.
+func RealizationRegistry() []RealizationRule {
+	return slices.Clone(realizationRegistry)
+}
+
+// RegisterRealization adds a custom rule on realized pitches to the registry, to be
+// checked after the registered ones wherever generated melodies are realized. It fails
+// if r has no name or Check function, or if a rule with the same name is already
+// registered. Like Register, it is not safe for concurrent use.
+func RegisterRealization(r RealizationRule) error {
+	if r.Name == "" {
+		return errors.New("realization rule has no name")
+	}
+	if r.Check == nil {
+		return fmt.Errorf("realization rule %s has no Check function", r.Name)
+	}
+	if slices.ContainsFunc(realizationRegistry, func(other RealizationRule) bool { return other.Name == r.Name }) {
+		return fmt.Errorf("%w: %s", ErrDuplicateRule, r.Name)
+	}
+	realizationRegistry = append(realizationRegistry, r)
+	return nil
+}
+
+// RealizationRules returns the rules a realization of a generated melody in the scale must
+// satisfy: the registered rules on realized pitches (see RealizationRegistry), followed
+// by BeginsAndEndsOnFinal for the scale, in the same octave.
+func RealizationRules(scale music.Scale) []RealizationRule {
+	return append(RealizationRegistry(), newRealizationRule("BeginsAndEndsOnFinal",
+		"The melody must begin and end on the final of the mode, in the same octave.",
+		BeginsAndEndsOnFinal(scale, true), false))
+}
+
+// RealizationRulesFrom works like RealizationRules for melodies beginning opening steps and
+// ending ending steps above the final (see RuleSet.SetOpening and RuleSet.SetEnding):
+// BeginsAndEndsOnFinal is replaced by FramedByFinal.
+func RealizationRulesFrom(scale music.Scale, opening, ending int) []RealizationRule {
+	if opening == 0 && ending == 0 {
+		return RealizationRules(scale)
+	}
+	return append(RealizationRegistry(), newRealizationRule("FramedByFinal",
+		fmt.Sprintf("The melody must begin %s and end %s.", relativeToFinal(opening), relativeToFinal(ending)),
+		FramedByFinal(scale, opening, ending), false))
+}
+
+// relativeToFinal describes a note at the given height above the final, e.g. "on the final"
+// or "an octave below the final".
+func relativeToFinal(height int) string {
+	switch {
+	case height == 0:
+		return "on the final"
+	case height < 0:
+		return withArticle(intervalName(-height)) + " below the final"
+	}
+	return withArticle(intervalName(height)) + " above the final"
+}
+
+// VoiceRangeRule returns the rule on realized pitches keeping every note within the range
+// of the intended voice (see InVoiceRange). It is not registered, since it depends on the voice.
+func VoiceRangeRule(voice music.NoteRange) RealizationRule {
+	return newRealizationRule("InVoiceRange", fmt.Sprintf("Every note must lie within the voice range %s.", voice),
+		InVoiceRange(voice), true)
+}
+
+// CheckRealization returns the violations of the rules by the realization, in the given order.
+func CheckRealization(r music.Realization, rs []RealizationRule) []Violation {
+	var result []Violation
+	for _, rule := range rs {
+		result = append(result, rule.Check(r)...)
+	}
+	return result
+}
+
+// AllRealizationRules reports whether the realization satisfies all the rules,
+// stopping at the first rule it breaks.
+func AllRealizationRules(r music.Realization, rs []RealizationRule) bool {
+	for _, rule := range rs {
+		if len(rule.Check(r)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// newRealizationRule builds a RealizationRule from a validation function on realized pitches.
+// Like Rule.Local, local tells that a violation can be narrowed to the shortest run of notes
+// breaking the rule; otherwise it spans the whole melody.
+func newRealizationRule(name, description string, check RealizationFunc, local bool) RealizationRule {
+	rule := Rule{Name: name, Description: description}
+	return RealizationRule{Name: name, Description: description, Check: func(r music.Realization) []Violation {
+		if check(r) {
+			return nil
+		}
+		if !local || len(r) < 2 {
+			return []Violation{NewViolation(rule, 0, len(r)-2, false)}
+		}
+		// Notes 0..end+1 span the intervals 0..end
+		end := 0
+		for end < len(r)-2 && check(r[:end+2]) {
+			end++
+		}
+		start := end
+		for start > 0 && check(r[start:end+2]) {
+			start--
+		}
+		return []Violation{NewViolation(rule, start, end, true)}
+	}}
+}
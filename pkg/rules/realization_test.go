@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
+
+// realize parses note names into a Realization.
+func realize(t *testing.T, names ...string) music.Realization {
+	t.Helper()
+	r := make(music.Realization, len(names))
+	for i, name := range names {
+		n, err := music.ParseNote(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r[i] = n
+	}
+	return r
+}
+
+func TestRealizationRules(t *testing.T) {
+	rs := RealizationRules(music.NewScale(music.Minor))
+	var names []string
+	for _, r := range rs {
+		if r.Name == "" || r.Description == "" || r.Check == nil {
+			t.Errorf("incomplete rule %+v", r)
+		}
+		names = append(names, r.Name)
+	}
+	want := []string{"IsFreeOfAugmentedDiminished", "NoAugmentedSecond", "NoTritoneLeapPair", "NoTritoneOutline", "BeginsAndEndsOnFinal"}
+	if !slices.Equal(names, want) {
+		t.Errorf("RealizationRules() = %v, want %v", names, want)
+	}
+	if len(RealizationRegistry()) != len(want)-1 {
+		t.Error("RealizationRegistry() must not include BeginsAndEndsOnFinal")
+	}
+}
+
+func TestRealizationRulesFrom(t *testing.T) {
+	dorian := music.NewScale(music.Dorian)
+	if got, want := len(RealizationRulesFrom(dorian, 0, 0)), len(RealizationRules(dorian)); got != want {
+		t.Errorf("RealizationRulesFrom(0, 0) has %d rules, want those of RealizationRules (%d)", got, want)
+	}
+
+	rs := RealizationRulesFrom(dorian, -3, 0)
+	last := rs[len(rs)-1]
+	if last.Name != "FramedByFinal" || last.Description != "The melody must begin a fourth below the final and end on the final." {
+		t.Errorf("last rule = %s: %q", last.Name, last.Description)
+	}
+	if !AllRealizationRules(realize(t, "A3", "C4", "B3", "C4", "D4"), rs) {
+		t.Error("A3 C4 B3 C4 D4 must satisfy the rules opening a fourth below D")
+	}
+	if AllRealizationRules(realize(t, "D4", "E4", "C4", "D4"), rs) {
+		t.Error("D4 E4 C4 D4 must break the rules opening a fourth below D")
+	}
+
+	plagal := RealizationRulesFrom(dorian, 0, -7)
+	if want := "The melody must begin on the final and end an octave below the final."; plagal[len(plagal)-1].Description != want {
+		t.Errorf("Description = %q, want %q", plagal[len(plagal)-1].Description, want)
+	}
+}
+
+func TestCheckRealization(t *testing.T) {
+	minor := RealizationRules(music.NewScale(music.Minor))
+	tests := []struct {
+		name  string
+		notes []string
+		want  []Violation
+	}{
+		{"valid", []string{"A4", "C5", "B4", "A4"}, nil},
+		{"augmented second located", []string{"A4", "B4", "C5", "A4", "F4", "G#4", "A4"}, []Violation{
+			{Rule: "NoAugmentedSecond", Start: 4, End: 4, Message: "notes 5-6: Adjacent notes must not form an augmented second, such as F–G# in minor.", Located: true},
+		}},
+		{"wrong final spans the melody", []string{"A4", "B4", "C5"}, []Violation{
+			{Rule: "BeginsAndEndsOnFinal", Start: 0, End: 1, Message: "The melody must begin and end on the final of the mode, in the same octave."},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := realize(t, tt.notes...)
+			got := CheckRealization(r, minor)
+			var violations []Violation
+			for _, v := range got {
+				if v.Rule != "IsFreeOfAugmentedDiminished" {
+					violations = append(violations, v)
+				}
+			}
+			if !slices.Equal(violations, tt.want) {
+				t.Errorf("CheckRealization(%v) = %v, want %v", r, got, tt.want)
+			}
+			if AllRealizationRules(r, minor) != (len(got) == 0) {
+				t.Errorf("AllRealizationRules(%v) disagrees with CheckRealization", r)
+			}
+		})
+	}
+}
+
+func TestVoiceRangeRule(t *testing.T) {
+	bass, err := music.ParseVoiceRange("bass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := VoiceRangeRule(bass)
+	if rule.Name != "InVoiceRange" || rule.Description != "Every note must lie within the voice range F2-C4." {
+		t.Errorf("unexpected rule %+v", rule)
+	}
+	if v := rule.Check(realize(t, "D3", "F3", "E3", "D3")); v != nil {
+		t.Errorf("Check() = %v, want no violation", v)
+	}
+	want := []Violation{{Rule: "InVoiceRange", Start: 1, End: 1, Message: "notes 2-3: " + rule.Description, Located: true}}
+	if v := rule.Check(realize(t, "A3", "C4", "D4", "C4", "A3")); !slices.Equal(v, want) {
+		t.Errorf("Check() = %v, want %v", v, want)
+	}
+}
+
+func TestRegisterRealization(t *testing.T) {
+	saved := realizationRegistry
+	t.Cleanup(func() { realizationRegistry = saved })
+
+	noHighNotes := RealizationRule{Name: "NoHighNotes", Description: "No note above C5.",
+		Check: func(r music.Realization) []Violation {
+			for i, n := range r {
+				if n.Octave > 5 || (n.Octave == 5 && n.Step > 0) {
+					return []Violation{{Rule: "NoHighNotes", Start: max(0, i-1), End: max(0, i-1), Message: "too high"}}
+				}
+			}
+			return nil
+		}}
+	if err := RegisterRealization(noHighNotes); err != nil {
+		t.Fatalf("RegisterRealization returned %v", err)
+	}
+	rs := RealizationRules(music.NewScale(music.Major))
+	if rs[len(rs)-2].Name != "NoHighNotes" {
+		t.Errorf("registered rule must be checked before BeginsAndEndsOnFinal, got %v", rs)
+	}
+	if AllRealizationRules(realize(t, "C5", "E5", "D5", "C5"), rs) {
+		t.Error("the registered rule must be checked")
+	}
+
+	if err := RegisterRealization(noHighNotes); !errors.Is(err, ErrDuplicateRule) {
+		t.Errorf("RegisterRealization twice = %v, want ErrDuplicateRule", err)
+	}
+	if err := RegisterRealization(RealizationRule{Name: "NoCheck"}); err == nil {
+		t.Error("RegisterRealization must reject a rule without a Check function")
+	}
+}
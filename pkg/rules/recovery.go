@@ -0,0 +1,13 @@
+package rules
+
+import "fmt"
+
+// LeapRecoveryRule returns a complete rule requiring that at least percent percent of the leaps
+// are followed immediately by a step in the opposite direction (see MinLeapRecovery).
+func LeapRecoveryRule(percent int) Rule {
+	return Rule{
+		Name:        "MinLeapRecovery",
+		Description: fmt.Sprintf("At least %d%% of the leaps must be followed by a step in the opposite direction.", percent),
+		Check:       MinLeapRecovery(percent),
+	}
+}
@@ -0,0 +1,22 @@
+package rules
+
+import "testing"
+
+func TestLeapRecoveryRule(t *testing.T) {
+	r := LeapRecoveryRule(75)
+	if r.Name != "MinLeapRecovery" || r.Partial {
+		t.Errorf("unexpected rule %+v", r)
+	}
+	if want := "At least 75% of the leaps must be followed by a step in the opposite direction."; r.Description != want {
+		t.Errorf("Description = %q, want %q", r.Description, want)
+	}
+
+	// Three of four leaps recovered
+	intervals := []int{2, -1, 3, -1, -2, 1, 4, 1, -1}
+	if v := CheckRules(intervals, []Rule{r}); len(v) != 0 {
+		t.Errorf("CheckRules(%v) = %v, want none", intervals, v)
+	}
+	if v := CheckRules(intervals, []Rule{LeapRecoveryRule(80)}); len(v) != 1 || v[0].Start != 0 || v[0].End != len(intervals)-1 {
+		t.Errorf("CheckRules(%v) = %v, want a violation over the whole melody", intervals, v)
+	}
+}
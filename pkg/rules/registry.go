@@ -0,0 +1,111 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Rule is a validation function together with the metadata needed to refer to it
+// from CLIs, configuration files and reports.
+//
+// Fields:
+//   - Name: stable identifier of the rule, equal to the name of its function (e.g. "NoBeginWithFive")
+//   - Description: one-line human-readable summary of what the rule forbids or requires
+//   - Partial: true if the rule can be checked on incomplete melodies, so that the generator
+//     can prune the search with it; false if it only makes sense for a complete cantus firmus
+//   - Local: true if the rule depends only on the shape of the melody and not on where
+//     it starts, so that a violation can be narrowed to the intervals causing it (see Check)
+//   - Check: the validation function
+//   - Incremental: optional; returns a fresh IncrementalRule equivalent to Check on the
+//     prefixes of a melody, used by the generator instead of re-running Check on every prefix
+type Rule struct {
+	Name        string
+	Description string
+	Partial     bool
+	Local       bool
+	Check       ValidationFunc
+	Incremental func() IncrementalRule
+}
+
+// registry lists the rules on interval sequences, in the order the generator checks them.
+var registry = []Rule{
+	{"NoBeginWithFive", "The melody must not begin with a leap of a sixth up.", true, false, NoBeginWithFive, newFirstInterval},
+	{"NoExcessiveNoteRepetition", "No pitch may occur more than three times.", true, true, NoExcessiveNoteRepetition, newNoteCounter},
+	{"SingleRepeatedNote", "At most one note may be repeated, and not at the start.", true, false, SingleRepeatedNote, newRepeatCounter},
+	{"LimitDirectionalMotion", "At most four intervals in one direction, spanning at most a sixth.", true, true, LimitDirectionalMotion, Windowed(LimitDirectionalMotion, 5)},
+	{"NoRangeExceedsDecima", "The range must not exceed a tenth.", true, true, NoRangeExceedsDecima, IncrementalMaxRange(RangeTenth)},
+	{"NoRepeatingPatterns", "Groups of two or three pitches must not be repeated.", true, true, NoRepeatingPatterns, Windowed(NoRepeatingPatterns, 8)},
+	{"PreparedLeaps", "Large leaps must be prepared by motion in the opposite direction.", true, true, PreparedLeaps, Windowed(PreparedLeaps, 4)},
+	{"ValidateLeapResolution", "Large leaps must be resolved by motion in the opposite direction.", true, true, ValidateLeapResolution, Windowed(ValidateLeapResolution, 4)},
+	{"NoTripleAlternatingNote", "A pitch must not return three times in alternation (a, b, a, c, a).", true, true, NoTripleAlternatingNote, Windowed(NoTripleAlternatingNote, 4)},
+	{"NoNoteRepetitionAfterLeap", "Two equal leaps in opposite directions must not return to the same pitch.", true, true, NoNoteRepetitionAfterLeap, Windowed(NoNoteRepetitionAfterLeap, 2)},
+	{"NoRepeatingExtremes", "Adjacent peaks or valleys must not repeat the same pitch.", true, true, NoRepeatingExtremes, nil},
+	{"AvoidSeventhBetweenExtrema", "Adjacent turning points must not outline a seventh.", true, false, AvoidSeventhBetweenExtrema, newSeventhTracker},
+	{"NoSequences", "The melody must not contain melodic sequences.", true, true, NoSequences, newSequenceDetector},
+	{"NoCloseLargeLeaps", "Two leaps larger than a third must not be separated by a single interval.", true, true, NoCloseLargeLeaps, Windowed(NoCloseLargeLeaps, 3)},
+	{"NoDissonantLeapPair", "Two successive leaps in one direction must not outline a seventh or ninth.", true, true, NoDissonantLeapPair, Windowed(NoDissonantLeapPair, 2)},
+	{"NoMoreThanTwoConsecutiveThirds", "At most two thirds in a row.", true, true, NoMoreThanTwoConsecutiveThirds, Windowed(NoMoreThanTwoConsecutiveThirds, 3)},
+	{"OctaveLeap", "At most one octave leap, approached and left by contrary motion.", true, false, OctaveLeap, nil},
+	{"MinDirectionChanges", "The melody must change direction at least twice.", false, false, MinDirectionChanges, nil},
+	{"ValidateClimax", "The highest (and lowest) pitch must occur only once.", false, false, ValidateClimax, nil},
+	{"AvoidSeventhNinthBetweenExtremes", "The final and the extremes must not outline a seventh or ninth.", false, false, AvoidSeventhNinthBetweenExtremes, nil},
+	{"ValidateLeadingTone", "The note a step below the final may only appear in stepwise figures around the final.", false, false, ValidateLeadingTone, nil},
+	cadenceRule(CadenceSupertonic, CadenceLeadingTone),
+}
+
+// Registry returns all registered rules on interval sequences in the order the generator
+// checks them, partial rules first. Parameterized rules such as RestrictToDegrees and rules
+// on realized pitches such as IsFreeOfAugmentedDiminished (see RealizationRegistry) are not included.
+// The returned slice is a copy and may be modified.
+func Registry() []Rule {
+	return slices.Clone(registry)
+}
+
+// Register adds a custom rule to the registry, so that it is included in DefaultRuleSet,
+// the presets, Check and the rule names accepted by the CLI, as if it were built in.
+// A partial rule is checked after the registered partial rules, a complete rule after
+// all registered rules. Register fails if r has no name or Check function, or if a rule
+// with the same name is already registered.
+//
+// Register is not safe for concurrent use and must be called before any rule set is
+// built, typically from an init function. To use a rule in a single rule set only,
+// see RuleSet.Add.
+func Register(r Rule) error {
+	if err := validateRule(r); err != nil {
+		return err
+	}
+	if _, ok := LookupByName(r.Name); ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateRule, r.Name)
+	}
+	i := len(registry)
+	if r.Partial {
+		i = slices.IndexFunc(registry, func(r Rule) bool { return !r.Partial })
+		if i < 0 {
+			i = len(registry)
+		}
+	}
+	registry = slices.Insert(registry, i, r)
+	return nil
+}
+
+// validateRule checks that a rule can be added to a rule set or the registry.
+func validateRule(r Rule) error {
+	if r.Name == "" {
+		return errors.New("rule has no name")
+	}
+	if r.Check == nil {
+		return fmt.Errorf("rule %s has no Check function", r.Name)
+	}
+	return nil
+}
+
+// LookupByName returns the registered rule with the given name (see Rule.Name).
+func LookupByName(name string) (Rule, bool) {
+	for _, r := range registry {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,105 @@
+package rules
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := Registry()
+	if len(registry) == 0 {
+		t.Fatal("Registry() returned no rules")
+	}
+
+	seen := make(map[string]bool)
+	partialDone := false
+	for _, r := range registry {
+		if r.Name == "" || r.Description == "" || r.Check == nil {
+			t.Errorf("incomplete rule %+v", r)
+		}
+		if seen[r.Name] {
+			t.Errorf("rule %s is registered twice", r.Name)
+		}
+		seen[r.Name] = true
+
+		if !r.Partial {
+			partialDone = true
+		} else if partialDone {
+			t.Errorf("partial rule %s is listed after complete rules", r.Name)
+		}
+	}
+
+	// The registry must not be modified through the returned slice
+	registry[0].Name = "Changed"
+	if Registry()[0].Name == "Changed" {
+		t.Error("modifying the result of Registry() changed the registry")
+	}
+}
+
+func TestLookupByName(t *testing.T) {
+	r, ok := LookupByName("NoBeginWithFive")
+	if !ok {
+		t.Fatal("LookupByName(\"NoBeginWithFive\") found nothing")
+	}
+	if !r.Partial || r.Check([]int{5}) || !r.Check([]int{4}) {
+		t.Errorf("LookupByName(\"NoBeginWithFive\") returned the wrong rule: %+v", r)
+	}
+
+	if r, ok := LookupByName("ValidateClimax"); !ok || r.Partial {
+		t.Errorf("LookupByName(\"ValidateClimax\") = %+v, %v; want a complete rule", r, ok)
+	}
+	if _, ok := LookupByName("NoSuchRule"); ok {
+		t.Error("LookupByName(\"NoSuchRule\") should find nothing")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	saved := registry
+	t.Cleanup(func() { registry = saved })
+
+	noFourthUp := Rule{Name: "NoFourthUp", Description: "No leap of a fourth up.", Partial: true, Local: true,
+		Check: func(intervals []int) bool { return !slices.Contains(intervals, 3) }}
+	evenLength := Rule{Name: "EvenLength", Description: "An even number of intervals.",
+		Check: func(intervals []int) bool { return len(intervals)%2 == 0 }}
+	for _, r := range []Rule{noFourthUp, evenLength} {
+		if err := Register(r); err != nil {
+			t.Fatalf("Register(%s) returned %v", r.Name, err)
+		}
+	}
+
+	names := DefaultRuleSet().Names()
+	if i, last := slices.Index(names, "NoFourthUp"), slices.Index(names, "OctaveLeap"); i != last+1 {
+		t.Errorf("partial rule registered at %d, want right after the registered partial rules (%d)", i, last+1)
+	}
+	if names[len(names)-1] != "EvenLength" {
+		t.Errorf("complete rule must be registered last, got %v", names)
+	}
+	if r, ok := LookupByName("NoFourthUp"); !ok || r.Check([]int{1, 3}) {
+		t.Errorf("LookupByName(\"NoFourthUp\") = %+v, %v; want the registered rule", r, ok)
+	}
+	if vs := Check([]int{1, 3, -1, -1, -1}); !slices.ContainsFunc(vs, func(v Violation) bool { return v.Rule == "EvenLength" }) {
+		t.Errorf("Check must report the registered rules, got %v", vs)
+	}
+
+	tests := []struct {
+		name string
+		rule Rule
+		want error
+	}{
+		{"duplicate name", Rule{Name: "NoSequences", Check: NoSequences}, ErrDuplicateRule},
+		{"no name", Rule{Check: NoSequences}, nil},
+		{"no check", Rule{Name: "NoCheck"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Register(tt.rule)
+			if err == nil {
+				t.Fatal("Register must fail")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Register() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package rules
+
+import "fmt"
+
+// RepeatedNotesRule returns a partial rule allowing at most limit repeated notes, none of them
+// at the start (see MaxRepeatedNotes), to go with repeated notes allowed in the search (see
+// RuleSet.SetRepeatedNotes) when a curriculum allows more or fewer than SingleRepeatedNote.
+func RepeatedNotesRule(limit int) Rule {
+	return Rule{
+		Name:        "MaxRepeatedNotes",
+		Description: fmt.Sprintf("At most %d notes may be repeated, and not at the start.", limit),
+		Partial:     true,
+		Check:       MaxRepeatedNotes(limit),
+		Incremental: IncrementalMaxRepeatedNotes(limit),
+	}
+}
@@ -0,0 +1,32 @@
+package rules
+
+import "testing"
+
+func TestRepeatedNotesRule(t *testing.T) {
+	r := RepeatedNotesRule(2)
+	if r.Name != "MaxRepeatedNotes" || !r.Partial || r.Local {
+		t.Errorf("unexpected rule %+v", r)
+	}
+	if want := "At most 2 notes may be repeated, and not at the start."; r.Description != want {
+		t.Errorf("Description = %q, want %q", r.Description, want)
+	}
+
+	tests := []struct {
+		name      string
+		intervals []int
+		want      bool
+	}{
+		{"no repeated note", []int{1, 2, -1, -1, -1}, true},
+		{"two repeated notes", []int{1, 0, 2, 0, -1, -1, -1}, true},
+		{"three repeated notes", []int{1, 0, 0, 2, 0, -1, -1, -1}, false},
+		{"repeated note at the start", []int{0, 1, -1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Check(tt.intervals); got != tt.want {
+				t.Errorf("MaxRepeatedNotes(2)(%v) = %v, want %v", tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
@@ -8,7 +8,13 @@
 // to traditional counterpoint rules.
 package rules
 
-import "go-cantus-firmus/internal/utils"
+import (
+	"maps"
+	"slices"
+
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+)
 
 // ValidationFunc defines the type for a validation function.
 type ValidationFunc func(s []int) bool
@@ -24,6 +30,21 @@ func AllRules(s []int, validators []ValidationFunc) bool {
 	return true
 }
 
+// FirstFailure checks a slice against the enabled hard rules of a rule set, partial rules first,
+// as the generator does, and stops at the first rule it breaks. It returns the name of that rule
+// and false, or an empty name and true if the slice satisfies all of them. Unlike Check, it does
+// not locate the violation, and partial rules are checked on the whole slice only, not on every prefix.
+func FirstFailure(s []int, set *RuleSet) (ruleName string, ok bool) {
+	for _, rs := range [][]Rule{set.Partial(), set.Complete()} {
+		for _, r := range rs {
+			if !r.Check(s) {
+				return r.Name, false
+			}
+		}
+	}
+	return "", true
+}
+
 // NoBeginWithFive checks that the interval sequence doesn't start with 5.
 // Returns false if the first interval is 5, true otherwise.
 func NoBeginWithFive(intervals []int) bool {
@@ -113,6 +134,35 @@ func NoExcessiveNoteRepetition(intervals []int) bool {
 	return true
 }
 
+// SingleRepeatedNote checks that at most one note is repeated immediately (an interval
+// of 0, the unison some treatises allow once in a cantus firmus) and that the melody does
+// not begin with a repeated note. A melody cannot end with one either, since it ends with
+// two steps. Works with partial slices during generation.
+// Returns:
+//   - false if the first interval is 0 or more than one interval is 0 (rule violated)
+//   - true otherwise (rule satisfied)
+func SingleRepeatedNote(intervals []int) bool {
+	return MaxRepeatedNotes(1)(intervals)
+}
+
+// MaxRepeatedNotes returns a rule allowing at most limit repeated notes (intervals of 0),
+// none of them at the start, for curricula that allow more than the single one of
+// SingleRepeatedNote; a limit of 0 forbids them. Works with partial slices during generation.
+func MaxRepeatedNotes(limit int) ValidationFunc {
+	return func(intervals []int) bool {
+		if len(intervals) > 0 && intervals[0] == 0 {
+			return false
+		}
+		count := 0
+		for _, interval := range intervals {
+			if interval == 0 {
+				count++
+			}
+		}
+		return count <= limit
+	}
+}
+
 // NoRangeExceedsDecima checks that the range of the cantus firmus (difference between
 // highest and lowest notes) does not exceed a decima (9 in interval notation).
 // Works with partial slices during generation.
@@ -121,10 +171,21 @@ func NoExcessiveNoteRepetition(intervals []int) bool {
 //   - false if the range exceeds 9 (rule violated)
 //   - true otherwise (rule satisfied)
 func NoRangeExceedsDecima(intervals []int) bool {
-	if len(intervals) == 0 {
-		return true
+	return rangeWithin(intervals, 9)
+}
+
+// MaxRange returns a rule checking that the range of the melody (difference between its
+// highest and lowest notes) does not exceed limit steps, e.g. RangeOctave.
+// Works with partial slices during generation.
+func MaxRange(limit int) ValidationFunc {
+	return func(intervals []int) bool {
+		return rangeWithin(intervals, limit)
 	}
+}
 
+// rangeWithin reports whether the range of the melody (difference between its highest
+// and lowest notes) does not exceed limit steps.
+func rangeWithin(intervals []int, limit int) bool {
 	currentSum := 0
 	minSum := 0
 	maxSum := 0
@@ -138,7 +199,7 @@ func NoRangeExceedsDecima(intervals []int) bool {
 			maxSum = currentSum
 		}
 
-		if maxSum-minSum > 9 {
+		if maxSum-minSum > limit {
 			return false
 		}
 	}
@@ -168,11 +229,7 @@ func NoRepeatingPatterns(intervals []int) bool {
 		return true
 	}
 
-	partialSums := make([]int, len(intervals)+1)
-	partialSums[0] = 0
-	for i, interval := range intervals {
-		partialSums[i+1] = partialSums[i] + interval
-	}
+	partialSums := music.PartialSums(intervals)
 
 	n := len(partialSums)
 
@@ -439,21 +496,14 @@ func NoTripleAlternatingNote(intervals []int) bool {
 	}
 
 	// Compute partial sums (note heights relative to the starting note)
-	partialSums := make([]int, len(intervals)+1)
-	partialSums[0] = 0
-	for i, interval := range intervals {
-		partialSums[i+1] = partialSums[i] + interval
-	}
+	partialSums := music.PartialSums(intervals)
 
 	// Check for the pattern a, b, a, c, a
-	for i := 0; i <= len(partialSums)-5; i++ {
-		a := partialSums[i]
-
+	for _, w := range music.Windows(partialSums, 5) {
 		// Check if the same note appears at positions i, i+2, and i+4
-		if partialSums[i+2] == a && partialSums[i+4] == a {
+		if w[2] == w[0] && w[4] == w[0] {
 			return false
 		}
-
 	}
 
 	return true
@@ -470,9 +520,8 @@ func NoNoteRepetitionAfterLeap(intervals []int) bool {
 		return true
 	}
 
-	for i := 0; i < len(intervals)-1; i++ {
-		current := intervals[i]
-		next := intervals[i+1]
+	for _, w := range music.Windows(intervals, 2) {
+		current, next := w[0], w[1]
 
 		if utils.Abs(current) > 1 &&
 			utils.Abs(next) > 1 &&
@@ -499,28 +548,14 @@ func NoRepeatingExtremes(intervals []int) bool {
 	}
 
 	// Build partial sums (note heights)
-	partialSums := make([]int, len(intervals)+1)
-	partialSums[0] = 0
-	for i, interval := range intervals {
-		partialSums[i+1] = partialSums[i] + interval
-	}
+	partialSums := music.PartialSums(intervals)
 
 	// Find all local extrema (excluding first and last notes)
-	extrema := make([]int, 0)
-	for i := 1; i < len(partialSums)-1; i++ {
-		prev := partialSums[i-1]
-		current := partialSums[i]
-		next := partialSums[i+1]
-
-		// Check for peak (current > neighbors) or valley (current < neighbors)
-		if (current > prev && current > next) || (current < prev && current < next) {
-			extrema = append(extrema, current)
-		}
-	}
+	extrema := music.Extrema(partialSums)
 
 	// Check for the pattern a, b, a in extrema
 	for i := 0; i < len(extrema)-2; i++ {
-		if extrema[i] == extrema[i+2] {
+		if partialSums[extrema[i]] == partialSums[extrema[i+2]] {
 			return false
 		}
 	}
@@ -539,38 +574,130 @@ func AvoidSeventhBetweenExtrema(intervals []int) bool {
 	}
 
 	// Build partial sums (note heights)
-	partialSums := make([]int, len(intervals)+1)
-	partialSums[0] = 0
-	for i, interval := range intervals {
-		partialSums[i+1] = partialSums[i] + interval
-	}
+	partialSums := music.PartialSums(intervals)
 
 	// Find all local extrema, including first and last notes
-	extrema := make([]int, 0)
-	extrema = append(extrema, partialSums[0]) // Include first note
+	extrema := []int{0} // Include first note
+	extrema = append(extrema, music.Extrema(partialSums)...)
+	extrema = append(extrema, len(partialSums)-1) // Include last note
 
-	for i := 1; i < len(partialSums)-1; i++ {
-		prev := partialSums[i-1]
-		current := partialSums[i]
-		next := partialSums[i+1]
-
-		// Check for peak or valley
-		if (current > prev && current > next) || (current < prev && current < next) {
-			extrema = append(extrema, current)
+	for _, w := range music.Windows(extrema, 2) {
+		if utils.Abs(partialSums[w[0]]-partialSums[w[1]]) == 6 {
+			return false
 		}
 	}
 
-	extrema = append(extrema, partialSums[len(partialSums)-1]) // Include last note
+	return true
+}
 
-	for i := 0; i < len(extrema)-1; i++ {
-		if utils.Abs(extrema[i]-extrema[i+1]) == 6 {
+// OctaveLeap checks leaps of an octave (7 or -7 in interval notation), which the
+// generator only produces when the rule set allows them (see RuleSet.SetLeaps).
+// A melody may contain at most one octave leap, and it must be approached and left
+// by motion in the opposite direction.
+// Works with partial slices during generation.
+//
+// Returns:
+//   - false if there is more than one octave leap or one is not framed by contrary motion
+//   - true otherwise (rule satisfied)
+func OctaveLeap(intervals []int) bool {
+	count := 0
+	for i, interval := range intervals {
+		if utils.Abs(interval) != 7 {
+			continue
+		}
+		count++
+		if count > 1 || i == 0 || sign(intervals[i-1]) != -sign(interval) {
+			return false
+		}
+		if i+1 < len(intervals) && sign(intervals[i+1]) != -sign(interval) {
 			return false
 		}
 	}
-
 	return true
 }
 
+// Scale degrees from which the final may be approached (see Cadence)
+const (
+	CadenceSupertonic  = 2
+	CadenceLeadingTone = 7
+)
+
+// Cadence returns a rule requiring the penultimate note to approach the final by step
+// from one of the given scale degrees: CadenceSupertonic (a step above the final)
+// or CadenceLeadingTone (a step below it). Other degrees are ignored.
+// Requires the complete sequence.
+func Cadence(degrees ...int) ValidationFunc {
+	allowed := map[int]bool{}
+	for _, degree := range degrees {
+		switch degree {
+		case CadenceSupertonic:
+			allowed[-1] = true
+		case CadenceLeadingTone:
+			allowed[1] = true
+		}
+	}
+	return func(intervals []int) bool {
+		return len(intervals) > 0 && allowed[intervals[len(intervals)-1]]
+	}
+}
+
+// CountLeaps returns the number of leaps (intervals larger than a step) in the sequence.
+func CountLeaps(intervals []int) int {
+	count := 0
+	for _, interval := range intervals {
+		if utils.Abs(interval) > 1 {
+			count++
+		}
+	}
+	return count
+}
+
+// MaxLeaps returns a rule allowing at most max leaps in the melody.
+// Works with partial slices during generation.
+func MaxLeaps(max int) ValidationFunc {
+	return func(intervals []int) bool {
+		return CountLeaps(intervals) <= max
+	}
+}
+
+// MinLeaps returns a rule requiring at least min leaps in the melody.
+// Requires the complete sequence.
+func MinLeaps(min int) ValidationFunc {
+	return func(intervals []int) bool {
+		return CountLeaps(intervals) >= min
+	}
+}
+
+// MaxLeapRatio returns a rule allowing at most num leaps per den intervals of the melody
+// (e.g. MaxLeapRatio(1, 3) for at most a third), whatever its length.
+// Requires the complete sequence.
+func MaxLeapRatio(num, den int) ValidationFunc {
+	return func(intervals []int) bool {
+		return CountLeaps(intervals)*den <= len(intervals)*num
+	}
+}
+
+// MinLeapRecovery returns a rule requiring that at least percent percent of the leaps of the
+// melody are followed immediately by a step in the opposite direction, a statistical smoothness
+// constraint beyond the resolution of individual leaps (see ValidateLeapResolution). A leap
+// ending the melody is not recovered; a melody without leaps satisfies the rule.
+// Requires the complete sequence.
+func MinLeapRecovery(percent int) ValidationFunc {
+	return func(intervals []int) bool {
+		leaps, recovered := 0, 0
+		for i, interval := range intervals {
+			if utils.Abs(interval) <= 1 {
+				continue
+			}
+			leaps++
+			if i+1 < len(intervals) && utils.Abs(intervals[i+1]) == 1 && sign(intervals[i+1]) != sign(interval) {
+				recovered++
+			}
+		}
+		return recovered*100 >= leaps*percent
+	}
+}
+
 // MinDirectionChanges checks that the melody changes direction (ascending/descending)
 // at least twice in the complete interval sequence.
 // Returns:
@@ -595,6 +722,32 @@ func MinDirectionChanges(intervals []int) bool {
 	return directionChanges >= 2
 }
 
+// MaxDirectionChanges returns a rule allowing the melody to change direction (ascending/descending)
+// at most limit times, the counterpart of MinDirectionChanges against zigzagging contours.
+// Repeated notes do not change direction. Works with partial slices during generation.
+func MaxDirectionChanges(limit int) ValidationFunc {
+	return func(intervals []int) bool {
+		return countDirectionChanges(intervals) <= limit
+	}
+}
+
+// countDirectionChanges returns the number of times the melody changes direction,
+// ignoring repeated notes.
+func countDirectionChanges(intervals []int) int {
+	changes, prevSign := 0, 0
+	for _, interval := range intervals {
+		if interval == 0 {
+			continue
+		}
+		currentSign := sign(interval)
+		if prevSign != 0 && currentSign != prevSign {
+			changes++
+		}
+		prevSign = currentSign
+	}
+	return changes
+}
+
 // ValidateClimax checks the climax rules for the cantus firmus:
 // - If all heights are >= 0 (relative to starting note), there should be exactly one maximum
 // - If all heights are <= 0, there should be exactly one minimum
@@ -605,11 +758,7 @@ func ValidateClimax(intervals []int) bool {
 	}
 
 	// Build partial sums (note heights relative to starting note)
-	partialSums := make([]int, len(intervals)+1)
-	partialSums[0] = 0
-	for i, interval := range intervals {
-		partialSums[i+1] = partialSums[i] + interval
-	}
+	partialSums := music.PartialSums(intervals)
 
 	allPositive := true
 	allNegative := true
@@ -633,6 +782,47 @@ func ValidateClimax(intervals []int) bool {
 	return countMaxima(partialSums) == 1 && countMinima(partialSums) == 1
 }
 
+// MaxClimaxHeight returns a rule checking that no note of the melody rises more than height
+// steps above the first note, to which the melody returns at the end (5 for a sixth).
+// Works with partial slices during generation.
+func MaxClimaxHeight(height int) ValidationFunc {
+	return func(intervals []int) bool {
+		return slices.Max(music.PartialSums(intervals)) <= height
+	}
+}
+
+// MinClimaxHeight returns a rule checking that the climax, the highest note of the melody,
+// is at least height steps above the first note (4 for a fifth).
+// Requires the complete sequence.
+func MinClimaxHeight(height int) ValidationFunc {
+	return func(intervals []int) bool {
+		return slices.Max(music.PartialSums(intervals)) >= height
+	}
+}
+
+// ClimaxApproach returns a rule restricting the motion into and out of the climax, the highest
+// note of the melody: every occurrence of it must be approached by an interval of at most
+// approach steps and left by one of at most departure steps (1 for a step, 2 for a third).
+// A limit of 0 leaves that side unrestricted. Requires the complete sequence.
+func ClimaxApproach(approach, departure int) ValidationFunc {
+	return func(intervals []int) bool {
+		heights := music.PartialSums(intervals)
+		climax := slices.Max(heights)
+		for i, height := range heights {
+			if height != climax {
+				continue
+			}
+			if approach > 0 && i > 0 && utils.Abs(intervals[i-1]) > approach {
+				return false
+			}
+			if departure > 0 && i < len(intervals) && utils.Abs(intervals[i]) > departure {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 // countMaxima counts how many times the maximum value appears in the slice
 func countMaxima(sums []int) int {
 	if len(sums) == 0 {
@@ -741,80 +931,41 @@ func hasConsecutivePatterns(intervals []int) bool {
 	return false
 }
 
-// hasRepeatingLeapPatterns checks for repeating patterns containing leaps
+// hasRepeatingLeapPatterns checks for a group of intervals, starting with three intervals
+// that contain a leap (see isLeapPattern), repeated right after itself. When the group is
+// shorter than three intervals, the three intervals starting with the repetition must match
+// as well.
+//
+// For every length d of the group, the sequence is compared with itself shifted by d in
+// a single backward scan that counts how many intervals in a row match the interval d
+// positions later; a group starting at s is repeated when d of them match from s on.
+// The whole check thus takes quadratic time in the length of the melody.
 func hasRepeatingLeapPatterns(intervals []int) bool {
-	if len(intervals) < 3 {
-		return false
-	}
-
-	leaps := map[int]bool{-4: true, -3: true, -2: true, 2: true, 3: true, 4: true, 5: true}
-
-	// Collect all potential patterns (triplets) with at least one leap and not all equal
-	patterns := make([][3]int, 0)
-	patternIndices := make([]int, 0) // Store starting indices of patterns
-
-	for i := 0; i <= len(intervals)-3; i++ {
-		a := intervals[i]
-		b := intervals[i+1]
-		c := intervals[i+2]
-
-		// Verify at least two elements are different and at least one is a leap
-		if (a != b || b != c) && (leaps[a] || leaps[b] || leaps[c]) {
-			patterns = append(patterns, [3]int{a, b, c})
-			patternIndices = append(patternIndices, i)
-		}
-	}
-
-	// Look for repeating patterns
-	for i := 0; i < len(patterns); i++ {
-		for j := i + 1; j < len(patterns); j++ {
-			if patterns[i] == patterns[j] {
-				// Found repeating pattern
-				start1 := patternIndices[i]
-				start2 := patternIndices[j]
-
-				// Extend first pattern up to the start of the second
-				extended1 := extendPattern(intervals, start1, start2)
-				if len(extended1) == 0 {
-					continue // Not enough elements for comparison
-				}
-
-				// Extend second pattern to match the length of the first
-				extended2 := extendPattern(intervals, start2, start2+len(extended1))
-				if len(extended2) != len(extended1) {
-					continue // Not enough elements for comparison
-				}
-
-				// Compare the extended patterns
-				if equalSlices(extended1, extended2) {
-					return true
-				}
+	n := len(intervals)
+	for d := 1; 2*d <= n; d++ {
+		matches := 0
+		for s := n - d - 1; s >= 0; s-- {
+			if intervals[s] != intervals[s+d] {
+				matches = 0
+				continue
+			}
+			matches++
+			if matches >= d && s+d+3 <= n && isLeapPattern(intervals[s], intervals[s+1], intervals[s+2]) &&
+				slices.Equal(intervals[s:s+3], intervals[s+d:s+d+3]) {
+				return true
 			}
 		}
 	}
-
 	return false
 }
 
-// extendPattern extends the pattern from start to end using elements from intervals
-func extendPattern(intervals []int, start, end int) []int {
-	if end > len(intervals) {
-		return nil // Not enough elements to extend
-	}
-	return intervals[start:end]
-}
+// sequenceLeaps are the leaps that make a group of three intervals a potential sequence pattern
+var sequenceLeaps = map[int]bool{-4: true, -3: true, -2: true, 2: true, 3: true, 4: true, 5: true}
 
-// equalSlices checks if two slices are equal
-func equalSlices(a, b []int) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for i := range a {
-		if a[i] != b[i] {
-			return false
-		}
-	}
-	return true
+// isLeapPattern reports whether three intervals can start a repeating leap pattern:
+// at least two of them differ and at least one is a leap.
+func isLeapPattern(a, b, c int) bool {
+	return (a != b || b != c) && (sequenceLeaps[a] || sequenceLeaps[b] || sequenceLeaps[c])
 }
 
 // AvoidSeventhNinthBetweenExtremes checks that there are no seventh (6) or ninth (8) intervals
@@ -832,14 +983,25 @@ func AvoidSeventhNinthBetweenExtremes(intervals []int) bool {
 	if len(intervals) == 0 {
 		return true
 	}
+	return extremesAvoidSeventhNinth(music.PartialSums(intervals))
+}
 
-	// Build partial sums (notes relative to tonic)
-	partialSums := make([]int, len(intervals)+1)
-	partialSums[0] = 0
-	for i, interval := range intervals {
-		partialSums[i+1] = partialSums[i] + interval
+// AvoidSeventhNinthBetweenExtremesFrom returns AvoidSeventhNinthBetweenExtremes for a melody
+// beginning opening steps above the final (4 for the fifth, -3 for the fifth below), so that
+// the extremes are measured from the final rather than from the first note.
+// This function should only be applied to complete interval slices.
+func AvoidSeventhNinthBetweenExtremesFrom(opening int) ValidationFunc {
+	return func(intervals []int) bool {
+		if len(intervals) == 0 {
+			return true
+		}
+		return extremesAvoidSeventhNinth(heightsAbove(intervals, opening))
 	}
+}
 
+// extremesAvoidSeventhNinth checks the conditions of AvoidSeventhNinthBetweenExtremes
+// on the heights of the notes above the final.
+func extremesAvoidSeventhNinth(partialSums []int) bool {
 	// Find maximum and minimum
 	maxSum := partialSums[0]
 	minSum := partialSums[0]
@@ -880,12 +1042,24 @@ func ValidateLeadingTone(intervals []int) bool {
 	}
 
 	// Build a slice of partial sums (notes relative to the starting note)
-	partialSums := make([]int, len(intervals)+1)
-	partialSums[0] = 0
-	for i, interval := range intervals {
-		partialSums[i+1] = partialSums[i] + interval
+	return leadingToneFigures(music.PartialSums(intervals))
+}
+
+// ValidateLeadingToneFrom returns ValidateLeadingTone for a melody beginning opening steps
+// above the final (4 for the fifth, -3 for the fifth below), so that the leading tone is
+// the note a step below the final rather than below the first note.
+func ValidateLeadingToneFrom(opening int) ValidationFunc {
+	return func(intervals []int) bool {
+		if len(intervals) == 0 {
+			return true
+		}
+		return leadingToneFigures(heightsAbove(intervals, opening))
 	}
+}
 
+// leadingToneFigures checks the rules of ValidateLeadingTone on the heights of the notes
+// above the final.
+func leadingToneFigures(partialSums []int) bool {
 	// Check each partial sum against the introductory tone rules
 	for i, sum := range partialSums {
 		switch sum {
@@ -1008,27 +1182,178 @@ func NoCloseLargeLeaps(intervals []int) bool {
 	return true
 }
 
+// NoDissonantLeapPair checks that no two successive leaps in the same direction
+// add up to a seventh or a ninth (or a compound of either), e.g. a fifth followed by a third.
+// Unlike NoCloseLargeLeaps, it looks at adjacent leaps and at the interval they outline.
+// Tritones depend on the mode and are checked on realized pitches by NoTritoneLeapPair.
+// Works with partial slices during generation.
+// Returns:
+//   - false if two successive leaps outline a seventh or ninth (rule violated)
+//   - true otherwise (rule satisfied)
+func NoDissonantLeapPair(intervals []int) bool {
+	for i := 1; i < len(intervals); i++ {
+		first, second := intervals[i-1], intervals[i]
+		if utils.Abs(first) <= 1 || utils.Abs(second) <= 1 || sign(first) != sign(second) {
+			continue
+		}
+		span := utils.Abs(first + second)
+		if span%7 == 6 || (span > 7 && span%7 == 1) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NoLeapOutline returns a rule rejecting any leap, and any two adjacent leaps in either direction,
+// whose endpoints outline one of the given interval sizes in steps (6 for a seventh, 8 for a ninth).
+// It generalizes NoDissonantLeapPair and, for adjacent turning points, AvoidSeventhNinthBetweenExtremes
+// to a configurable set of intervals. Tritones depend on the mode and are checked on realized
+// pitches by NoTritoneLeapOutline. Works with partial slices during generation.
+func NoLeapOutline(sizes []int) ValidationFunc {
+	forbidden := make(map[int]bool)
+	for _, size := range sizes {
+		forbidden[size] = true
+	}
+	return func(intervals []int) bool {
+		for i, interval := range intervals {
+			if utils.Abs(interval) <= 1 {
+				continue
+			}
+			if forbidden[utils.Abs(interval)] {
+				return false
+			}
+			if i > 0 && utils.Abs(intervals[i-1]) > 1 && forbidden[utils.Abs(intervals[i-1]+interval)] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 // NoMoreThanTwoConsecutiveThirds checks that there are no more than two consecutive intervals
 // with absolute value equal to 2 in the interval sequence.
 // Returns:
 //   - false if three or more consecutive intervals with absolute value 2 are found (rule violated)
 //   - true otherwise (rule satisfied)
 func NoMoreThanTwoConsecutiveThirds(intervals []int) bool {
-	if len(intervals) < 3 {
+	return maxTwoConsecutiveThirds(intervals)
+}
+
+// maxTwoConsecutiveThirds implements NoMoreThanTwoConsecutiveThirds.
+var maxTwoConsecutiveThirds = MaxConsecutiveIntervals(map[int]int{2: 2})
+
+// MaxConsecutiveIntervals returns a rule limiting runs of intervals of the same size in either
+// direction: limits maps an interval size in steps (1 for a second, 2 for a third, ...) to
+// the largest number of such intervals allowed in a row, e.g. map[int]int{1: 5, 3: 1} for at
+// most five steps and a single fourth in a row. Sizes without a limit are unrestricted.
+// Works with partial slices during generation.
+func MaxConsecutiveIntervals(limits map[int]int) ValidationFunc {
+	limits = maps.Clone(limits)
+	return func(intervals []int) bool {
+		run := 0
+		for i, interval := range intervals {
+			size := utils.Abs(interval)
+			if i > 0 && size == utils.Abs(intervals[i-1]) {
+				run++
+			} else {
+				run = 1
+			}
+			if limit, ok := limits[size]; ok && run > limit {
+				return false
+			}
+		}
 		return true
 	}
+}
 
-	consecutiveTwos := 0
-	for _, interval := range intervals {
-		if utils.Abs(interval) == 2 {
-			consecutiveTwos++
-			if consecutiveTwos > 2 {
+// RestrictToDegrees returns a validation function that allows only the given scale degrees
+// in the cantus firmus. Degrees are numbered from 1 (the tonic, i.e. the starting note)
+// to 7, and every note's degree is derived from its cumulative interval sum, so octave
+// duplicates of a degree are treated alike. This models gapped (restricted) scales used
+// in chant-style lines, e.g. []int{1, 2, 3, 4, 5} to avoid the 6th and 7th degrees.
+// Works with partial slices during generation.
+//
+// Returns a function that:
+//   - returns false if any note falls on a degree outside the allowed set (rule violated)
+//   - returns true otherwise (rule satisfied)
+func RestrictToDegrees(degrees []int) ValidationFunc {
+	allowed := make(map[int]bool)
+	for _, d := range degrees {
+		allowed[d] = true
+	}
+
+	return func(intervals []int) bool {
+		currentSum := 0
+		if !allowed[degreeOf(currentSum)] {
+			return false
+		}
+
+		for _, interval := range intervals {
+			currentSum += interval
+			if !allowed[degreeOf(currentSum)] {
 				return false
 			}
-		} else {
-			consecutiveTwos = 0
 		}
+
+		return true
 	}
+}
 
-	return true
+// StartsWith returns a rule requiring the melody to begin with the given intervals, so that a
+// partially written melody can be completed. Prefixes shorter than the given intervals must
+// agree with them. Works with partial slices during generation.
+func StartsWith(prefix []int) ValidationFunc {
+	prefix = slices.Clone(prefix)
+	return func(intervals []int) bool {
+		n := min(len(intervals), len(prefix))
+		return slices.Equal(intervals[:n], prefix[:n])
+	}
+}
+
+// PinnedInterval returns a rule requiring the interval at the given 1-based position (interval 1
+// joins notes 1 and 2) to be one of the allowed intervals. Prefixes that do not reach the
+// position satisfy the rule. Works with partial slices during generation.
+func PinnedInterval(position int, allowed []int) ValidationFunc {
+	allowed = slices.Clone(allowed)
+	return func(intervals []int) bool {
+		return len(intervals) < position || slices.Contains(allowed, intervals[position-1])
+	}
+}
+
+// PinnedDegree returns a rule requiring the note at the given 1-based position (note 1 is the
+// final the melody starts on) to fall on one of the allowed scale degrees (1-7), counted from
+// the final like RestrictToDegrees. Prefixes that do not reach the position satisfy the rule.
+// Works with partial slices during generation.
+func PinnedDegree(position int, degrees []int) ValidationFunc {
+	degrees = slices.Clone(degrees)
+	return func(intervals []int) bool {
+		if len(intervals) < position-1 {
+			return true
+		}
+		height := 0
+		for _, interval := range intervals[:position-1] {
+			height += interval
+		}
+		return slices.Contains(degrees, degreeOf(height))
+	}
+}
+
+// heightsAbove returns the heights of the notes of a melody above the final, the first note
+// lying opening steps above it.
+func heightsAbove(intervals []int, opening int) []int {
+	heights := music.PartialSums(intervals)
+	for i := range heights {
+		heights[i] += opening
+	}
+	return heights
+}
+
+// degreeOf converts a height relative to the tonic into a scale degree (1-7)
+func degreeOf(height int) int {
+	degree := height % 7
+	if degree < 0 {
+		degree += 7
+	}
+	return degree + 1
 }
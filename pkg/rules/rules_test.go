@@ -1,6 +1,8 @@
 package rules
 
 import (
+	"math/rand"
+	"slices"
 	"testing"
 )
 
@@ -129,6 +131,29 @@ func TestLimitDirectionalMotion(t *testing.T) {
 	}
 }
 
+func TestSingleRepeatedNote(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		want      bool
+	}{
+		{"no repeated note", []int{1, 2, -1, -1, -1}, true},
+		{"one repeated note", []int{1, 0, 2, -1, -1, -1}, true},
+		{"two repeated notes", []int{1, 0, 2, 0, -1, -1, -1}, false},
+		{"repeated note at the start", []int{0, 1, -1}, false},
+		{"partial sequence ending with a repeated note", []int{1, 2, 0}, true},
+		{"empty slice", []int{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SingleRepeatedNote(tt.intervals); got != tt.want {
+				t.Errorf("SingleRepeatedNote(%v) = %v, want %v", tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNoExcessiveNoteRepetition(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -774,6 +799,144 @@ func TestAvoidSeventhBetweenExtrema(t *testing.T) {
 	}
 }
 
+func TestOctaveLeap(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		expected  bool
+	}{
+		{
+			name:      "no octave leap",
+			intervals: []int{1, 3, -1, -1},
+			expected:  true,
+		},
+		{
+			name:      "octave leap framed by contrary motion",
+			intervals: []int{-1, 7, -1, -1},
+			expected:  true,
+		},
+		{
+			name:      "descending octave leap framed by contrary motion",
+			intervals: []int{1, -7, 1},
+			expected:  true,
+		},
+		{
+			name:      "octave leap at the end of a partial slice",
+			intervals: []int{-1, 7},
+			expected:  true,
+		},
+		{
+			name:      "octave leap at the start",
+			intervals: []int{7, -1},
+			expected:  false,
+		},
+		{
+			name:      "octave leap approached in the same direction",
+			intervals: []int{1, 7, -1},
+			expected:  false,
+		},
+		{
+			name:      "octave leap left in the same direction",
+			intervals: []int{-1, 7, 1},
+			expected:  false,
+		},
+		{
+			name:      "two octave leaps",
+			intervals: []int{-1, 7, -1, -1, 1, -7, 1},
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OctaveLeap(tt.intervals)
+			if got != tt.expected {
+				t.Errorf("OctaveLeap(%v) = %v, want %v", tt.intervals, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCadence(t *testing.T) {
+	tests := []struct {
+		name      string
+		degrees   []int
+		intervals []int
+		expected  bool
+	}{
+		{"from the supertonic", []int{CadenceSupertonic, CadenceLeadingTone}, []int{2, -1, -1}, true},
+		{"from the leading tone", []int{CadenceSupertonic, CadenceLeadingTone}, []int{-2, 1, 1}, true},
+		{"by leap", []int{CadenceSupertonic, CadenceLeadingTone}, []int{1, 1, -2}, false},
+		{"repeated final", []int{CadenceSupertonic, CadenceLeadingTone}, []int{1, -1, 0}, false},
+		{"leading tone not allowed", []int{CadenceSupertonic}, []int{-2, 1, 1}, false},
+		{"supertonic not allowed", []int{CadenceLeadingTone}, []int{2, -1, -1}, false},
+		{"empty slice", []int{CadenceSupertonic}, []int{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Cadence(tt.degrees...)(tt.intervals)
+			if got != tt.expected {
+				t.Errorf("Cadence(%v)(%v) = %v, want %v", tt.degrees, tt.intervals, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLeapCountRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		leaps     int
+		atMost2   bool
+		atLeast2  bool
+	}{
+		{"empty slice", []int{}, 0, true, false},
+		{"steps only", []int{1, 1, -1, -1}, 0, true, false},
+		{"two leaps", []int{2, -1, -3, 1, 1}, 2, true, true},
+		{"three leaps", []int{2, -1, -3, 4, -1, -1}, 3, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountLeaps(tt.intervals); got != tt.leaps {
+				t.Errorf("CountLeaps(%v) = %d, want %d", tt.intervals, got, tt.leaps)
+			}
+			if got := MaxLeaps(2)(tt.intervals); got != tt.atMost2 {
+				t.Errorf("MaxLeaps(2)(%v) = %v, want %v", tt.intervals, got, tt.atMost2)
+			}
+			if got := MinLeaps(2)(tt.intervals); got != tt.atLeast2 {
+				t.Errorf("MinLeaps(2)(%v) = %v, want %v", tt.intervals, got, tt.atLeast2)
+			}
+		})
+	}
+}
+
+func TestMaxLeapRatio(t *testing.T) {
+	tests := []struct {
+		name      string
+		num, den  int
+		intervals []int
+		expected  bool
+	}{
+		{"one leap in three intervals", 1, 3, []int{2, -1, -1}, true},
+		{"two leaps in three intervals", 1, 3, []int{2, -3, 1}, false},
+		{"three leaps in nine intervals", 1, 3, []int{2, -1, -1, 3, -1, 2, -1, -1, -1}, true},
+		{"four leaps in nine intervals", 1, 3, []int{2, -1, -2, 3, -1, 2, -1, -1, -1}, false},
+		{"no leaps allowed", 0, 1, []int{1, 1, -1, -1}, true},
+		{"empty slice", 1, 3, []int{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MaxLeapRatio(tt.num, tt.den)(tt.intervals)
+			if got != tt.expected {
+				t.Errorf("MaxLeapRatio(%d, %d)(%v) = %v, want %v", tt.num, tt.den, tt.intervals, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestMinDirectionChanges(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -836,6 +999,114 @@ func TestMinDirectionChanges(t *testing.T) {
 	}
 }
 
+func TestNoLeapOutline(t *testing.T) {
+	sevenths := []int{6, 8}
+	tests := []struct {
+		name      string
+		intervals []int
+		want      bool
+	}{
+		{"no leaps", []int{1, 1, -1}, true},
+		{"leap of a seventh", []int{1, 6, -1}, false},
+		{"leaps outlining a seventh", []int{3, 3, -1}, false},
+		{"leaps outlining a ninth down", []int{-4, -4, 1}, false},
+		{"leaps in opposite directions", []int{-2, 7, -1}, true},
+		{"step between leaps", []int{3, 1, 2}, true},
+		{"leaps outlining an octave", []int{4, 3, -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NoLeapOutline(sevenths)(tt.intervals); got != tt.want {
+				t.Errorf("NoLeapOutline(%v)(%v) = %v, want %v", sevenths, tt.intervals, got, tt.want)
+			}
+		})
+	}
+
+	// Fourths and fifths, which NoDissonantLeapPair allows
+	if NoLeapOutline([]int{3})([]int{-2, 5, -1}) {
+		t.Error("a third down and a sixth up outline a fourth")
+	}
+}
+
+func TestMinLeapRecovery(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		percent   int
+		want      bool
+	}{
+		{"no leaps", []int{1, 1, -1, -1}, 100, true},
+		{"every leap recovered", []int{2, -1, -3, 1, 1}, 100, true},
+		{"recovery in the same direction", []int{2, 1, -1, -1}, 50, false},
+		{"recovery by a leap", []int{3, -2, 1, -1}, 50, true},
+		{"half the leaps recovered", []int{3, -2, 1, -1}, 51, false},
+		{"leap at the end", []int{1, -1, 3}, 1, false},
+		{"no requirement", []int{1, -1, 3}, 0, true},
+		{"repeated note after a leap", []int{-2, 0, 1, -1}, 50, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MinLeapRecovery(tt.percent)(tt.intervals); got != tt.want {
+				t.Errorf("MinLeapRecovery(%d)(%v) = %v, want %v", tt.percent, tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxDirectionChanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		limit     int
+		want      bool
+	}{
+		{"empty slice", []int{}, 0, true},
+		{"one direction", []int{1, 1, 2, 1}, 0, true},
+		{"one change over no limit", []int{1, 1, -2}, 0, false},
+		{"changes at the limit", []int{2, -1, -1, 3, -1, 2}, 4, true},
+		{"changes over the limit", []int{1, -1, 1, -1, 1, -1}, 4, false},
+		{"repeated note keeps the direction", []int{1, 0, 1, 0, -1}, 1, true},
+		{"repeated note between changes", []int{1, 0, -1, 0, 1}, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaxDirectionChanges(tt.limit)(tt.intervals); got != tt.want {
+				t.Errorf("MaxDirectionChanges(%d)(%v) = %v, want %v", tt.limit, tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClimaxApproach(t *testing.T) {
+	tests := []struct {
+		name                string
+		intervals           []int
+		approach, departure int
+		want                bool
+	}{
+		{"stepwise climax", []int{1, 2, 1, -1, -2, -1}, 1, 1, true},
+		{"climax approached by a leap", []int{1, 1, 3, -1, -2, -1, -1}, 1, 0, false},
+		{"leap into the climax unrestricted", []int{1, 1, 3, -1, -2, -1, -1}, 0, 1, true},
+		{"climax left by a third", []int{1, 2, 1, -2, -1, -1}, 1, 2, true},
+		{"climax left by a fourth", []int{1, 2, 1, -3, 1, -1}, 1, 2, false},
+		{"climax on the first note", []int{-2, -1, 3}, 1, 1, false},
+		{"climax on the last note", []int{-1, 1, 2}, 2, 1, true},
+		{"every occurrence approached by step", []int{1, -1, 1, -2, -1}, 1, 0, true},
+		{"second occurrence approached by a leap", []int{2, -2, 2, -1, -1}, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClimaxApproach(tt.approach, tt.departure)(tt.intervals); got != tt.want {
+				t.Errorf("ClimaxApproach(%d, %d)(%v) = %v, want %v", tt.approach, tt.departure, tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
 // TODO: fix cases
 func TestValidateClimax(t *testing.T) {
 	tests := []struct {
@@ -1001,6 +1272,45 @@ func TestNoSequences(t *testing.T) {
 	}
 }
 
+// repeatingLeapPatternsByPairs is the straightforward form of hasRepeatingLeapPatterns:
+// it compares every pair of equal leap patterns and the groups of intervals they start.
+func repeatingLeapPatternsByPairs(intervals []int) bool {
+	for i := 0; i+3 <= len(intervals); i++ {
+		if !isLeapPattern(intervals[i], intervals[i+1], intervals[i+2]) {
+			continue
+		}
+		for j := i + 1; j+3 <= len(intervals); j++ {
+			d := j - i
+			if j+d <= len(intervals) && slices.Equal(intervals[i:i+3], intervals[j:j+3]) &&
+				slices.Equal(intervals[i:j], intervals[j:j+d]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestHasRepeatingLeapPatterns_Equivalence(t *testing.T) {
+	alphabet := []int{-4, -3, -2, -1, -1, 1, 1, 2, 3}
+	rng := rand.New(rand.NewSource(1))
+	for range 20000 {
+		intervals := make([]int, 3+rng.Intn(14))
+		for i := range intervals {
+			intervals[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		if got, want := hasRepeatingLeapPatterns(intervals), repeatingLeapPatternsByPairs(intervals); got != want {
+			t.Fatalf("hasRepeatingLeapPatterns(%v) = %v, comparing pairs gives %v", intervals, got, want)
+		}
+	}
+}
+
+func BenchmarkNoSequences(b *testing.B) {
+	intervals := []int{1, 2, -1, -1, 3, -1, -2, 1, 1, 4, -1, -2, -1, -1, -1}
+	for i := 0; i < b.N; i++ {
+		NoSequences(intervals)
+	}
+}
+
 func TestAvoidSeventhNinthBetweenExtremes(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1079,6 +1389,28 @@ func TestAvoidSeventhNinthBetweenExtremes(t *testing.T) {
 	}
 }
 
+func TestAvoidSeventhNinthBetweenExtremesFrom(t *testing.T) {
+	tests := []struct {
+		name      string
+		opening   int
+		intervals []int
+		want      bool
+	}{
+		{"opening on the final", 0, []int{1, 1, 1, 1, 1, 1}, false},
+		{"seventh below the first note, not below the final", 4, []int{1, -1, -2, -1, -1, -1, -1, 1, 1}, true},
+		{"seventh above the final", 4, []int{2, -1, -2, -1, -1, -1, -1, 1}, false},
+		{"empty slice", 4, []int{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AvoidSeventhNinthBetweenExtremesFrom(tt.opening)(tt.intervals); got != tt.want {
+				t.Errorf("AvoidSeventhNinthBetweenExtremesFrom(%d)(%v) = %v, want %v", tt.opening, tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestValidateLeadingTone(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1205,6 +1537,30 @@ func TestValidateLeadingTone(t *testing.T) {
 	}
 }
 
+func TestValidateLeadingToneFrom(t *testing.T) {
+	tests := []struct {
+		name      string
+		opening   int
+		intervals []int
+		want      bool
+	}{
+		{"opening on the final", 0, []int{-2, 1, 1}, true},
+		{"step below the first note is no leading tone", 4, []int{-1, 2, -1, -1, -1, -1, -1}, true},
+		{"leading tone approached by leap", 4, []int{-2, -3, 1}, false},
+		{"leading tone resolving to the final", 4, []int{-1, -1, -1, -1, -1, 1}, true},
+		{"opening below the final", -3, []int{1, 1, 1, 1, -2, 1}, false},
+		{"empty slice", 4, []int{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateLeadingToneFrom(tt.opening)(tt.intervals); got != tt.want {
+				t.Errorf("ValidateLeadingToneFrom(%d)(%v) = %v, want %v", tt.opening, tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNoCloseLargeLeaps(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1291,6 +1647,32 @@ func TestNoCloseLargeLeaps(t *testing.T) {
 	}
 }
 
+func TestNoDissonantLeapPair(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []int
+		want      bool
+	}{
+		{"empty slice", []int{}, true},
+		{"fifth and third up outline a seventh", []int{1, 4, 2, -1}, false},
+		{"fourth and fourth down outline a seventh", []int{-3, -3}, false},
+		{"fifth and fourth up outline a ninth", []int{4, 4}, false},
+		{"fourth and third up outline a sixth", []int{3, 2, -1}, true},
+		{"third and third up outline a fifth", []int{2, 2, -1}, true},
+		{"leaps in opposite directions", []int{4, -2, 4}, true},
+		{"leap and step outline a seventh", []int{5, 1}, true},
+		{"octave and sixth outline a compound seventh", []int{7, 6}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NoDissonantLeapPair(tt.intervals); got != tt.want {
+				t.Errorf("NoDissonantLeapPair(%v) = %v, want %v", tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNoMoreThanTwoConsecutiveThirds(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1352,3 +1734,139 @@ func TestNoMoreThanTwoConsecutiveThirds(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxConsecutiveIntervals(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    map[int]int
+		intervals []int
+		want      bool
+	}{
+		{"no limits", nil, []int{1, 1, 1, 1, 1, 1}, true},
+		{"steps within limit", map[int]int{1: 3}, []int{1, 1, -1, 2, 1}, true},
+		{"too many steps in both directions", map[int]int{1: 3}, []int{1, 1, -1, -1, 2}, false},
+		{"single fourth", map[int]int{3: 1}, []int{3, -1, 3, -1}, true},
+		{"two fourths in a row", map[int]int{3: 1}, []int{-1, 3, -3, 1}, false},
+		{"other sizes unrestricted", map[int]int{3: 1}, []int{2, 2, 2, 2}, true},
+		{"runs of different sizes", map[int]int{1: 2, 2: 2}, []int{1, 1, 2, -2, 1, 1}, true},
+		{"run interrupted by another size", map[int]int{2: 2}, []int{2, 2, 1, 2, 2}, true},
+		{"empty slice", map[int]int{1: 1}, []int{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaxConsecutiveIntervals(tt.limits)(tt.intervals); got != tt.want {
+				t.Errorf("MaxConsecutiveIntervals(%v)(%v) = %v, want %v", tt.limits, tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestrictToDegrees(t *testing.T) {
+	tests := []struct {
+		name      string
+		degrees   []int
+		intervals []int
+		want      bool
+	}{
+		{
+			name:      "empty slice with tonic allowed",
+			degrees:   []int{1, 2, 3},
+			intervals: []int{},
+			want:      true,
+		},
+		{
+			name:      "tonic not allowed",
+			degrees:   []int{2, 3},
+			intervals: []int{},
+			want:      false,
+		},
+		{
+			name:      "stays within allowed degrees",
+			degrees:   []int{1, 2, 3, 4, 5},
+			intervals: []int{1, 1, 2, -1, -3},
+			want:      true,
+		},
+		{
+			name:      "reaches excluded sixth degree",
+			degrees:   []int{1, 2, 3, 4, 5, 7},
+			intervals: []int{2, 3, -1},
+			want:      false,
+		},
+		{
+			name:      "excluded degree below the tonic",
+			degrees:   []int{1, 2, 3, 4, 5, 7},
+			intervals: []int{-1, -1},
+			want:      false,
+		},
+		{
+			name:      "allowed degree in another octave",
+			degrees:   []int{1, 2, 3, 4, 5},
+			intervals: []int{4, 3, -4, -3},
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RestrictToDegrees(tt.degrees)(tt.intervals); got != tt.want {
+				t.Errorf("RestrictToDegrees(%v)(%v) = %v, want %v", tt.degrees, tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartsWith(t *testing.T) {
+	prefix := []int{1, 2, -1}
+	tests := []struct {
+		name      string
+		intervals []int
+		want      bool
+	}{
+		{"empty melody", []int{}, true},
+		{"agrees so far", []int{1, 2}, true},
+		{"continues the prefix", []int{1, 2, -1, -1, 1, -1, -1}, true},
+		{"differs from the prefix", []int{1, 3}, false},
+		{"differs at the end of the prefix", []int{1, 2, 1, -1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StartsWith(prefix)(tt.intervals); got != tt.want {
+				t.Errorf("StartsWith(%v)(%v) = %v, want %v", prefix, tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstFailure(t *testing.T) {
+	set := DefaultRuleSet()
+	tests := []struct {
+		name      string
+		intervals []int
+		wantRule  string
+		wantOK    bool
+	}{
+		{"valid cantus", []int{2, -1, -1, 3, -1, 2, -1, -1, -1, -1}, "", true},
+		{"partial rule", []int{5, -1, -1, -1, -1, -1}, "NoBeginWithFive", false},
+		{"complete rule", []int{-3, 2, -1, 2, -3, 1, 2}, "ValidateClimax", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := FirstFailure(tt.intervals, set)
+			if rule != tt.wantRule || ok != tt.wantOK {
+				t.Errorf("FirstFailure(%v) = %q, %v; want %q, %v", tt.intervals, rule, ok, tt.wantRule, tt.wantOK)
+			}
+		})
+	}
+
+	set.Disable("NoBeginWithFive")
+	if rule, _ := FirstFailure([]int{5, -1, -1, -1, -1, -1}, set); rule == "NoBeginWithFive" {
+		t.Error("FirstFailure must skip disabled rules")
+	}
+	set.SetSoft("ValidateClimax", 1)
+	if rule, _ := FirstFailure([]int{-3, 2, -1, 2, -3, 1, 2}, set); rule == "ValidateClimax" {
+		t.Error("FirstFailure must skip soft rules")
+	}
+}
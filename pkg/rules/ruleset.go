@@ -0,0 +1,296 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUnknownRule is returned when a rule is referred to by a name that is not in the rule set.
+var ErrUnknownRule = errors.New("unknown rule")
+
+// ErrDuplicateRule is returned when adding a rule whose name is already taken.
+var ErrDuplicateRule = errors.New("duplicate rule")
+
+// defaultLeaps are the leaps of a cantus firmus in strict style: thirds to fifths in both
+// directions and the ascending sixth. Steps (-1 and 1) are always allowed.
+var defaultLeaps = []int{-4, -3, -2, 2, 3, 4, 5}
+
+// RuleSet is an ordered set of rules, each of which can be switched on and off,
+// together with the leaps a melody may use, the limit of its range and the notes it opens and ends on.
+//
+// Enabled rules are hard by default: a melody breaking one is rejected. A rule marked
+// soft with a weight (see SetSoft) does not reject melodies; instead every soft rule
+// a melody breaks adds its weight to the melody's penalty (see Penalty), so that
+// candidates can be ranked rather than discarded. Hard rules are errors and soft
+// rules warnings (see Severity).
+//
+// A RuleSet is not safe for concurrent modification; use Clone to derive variants.
+type RuleSet struct {
+	rules    []Rule
+	disabled map[string]bool
+	soft     map[string]float64
+	leaps    []int
+	maxRange int
+	cadence  []int
+	repeats  bool
+	opening  int
+	ending   int
+	// subtonic is set when the mode has no leading tone (see SetMode)
+	subtonic bool
+}
+
+// NewRuleSet returns a rule set containing the given rules, all enabled, in the given order,
+// and the leaps and range limit (a tenth) of strict style.
+func NewRuleSet(rs ...Rule) *RuleSet {
+	return &RuleSet{
+		rules:    slices.Clone(rs),
+		disabled: make(map[string]bool),
+		soft:     make(map[string]float64),
+		leaps:    defaultLeaps,
+		maxRange: RangeTenth,
+		cadence:  []int{CadenceSupertonic, CadenceLeadingTone},
+	}
+}
+
+// DefaultRuleSet returns a rule set with all registered rules (see Registry) enabled.
+func DefaultRuleSet() *RuleSet {
+	return NewRuleSet(registry...)
+}
+
+// Clone returns an independent copy of the rule set.
+func (s *RuleSet) Clone() *RuleSet {
+	clone := NewRuleSet(s.rules...)
+	for name := range s.disabled {
+		clone.disabled[name] = true
+	}
+	for name, weight := range s.soft {
+		clone.soft[name] = weight
+	}
+	clone.leaps = s.leaps
+	clone.maxRange = s.maxRange
+	clone.cadence = s.cadence
+	clone.repeats = s.repeats
+	clone.opening = s.opening
+	clone.ending = s.ending
+	clone.subtonic = s.subtonic
+	return clone
+}
+
+// MaxRange returns the largest range of a melody allowed by the rule set, in steps.
+func (s *RuleSet) MaxRange() int {
+	return s.maxRange
+}
+
+// SetMaxRange limits the range of a melody to limit steps (e.g. RangeOctave or RangeTwelfth)
+// by replacing the rule set's range rule, keeping its position and whether it is enabled or soft.
+// It fails if the rule set has no range rule.
+func (s *RuleSet) SetMaxRange(limit int) error {
+	if limit <= 0 {
+		return fmt.Errorf("range limit must be positive, got %d", limit)
+	}
+	old := rangeRule(s.maxRange).Name
+	i := slices.IndexFunc(s.rules, func(r Rule) bool { return r.Name == old })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, old)
+	}
+
+	r := rangeRule(limit)
+	s.rules[i] = r
+	if s.disabled[old] {
+		delete(s.disabled, old)
+		s.disabled[r.Name] = true
+	}
+	if weight, ok := s.soft[old]; ok {
+		delete(s.soft, old)
+		s.soft[r.Name] = weight
+	}
+	s.maxRange = limit
+	return nil
+}
+
+// Leaps returns the intervals other than steps that a melody may use, in the order
+// the generator tries them. The returned slice is a copy and may be modified.
+func (s *RuleSet) Leaps() []int {
+	return slices.Clone(s.leaps)
+}
+
+// SetLeaps sets the intervals other than steps that a melody may use, in the order
+// the generator should try them.
+func (s *RuleSet) SetLeaps(leaps ...int) {
+	s.leaps = slices.Clone(leaps)
+}
+
+// RepeatedNotes reports whether a melody may repeat a note immediately (see SetRepeatedNotes).
+func (s *RuleSet) RepeatedNotes() bool {
+	return s.repeats
+}
+
+// SetRepeatedNotes sets whether a melody may repeat a note immediately, that is use the
+// interval 0 besides steps and leaps. Strict style forbids repeated notes, which is the
+// default; some treatises allow a single one, as the SingleRepeatedNote rule does.
+func (s *RuleSet) SetRepeatedNotes(allow bool) {
+	s.repeats = allow
+}
+
+// SetCadence sets the scale degrees from which the final may be approached by step
+// (CadenceSupertonic, CadenceLeadingTone or both) by replacing the Cadence rule,
+// keeping whether it is enabled or soft.
+func (s *RuleSet) SetCadence(degrees ...int) error {
+	if len(degrees) == 0 {
+		return errors.New("cadence needs at least one degree")
+	}
+	for _, degree := range degrees {
+		if degree != CadenceSupertonic && degree != CadenceLeadingTone {
+			return fmt.Errorf("cadence degree must be %d or %d, got %d", CadenceSupertonic, CadenceLeadingTone, degree)
+		}
+	}
+	i := slices.IndexFunc(s.rules, func(r Rule) bool { return r.Name == "Cadence" })
+	if i < 0 {
+		return fmt.Errorf("%w: Cadence", ErrUnknownRule)
+	}
+	s.rules[i] = cadenceRule(degrees...)
+	s.cadence = slices.Clone(degrees)
+	return nil
+}
+
+// Cadence returns the scale degrees from which the final may be approached by step
+// (see SetCadence). The returned slice is a copy and may be modified.
+func (s *RuleSet) Cadence() []int {
+	return slices.Clone(s.cadence)
+}
+
+// Replace substitutes r for the rule with the given name, keeping its position in the set.
+// The new rule is enabled and hard.
+func (s *RuleSet) Replace(name string, r Rule) error {
+	i := slices.IndexFunc(s.rules, func(r Rule) bool { return r.Name == name })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, name)
+	}
+	delete(s.disabled, name)
+	delete(s.soft, name)
+	s.rules[i] = r
+	return nil
+}
+
+// Add appends r to the rule set, enabled and hard. It fails if r has no name or Check
+// function, or if the set already contains a rule with the same name.
+func (s *RuleSet) Add(r Rule) error {
+	if err := validateRule(r); err != nil {
+		return err
+	}
+	if s.contains(r.Name) {
+		return fmt.Errorf("%w: %s", ErrDuplicateRule, r.Name)
+	}
+	s.rules = append(s.rules, r)
+	return nil
+}
+
+// Enable switches on the rule with the given name.
+func (s *RuleSet) Enable(name string) error {
+	if !s.contains(name) {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, name)
+	}
+	delete(s.disabled, name)
+	return nil
+}
+
+// Disable switches off the rule with the given name.
+func (s *RuleSet) Disable(name string) error {
+	if !s.contains(name) {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, name)
+	}
+	s.disabled[name] = true
+	return nil
+}
+
+// Enabled reports whether the rule set contains the rule with the given name and it is switched on.
+func (s *RuleSet) Enabled(name string) bool {
+	return s.contains(name) && !s.disabled[name]
+}
+
+// Names returns the names of all rules in the set, enabled or not, in order.
+func (s *RuleSet) Names() []string {
+	names := make([]string, len(s.rules))
+	for i, r := range s.rules {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// SetSoft marks the rule with the given name as soft: breaking it adds weight to
+// the penalty of a melody instead of rejecting it. The weight must be positive.
+func (s *RuleSet) SetSoft(name string, weight float64) error {
+	if !s.contains(name) {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, name)
+	}
+	if weight <= 0 {
+		return fmt.Errorf("weight of soft rule %s must be positive, got %g", name, weight)
+	}
+	s.soft[name] = weight
+	return nil
+}
+
+// SetHard marks the rule with the given name as hard again (see SetSoft).
+func (s *RuleSet) SetHard(name string) error {
+	if !s.contains(name) {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, name)
+	}
+	delete(s.soft, name)
+	return nil
+}
+
+// Weight returns the weight of the rule with the given name if it is soft, and 0 otherwise.
+func (s *RuleSet) Weight(name string) float64 {
+	return s.soft[name]
+}
+
+// Rules returns the enabled rules in order, hard and soft.
+func (s *RuleSet) Rules() []Rule {
+	return s.filter(func(Rule) bool { return true })
+}
+
+// Partial returns the enabled hard rules that can be checked on incomplete melodies, in order.
+func (s *RuleSet) Partial() []Rule {
+	return s.filter(func(r Rule) bool { return r.Partial && s.soft[r.Name] == 0 })
+}
+
+// Complete returns the enabled hard rules that are checked on finished melodies only, in order.
+func (s *RuleSet) Complete() []Rule {
+	return s.filter(func(r Rule) bool { return !r.Partial && s.soft[r.Name] == 0 })
+}
+
+// Soft returns the enabled soft rules in order.
+func (s *RuleSet) Soft() []Rule {
+	return s.filter(func(r Rule) bool { return s.soft[r.Name] > 0 })
+}
+
+// Penalty returns the sum of the weights of the enabled soft rules broken by a complete
+// interval sequence. Partial rules are checked on every prefix, as during generation.
+func (s *RuleSet) Penalty(intervals []int) float64 {
+	penalty := 0.0
+	for _, v := range s.Warnings(intervals) {
+		penalty += s.soft[v.Rule]
+	}
+	return penalty
+}
+
+// Check returns the enabled rules, hard and soft, broken by the interval sequence (see CheckRules).
+func (s *RuleSet) Check(intervals []int) []Violation {
+	return CheckRules(intervals, s.Rules())
+}
+
+func (s *RuleSet) contains(name string) bool {
+	return slices.ContainsFunc(s.rules, func(r Rule) bool { return r.Name == name })
+}
+
+// filter returns the enabled rules satisfying keep, in order.
+func (s *RuleSet) filter(keep func(Rule) bool) []Rule {
+	var result []Rule
+	for _, r := range s.rules {
+		if !s.disabled[r.Name] && keep(r) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,285 @@
+package rules
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRuleSet_EnableDisable(t *testing.T) {
+	rs := DefaultRuleSet()
+	if got, want := len(rs.Rules()), len(registry); got != want {
+		t.Fatalf("DefaultRuleSet has %d enabled rules, want %d", got, want)
+	}
+
+	if err := rs.Disable("ValidateClimax"); err != nil {
+		t.Fatalf("Disable(ValidateClimax) returned %v", err)
+	}
+	if rs.Enabled("ValidateClimax") {
+		t.Error("ValidateClimax is still enabled after Disable")
+	}
+	for _, r := range rs.Complete() {
+		if r.Name == "ValidateClimax" {
+			t.Error("Complete() returned a disabled rule")
+		}
+	}
+	if !slices.Contains(rs.Names(), "ValidateClimax") {
+		t.Error("Names() must list disabled rules")
+	}
+
+	if err := rs.Enable("ValidateClimax"); err != nil {
+		t.Fatalf("Enable(ValidateClimax) returned %v", err)
+	}
+	if !rs.Enabled("ValidateClimax") {
+		t.Error("ValidateClimax is disabled after Enable")
+	}
+
+	if err := rs.Disable("NoSuchRule"); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("Disable(NoSuchRule) = %v, want ErrUnknownRule", err)
+	}
+	if err := rs.Enable("NoSuchRule"); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("Enable(NoSuchRule) = %v, want ErrUnknownRule", err)
+	}
+}
+
+func TestRuleSet_PartialComplete(t *testing.T) {
+	rs := DefaultRuleSet()
+	partial, complete := rs.Partial(), rs.Complete()
+	if len(partial)+len(complete) != len(registry) {
+		t.Fatalf("Partial() and Complete() return %d rules, want %d", len(partial)+len(complete), len(registry))
+	}
+	for _, r := range partial {
+		if !r.Partial {
+			t.Errorf("Partial() returned complete rule %s", r.Name)
+		}
+	}
+	for _, r := range complete {
+		if r.Partial {
+			t.Errorf("Complete() returned partial rule %s", r.Name)
+		}
+	}
+}
+
+func TestRuleSet_Clone(t *testing.T) {
+	rs := DefaultRuleSet()
+	rs.Disable("NoSequences")
+
+	clone := rs.Clone()
+	if clone.Enabled("NoSequences") {
+		t.Error("Clone() lost the disabled state")
+	}
+	clone.Enable("NoSequences")
+	if rs.Enabled("NoSequences") {
+		t.Error("enabling a rule in the clone changed the original")
+	}
+}
+
+func TestRuleSet_RepeatedNotes(t *testing.T) {
+	rs := DefaultRuleSet()
+	if rs.RepeatedNotes() {
+		t.Error("repeated notes must be forbidden by default")
+	}
+	rs.SetRepeatedNotes(true)
+	if !rs.RepeatedNotes() || !rs.Clone().RepeatedNotes() {
+		t.Error("SetRepeatedNotes(true) must allow repeated notes, also in clones")
+	}
+	if DefaultRuleSet().RepeatedNotes() {
+		t.Error("SetRepeatedNotes must not change the default rule set")
+	}
+}
+
+func TestRuleSet_Check(t *testing.T) {
+	intervals := []int{1, 1, -1, -1}
+	rs := NewRuleSet(mustRule(t, "MinDirectionChanges"))
+	if got := rs.Check(intervals); len(got) != 1 {
+		t.Fatalf("Check(%v) = %v, want one violation", intervals, got)
+	}
+	rs.Disable("MinDirectionChanges")
+	if got := rs.Check(intervals); got != nil {
+		t.Errorf("Check(%v) with the rule disabled = %v, want none", intervals, got)
+	}
+}
+
+func mustRule(t *testing.T, name string) Rule {
+	t.Helper()
+	r, ok := LookupByName(name)
+	if !ok {
+		t.Fatalf("rule %s is not registered", name)
+	}
+	return r
+}
+
+func TestRuleSet_LeapsReplace(t *testing.T) {
+	rs := DefaultRuleSet()
+	leaps := rs.Leaps()
+	leaps[0] = 100
+	if rs.Leaps()[0] == 100 {
+		t.Error("Leaps() must return a copy")
+	}
+
+	rs.SetLeaps(2, -2)
+	if !slices.Equal(rs.Leaps(), []int{2, -2}) {
+		t.Errorf("Leaps() = %v after SetLeaps(2, -2)", rs.Leaps())
+	}
+	if slices.Equal(rs.Clone().Leaps(), DefaultRuleSet().Leaps()) {
+		t.Error("Clone() lost the leaps")
+	}
+
+	rs.Disable("NoSequences")
+	replacement := Rule{Name: "Anything", Partial: true, Check: func([]int) bool { return true }}
+	if err := rs.Replace("NoSequences", replacement); err != nil {
+		t.Fatalf("Replace(NoSequences) returned %v", err)
+	}
+	if rs.Enabled("NoSequences") || !rs.Enabled("Anything") {
+		t.Error("Replace must swap the rule and enable the replacement")
+	}
+	if i := slices.Index(rs.Names(), "Anything"); i != slices.Index(DefaultRuleSet().Names(), "NoSequences") {
+		t.Errorf("replacement is at position %d, want the position of the replaced rule", i)
+	}
+	if err := rs.Replace("NoSuchRule", replacement); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("Replace(NoSuchRule) = %v, want ErrUnknownRule", err)
+	}
+}
+
+func TestRuleSet_Add(t *testing.T) {
+	rs := DefaultRuleSet()
+	if err := rs.Add(LeapRatioRule(1, 3)); err != nil {
+		t.Fatalf("Add(MaxLeapRatio) returned %v", err)
+	}
+	if names := rs.Names(); names[len(names)-1] != "MaxLeapRatio" || !rs.Enabled("MaxLeapRatio") {
+		t.Errorf("Add must append an enabled rule, got %v", names)
+	}
+	if !slices.ContainsFunc(rs.Complete(), func(r Rule) bool { return r.Name == "MaxLeapRatio" }) {
+		t.Error("MaxLeapRatio must be a complete rule")
+	}
+	if err := rs.Add(LeapRatioRule(1, 2)); !errors.Is(err, ErrDuplicateRule) {
+		t.Errorf("Add(MaxLeapRatio) again = %v, want ErrDuplicateRule", err)
+	}
+	if err := rs.Add(Rule{Name: "NoCheck"}); err == nil {
+		t.Error("Add must reject a rule without a Check function")
+	}
+	if len(DefaultRuleSet().Names()) == len(rs.Names()) {
+		t.Error("Add must not change the default rule set")
+	}
+}
+
+func TestRuleSet_Soft(t *testing.T) {
+	rs := DefaultRuleSet()
+	if err := rs.SetSoft("ValidateClimax", 2); err != nil {
+		t.Fatalf("SetSoft(ValidateClimax, 2) returned %v", err)
+	}
+	if err := rs.SetSoft("NoSequences", 0.5); err != nil {
+		t.Fatalf("SetSoft(NoSequences, 0.5) returned %v", err)
+	}
+
+	for _, r := range append(rs.Partial(), rs.Complete()...) {
+		if r.Name == "ValidateClimax" || r.Name == "NoSequences" {
+			t.Errorf("soft rule %s is listed among the hard rules", r.Name)
+		}
+	}
+	if got := len(rs.Soft()); got != 2 {
+		t.Errorf("Soft() returned %d rules, want 2", got)
+	}
+	if got := rs.Weight("ValidateClimax"); got != 2 {
+		t.Errorf("Weight(ValidateClimax) = %g, want 2", got)
+	}
+
+	tests := []struct {
+		name      string
+		intervals []int
+		want      float64
+	}{
+		{"no soft rule broken", []int{2, -1, -1, 3, -1, 2, -1, -1, -1, -1}, 0},
+		{"repeated climax", []int{1, 1, -1, 1, -1, -1}, 2},
+		{"hard rules do not count", []int{5, -1, -2, -1, -1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rs.Penalty(tt.intervals); got != tt.want {
+				t.Errorf("Penalty(%v) = %g, want %g", tt.intervals, got, tt.want)
+			}
+		})
+	}
+
+	if err := rs.SetSoft("ValidateClimax", 0); err == nil {
+		t.Error("SetSoft with a zero weight must fail")
+	}
+	if err := rs.SetSoft("NoSuchRule", 1); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("SetSoft(NoSuchRule) = %v, want ErrUnknownRule", err)
+	}
+
+	clone := rs.Clone()
+	if err := clone.SetHard("ValidateClimax"); err != nil {
+		t.Fatalf("SetHard(ValidateClimax) returned %v", err)
+	}
+	if clone.Weight("ValidateClimax") != 0 || rs.Weight("ValidateClimax") != 2 {
+		t.Error("SetHard on the clone must only affect the clone")
+	}
+}
+
+func TestRuleSet_SetMaxRange(t *testing.T) {
+	rs := DefaultRuleSet()
+	if rs.MaxRange() != RangeTenth {
+		t.Fatalf("MaxRange() = %d, want %d", rs.MaxRange(), RangeTenth)
+	}
+	position := slices.Index(rs.Names(), "NoRangeExceedsDecima")
+
+	if err := rs.SetSoft("NoRangeExceedsDecima", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.SetMaxRange(RangeOctave); err != nil {
+		t.Fatalf("SetMaxRange(RangeOctave) returned %v", err)
+	}
+	if rs.MaxRange() != RangeOctave {
+		t.Errorf("MaxRange() = %d after SetMaxRange(RangeOctave)", rs.MaxRange())
+	}
+	if got := slices.Index(rs.Names(), "NoRangeExceedsOctave"); got != position {
+		t.Errorf("the range rule moved from position %d to %d", position, got)
+	}
+	if rs.Weight("NoRangeExceedsOctave") != 3 {
+		t.Error("SetMaxRange must keep the range rule soft")
+	}
+
+	rs.Disable("NoRangeExceedsOctave")
+	if err := rs.SetMaxRange(13); err != nil {
+		t.Fatalf("SetMaxRange(13) returned %v", err)
+	}
+	if rs.Enabled("NoRangeExceeds13Steps") || !slices.Contains(rs.Names(), "NoRangeExceeds13Steps") {
+		t.Error("SetMaxRange must keep the range rule disabled")
+	}
+
+	if err := rs.SetMaxRange(0); err == nil {
+		t.Error("SetMaxRange(0) must fail")
+	}
+	if err := NewRuleSet().SetMaxRange(RangeOctave); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("SetMaxRange on a rule set without a range rule = %v, want ErrUnknownRule", err)
+	}
+}
+
+func TestRuleSet_SetCadence(t *testing.T) {
+	fromBelow := []int{-2, 1, 1}
+
+	rs := DefaultRuleSet()
+	if got := rs.Check(fromBelow); slices.ContainsFunc(got, func(v Violation) bool { return v.Rule == "Cadence" }) {
+		t.Errorf("the default cadence must accept the leading tone, got %v", got)
+	}
+
+	if err := rs.SetCadence(CadenceSupertonic); err != nil {
+		t.Fatalf("SetCadence(CadenceSupertonic) returned %v", err)
+	}
+	got := rs.Check(fromBelow)
+	i := slices.IndexFunc(got, func(v Violation) bool { return v.Rule == "Cadence" })
+	if i < 0 {
+		t.Fatalf("Check(%v) = %v, want a Cadence violation", fromBelow, got)
+	}
+	if want := "The final must be approached by step from the supertonic."; got[i].Message != want {
+		t.Errorf("message = %q, want %q", got[i].Message, want)
+	}
+
+	if err := rs.SetCadence(3); err == nil {
+		t.Error("SetCadence(3) must fail")
+	}
+	if err := rs.SetCadence(); err == nil {
+		t.Error("SetCadence() must fail")
+	}
+}
@@ -0,0 +1,71 @@
+package rules
+
+import "fmt"
+
+// Severity tells whether breaking a rule of a rule set rejects a melody or is only reported.
+type Severity int
+
+const (
+	// SeverityError rejects a melody breaking the rule. Hard rules are errors.
+	SeverityError Severity = iota
+	// SeverityWarning reports a broken rule without rejecting the melody, for stylistic advice
+	// such as "more than one leap of a sixth is unusual". Soft rules are warnings: breaking
+	// one adds its weight to the melody's penalty (see RuleSet.SetSoft).
+	SeverityWarning
+)
+
+// WarningWeight is the weight SetSeverity gives a rule turned into a warning that is not soft yet.
+const WarningWeight = 1.0
+
+// String returns "error" or "warning".
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseSeverity parses "error" or "warning".
+func ParseSeverity(s string) (Severity, error) {
+	switch s {
+	case "error":
+		return SeverityError, nil
+	case "warning":
+		return SeverityWarning, nil
+	}
+	return 0, fmt.Errorf("invalid severity %q: want error or warning", s)
+}
+
+// Severity returns the severity of the rule with the given name: SeverityWarning if it is soft,
+// SeverityError otherwise.
+func (s *RuleSet) Severity(name string) Severity {
+	if s.soft[name] > 0 {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+// SetSeverity sets the severity of the rule with the given name. A rule turned into a warning
+// becomes soft with WarningWeight, unless it is soft already and keeps its weight; a rule turned
+// into an error becomes hard.
+func (s *RuleSet) SetSeverity(name string, severity Severity) error {
+	switch severity {
+	case SeverityError:
+		return s.SetHard(name)
+	case SeverityWarning:
+		if weight := s.soft[name]; weight > 0 {
+			return nil
+		}
+		return s.SetSoft(name, WarningWeight)
+	}
+	return fmt.Errorf("invalid severity %v", severity)
+}
+
+// Warnings returns the enabled soft rules broken by the interval sequence, which do not make
+// it invalid (see CheckRules).
+func (s *RuleSet) Warnings(intervals []int) []Violation {
+	return CheckRules(intervals, s.Soft())
+}
@@ -0,0 +1,62 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for _, want := range []Severity{SeverityError, SeverityWarning} {
+		got, err := ParseSeverity(want.String())
+		if err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %v, %v; want %v", want.String(), got, err, want)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity(fatal) must fail")
+	}
+}
+
+func TestRuleSet_SetSeverity(t *testing.T) {
+	rs := DefaultRuleSet()
+	if got := rs.Severity("ValidateClimax"); got != SeverityError {
+		t.Fatalf("Severity(ValidateClimax) = %v by default, want error", got)
+	}
+
+	if err := rs.SetSeverity("ValidateClimax", SeverityWarning); err != nil {
+		t.Fatalf("SetSeverity(ValidateClimax, warning) returned %v", err)
+	}
+	if rs.Severity("ValidateClimax") != SeverityWarning || rs.Weight("ValidateClimax") != WarningWeight {
+		t.Errorf("ValidateClimax is a %v of weight %g, want a warning of weight %g",
+			rs.Severity("ValidateClimax"), rs.Weight("ValidateClimax"), WarningWeight)
+	}
+
+	rs.SetSoft("NoSequences", 3)
+	if err := rs.SetSeverity("NoSequences", SeverityWarning); err != nil {
+		t.Fatalf("SetSeverity(NoSequences, warning) returned %v", err)
+	}
+	if got := rs.Weight("NoSequences"); got != 3 {
+		t.Errorf("a soft rule turned into a warning has weight %g, want to keep 3", got)
+	}
+
+	// A repeated climax is a warning only
+	intervals := []int{1, 1, -1, 1, -1, -1}
+	warnings := rs.Warnings(intervals)
+	if len(warnings) != 1 || warnings[0].Rule != "ValidateClimax" {
+		t.Errorf("Warnings(%v) = %v, want ValidateClimax", intervals, warnings)
+	}
+
+	if err := rs.SetSeverity("ValidateClimax", SeverityError); err != nil {
+		t.Fatalf("SetSeverity(ValidateClimax, error) returned %v", err)
+	}
+	if rs.Severity("ValidateClimax") != SeverityError || len(rs.Warnings(intervals)) != 0 {
+		t.Error("ValidateClimax must be an error again")
+	}
+
+	if err := rs.SetSeverity("NoSuchRule", SeverityWarning); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("SetSeverity(NoSuchRule) = %v, want ErrUnknownRule", err)
+	}
+	if err := rs.SetSeverity("ValidateClimax", Severity(7)); err == nil {
+		t.Error("SetSeverity with an invalid severity must fail")
+	}
+}
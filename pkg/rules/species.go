@@ -0,0 +1,143 @@
+package rules
+
+import (
+	"fmt"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/internal/utils"
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"slices"
+)
+
+// Vertical intervals of first-species counterpoint, reduced to a simple interval in steps
+const (
+	verticalUnison = 0
+	verticalThird  = 2
+	verticalFifth  = 4
+	verticalSixth  = 5
+)
+
+// FirstSpeciesRule returns a partial rule requiring a melody to form first-species counterpoint
+// with a fixed voice (see FirstSpecies), so that the generator writes a second voice against it.
+func FirstSpeciesRule(voice []int, offset int) Rule {
+	return Rule{
+		Name:        "FirstSpecies",
+		Description: fmt.Sprintf("The melody must form first-species counterpoint with the voice %v.", voice),
+		Partial:     true,
+		Check:       FirstSpecies(voice, offset),
+		Incremental: IncrementalFirstSpecies(voice, offset),
+	}
+}
+
+// FirstSpecies returns a rule checking a melody note against note with a fixed voice given by
+// its intervals, the melody beginning offset steps above the first note of the voice (below it
+// if negative):
+//   - every vertical interval is a consonance: a unison, third, fifth, sixth or octave, or one
+//     of them an octave or more wider
+//   - the first is a unison, fifth or octave, and the last, once the melody is as long as the
+//     voice, a unison or octave
+//   - no two consecutive vertical intervals are both fifths, or both unisons or octaves, unless
+//     neither voice moves: parallel perfect consonances are forbidden, in contrary motion too
+//   - the melody does not cross the voice: it stays on the side it began on
+//
+// The intervals are diatonic, so their quality is not checked: a diminished fifth counts as a
+// fifth (see ConsonantVerticals for the realized voices). Works with partial slices during
+// generation; a melody longer than the voice breaks the rule.
+func FirstSpecies(voice []int, offset int) ValidationFunc {
+	c := counterpoint{voice: slices.Clone(voice), offset: offset}
+	return func(intervals []int) bool {
+		if len(intervals) > len(c.voice) || !c.first() {
+			return false
+		}
+		vertical := offset
+		for i, interval := range intervals {
+			next := vertical + interval - c.voice[i]
+			if !c.accepts(i+1, vertical, next, interval) {
+				return false
+			}
+			vertical = next
+		}
+		return true
+	}
+}
+
+// ConsonantVerticals reports whether a realized melody forms no augmented or diminished vertical
+// interval, such as the tritone, with a realized voice sounding note against note with it, which
+// FirstSpecies cannot tell from the perfect and imperfect consonances. Only the notes both voices
+// have are compared.
+func ConsonantVerticals(melody, voice music.Realization) bool {
+	for i := range min(len(melody), len(voice)) {
+		quality, err := music.CalculateIntervalQuality(voice[i], melody[i])
+		if err != nil || quality == "A" || quality == "d" {
+			return false
+		}
+	}
+	return true
+}
+
+// IncrementalFirstSpecies returns incremental checkers equivalent to FirstSpecies(voice, offset).
+func IncrementalFirstSpecies(voice []int, offset int) func() IncrementalRule {
+	c := counterpoint{voice: slices.Clone(voice), offset: offset}
+	return func() IncrementalRule {
+		return &speciesTracker{counterpoint: c, verticals: []int{offset}}
+	}
+}
+
+// counterpoint is the fixed voice of FirstSpecies and the distance of the melody from it.
+type counterpoint struct {
+	voice  []int
+	offset int
+}
+
+// first reports whether the melody may begin offset steps from the voice.
+func (c counterpoint) first() bool {
+	class := utils.Abs(c.offset) % 7
+	return class == verticalUnison || class == verticalFifth
+}
+
+// accepts reports whether note i of the melody, moving by interval, may form the vertical
+// interval next with note i of the voice after the vertical interval before at the note before.
+func (c counterpoint) accepts(i, before, next, interval int) bool {
+	if i > len(c.voice) || c.offset > 0 && next < 0 || c.offset < 0 && next > 0 {
+		return false
+	}
+	class := utils.Abs(next) % 7
+	switch class {
+	case verticalUnison, verticalFifth:
+		moved := interval != 0 || c.voice[i-1] != 0
+		if moved && utils.Abs(before)%7 == class {
+			return false
+		}
+	case verticalThird, verticalSixth:
+	default:
+		return false
+	}
+	return i < len(c.voice) || class == verticalUnison
+}
+
+// speciesTracker checks the vertical interval of every new note, remembering the vertical
+// interval at every note of the melody.
+type speciesTracker struct {
+	counterpoint
+	verticals []int
+}
+
+func (s *speciesTracker) Push(interval int) bool {
+	i := len(s.verticals)
+	before := s.verticals[i-1]
+	if i > len(s.voice) {
+		s.verticals = append(s.verticals, before)
+		return false
+	}
+	next := before + interval - s.voice[i-1]
+	s.verticals = append(s.verticals, next)
+	return s.accepts(i, before, next, interval)
+}
+
+func (s *speciesTracker) Pop() {
+	s.verticals = s.verticals[:len(s.verticals)-1]
+}
+
+// AppendSummary appends the vertical interval at the last note, from which the following
+// ones are reckoned.
+func (s *speciesTracker) AppendSummary(b []byte) []byte {
+	return appendInts(b, s.verticals[len(s.verticals)-1])
+}
@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"github.com/sergei-shchetnikov/go-cantus-firmus/pkg/music"
+	"testing"
+)
+
+func TestFirstSpecies(t *testing.T) {
+	// Fux's dorian cantus firmus D F E D G F A G F E D, with his counterpoint above it
+	// beginning on the fifth: A A G A B C C B D C D
+	voice := []int{2, -1, -1, 3, -1, 2, -1, -1, -1, -1}
+	above := []int{0, -1, 1, 1, 1, 0, -1, 2, -1, 1}
+
+	tests := []struct {
+		name      string
+		offset    int
+		intervals []int
+		want      bool
+	}{
+		{"Fux's counterpoint", 4, above, true},
+		{"beginning", 4, above[:5], true},
+		{"no note yet", 7, nil, true},
+		{"beginning on a third", 2, nil, false},
+		{"parallel fifths", 4, []int{2}, false},
+		{"parallel octaves", 7, []int{2}, false},
+		{"fifth to octave", 4, []int{5}, true},
+		{"fifth to third to octave", 4, []int{0, 4}, true},
+		{"octave to unison", 7, []int{-5}, false},
+		{"dissonance", 4, []int{1}, false},
+		{"crossing", 4, []int{-4}, false},
+		{"ending on a sixth", 4, append(above[:9:9], 0), false},
+		{"longer than the voice", 4, append(above[:10:10], -1), false},
+		{"below the voice", -7, []int{0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FirstSpecies(voice, tt.offset)(tt.intervals); got != tt.want {
+				t.Errorf("FirstSpecies(%v, %d)(%v) = %v, want %v", voice, tt.offset, tt.intervals, got, tt.want)
+			}
+		})
+	}
+
+	long := []int{1, 1, -2, 1, 3, -1, -1, -1, 2, -1, -1, -1, 1, 1, -1}
+	checkIncremental(t, FirstSpeciesRule(long, 4))
+	checkIncremental(t, FirstSpeciesRule(long, -7))
+}
+
+func TestConsonantVerticals(t *testing.T) {
+	voice := realize(t, "D3", "F3", "E3", "D3")
+	tests := []struct {
+		name   string
+		melody music.Realization
+		want   bool
+	}{
+		{"perfect and imperfect consonances", realize(t, "D4", "A3", "C4", "D4"), true},
+		{"augmented fourth", realize(t, "A3", "B3", "C4", "D4"), false},
+		{"diminished fifth below", realize(t, "A2", "B2"), false},
+		{"augmented unison", realize(t, "D3", "F#3"), false},
+		{"shorter melody", realize(t, "A3"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConsonantVerticals(tt.melody, voice); got != tt.want {
+				t.Errorf("ConsonantVerticals(%v, %v) = %v, want %v", tt.melody, voice, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package rules
+
+import "fmt"
+
+// Violation describes a rule broken by an interval sequence.
+//
+// Fields:
+//   - Rule: name of the broken rule (see Rule.Name)
+//   - Start, End: indices of the first and last interval of the offending range, inclusive.
+//     Rules on the whole melody span all intervals.
+//   - Message: human-readable explanation naming the notes involved
+//   - Located: whether the range was narrowed to where the rule is broken; otherwise the rule
+//     applies to the melody as a whole
+type Violation struct {
+	Rule    string
+	Start   int
+	End     int
+	Message string
+	Located bool
+}
+
+// Check returns the registered rules (see Registry) broken by the interval sequence,
+// in the order they are checked. It is the detailed counterpart of AllRules.
+func Check(s []int) []Violation {
+	return CheckRules(s, registry)
+}
+
+// CheckRules returns the rules from rs broken by the interval sequence, in the given order.
+//
+// A partial rule ends at the last interval of the shortest prefix it fails on; for a local
+// rule the range starts at the last interval from which the rule still fails up to that end,
+// otherwise at the first interval. A complete rule spans the whole sequence.
+func CheckRules(s []int, rs []Rule) []Violation {
+	var result []Violation
+	for _, r := range rs {
+		if !r.Partial {
+			if !r.Check(s) {
+				result = append(result, NewViolation(r, 0, len(s)-1, false))
+			}
+			continue
+		}
+
+		for end := range s {
+			if r.Check(s[:end+1]) {
+				continue
+			}
+			start := 0
+			if r.Local {
+				start = end
+				for start > 0 && r.Check(s[start:end+1]) {
+					start--
+				}
+			}
+			result = append(result, NewViolation(r, start, end, true))
+			break
+		}
+	}
+	return result
+}
+
+// locationFormat is the format of located violation messages, from the first and last
+// note numbers and the rule description.
+const locationFormat = "notes %d-%d: %s"
+
+// NewViolation builds the Violation of r over the intervals start..end, e.g. for a requirement
+// checked outside of CheckRules. Located violations name the notes involved, counting from 1:
+// interval i lies between notes i+1 and i+2. The message is translated with the catalog in
+// use (see SetCatalog).
+func NewViolation(r Rule, start, end int, located bool) Violation {
+	message := tr(r.Description)
+	if located {
+		message = fmt.Sprintf(tr(locationFormat), start+1, end+2, message)
+	}
+	return Violation{Rule: r.Name, Start: start, End: end, Message: message, Located: located}
+}
@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckRules(t *testing.T) {
+	direction, _ := LookupByName("LimitDirectionalMotion")
+	beginning, _ := LookupByName("NoBeginWithFive")
+	changes, _ := LookupByName("MinDirectionChanges")
+
+	tests := []struct {
+		name      string
+		intervals []int
+		rule      Rule
+		want      []Violation
+	}{
+		{"local rule narrowed to the run", []int{-1, 1, 1, 1, 1, 1, -1}, direction, []Violation{
+			{"LimitDirectionalMotion", 1, 5, "notes 2-7: " + direction.Description, true},
+		}},
+		{"non-local rule starts at the beginning", []int{5, -1, -1}, beginning, []Violation{
+			{"NoBeginWithFive", 0, 0, "notes 1-2: " + beginning.Description, true},
+		}},
+		{"complete rule spans the melody", []int{1, 1, -1, -1}, changes, []Violation{
+			{"MinDirectionChanges", 0, 3, changes.Description, false},
+		}},
+		{"satisfied rule", []int{1, -1, 1, -1}, direction, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckRules(tt.intervals, []Rule{tt.rule})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckRules(%v) = %v, want %v", tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	// Dorian cantus firmus: D F E D G F A G F E D
+	valid := []int{2, -1, -1, 3, -1, 2, -1, -1, -1, -1}
+	if got := Check(valid); got != nil {
+		t.Errorf("Check(%v) = %v, want no violations", valid, got)
+	}
+
+	invalid := []int{1, 1, 1, 1, 1, -1, -1, -1, -1, -1}
+	got := Check(invalid)
+	if len(got) == 0 {
+		t.Fatalf("Check(%v) returned no violations", invalid)
+	}
+	for _, v := range got {
+		if v.Start < 0 || v.End >= len(invalid) || v.Start > v.End || v.Message == "" {
+			t.Errorf("malformed violation %+v", v)
+		}
+		if _, ok := LookupByName(v.Rule); !ok {
+			t.Errorf("violation of unregistered rule %s", v.Rule)
+		}
+	}
+}